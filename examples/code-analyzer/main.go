@@ -2,12 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
@@ -15,11 +20,15 @@ import (
 	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/codemapping"
 )
 
+// outputFormats are the config file formats accepted by --format
+var outputFormats = []string{"yaml", "json", "toml"}
+
 func main() {
 	var (
 		outputPath string
 		verbose    bool
 		format     string
+		watch      bool
 	)
 
 	rootCmd := &cobra.Command{
@@ -39,8 +48,20 @@ The analyzer will:
   • Detect programming language and framework
   • Extract dependencies and versions
   • Generate platform configuration with resource recommendations
-  • Provide actionable recommendations for improvements`,
+  • Provide actionable recommendations for improvements
+
+With --watch, the analyzer keeps running after the first report and re-analyzes
+the repository whenever files change (after 2 seconds without further changes),
+rewriting the config and printing what changed. Re-analysis is incremental: the
+config is only regenerated when the dependencies, files, or other inputs it is
+based on changed. Press Ctrl-C to stop.
+
+Example:
+  platform-ai-example analyze ./my-service --watch`,
 		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateFormat(format)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			repoPath := args[0]
 
@@ -55,7 +76,10 @@ The analyzer will:
 				return fmt.Errorf("ANTHROPIC_API_KEY environment variable is required")
 			}
 
-			ctx := context.Background()
+			// Ctrl-C cancels in-flight analysis and stops the watcher
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
 			sdk, err := platformai.New(ctx, &platformai.Config{
 				LLM: platformai.LLMConfig{
 					Provider: "anthropic",
@@ -74,13 +98,13 @@ The analyzer will:
 			fmt.Println("🔍 Analyzing repository...")
 
 			// Perform analysis
-			mapper := sdk.CodeMapping()
-			result, err := mapper.Analyze(ctx, codemapping.AnalyzeRequest{
+			req := codemapping.AnalyzeRequest{
 				RepoPath: repoPath,
 				Options: codemapping.AnalyzeOptions{
 					Verbose: verbose,
 				},
-			})
+			}
+			result, err := sdk.Analyze(ctx, req)
 			if err != nil {
 				return fmt.Errorf("analysis failed: %w", err)
 			}
@@ -90,7 +114,7 @@ The analyzer will:
 
 			// Write config file
 			if outputPath == "" {
-				outputPath = filepath.Join(repoPath, ".platform", "config.yaml")
+				outputPath = filepath.Join(repoPath, ".platform", "config."+format)
 			}
 
 			if err := writeConfigFile(result.Config, outputPath, format); err != nil {
@@ -100,13 +124,50 @@ The analyzer will:
 			fmt.Printf("\n📝 Generated configuration: %s\n\n", outputPath)
 			fmt.Printf("View config: cat %s\n", outputPath)
 
-			return nil
+			if !watch {
+				return nil
+			}
+
+			fmt.Printf("\n👀 Watching %s for changes (Ctrl-C to stop)...\n", repoPath)
+			previous := result
+			err = watchRepository(ctx, repoPath, outputPath, func() {
+				fmt.Println("\n🔍 Change detected, re-analyzing...")
+				// Incremental: the config is only regenerated when its inputs changed
+				req.Options.Baseline = previous
+				result, err := sdk.Analyze(ctx, req)
+				if err != nil {
+					if ctx.Err() == nil {
+						fmt.Fprintf(os.Stderr, "❌ Analysis failed: %v\n", err)
+					}
+					return
+				}
+				previous = result
+				if result.ConfigReused {
+					fmt.Println("  No configuration inputs changed")
+					return
+				}
+				if err := writeConfigFile(result.Config, outputPath, format); err != nil {
+					fmt.Fprintf(os.Stderr, "❌ Failed to write config: %v\n", err)
+					return
+				}
+
+				changes := codemapping.DiffConfigs(req.Options.Baseline.Config, result.Config)
+				if len(changes) == 0 {
+					fmt.Println("  No configuration changes")
+				}
+				for _, change := range changes {
+					fmt.Printf("  %s\n", change)
+				}
+			})
+			fmt.Println("\n👋 Stopped watching")
+			return err
 		},
 	}
 
-	analyzeCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output path for config file (default: .platform/config.yaml)")
+	analyzeCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output path for config file (default: .platform/config.<format>)")
 	analyzeCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
-	analyzeCmd.Flags().StringVarP(&format, "format", "f", "yaml", "Output format (yaml)")
+	analyzeCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Re-analyze the repository when files change")
+	analyzeCmd.Flags().StringVarP(&format, "format", "f", "yaml", "Output format ("+strings.Join(outputFormats, ", ")+")")
 
 	rootCmd.AddCommand(analyzeCmd)
 
@@ -218,7 +279,7 @@ func writeConfigFile(config *codemapping.PlatformConfig, outputPath, format stri
 		return err
 	}
 
-	// Add header comment
+	// Header comment, written for YAML only
 	header := `# Platform Configuration
 # Auto-generated by Platform AI SDK
 # Generated: ` + time.Now().Format(time.RFC3339) + `
@@ -235,9 +296,28 @@ func writeConfigFile(config *codemapping.PlatformConfig, outputPath, format stri
 			return err
 		}
 		data = append([]byte(header), data...)
+	case "json":
+		data, err = json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	case "toml":
+		data, err = toml.Marshal(config)
+		if err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported format: %s", format)
 	}
 
 	return os.WriteFile(outputPath, data, 0600)
 }
+
+// validateFormat rejects output formats writeConfigFile cannot produce
+func validateFormat(format string) error {
+	if !slices.Contains(outputFormats, format) {
+		return fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(outputFormats, ", "))
+	}
+	return nil
+}
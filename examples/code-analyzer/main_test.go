@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/codemapping"
+)
+
+func testConfig() *codemapping.PlatformConfig {
+	return &codemapping.PlatformConfig{
+		Service: codemapping.ServiceConfig{Name: "orders", Template: "microservice", Runtime: "go1.22", Port: 8080,
+			Secrets: []codemapping.SecretRef{{Name: "orders-db", Key: "url", EnvVar: "DATABASE_URL"}},
+		},
+		Resources: codemapping.ResourceConfig{
+			CPU:     "500m",
+			Memory:  "512Mi",
+			Scaling: codemapping.ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPUPercent: 70},
+		},
+		Database:   &codemapping.DatabaseConfig{Type: "postgresql", Version: "16", Storage: "10Gi"},
+		Monitoring: codemapping.MonitoringConfig{Metrics: true, Logs: true},
+		Security:   codemapping.SecurityConfig{HealthCheck: codemapping.HealthCheckConfig{Path: "/health", Port: 8080}},
+	}
+}
+
+func TestWriteConfigFile(t *testing.T) {
+	tests := []struct {
+		format    string
+		unmarshal func([]byte, any) error
+		header    bool
+	}{
+		{"yaml", yaml.Unmarshal, true},
+		{"json", json.Unmarshal, false},
+		{"toml", toml.Unmarshal, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			want := testConfig()
+			path := filepath.Join(t.TempDir(), "config."+tt.format)
+			if err := writeConfigFile(want, path, tt.format); err != nil {
+				t.Fatalf("writeConfigFile() error = %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bytes.HasPrefix(data, []byte("# Platform Configuration")); got != tt.header {
+				t.Errorf("header written = %v, want %v", got, tt.header)
+			}
+
+			var got codemapping.PlatformConfig
+			if err := tt.unmarshal(data, &got); err != nil {
+				t.Fatalf("unmarshal error = %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(&got, want) {
+				t.Errorf("round trip = %+v, want %+v", got, *want)
+			}
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range outputFormats {
+		if err := validateFormat(format); err != nil {
+			t.Errorf("validateFormat(%q) error = %v", format, err)
+		}
+	}
+	if err := validateFormat("xml"); err == nil {
+		t.Error("validateFormat(\"xml\") succeeded, want error")
+	}
+}
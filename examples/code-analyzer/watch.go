@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long the repository must be quiet before it is re-analyzed
+const watchDebounce = 2 * time.Second
+
+// skippedWatchDirs are directories whose changes never trigger a re-analysis
+var skippedWatchDirs = map[string]bool{
+	".git":         true,
+	".platform":    true,
+	"node_modules": true,
+	"vendor":       true,
+	"__pycache__":  true,
+}
+
+// watchRepository calls onChange once files under repoPath changed and no
+// further change arrived for watchDebounce. Changes to the ignored file, the
+// generated config, are skipped. It returns nil when ctx is done, closing the
+// watcher.
+func watchRepository(ctx context.Context, repoPath, ignored string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	if err := addWatchDirs(watcher, repoPath); err != nil {
+		return err
+	}
+	ignored, _ = filepath.Abs(ignored)
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if path, _ := filepath.Abs(event.Name); path == ignored || skippedWatchDirs[filepath.Base(event.Name)] {
+				continue
+			}
+			// fsnotify is not recursive, so new directories are watched explicitly
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchDirs(watcher, event.Name); err != nil {
+						fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+					}
+				}
+			}
+			debounce.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "⚠️  watch error: %v\n", err)
+		case <-debounce.C:
+			onChange()
+		}
+	}
+}
+
+// addWatchDirs watches root and every directory below it, except skipped ones
+func addWatchDirs(watcher *fsnotify.Watcher, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && skippedWatchDirs[d.Name()] {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchRepository(t *testing.T) {
+	repo := t.TempDir()
+	config := filepath.Join(repo, "config.yaml")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- watchRepository(ctx, repo, config, func() { changes <- struct{}{} })
+	}()
+	time.Sleep(100 * time.Millisecond) // Let the watcher start
+
+	// A burst of edits is debounced into one re-analysis
+	for range 3 {
+		if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	select {
+	case <-changes:
+	case <-time.After(watchDebounce + 2*time.Second):
+		t.Fatal("onChange was not called")
+	}
+
+	// Writing the generated config must not trigger another re-analysis
+	if err := os.WriteFile(config, []byte("service: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+		t.Error("onChange called again after the config was written")
+	case <-time.After(watchDebounce + 500*time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("watchRepository() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("watchRepository() did not return after cancellation")
+	}
+}
@@ -5,3 +5,11 @@ go 1.25.1
 replace github.com/philipsahli/innominatus-ai-sdk => ../..
 
 require github.com/philipsahli/innominatus-ai-sdk v0.0.0-00010101000000-000000000000
+
+require (
+	go.opentelemetry.io/otel v1.35.0 // indirect
+	go.opentelemetry.io/otel/trace v1.35.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,155 @@
+package platformai
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Operations recorded by the SDK
+const (
+	AnalyticsGenerate = "generate"
+	AnalyticsRetrieve = "retrieve"
+	AnalyticsAnalyze  = "analyze"
+)
+
+// AnalyticsEvent is one recorded API call
+type AnalyticsEvent struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Operation    string    `json:"operation"` // e.g. "generate", "retrieve", "analyze"
+	Model        string    `json:"model,omitempty"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	DurationMs   int64     `json:"duration_ms"`
+	Error        bool      `json:"error"`
+}
+
+// AnalyticsTotals aggregates a group of events
+type AnalyticsTotals struct {
+	Calls        int
+	Errors       int
+	InputTokens  int
+	OutputTokens int
+	DurationMs   int64
+}
+
+// AnalyticsSummary aggregates the events recorded since a point in time
+type AnalyticsSummary struct {
+	Since       time.Time
+	Total       AnalyticsTotals
+	ByOperation map[string]AnalyticsTotals
+	ByModel     map[string]AnalyticsTotals
+}
+
+// maxAnalyticsEvents bounds the events kept in memory for Summary
+const maxAnalyticsEvents = 10000
+
+// Analytics records SDK calls for usage reporting. Every event is written as a
+// JSON line to the configured writer; the most recent maxAnalyticsEvents are
+// also kept in memory for Summary.
+type Analytics struct {
+	mu     sync.Mutex
+	events []AnalyticsEvent // Ring buffer once it holds maxAnalyticsEvents
+	next   int              // Index of the oldest event once events is full
+	enc    *json.Encoder
+}
+
+// NewAnalytics creates analytics that persist events to w; nil discards them
+func NewAnalytics(w io.Writer) *Analytics {
+	if w == nil {
+		w = io.Discard
+	}
+	return &Analytics{enc: json.NewEncoder(w)}
+}
+
+// Record stores an event. A zero Timestamp is set to the current time.
+func (a *Analytics) Record(event AnalyticsEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.events) < maxAnalyticsEvents {
+		a.events = append(a.events, event)
+	} else {
+		a.events[a.next] = event
+		a.next = (a.next + 1) % maxAnalyticsEvents
+	}
+	// Persistence is best effort; a failing writer must not fail API calls
+	_ = a.enc.Encode(event)
+}
+
+// Summary aggregates the retained events recorded at or after since
+func (a *Analytics) Summary(since time.Time) AnalyticsSummary {
+	summary := AnalyticsSummary{
+		Since:       since,
+		ByOperation: make(map[string]AnalyticsTotals),
+		ByModel:     make(map[string]AnalyticsTotals),
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, event := range a.events {
+		if event.Timestamp.Before(since) {
+			continue
+		}
+		summary.Total = summary.Total.add(event)
+		summary.ByOperation[event.Operation] = summary.ByOperation[event.Operation].add(event)
+		summary.ByModel[event.Model] = summary.ByModel[event.Model].add(event)
+	}
+	return summary
+}
+
+// add returns the totals with event included
+func (t AnalyticsTotals) add(event AnalyticsEvent) AnalyticsTotals {
+	t.Calls++
+	if event.Error {
+		t.Errors++
+	}
+	t.InputTokens += event.InputTokens
+	t.OutputTokens += event.OutputTokens
+	t.DurationMs += event.DurationMs
+	return t
+}
+
+// WithAnalyticsWriter persists analytics events to w as JSON lines
+func WithAnalyticsWriter(w io.Writer) Option {
+	return func(s *SDK) error {
+		s.analytics = NewAnalytics(w)
+		return nil
+	}
+}
+
+// Analytics returns the SDK's usage analytics
+func (s *SDK) Analytics() *Analytics {
+	return s.analytics
+}
+
+// analyticsOperation maps a tracked operation such as "llm.Generate" to its analytics operation
+func analyticsOperation(operation string) string {
+	switch {
+	case strings.HasPrefix(operation, "llm."):
+		return AnalyticsGenerate
+	case strings.HasPrefix(operation, "rag."):
+		return AnalyticsRetrieve
+	case strings.HasPrefix(operation, "codemapping."):
+		return AnalyticsAnalyze
+	default:
+		return operation
+	}
+}
+
+// model returns the configured LLM model, if known
+func (s *SDK) model() string {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	if s.config == nil {
+		return ""
+	}
+	return s.config.LLM.Model
+}
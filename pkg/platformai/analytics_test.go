@@ -0,0 +1,130 @@
+package platformai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/codemapping"
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+func TestAnalytics_Summary(t *testing.T) {
+	var out bytes.Buffer
+	analytics := NewAnalytics(&out)
+	base := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	// An event before the reporting window
+	analytics.Record(AnalyticsEvent{Timestamp: base.Add(-time.Hour), Operation: AnalyticsGenerate, Model: "sonnet", InputTokens: 1000})
+
+	for i := 0; i < 10; i++ {
+		event := AnalyticsEvent{
+			Timestamp:    base.Add(time.Duration(i) * time.Minute),
+			Operation:    AnalyticsGenerate,
+			Model:        "sonnet",
+			InputTokens:  100,
+			OutputTokens: 20,
+			DurationMs:   50,
+		}
+		if i%2 == 1 {
+			event.Operation = AnalyticsRetrieve
+			event.Model = "voyage-3"
+			event.OutputTokens = 0
+			event.Error = i == 9
+		}
+		analytics.Record(event)
+	}
+
+	summary := analytics.Summary(base)
+	want := AnalyticsTotals{Calls: 10, Errors: 1, InputTokens: 1000, OutputTokens: 100, DurationMs: 500}
+	if summary.Total != want {
+		t.Errorf("Summary().Total = %+v, want %+v", summary.Total, want)
+	}
+	if got := summary.ByOperation[AnalyticsGenerate]; got != (AnalyticsTotals{Calls: 5, InputTokens: 500, OutputTokens: 100, DurationMs: 250}) {
+		t.Errorf("Summary().ByOperation[generate] = %+v", got)
+	}
+	if got := summary.ByOperation[AnalyticsRetrieve]; got != (AnalyticsTotals{Calls: 5, Errors: 1, InputTokens: 500, DurationMs: 250}) {
+		t.Errorf("Summary().ByOperation[retrieve] = %+v", got)
+	}
+	if len(summary.ByModel) != 2 || summary.ByModel["voyage-3"].Calls != 5 {
+		t.Errorf("Summary().ByModel = %+v, want 5 calls each for two models", summary.ByModel)
+	}
+
+	var lines int
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var event AnalyticsEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("persisted line %q is not JSON: %v", scanner.Text(), err)
+		}
+		lines++
+	}
+	if lines != 11 {
+		t.Errorf("persisted %d events, want 11", lines)
+	}
+}
+
+func TestSDK_AnalyticsRecordsCalls(t *testing.T) {
+	sdk := &SDK{config: &Config{LLM: LLMConfig{Model: "sonnet"}}, analytics: NewAnalytics(nil)}
+	mock := llm.NewMockClient("ok")
+	mock.GenerateFunc = func(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+		return &llm.GenerateResponse{Text: "ok", Usage: llm.Usage{PromptTokens: 12, CompletionTokens: 3}}, nil
+	}
+	sdk.setLLMClient(mock)
+
+	for i := 0; i < 2; i++ {
+		if _, err := sdk.LLM().Generate(context.Background(), llm.GenerateRequest{UserPrompt: "hi"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := sdk.Analytics().Summary(time.Time{}).ByModel["sonnet"]
+	if got.Calls != 2 || got.InputTokens != 24 || got.OutputTokens != 6 {
+		t.Errorf("Summary().ByModel[sonnet] = %+v, want 2 calls with 24 input and 6 output tokens", got)
+	}
+}
+
+func TestAnalytics_RecordCapped(t *testing.T) {
+	analytics := NewAnalytics(nil)
+	base := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < maxAnalyticsEvents+10; i++ {
+		analytics.Record(AnalyticsEvent{Timestamp: base.Add(time.Duration(i) * time.Second), Operation: AnalyticsGenerate})
+	}
+
+	if n := len(analytics.events); n != maxAnalyticsEvents {
+		t.Errorf("retained %d events, want %d", n, maxAnalyticsEvents)
+	}
+	// The oldest ten events were dropped
+	if got := analytics.Summary(time.Time{}).Total.Calls; got != maxAnalyticsEvents {
+		t.Errorf("Summary().Total.Calls = %d, want %d", got, maxAnalyticsEvents)
+	}
+	if got := analytics.Summary(base.Add(10 * time.Second)).Total.Calls; got != maxAnalyticsEvents {
+		t.Errorf("Summary(base+10s).Total.Calls = %d, want %d", got, maxAnalyticsEvents)
+	}
+}
+
+func TestSDK_AnalyticsRecordsAnalyze(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module example.com/app\n\ngo 1.22\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sdk := &SDK{config: &Config{LLM: LLMConfig{Model: "sonnet"}}, analytics: NewAnalytics(nil)}
+	sdk.setLLMClient(llm.NewMockClient(`{"name": "app", "language": "go"}`))
+
+	if _, err := sdk.Analyze(context.Background(), codemapping.AnalyzeRequest{RepoPath: repo}); err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	summary := sdk.Analytics().Summary(time.Time{})
+	if got := summary.ByOperation[AnalyticsAnalyze].Calls; got != 1 {
+		t.Errorf("Summary().ByOperation[analyze].Calls = %d, want 1", got)
+	}
+	if got := summary.ByOperation[AnalyticsGenerate].Calls; got == 0 {
+		t.Error("Summary().ByOperation[generate] has no calls, want the config generation call")
+	}
+}
@@ -0,0 +1,99 @@
+package codemapping
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestAnalyzer_AnalyzeElixir(t *testing.T) {
+	analysis, err := NewAnalyzer().Analyze(context.Background(), "testdata/sample-elixir-repo")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if analysis.LanguageVersion != "~> 1.15" {
+		t.Errorf("LanguageVersion = %q, want ~> 1.15", analysis.LanguageVersion)
+	}
+	wantDeps := map[string]string{
+		"phoenix":     "~> 1.7",
+		"postgrex":    ">= 0.0.0",
+		"plug_cowboy": "~> 2.5",
+		"credo":       "~> 1.7",
+	}
+	for name, version := range wantDeps {
+		if got := analysis.Dependencies[name]; got != version {
+			t.Errorf("Dependencies[%s] = %q, want %q", name, got, version)
+		}
+	}
+
+	detector := NewDetector()
+	if got := detector.DetectLanguage(analysis); got != "elixir" {
+		t.Errorf("DetectLanguage() = %q, want elixir", got)
+	}
+	if got := detector.DetectFramework(analysis); got != "phoenix" {
+		t.Errorf("DetectFramework() = %q, want phoenix", got)
+	}
+}
+
+func TestDetector_DetectFrameworkElixir(t *testing.T) {
+	tests := []struct {
+		deps map[string]string
+		want string
+	}{
+		{map[string]string{"phoenix": "~> 1.7", "absinthe": "~> 1.7", "plug": "~> 1.14"}, "phoenix"},
+		{map[string]string{"absinthe": "~> 1.7", "plug_cowboy": "~> 2.5"}, "absinthe"},
+		{map[string]string{"plug_cowboy": "~> 2.5"}, "plug"},
+		{map[string]string{"jason": "~> 1.2"}, "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			analysis := &RepositoryAnalysis{Files: []string{"mix.exs"}, Dependencies: tt.deps}
+			if got := NewDetector().DetectFramework(analysis); got != tt.want {
+				t.Errorf("DetectFramework() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_AnalyzeGoWorkspace(t *testing.T) {
+	analysis, err := NewAnalyzer().Analyze(context.Background(), "testdata/sample-go-workspace")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if !analysis.HasGoWorkspace {
+		t.Error("HasGoWorkspace = false, want true")
+	}
+	if want := []string{"./api", "./worker"}; !slices.Equal(analysis.WorkspaceModules, want) {
+		t.Errorf("WorkspaceModules = %v, want %v", analysis.WorkspaceModules, want)
+	}
+	for _, dep := range []string{"github.com/gin-gonic/gin", "github.com/lib/pq", "github.com/redis/go-redis/v9"} {
+		if _, ok := analysis.Dependencies[dep]; !ok {
+			t.Errorf("Dependencies missing %s from a workspace module", dep)
+		}
+	}
+}
+
+func TestAnalyzer_AnalyzeEnvFile(t *testing.T) {
+	analysis, err := NewAnalyzer().Analyze(context.Background(), "testdata/sample-env-repo")
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := map[string]string{
+		"PORT":              "3000",
+		"LOG_LEVEL":         "info",
+		"DATABASE_URL":      "postgres://db:5432/orders",
+		"REDIS_URL":         "redis://cache:6379/0",
+		"STRIPE_SECRET_KEY": maskedEnvValue,
+		"JWT_SECRET":        maskedEnvValue,
+		"SMTP_PASSWORD":     maskedEnvValue,
+		"GITHUB_TOKEN":      maskedEnvValue,
+	}
+	if !maps.Equal(analysis.EnvVars, want) {
+		t.Errorf("EnvVars = %v, want %v", analysis.EnvVars, want)
+	}
+}
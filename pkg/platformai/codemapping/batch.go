@@ -0,0 +1,111 @@
+package codemapping
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BatchAnalyzeOptions controls BatchAnalyze
+type BatchAnalyzeOptions struct {
+	Concurrency      int  // Repositories analyzed at once; <= 0 analyzes one at a time
+	StopOnFirstError bool // Skip requests not yet started once one fails
+}
+
+// BatchAnalyzeEvent is an event emitted by BatchAnalyze: a *ProgressEvent,
+// *ResultEvent, or *ErrorEvent
+type BatchAnalyzeEvent interface {
+	batchAnalyzeEvent()
+}
+
+// ProgressEvent reports how many requests have finished. It follows every
+// ResultEvent and ErrorEvent.
+type ProgressEvent struct {
+	Done  int
+	Total int
+}
+
+// ResultEvent carries the result of a successful analysis
+type ResultEvent struct {
+	Request AnalyzeRequest
+	Result  *AnalyzeResult
+}
+
+// ErrorEvent reports a failed analysis
+type ErrorEvent struct {
+	Request AnalyzeRequest
+	Err     error
+}
+
+func (*ProgressEvent) batchAnalyzeEvent() {}
+func (*ResultEvent) batchAnalyzeEvent()   {}
+func (*ErrorEvent) batchAnalyzeEvent()    {}
+
+// BatchAnalyze analyzes several repositories concurrently. Each finished request
+// produces a ResultEvent or ErrorEvent followed by a ProgressEvent; the channel
+// is closed once every request has finished or been skipped.
+func (m *Module) BatchAnalyze(ctx context.Context, requests []AnalyzeRequest, opts BatchAnalyzeOptions) (<-chan BatchAnalyzeEvent, error) {
+	for i, req := range requests {
+		if req.RepoPath == "" {
+			return nil, fmt.Errorf("request %d: repository path is required", i)
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	// Two events per request, so workers never block on a slow reader
+	events := make(chan BatchAnalyzeEvent, 2*len(requests))
+	jobs := make(chan AnalyzeRequest)
+	ctx, cancel := context.WithCancel(ctx)
+
+	var mu sync.Mutex // Keeps each result and its progress event adjacent
+	done := 0
+	emit := func(event BatchAnalyzeEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		done++
+		events <- event
+		events <- &ProgressEvent{Done: done, Total: len(requests)}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range jobs {
+				result, err := m.Analyze(ctx, req)
+				if err != nil {
+					if opts.StopOnFirstError {
+						cancel()
+					}
+					emit(&ErrorEvent{Request: req, Err: err})
+					continue
+				}
+				emit(&ResultEvent{Request: req, Result: result})
+			}
+		}()
+	}
+
+	go func() {
+		defer cancel()
+		defer close(events)
+
+	feed:
+		for _, req := range requests {
+			select {
+			case jobs <- req:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(jobs)
+		wg.Wait()
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,102 @@
+package codemapping
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// batchRepos creates n minimal Go repositories
+func batchRepos(t *testing.T, n int) []AnalyzeRequest {
+	t.Helper()
+	requests := make([]AnalyzeRequest, n)
+	for i := range requests {
+		repo := t.TempDir()
+		if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module example.com/svc\n\ngo 1.21\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		requests[i] = AnalyzeRequest{RepoPath: repo}
+	}
+	return requests
+}
+
+func TestModule_BatchAnalyze(t *testing.T) {
+	module := NewModule(llm.NewMockClient(testConfigJSON))
+	requests := batchRepos(t, 5)
+	requests = append(requests, AnalyzeRequest{RepoPath: filepath.Join(t.TempDir(), "missing")})
+
+	events, err := module.BatchAnalyze(context.Background(), requests, BatchAnalyzeOptions{Concurrency: 3})
+	if err != nil {
+		t.Fatalf("BatchAnalyze() error = %v", err)
+	}
+
+	var results, failures, progress int
+	var pending bool // A result or error awaiting its progress event
+	for event := range events {
+		switch e := event.(type) {
+		case *ResultEvent:
+			if pending {
+				t.Fatal("ResultEvent received before the previous ProgressEvent")
+			}
+			if e.Result == nil || e.Result.Config == nil {
+				t.Errorf("ResultEvent for %s has no config", e.Request.RepoPath)
+			}
+			results++
+			pending = true
+		case *ErrorEvent:
+			if pending {
+				t.Fatal("ErrorEvent received before the previous ProgressEvent")
+			}
+			failures++
+			pending = true
+		case *ProgressEvent:
+			if !pending {
+				t.Fatal("ProgressEvent received without a preceding result")
+			}
+			progress++
+			if e.Done != progress || e.Total != len(requests) {
+				t.Errorf("ProgressEvent = %+v, want Done %d Total %d", e, progress, len(requests))
+			}
+			pending = false
+		}
+	}
+
+	if results != 5 || failures != 1 || progress != len(requests) {
+		t.Errorf("got %d results, %d errors, %d progress events; want 5, 1, %d", results, failures, progress, len(requests))
+	}
+}
+
+func TestModule_BatchAnalyzeStopOnFirstError(t *testing.T) {
+	mock := llm.NewMockClient("")
+	mock.GenerateFunc = func(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+		return nil, errors.New("LLM unavailable")
+	}
+	module := NewModule(mock)
+	requests := batchRepos(t, 10)
+
+	events, err := module.BatchAnalyze(context.Background(), requests, BatchAnalyzeOptions{Concurrency: 1, StopOnFirstError: true})
+	if err != nil {
+		t.Fatalf("BatchAnalyze() error = %v", err)
+	}
+
+	var failures int
+	for event := range events {
+		if _, ok := event.(*ErrorEvent); ok {
+			failures++
+		}
+	}
+	if failures == 0 || failures >= len(requests) {
+		t.Errorf("got %d errors, want analysis to stop early after the first", failures)
+	}
+}
+
+func TestModule_BatchAnalyzeInvalidRequest(t *testing.T) {
+	module := NewModule(llm.NewMockClient(testConfigJSON))
+	if _, err := module.BatchAnalyze(context.Background(), []AnalyzeRequest{{}}, BatchAnalyzeOptions{}); err == nil {
+		t.Error("BatchAnalyze() expected error for request without repository path")
+	}
+}
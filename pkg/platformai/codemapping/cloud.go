@@ -0,0 +1,67 @@
+package codemapping
+
+import "fmt"
+
+// Clouds accepted by GenerateCloudConfig
+const (
+	CloudAWS   = "aws"   // Amazon EKS
+	CloudGCP   = "gcp"   // Google GKE
+	CloudAzure = "azure" // Azure AKS
+)
+
+// Placeholders for the account-specific parts of generated identity annotations
+const (
+	AWSAccountPlaceholder  = "ACCOUNT_ID"
+	GCPProjectPlaceholder  = "PROJECT_ID"
+	AzureClientPlaceholder = "CLIENT_ID"
+)
+
+// GenerateCloudConfig returns a copy of cfg for one managed Kubernetes offering.
+// It annotates the service account for the cloud's workload identity (an IAM role
+// ARN on EKS, a Google service account on GKE, a managed identity client ID on
+// AKS) and pins pods to the node pool recommended for application workloads.
+// Account-specific values are left as placeholders.
+func GenerateCloudConfig(cfg *PlatformConfig, cloud string) (*PlatformConfig, error) {
+	name := cfg.Service.Name
+	if name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+
+	var annotations, nodeSelector map[string]string
+	switch cloud {
+	case CloudAWS:
+		annotations = map[string]string{
+			"eks.amazonaws.com/role-arn": fmt.Sprintf("arn:aws:iam::%s:role/%s", AWSAccountPlaceholder, name),
+		}
+		nodeSelector = map[string]string{"eks.amazonaws.com/capacityType": "ON_DEMAND"}
+	case CloudGCP:
+		annotations = map[string]string{
+			"iam.gke.io/gcp-service-account": fmt.Sprintf("%s@%s.iam.gserviceaccount.com", name, GCPProjectPlaceholder),
+		}
+		// Workload identity requires nodes running the GKE metadata server
+		nodeSelector = map[string]string{"iam.gke.io/gke-metadata-server-enabled": "true"}
+	case CloudAzure:
+		annotations = map[string]string{
+			"azure.workload.identity/client-id": AzureClientPlaceholder,
+		}
+		nodeSelector = map[string]string{"kubernetes.azure.com/mode": "user"}
+	default:
+		return nil, fmt.Errorf("unsupported cloud %q (supported: %s, %s, %s)", cloud, CloudAWS, CloudGCP, CloudAzure)
+	}
+
+	out := cloneConfig(*cfg)
+	out.Cloud = cloud
+	if out.Annotations == nil {
+		out.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		out.Annotations[k] = v
+	}
+	if out.NodeSelector == nil {
+		out.NodeSelector = make(map[string]string, len(nodeSelector))
+	}
+	for k, v := range nodeSelector {
+		out.NodeSelector[k] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,47 @@
+package codemapping
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestGenerateCloudConfig(t *testing.T) {
+	base := validConfig()
+	base.Service.Name = "orders"
+
+	tests := []struct {
+		cloud      string
+		annotation string
+		pattern    string
+		selector   string
+	}{
+		{CloudAWS, "eks.amazonaws.com/role-arn", `^arn:aws:iam::[A-Z_0-9]+:role/orders$`, "eks.amazonaws.com/capacityType"},
+		{CloudGCP, "iam.gke.io/gcp-service-account", `^orders@[A-Z_a-z0-9-]+\.iam\.gserviceaccount\.com$`, "iam.gke.io/gke-metadata-server-enabled"},
+		{CloudAzure, "azure.workload.identity/client-id", `.+`, "kubernetes.azure.com/mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cloud, func(t *testing.T) {
+			cfg, err := GenerateCloudConfig(base, tt.cloud)
+			if err != nil {
+				t.Fatalf("GenerateCloudConfig() error = %v", err)
+			}
+			if cfg.Cloud != tt.cloud {
+				t.Errorf("Cloud = %q, want %q", cfg.Cloud, tt.cloud)
+			}
+			if got := cfg.Annotations[tt.annotation]; !regexp.MustCompile(tt.pattern).MatchString(got) {
+				t.Errorf("annotation %s = %q, want match for %s", tt.annotation, got, tt.pattern)
+			}
+			if _, ok := cfg.NodeSelector[tt.selector]; !ok {
+				t.Errorf("NodeSelector = %v, want key %s", cfg.NodeSelector, tt.selector)
+			}
+		})
+	}
+
+	if base.Annotations != nil || base.NodeSelector != nil || base.Cloud != "" {
+		t.Error("GenerateCloudConfig() modified the base config")
+	}
+	if _, err := GenerateCloudConfig(base, "openstack"); err == nil {
+		t.Error("GenerateCloudConfig() expected error for unsupported cloud")
+	}
+}
@@ -0,0 +1,51 @@
+package codemapping
+
+import "github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+
+// configExamples show ConfigGenerator's model the expected output for two
+// typical repositories: a Go API with a database and a Node.js web app without one
+var configExamples = []llm.FewShotExample{
+	{
+		UserMessage: `Analyze this repository and generate platform configuration:
+
+Repository Analysis:
+- Primary Language: Go
+- Framework: gin
+- Language Version: 1.22
+- Has Dockerfile: true
+- Total Files: 42
+- Total Dependencies: 3
+
+Key Dependencies:
+  - github.com/gin-gonic/gin: v1.9.1
+  - github.com/lib/pq: v1.10.9
+  - github.com/spf13/viper: v1.18.2
+
+Sample Files:
+main.go
+internal/api/handlers.go
+Dockerfile`,
+		AssistantMessage: `{"service":{"name":"orders-api","template":"api","runtime":"go1.22","framework":"gin","port":8080,"runtime_type":"container","secrets":[{"name":"orders-api-db","key":"url","env_var":"DATABASE_URL"}]},"resources":{"cpu":"250m","memory":"256Mi","scaling":{"min_replicas":2,"max_replicas":8,"target_cpu_percent":70}},"database":{"type":"postgresql","version":"16","storage":"10Gi"},"cache":null,"monitoring":{"metrics":true,"logs":true,"traces":true},"security":{"health_check":{"path":"/health","port":8080}}}`,
+	},
+	{
+		UserMessage: `Analyze this repository and generate platform configuration:
+
+Repository Analysis:
+- Primary Language: JavaScript
+- Framework: express
+- Language Version: 20
+- Has Dockerfile: false
+- Total Files: 18
+- Total Dependencies: 2
+
+Key Dependencies:
+  - express: ^4.18.2
+  - redis: ^4.6.10
+
+Sample Files:
+package.json
+src/index.js
+src/routes.js`,
+		AssistantMessage: `{"service":{"name":"storefront","template":"web-app","runtime":"node20","framework":"express","port":3000,"runtime_type":"container","secrets":[{"name":"storefront-cache","key":"url","env_var":"REDIS_URL"}]},"resources":{"cpu":"500m","memory":"512Mi","scaling":{"min_replicas":2,"max_replicas":10,"target_cpu_percent":70}},"database":null,"cache":{"type":"redis","version":"7","memory":"256Mi"},"monitoring":{"metrics":true,"logs":true,"traces":true},"security":{"health_check":{"path":"/health","port":3000}}}`,
+	},
+}
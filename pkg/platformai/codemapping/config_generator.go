@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
@@ -11,15 +13,18 @@ import (
 
 // ConfigGenerator generates platform configuration using LLM
 type ConfigGenerator struct {
-	llm llm.Client
+	llm       llm.Client
+	templates *TemplateLibrary
 }
 
-// NewConfigGenerator creates a new config generator
+// NewConfigGenerator creates a new config generator using the built-in templates
 func NewConfigGenerator(llmClient llm.Client) *ConfigGenerator {
-	return &ConfigGenerator{llm: llmClient}
+	return &ConfigGenerator{llm: llmClient, templates: NewTemplateLibrary()}
 }
 
-// Generate creates platform configuration based on repository analysis
+// Generate creates platform configuration based on repository analysis. When a
+// template matches the repository's language, the LLM is asked to adjust it
+// rather than start from scratch, and fields it leaves out keep the template values.
 func (g *ConfigGenerator) Generate(ctx context.Context, analysis *RepositoryAnalysis) (*PlatformConfig, error) {
 	systemPrompt := `You are a Platform Engineering expert who generates optimal platform configurations.
 
@@ -28,8 +33,12 @@ Your task: Analyze repository information and generate a complete platform confi
 Guidelines:
 - Choose appropriate resource allocations based on tech stack
 - Set realistic scaling parameters
+- For Elixir/OTP services, prefer fewer, larger replicas: the BEAM VM handles concurrency inside one node
 - Configure monitoring and health checks
+- Restrict network traffic: deny by default, allow ingress on the service port, and egress only to required dependencies
 - Add necessary dependencies (database, cache, etc.)
+- Consider serverless runtimes for stateless, request-driven services
+- Reference credentials as secrets, never as literal values
 - Follow platform best practices
 
 Output: Valid JSON matching the PlatformConfig schema.`
@@ -66,6 +75,9 @@ Key Dependencies:
 Sample Files:
 %s
 
+Environment Variables (names from .env files; use them to infer configured databases, caches, and external APIs):
+%s
+
 Generate a complete platform configuration as JSON with these fields:
 {
   "service": {
@@ -73,7 +85,11 @@ Generate a complete platform configuration as JSON with these fields:
     "template": "string (e.g., 'microservice', 'web-app', 'api')",
     "runtime": "string (e.g., 'go1.21', 'node20', 'python3.11')",
     "framework": "string (detected framework)",
-    "port": 8080
+    "port": 8080,
+    "runtime_type": "string ('container', 'lambda', 'cloud-function', or 'cloud-run')",
+    "secrets": [
+      {"name": "string (secret name, e.g. 'orders-db')", "key": "string (key in the secret, e.g. 'url')", "env_var": "string (e.g. 'DATABASE_URL')"}
+    ]
   },
   "resources": {
     "cpu": "string (e.g., '500m', '1000m')",
@@ -103,15 +119,22 @@ Generate a complete platform configuration as JSON with these fields:
     "health_check": {
       "path": "/health",
       "port": 8080
+    },
+    "network_policy": {
+      "allowed_ingress_ports": [8080],
+      "allowed_egress_hosts": ["string (IP, CIDR, or hostname of a required dependency)"]
     }
   }
 }
 
 Rules:
 1. If no database/cache dependencies detected, set those fields to null
-2. Use appropriate resource sizes based on language (Go: smaller, Node/Python: larger)
-3. Set port based on framework defaults
-4. Ensure JSON is valid and properly formatted
+2. Use appropriate resource sizes based on language (Go: smaller, Node/Python: larger, Elixir: at least 512Mi, since the BEAM VM reserves memory up front, and whole CPU cores, since it runs one scheduler per core)
+3. Set port based on framework defaults (e.g. Phoenix: 4000)
+4. Add a secrets entry for every credential the service needs: database URLs for database drivers, API keys for API clients and cloud SDKs, tokens for message brokers; use an empty list if none
+5. Would this service benefit from serverless? Stateless, request-driven services with bursty or low traffic and no long-lived connections, background workers, or large in-memory state do; set runtime_type to 'lambda' (AWS), 'cloud-function' (GCP, single handler), or 'cloud-run' (GCP, full HTTP server) if so, otherwise 'container'
+6. List only the egress hosts the service needs (databases, caches, external APIs); prefer CIDRs, since Kubernetes network policies match IP ranges
+7. Ensure JSON is valid and properly formatted
 
 Respond with ONLY valid JSON, no markdown or explanation.`,
 		analysis.PrimaryLanguage,
@@ -122,23 +145,95 @@ Respond with ONLY valid JSON, no markdown or explanation.`,
 		len(analysis.Dependencies),
 		strings.Join(depSummary, "\n"),
 		strings.Join(fileList, "\n"),
+		envVarSummary(analysis.EnvVars),
 	)
 
-	response, err := g.llm.Generate(ctx, llm.GenerateRequest{
-		SystemPrompt: systemPrompt,
-		UserPrompt:   userPrompt,
-		Temperature:  0.3,
-		MaxTokens:    4096,
-	})
+	if analysis.HasGoWorkspace {
+		userPrompt += fmt.Sprintf(`
+
+This is a Go workspace with %d modules: %s
+Size resources for the module that serves traffic.`,
+			len(analysis.WorkspaceModules), strings.Join(analysis.WorkspaceModules, ", "))
+	}
+
+	templateName, config := g.templates.Match(analysis)
+	if config != nil {
+		baseline, err := json.Marshal(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal template: %w", err)
+		}
+		userPrompt += fmt.Sprintf(`
+
+Baseline Template (%s):
+%s
+
+Start from the baseline template and keep its values unless this repository clearly needs different ones.
+Adjust only repository-specific values: service name, runtime version, framework, port, database, and cache.`,
+			templateName, baseline)
+	} else {
+		config = &PlatformConfig{}
+	}
+
+	req, err := llm.NewGenerateRequestBuilder().
+		System(systemPrompt).
+		User(userPrompt).
+		Tokens(4096).
+		Examples(configExamples...).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	req = req.Deterministic() // Same analysis, same config
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	response, err := g.llm.Generate(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM generation failed: %w", err)
 	}
+	// Clients that ignore ctx may still answer after cancellation
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	// Parse JSON response
-	var config PlatformConfig
-	if err := json.Unmarshal([]byte(response.Text), &config); err != nil {
+	// Parse JSON response over the template
+	if err := json.Unmarshal([]byte(response.Text), config); err != nil {
 		return nil, fmt.Errorf("failed to parse LLM response as JSON: %w (response: %s)", err, response.Text)
 	}
+	if errs := ValidatePlatformConfig(config); len(errs) > 0 {
+		details := make([]string, len(errs))
+		for i, e := range errs {
+			details[i] = e.Error()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrInvalidResponse, strings.Join(details, "; "))
+	}
+	if analysis.HasGoWorkspace {
+		config.Service.Template = workspaceTemplate(config.Service.Template, analysis.WorkspaceModules)
+	}
 
-	return &config, nil
+	return config, nil
+}
+
+// workspaceTemplate notes the modules of a Go workspace in a service template
+// name, e.g. "go-service (go workspace: ./api, ./worker)"
+func workspaceTemplate(template string, modules []string) string {
+	note := "go workspace: " + strings.Join(modules, ", ")
+	if template == "" {
+		return note
+	}
+	return template + " (" + note + ")"
+}
+
+// envVarSummary lists environment variable names, sorted, one per line. Values
+// are left out so secrets never reach the prompt.
+func envVarSummary(vars map[string]string) string {
+	if len(vars) == 0 {
+		return "  (none)"
+	}
+	names := slices.Sorted(maps.Keys(vars))
+	for i, name := range names {
+		names[i] = "  - " + name
+	}
+	return strings.Join(names, "\n")
 }
@@ -0,0 +1,110 @@
+package codemapping
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+const testConfigJSON = `{
+  "service": {"name": "sample-go", "template": "microservice", "runtime": "go1.21", "framework": "gin", "port": 8080},
+  "resources": {"cpu": "500m", "memory": "512Mi", "scaling": {"min_replicas": 2, "max_replicas": 10, "target_cpu_percent": 70}},
+  "database": null,
+  "cache": null,
+  "monitoring": {"metrics": true, "logs": true, "traces": true},
+  "security": {"health_check": {"path": "/health", "port": 8080}}
+}`
+
+func testAnalysis() *RepositoryAnalysis {
+	return &RepositoryAnalysis{
+		PrimaryLanguage:   "go",
+		DetectedFramework: "gin",
+		LanguageVersion:   "1.21",
+		Files:             []string{"go.mod", "main.go"},
+		Dependencies:      map[string]string{"github.com/gin-gonic/gin": "v1.9.1"},
+	}
+}
+
+func TestConfigGenerator_Generate(t *testing.T) {
+	mock := llm.NewMockClient(testConfigJSON)
+	generator := NewConfigGenerator(mock)
+
+	config, err := generator.Generate(context.Background(), testAnalysis())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := &PlatformConfig{
+		Service: ServiceConfig{Name: "sample-go", Template: "microservice", Runtime: "go1.21", Framework: "gin", Port: 8080},
+		Resources: ResourceConfig{
+			CPU:     "500m",
+			Memory:  "512Mi",
+			Scaling: ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPUPercent: 70},
+		},
+		Monitoring: MonitoringConfig{Metrics: true, Logs: true, Traces: true},
+		Security:   SecurityConfig{HealthCheck: HealthCheckConfig{Path: "/health", Port: 8080}},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("Generate() config = %+v, want %+v", config, want)
+	}
+
+	requests := mock.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("LLM requests = %d, want 1", len(requests))
+	}
+	if requests[0].UserPrompt == "" || requests[0].SystemPrompt == "" {
+		t.Error("Generate() should send both system and user prompts")
+	}
+	if got := len(requests[0].FewShotExamples); got != 2 {
+		t.Errorf("Generate() sent %d few-shot examples, want 2", got)
+	}
+	if seed := requests[0].Seed; seed == nil || *seed != 42 || requests[0].Temperature != 0 {
+		t.Errorf("Generate() seed = %v, temperature = %v; want a deterministic request", seed, requests[0].Temperature)
+	}
+}
+
+func TestConfigGenerator_GenerateInvalidJSON(t *testing.T) {
+	generator := NewConfigGenerator(llm.NewMockClient("not json"))
+
+	if _, err := generator.Generate(context.Background(), testAnalysis()); err == nil {
+		t.Error("Generate() expected error for invalid JSON response")
+	}
+}
+
+func TestConfigGenerator_GenerateGoWorkspace(t *testing.T) {
+	mock := llm.NewMockClient(testConfigJSON)
+	analysis := testAnalysis()
+	analysis.HasGoWorkspace = true
+	analysis.WorkspaceModules = []string{"./api", "./worker"}
+
+	config, err := NewConfigGenerator(mock).Generate(context.Background(), analysis)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if want := "microservice (go workspace: ./api, ./worker)"; config.Service.Template != want {
+		t.Errorf("Service.Template = %q, want %q", config.Service.Template, want)
+	}
+	if prompt := mock.Requests()[0].UserPrompt; !strings.Contains(prompt, "Go workspace with 2 modules") {
+		t.Errorf("user prompt should describe the workspace, got:\n%s", prompt)
+	}
+}
+
+func TestConfigGenerator_GenerateEnvVarNames(t *testing.T) {
+	mock := llm.NewMockClient(testConfigJSON)
+	analysis := testAnalysis()
+	analysis.EnvVars = map[string]string{"REDIS_URL": "redis://cache:6379", "API_KEY": maskedEnvValue}
+
+	if _, err := NewConfigGenerator(mock).Generate(context.Background(), analysis); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	prompt := mock.Requests()[0].UserPrompt
+	if !strings.Contains(prompt, "  - API_KEY\n  - REDIS_URL") {
+		t.Errorf("user prompt should list env var names, got:\n%s", prompt)
+	}
+	if strings.Contains(prompt, "redis://cache") {
+		t.Error("user prompt should not contain env var values")
+	}
+}
@@ -3,18 +3,59 @@ package codemapping
 import (
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Detector detects programming language and framework
-type Detector struct{}
+type Detector struct {
+	mu              sync.RWMutex
+	languageMarkers []detectionRule // Registered marker files, checked before the built-ins
+	frameworkDeps   []detectionRule // Registered dependencies, checked before the built-ins
+}
+
+// detectionRule maps a marker file or dependency name to what it indicates
+type detectionRule struct {
+	key    string
+	result string
+}
 
 // NewDetector creates a new detector
 func NewDetector() *Detector {
 	return &Detector{}
 }
 
+// RegisterLanguageMarker makes a file named filename indicate language.
+// Registered markers take precedence over built-in ones, in registration order.
+func (d *Detector) RegisterLanguageMarker(filename, language string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.languageMarkers = append(d.languageMarkers, detectionRule{key: filename, result: language})
+}
+
+// RegisterFrameworkDependency makes a dependency named dependencyName indicate
+// frameworkName. Registered dependencies take precedence over built-in ones, in
+// registration order.
+func (d *Detector) RegisterFrameworkDependency(dependencyName, frameworkName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.frameworkDeps = append(d.frameworkDeps, detectionRule{key: dependencyName, result: frameworkName})
+}
+
 // DetectLanguage determines the primary programming language
 func (d *Detector) DetectLanguage(analysis *RepositoryAnalysis) string {
+	d.mu.RLock()
+	markers := d.languageMarkers
+	d.mu.RUnlock()
+
+	// Check for registered marker files
+	for _, marker := range markers {
+		if hasAnyFile(analysis.Files, marker.key) {
+			return marker.result
+		}
+	}
+
 	// Check for specific marker files
 	for _, file := range analysis.Files {
 		switch file {
@@ -28,6 +69,8 @@ func (d *Detector) DetectLanguage(analysis *RepositoryAnalysis) string {
 			return "rust"
 		case "pom.xml", "build.gradle":
 			return "java"
+		case "mix.exs":
+			return "elixir"
 		}
 	}
 
@@ -53,6 +96,8 @@ func (d *Detector) DetectLanguage(analysis *RepositoryAnalysis) string {
 		"kt":   "kotlin",
 		"rb":   "ruby",
 		"php":  "php",
+		"ex":   "elixir",
+		"exs":  "elixir",
 	}
 
 	// Find most common language
@@ -70,6 +115,17 @@ func (d *Detector) DetectLanguage(analysis *RepositoryAnalysis) string {
 
 // DetectFramework determines the framework being used
 func (d *Detector) DetectFramework(analysis *RepositoryAnalysis) string {
+	d.mu.RLock()
+	deps := d.frameworkDeps
+	d.mu.RUnlock()
+
+	// Registered frameworks
+	for _, dep := range deps {
+		if hasAnyDependency(analysis.Dependencies, dep.key) {
+			return dep.result
+		}
+	}
+
 	// Go frameworks
 	if hasAnyDependency(analysis.Dependencies,
 		"github.com/gin-gonic/gin",
@@ -146,6 +202,23 @@ func (d *Detector) DetectFramework(analysis *RepositoryAnalysis) string {
 		return "django"
 	}
 
+	// Elixir frameworks
+	if hasAnyDependency(analysis.Dependencies,
+		"phoenix",
+	) {
+		return "phoenix"
+	}
+	if hasAnyDependency(analysis.Dependencies,
+		"absinthe",
+	) {
+		return "absinthe"
+	}
+	if hasAnyDependency(analysis.Dependencies,
+		"plug", "plug_cowboy",
+	) {
+		return "plug"
+	}
+
 	// Check for framework indicators in files
 	for _, file := range analysis.Files {
 		fileName := filepath.Base(file)
@@ -0,0 +1,80 @@
+package codemapping
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// ConfigChange is one field that differs between two platform configs
+type ConfigChange struct {
+	Field  string // JSON path of the field, e.g. "resources.scaling.max_replicas"
+	Before any    // nil when the field was added
+	After  any    // nil when the field was removed
+}
+
+// String formats the change as "field: before -> after"
+func (c ConfigChange) String() string {
+	switch {
+	case c.Before == nil:
+		return fmt.Sprintf("+ %s: %v", c.Field, c.After)
+	case c.After == nil:
+		return fmt.Sprintf("- %s: %v", c.Field, c.Before)
+	default:
+		return fmt.Sprintf("~ %s: %v -> %v", c.Field, c.Before, c.After)
+	}
+}
+
+// DiffConfigs lists the fields that differ between before and after, sorted by
+// field. Lists are compared as a whole; either config may be nil.
+func DiffConfigs(before, after *PlatformConfig) []ConfigChange {
+	old, updated := flattenConfig(before), flattenConfig(after)
+
+	var changes []ConfigChange
+	for field, value := range old {
+		if next, ok := updated[field]; !ok {
+			changes = append(changes, ConfigChange{Field: field, Before: value})
+		} else if !reflect.DeepEqual(value, next) {
+			changes = append(changes, ConfigChange{Field: field, Before: value, After: next})
+		}
+	}
+	for field, value := range updated {
+		if _, ok := old[field]; !ok {
+			changes = append(changes, ConfigChange{Field: field, After: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// flattenConfig maps the JSON path of every leaf field of cfg to its value
+func flattenConfig(cfg *PlatformConfig) map[string]any {
+	fields := make(map[string]any)
+	if cfg == nil {
+		return fields
+	}
+	// PlatformConfig holds only strings, numbers, bools, maps, and slices, so
+	// marshaling cannot fail
+	data, _ := json.Marshal(cfg)
+	var tree map[string]any
+	_ = json.Unmarshal(data, &tree)
+	flattenInto(fields, "", tree)
+	return fields
+}
+
+func flattenInto(fields map[string]any, prefix string, value any) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		if value != nil {
+			fields[prefix] = value
+		}
+		return
+	}
+	for key, child := range object {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		flattenInto(fields, key, child)
+	}
+}
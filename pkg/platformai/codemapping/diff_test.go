@@ -0,0 +1,46 @@
+package codemapping
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	before := validConfig()
+	after := validConfig()
+	after.Resources.Scaling.MaxReplicas = 20
+	after.Service.Port = 9090
+	after.Cache = &CacheConfig{Type: "redis", Version: "7", Memory: "256Mi"}
+
+	got := DiffConfigs(before, after)
+	want := []ConfigChange{
+		{Field: "cache.memory", After: "256Mi"},
+		{Field: "cache.type", After: "redis"},
+		{Field: "cache.version", After: "7"},
+		{Field: "resources.scaling.max_replicas", Before: float64(10), After: float64(20)},
+		{Field: "service.port", Before: float64(8080), After: float64(9090)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffConfigs() = %v, want %v", got, want)
+	}
+
+	if changes := DiffConfigs(before, validConfig()); len(changes) != 0 {
+		t.Errorf("DiffConfigs() of equal configs = %v, want none", changes)
+	}
+}
+
+func TestConfigChange_String(t *testing.T) {
+	tests := []struct {
+		change ConfigChange
+		want   string
+	}{
+		{ConfigChange{Field: "cache.type", After: "redis"}, "+ cache.type: redis"},
+		{ConfigChange{Field: "database.type", Before: "postgresql"}, "- database.type: postgresql"},
+		{ConfigChange{Field: "resources.cpu", Before: "500m", After: "1000m"}, "~ resources.cpu: 500m -> 1000m"},
+	}
+	for _, tt := range tests {
+		if got := tt.change.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package codemapping
+
+import "github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+
+// Error codes of the code mapping module's sentinel errors
+const (
+	ErrCodeInvalidResponse = "invalid_response"
+)
+
+// Common error types for the code mapping module
+var (
+	// ErrInvalidResponse indicates that the LLM returned a config that fails
+	// validation. It matches platformai.ErrInvalidResponse with errors.Is.
+	ErrInvalidResponse = llm.NewSDKError(ErrCodeInvalidResponse, "invalid LLM response")
+)
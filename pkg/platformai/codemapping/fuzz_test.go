@@ -0,0 +1,31 @@
+//go:build fuzz
+
+package codemapping
+
+import (
+	"context"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// FuzzPlatformConfigParsing feeds arbitrary LLM responses through
+// ConfigGenerator.Generate. Run with: go test -tags fuzz -fuzz FuzzPlatformConfigParsing ./pkg/platformai/codemapping
+func FuzzPlatformConfigParsing(f *testing.F) {
+	f.Add(testConfigJSON)
+	for _, example := range configExamples {
+		f.Add(example.AssistantMessage)
+	}
+	f.Add(`{"service": null, "resources": {"scaling": {"min_replicas": -1}}}`)
+	f.Add(`[]`)
+
+	f.Fuzz(func(t *testing.T, response string) {
+		config, err := NewConfigGenerator(llm.NewMockClient(response)).Generate(context.Background(), testAnalysis())
+		if err != nil {
+			return
+		}
+		if errs := ValidatePlatformConfig(config); len(errs) > 0 {
+			t.Fatalf("Generate() accepted an invalid config: %v", errs)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package codemapping
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitStaleAfter is how long without commits before a repository counts as unmaintained
+const gitStaleAfter = 6 * 30 * 24 * time.Hour
+
+// GitSummary describes recent activity in a repository's git history
+type GitSummary struct {
+	RecentCommitCount  int // Commits among the last 20
+	ActiveContributors int // Authors of non-merge commits in the last 6 months
+	LastCommitDate     time.Time
+}
+
+// RunGitAnalysis summarizes the git history of the repository at repoPath. It
+// needs the git executable on PATH; canceling ctx kills a running git command.
+func RunGitAnalysis(ctx context.Context, repoPath string) (*GitSummary, error) {
+	commits, err := runGit(ctx, repoPath, "log", "--oneline", "-20")
+	if err != nil {
+		return nil, err
+	}
+	// HEAD keeps shortlog from reading a log from stdin
+	contributors, err := runGit(ctx, repoPath, "shortlog", "-sn", "--no-merges", "--since=6months", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	last, err := runGit(ctx, repoPath, "log", "-1", "--format=%cI")
+	if err != nil {
+		return nil, err
+	}
+	lastCommit, err := time.Parse(time.RFC3339, strings.TrimSpace(last))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse last commit date: %w", err)
+	}
+
+	return &GitSummary{
+		RecentCommitCount:  countLines(commits),
+		ActiveContributors: countLines(contributors),
+		LastCommitDate:     lastCommit,
+	}, nil
+}
+
+// runGit runs a git subcommand in repoPath and returns its output
+func runGit(ctx context.Context, repoPath string, args ...string) (string, error) {
+	// #nosec G204 - arguments are fixed by RunGitAnalysis; repoPath is passed with -C
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", repoPath}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// countLines counts the non-blank lines of s
+func countLines(s string) int {
+	n := 0
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
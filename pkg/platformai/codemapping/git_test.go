@@ -0,0 +1,107 @@
+package codemapping
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// fakeGit puts a git script answering RunGitAnalysis's commands first on PATH
+func fakeGit(t *testing.T, lastCommit string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake git is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$3 $4" in
+"log --oneline") printf 'a1b2c3 Add orders endpoint\nd4e5f6 Fix retry\n0a1b2c Initial commit\n' ;;
+"shortlog -sn") printf '     5\tAlice\n     2\tBob\n' ;;
+"log -1") echo ` + lastCommit + ` ;;
+*) echo "unexpected git $*" >&2; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunGitAnalysis(t *testing.T) {
+	fakeGit(t, "2025-03-14T09:30:00+01:00")
+
+	summary, err := RunGitAnalysis(context.Background(), t.TempDir())
+	if err != nil {
+		t.Fatalf("RunGitAnalysis() error = %v", err)
+	}
+	if summary.RecentCommitCount != 3 {
+		t.Errorf("RecentCommitCount = %d, want 3", summary.RecentCommitCount)
+	}
+	if summary.ActiveContributors != 2 {
+		t.Errorf("ActiveContributors = %d, want 2", summary.ActiveContributors)
+	}
+	if want := time.Date(2025, 3, 14, 8, 30, 0, 0, time.UTC); !summary.LastCommitDate.Equal(want) {
+		t.Errorf("LastCommitDate = %v, want %v", summary.LastCommitDate, want)
+	}
+}
+
+func TestRunGitAnalysis_InvalidDate(t *testing.T) {
+	fakeGit(t, "yesterday")
+
+	if _, err := RunGitAnalysis(context.Background(), t.TempDir()); err == nil {
+		t.Error("RunGitAnalysis() with an unparsable commit date succeeded, want error")
+	}
+}
+
+func TestRunGitAnalysis_Canceled(t *testing.T) {
+	fakeGit(t, "2025-03-14T09:30:00+01:00")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := RunGitAnalysis(ctx, t.TempDir()); err == nil {
+		t.Error("RunGitAnalysis() with a canceled context succeeded, want error")
+	}
+}
+
+func TestAnalyzer_AnalyzeGitSummary(t *testing.T) {
+	fakeGit(t, time.Now().Format(time.RFC3339))
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	analysis, err := NewAnalyzer().Analyze(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if analysis.GitSummary == nil || analysis.GitSummary.ActiveContributors != 2 {
+		t.Errorf("GitSummary = %+v, want the fake git history", analysis.GitSummary)
+	}
+}
+
+func TestGitActivityRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary *GitSummary
+		want    int
+	}{
+		{"no history", nil, 0},
+		{"recent commit", &GitSummary{LastCommitDate: time.Now().AddDate(0, -1, 0)}, 0},
+		{"stale", &GitSummary{LastCommitDate: time.Now().AddDate(-1, 0, 0)}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs := gitActivityRule{}.Evaluate(&RepositoryAnalysis{GitSummary: tt.summary}, nil)
+			if len(recs) != tt.want {
+				t.Fatalf("Evaluate() = %v, want %d recommendations", recs, tt.want)
+			}
+			if tt.want == 1 && recs[0].Level != "warning" {
+				t.Errorf("Evaluate() level = %q, want warning", recs[0].Level)
+			}
+		})
+	}
+}
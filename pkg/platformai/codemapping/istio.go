@@ -0,0 +1,172 @@
+package codemapping
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Istio networking resources. Only the fields the generator sets are modeled,
+// which keeps the SDK free of an Istio client dependency.
+type (
+	istioVirtualService struct {
+		APIVersion string                  `yaml:"apiVersion"`
+		Kind       string                  `yaml:"kind"`
+		Metadata   objectMeta              `yaml:"metadata"`
+		Spec       istioVirtualServiceSpec `yaml:"spec"`
+	}
+
+	istioVirtualServiceSpec struct {
+		Hosts []string         `yaml:"hosts"`
+		HTTP  []istioHTTPRoute `yaml:"http"`
+	}
+
+	istioHTTPRoute struct {
+		Route   []istioRouteDestination `yaml:"route"`
+		Retries *istioRetries           `yaml:"retries,omitempty"`
+	}
+
+	istioRouteDestination struct {
+		Destination istioDestination `yaml:"destination"`
+		Weight      int              `yaml:"weight,omitempty"`
+	}
+
+	istioDestination struct {
+		Host   string `yaml:"host"`
+		Subset string `yaml:"subset,omitempty"`
+	}
+
+	istioRetries struct {
+		Attempts      int    `yaml:"attempts"`
+		PerTryTimeout string `yaml:"perTryTimeout,omitempty"`
+		RetryOn       string `yaml:"retryOn,omitempty"`
+	}
+
+	istioDestinationRule struct {
+		APIVersion string                   `yaml:"apiVersion"`
+		Kind       string                   `yaml:"kind"`
+		Metadata   objectMeta               `yaml:"metadata"`
+		Spec       istioDestinationRuleSpec `yaml:"spec"`
+	}
+
+	istioDestinationRuleSpec struct {
+		Host          string             `yaml:"host"`
+		TrafficPolicy istioTrafficPolicy `yaml:"trafficPolicy"`
+		Subsets       []istioSubset      `yaml:"subsets,omitempty"`
+	}
+
+	istioTrafficPolicy struct {
+		ConnectionPool istioConnectionPool `yaml:"connectionPool"`
+	}
+
+	istioConnectionPool struct {
+		TCP  istioTCPSettings  `yaml:"tcp"`
+		HTTP istioHTTPSettings `yaml:"http"`
+	}
+
+	istioTCPSettings struct {
+		MaxConnections int `yaml:"maxConnections"`
+	}
+
+	istioHTTPSettings struct {
+		HTTP1MaxPendingRequests int `yaml:"http1MaxPendingRequests"`
+		HTTP2MaxRequests        int `yaml:"http2MaxRequests"`
+	}
+
+	istioSubset struct {
+		Name   string            `yaml:"name"`
+		Labels map[string]string `yaml:"labels"`
+	}
+)
+
+// Connection pool sizing per CPU core of a replica
+const (
+	istioConnectionsPerCore = 100
+	istioMinConnections     = 10
+)
+
+// GenerateIstioManifests renders an Istio VirtualService and DestinationRule for
+// the service in namespace, as two YAML documents. The VirtualService splits
+// traffic between subsets by ServiceMesh.TrafficWeight and retries failed
+// requests ServiceMesh.RetryAttempts times. The DestinationRule sizes the
+// connection pool from the replica's CPU request, at 100 connections per core.
+func GenerateIstioManifests(cfg *PlatformConfig, namespace string) ([]byte, error) {
+	mesh := cfg.ServiceMesh
+	if mesh == nil {
+		return nil, fmt.Errorf("service mesh is not configured")
+	}
+	if mesh.Type != "" && mesh.Type != "istio" {
+		return nil, fmt.Errorf("unsupported service mesh %q (supported: istio)", mesh.Type)
+	}
+	if cfg.Service.Name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	millicores, err := parseMillicores(cfg.Resources.CPU)
+	if err != nil {
+		return nil, err
+	}
+
+	host := fmt.Sprintf("%s.%s.svc.cluster.local", cfg.Service.Name, namespace)
+	meta := objectMeta{Name: cfg.Service.Name, Namespace: namespace, Labels: podLabels(cfg)}
+
+	route := istioHTTPRoute{}
+	var subsets []istioSubset
+	if len(mesh.TrafficWeight) == 0 {
+		route.Route = []istioRouteDestination{{Destination: istioDestination{Host: host}}}
+	} else {
+		names := make([]string, 0, len(mesh.TrafficWeight))
+		total := 0
+		for name, weight := range mesh.TrafficWeight {
+			if weight < 0 {
+				return nil, fmt.Errorf("traffic weight of subset %s must not be negative", name)
+			}
+			names = append(names, name)
+			total += weight
+		}
+		if total != 100 {
+			return nil, fmt.Errorf("traffic weights must add up to 100, got %d", total)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			route.Route = append(route.Route, istioRouteDestination{
+				Destination: istioDestination{Host: host, Subset: name},
+				Weight:      mesh.TrafficWeight[name],
+			})
+			subsets = append(subsets, istioSubset{Name: name, Labels: map[string]string{"version": name}})
+		}
+	}
+	if mesh.RetryAttempts > 0 {
+		route.Retries = &istioRetries{
+			Attempts:      mesh.RetryAttempts,
+			PerTryTimeout: "2s",
+			RetryOn:       "5xx,reset,connect-failure",
+		}
+	}
+
+	connections := max(millicores*istioConnectionsPerCore/1000, istioMinConnections)
+	documents := []any{
+		istioVirtualService{
+			APIVersion: "networking.istio.io/v1",
+			Kind:       "VirtualService",
+			Metadata:   meta,
+			Spec:       istioVirtualServiceSpec{Hosts: []string{host}, HTTP: []istioHTTPRoute{route}},
+		},
+		istioDestinationRule{
+			APIVersion: "networking.istio.io/v1",
+			Kind:       "DestinationRule",
+			Metadata:   meta,
+			Spec: istioDestinationRuleSpec{
+				Host: host,
+				TrafficPolicy: istioTrafficPolicy{ConnectionPool: istioConnectionPool{
+					TCP:  istioTCPSettings{MaxConnections: connections},
+					HTTP: istioHTTPSettings{HTTP1MaxPendingRequests: connections / 2, HTTP2MaxRequests: connections},
+				}},
+				Subsets: subsets,
+			},
+		},
+	}
+
+	return marshalManifests(documents...)
+}
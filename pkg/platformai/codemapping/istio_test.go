@@ -0,0 +1,128 @@
+package codemapping
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseIstioManifests splits the YAML stream into its documents, keyed by kind
+func parseIstioManifests(t *testing.T, data []byte) map[string]map[string]any {
+	t.Helper()
+	docs := make(map[string]map[string]any)
+	for _, part := range strings.Split(string(data), "---\n") {
+		var doc map[string]any
+		if err := yaml.Unmarshal([]byte(part), &doc); err != nil {
+			t.Fatalf("failed to parse manifest: %v\n%s", err, part)
+		}
+		docs[doc["kind"].(string)] = doc
+	}
+	return docs
+}
+
+// field follows a path of map keys and slice indexes through a parsed manifest
+func field(t *testing.T, v any, path ...any) any {
+	t.Helper()
+	for _, p := range path {
+		switch key := p.(type) {
+		case string:
+			v = v.(map[string]any)[key]
+		case int:
+			v = v.([]any)[key]
+		}
+	}
+	return v
+}
+
+func TestGenerateIstioManifests(t *testing.T) {
+	cfg := validConfig()
+	cfg.Service.Name = "orders"
+	cfg.Resources.CPU = "2"
+	cfg.ServiceMesh = &ServiceMeshConfig{
+		Type:          "istio",
+		TrafficWeight: map[string]int{"v1": 90, "v2": 10},
+		RetryAttempts: 3,
+	}
+
+	data, err := GenerateIstioManifests(cfg, "shop")
+	if err != nil {
+		t.Fatalf("GenerateIstioManifests() error = %v", err)
+	}
+	docs := parseIstioManifests(t, data)
+	vs, dr := docs["VirtualService"], docs["DestinationRule"]
+	if vs == nil || dr == nil {
+		t.Fatalf("manifests = %v, want VirtualService and DestinationRule", docs)
+	}
+
+	const host = "orders.shop.svc.cluster.local"
+	if got := field(t, vs, "spec", "hosts", 0); got != host {
+		t.Errorf("VirtualService host = %v, want %s", got, host)
+	}
+	if got := field(t, vs, "spec", "http", 0, "retries", "attempts"); got != 3 {
+		t.Errorf("retries.attempts = %v, want 3", got)
+	}
+	for i, want := range []struct {
+		subset string
+		weight int
+	}{{"v1", 90}, {"v2", 10}} {
+		route := field(t, vs, "spec", "http", 0, "route", i)
+		if field(t, route, "destination", "host") != host || field(t, route, "destination", "subset") != want.subset || field(t, route, "weight") != want.weight {
+			t.Errorf("route %d = %v, want %s at %v%%", i, route, want.subset, want.weight)
+		}
+	}
+
+	if got := field(t, dr, "spec", "host"); got != host {
+		t.Errorf("DestinationRule host = %v, want %s", got, host)
+	}
+	if got := field(t, dr, "spec", "trafficPolicy", "connectionPool", "tcp", "maxConnections"); got != 200 {
+		t.Errorf("maxConnections = %v, want 200 for 2 cores", got)
+	}
+	if got := field(t, dr, "spec", "subsets", 1, "labels", "version"); got != "v2" {
+		t.Errorf("second subset version label = %v, want v2", got)
+	}
+}
+
+func TestGenerateIstioManifests_NoRetriesOrSplit(t *testing.T) {
+	cfg := validConfig()
+	cfg.ServiceMesh = &ServiceMeshConfig{Type: "istio"}
+
+	data, err := GenerateIstioManifests(cfg, "")
+	if err != nil {
+		t.Fatalf("GenerateIstioManifests() error = %v", err)
+	}
+	docs := parseIstioManifests(t, data)
+	route := field(t, docs["VirtualService"], "spec", "http", 0).(map[string]any)
+	if _, ok := route["retries"]; ok {
+		t.Errorf("route = %v, want no retry policy", route)
+	}
+	if got := field(t, route, "route", 0, "destination", "host"); got != "api.default.svc.cluster.local" {
+		t.Errorf("destination host = %v, want the default namespace", got)
+	}
+	if got := field(t, docs["DestinationRule"], "spec", "trafficPolicy", "connectionPool", "tcp", "maxConnections"); got != 50 {
+		t.Errorf("maxConnections = %v, want 50 for 500m", got)
+	}
+}
+
+func TestGenerateIstioManifests_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*PlatformConfig)
+	}{
+		{"no mesh", func(c *PlatformConfig) { c.ServiceMesh = nil }},
+		{"linkerd", func(c *PlatformConfig) { c.ServiceMesh.Type = "linkerd" }},
+		{"weights not 100", func(c *PlatformConfig) { c.ServiceMesh.TrafficWeight = map[string]int{"v1": 50, "v2": 20} }},
+		{"negative weight", func(c *PlatformConfig) { c.ServiceMesh.TrafficWeight = map[string]int{"v1": 110, "v2": -10} }},
+		{"invalid cpu", func(c *PlatformConfig) { c.Resources.CPU = "half" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.ServiceMesh = &ServiceMeshConfig{Type: "istio"}
+			tt.modify(cfg)
+			if _, err := GenerateIstioManifests(cfg, "shop"); err == nil {
+				t.Error("GenerateIstioManifests() expected error")
+			}
+		})
+	}
+}
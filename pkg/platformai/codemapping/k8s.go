@@ -0,0 +1,451 @@
+package codemapping
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kubernetes resources. Only the fields the generators set are modeled, which
+// keeps the SDK free of the Kubernetes client libraries.
+type (
+	labelSelector struct {
+		MatchLabels map[string]string `yaml:"matchLabels"`
+	}
+
+	networkPolicy struct {
+		APIVersion string            `yaml:"apiVersion"`
+		Kind       string            `yaml:"kind"`
+		Metadata   objectMeta        `yaml:"metadata"`
+		Spec       networkPolicySpec `yaml:"spec"`
+	}
+
+	networkPolicySpec struct {
+		PodSelector labelSelector              `yaml:"podSelector"`
+		PolicyTypes []string                   `yaml:"policyTypes"`
+		Ingress     []networkPolicyIngressRule `yaml:"ingress"`
+		Egress      []networkPolicyEgressRule  `yaml:"egress"`
+	}
+
+	networkPolicyIngressRule struct {
+		Ports []networkPolicyPort `yaml:"ports"`
+	}
+
+	networkPolicyEgressRule struct {
+		Ports []networkPolicyPort `yaml:"ports,omitempty"`
+		To    []networkPolicyPeer `yaml:"to,omitempty"`
+	}
+
+	networkPolicyPort struct {
+		Protocol string `yaml:"protocol"`
+		Port     int    `yaml:"port"`
+	}
+
+	networkPolicyPeer struct {
+		IPBlock ipBlock `yaml:"ipBlock"`
+	}
+
+	ipBlock struct {
+		CIDR string `yaml:"cidr"`
+	}
+
+	podDisruptionBudget struct {
+		APIVersion string                  `yaml:"apiVersion"`
+		Kind       string                  `yaml:"kind"`
+		Metadata   objectMeta              `yaml:"metadata"`
+		Spec       podDisruptionBudgetSpec `yaml:"spec"`
+	}
+
+	podDisruptionBudgetSpec struct {
+		MinAvailable   *int          `yaml:"minAvailable,omitempty"`
+		MaxUnavailable *int          `yaml:"maxUnavailable,omitempty"`
+		Selector       labelSelector `yaml:"selector"`
+	}
+
+	ingress struct {
+		APIVersion string      `yaml:"apiVersion"`
+		Kind       string      `yaml:"kind"`
+		Metadata   objectMeta  `yaml:"metadata"`
+		Spec       ingressSpec `yaml:"spec"`
+	}
+
+	ingressSpec struct {
+		TLS   []ingressTLS  `yaml:"tls,omitempty"`
+		Rules []ingressRule `yaml:"rules"`
+	}
+
+	ingressTLS struct {
+		Hosts      []string `yaml:"hosts"`
+		SecretName string   `yaml:"secretName"`
+	}
+
+	ingressRule struct {
+		Host string          `yaml:"host"`
+		HTTP ingressRuleHTTP `yaml:"http"`
+	}
+
+	ingressRuleHTTP struct {
+		Paths []ingressPath `yaml:"paths"`
+	}
+
+	ingressPath struct {
+		Path     string         `yaml:"path"`
+		PathType string         `yaml:"pathType"`
+		Backend  ingressBackend `yaml:"backend"`
+	}
+
+	ingressBackend struct {
+		Service ingressServiceBackend `yaml:"service"`
+	}
+
+	ingressServiceBackend struct {
+		Name string             `yaml:"name"`
+		Port ingressServicePort `yaml:"port"`
+	}
+
+	ingressServicePort struct {
+		Number int `yaml:"number"`
+	}
+
+	resourceQuota struct {
+		APIVersion string            `yaml:"apiVersion"`
+		Kind       string            `yaml:"kind"`
+		Metadata   objectMeta        `yaml:"metadata"`
+		Spec       resourceQuotaSpec `yaml:"spec"`
+	}
+
+	resourceQuotaSpec struct {
+		Hard map[string]string `yaml:"hard"`
+	}
+
+	secret struct {
+		APIVersion string            `yaml:"apiVersion"`
+		Kind       string            `yaml:"kind"`
+		Metadata   objectMeta        `yaml:"metadata"`
+		Type       string            `yaml:"type"`
+		StringData map[string]string `yaml:"stringData"`
+	}
+)
+
+// EgressHostsAnnotation lists the allowed egress hostnames of a generated
+// NetworkPolicy. Network policies only match IP ranges, so hostnames must be
+// enforced by an egress gateway or a CNI with FQDN policies.
+const EgressHostsAnnotation = "platformai.innominatus.dev/egress-hosts"
+
+// GenerateK8sManifests writes the Kubernetes manifests for cfg to dir and returns
+// the paths written: networkpolicy.yaml when a network policy is configured,
+// pdb.yaml when the service runs at least two replicas, and ingress.yaml when an
+// ingress is configured
+func GenerateK8sManifests(cfg *PlatformConfig, dir string) ([]string, error) {
+	manifests := []struct {
+		file     string
+		generate func(*PlatformConfig) ([]byte, error)
+	}{
+		{"networkpolicy.yaml", func(cfg *PlatformConfig) ([]byte, error) {
+			if cfg.Security.NetworkPolicy == nil {
+				return nil, nil
+			}
+			return GenerateNetworkPolicy(cfg)
+		}},
+		{"pdb.yaml", GeneratePodDisruptionBudget},
+		{"ingress.yaml", func(cfg *PlatformConfig) ([]byte, error) {
+			if cfg.Ingress == nil {
+				return nil, nil
+			}
+			return GenerateIngress(cfg)
+		}},
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+	var written []string
+	for _, m := range manifests {
+		data, err := m.generate(cfg)
+		if err != nil {
+			return written, fmt.Errorf("failed to generate %s: %w", m.file, err)
+		}
+		if data == nil {
+			continue
+		}
+		path := filepath.Join(dir, m.file)
+		if err := os.WriteFile(path, data, 0o644); err != nil { // #nosec G306 - manifests are not secret
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// GeneratePodDisruptionBudget renders a PodDisruptionBudget keeping the service
+// available during voluntary disruptions such as node drains. Services with at
+// least 4 replicas allow one pod down at a time (maxUnavailable: 1); services
+// with 2 or 3 keep one pod up (minAvailable: 1). A single replica cannot be
+// protected, so it returns nil.
+func GeneratePodDisruptionBudget(cfg *PlatformConfig) ([]byte, error) {
+	if cfg.Service.Name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+
+	one := 1
+	spec := podDisruptionBudgetSpec{
+		Selector: labelSelector{MatchLabels: podLabels(cfg)},
+	}
+	switch replicas := cfg.Resources.Scaling.MinReplicas; {
+	case replicas >= 4:
+		spec.MaxUnavailable = &one
+	case replicas >= 2:
+		spec.MinAvailable = &one
+	default:
+		return nil, nil
+	}
+
+	return marshalManifest(podDisruptionBudget{
+		APIVersion: "policy/v1",
+		Kind:       "PodDisruptionBudget",
+		Metadata:   objectMeta{Name: cfg.Service.Name, Labels: podLabels(cfg)},
+		Spec:       spec,
+	})
+}
+
+// SSLRedirectAnnotation makes the NGINX ingress controller redirect HTTP to HTTPS
+const SSLRedirectAnnotation = "nginx.ingress.kubernetes.io/ssl-redirect"
+
+// GenerateIngress renders a networking.k8s.io/v1 Ingress routing cfg.Ingress.Host
+// and path to the service port, with the configured annotations. When a TLS
+// secret is set, the Ingress terminates TLS for the host and redirects HTTP to
+// HTTPS.
+func GenerateIngress(cfg *PlatformConfig) ([]byte, error) {
+	if cfg.Service.Name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+	ing := cfg.Ingress
+	if ing == nil || ing.Host == "" {
+		return nil, fmt.Errorf("ingress host is required")
+	}
+	if !validPort(cfg.Service.Port) {
+		return nil, fmt.Errorf("invalid service port %d", cfg.Service.Port)
+	}
+	path := ing.Path
+	if path == "" {
+		path = "/"
+	}
+
+	annotations := make(map[string]string, len(ing.Annotations)+1)
+	for k, v := range ing.Annotations {
+		annotations[k] = v
+	}
+	manifest := ingress{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "Ingress",
+		Metadata:   objectMeta{Name: cfg.Service.Name, Labels: podLabels(cfg)},
+		Spec: ingressSpec{
+			Rules: []ingressRule{{
+				Host: ing.Host,
+				HTTP: ingressRuleHTTP{Paths: []ingressPath{{
+					Path:     path,
+					PathType: "Prefix",
+					Backend: ingressBackend{Service: ingressServiceBackend{
+						Name: cfg.Service.Name,
+						Port: ingressServicePort{Number: cfg.Service.Port},
+					}},
+				}}},
+			}},
+		},
+	}
+	if ing.TLSSecret != "" {
+		manifest.Spec.TLS = []ingressTLS{{Hosts: []string{ing.Host}, SecretName: ing.TLSSecret}}
+		annotations[SSLRedirectAnnotation] = "true"
+	}
+	if len(annotations) > 0 {
+		manifest.Metadata.Annotations = annotations
+	}
+
+	return marshalManifest(manifest)
+}
+
+// ResourceQuotaName is the name of the quota generated by GenerateResourceQuota
+const ResourceQuotaName = "platform-quota"
+
+// quotaHeadroomPercent is added on top of the summed service resources
+const quotaHeadroomPercent = 20
+
+// GenerateResourceQuota renders a ResourceQuota for a namespace running the given
+// services. CPU and memory requests and limits are each the sum of the services'
+// ResourceConfig values plus 20% headroom, with memory rounded up to whole Mi.
+func GenerateResourceQuota(configs []*PlatformConfig) ([]byte, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("at least one platform config is required")
+	}
+
+	var milliCPU, memoryBytes int64
+	for _, cfg := range configs {
+		cpu, err := parseMillicores(cfg.Resources.CPU)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CPU of %s: %w", cfg.Service.Name, err)
+		}
+		memory, err := parseMemoryBytes(cfg.Resources.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse memory of %s: %w", cfg.Service.Name, err)
+		}
+		milliCPU += int64(cpu)
+		memoryBytes += memory
+	}
+
+	cpu := formatMillicores(withHeadroom(milliCPU))
+	memory := formatMemory((withHeadroom(memoryBytes) + mebibyte - 1) / mebibyte * mebibyte)
+
+	return marshalManifest(resourceQuota{
+		APIVersion: "v1",
+		Kind:       "ResourceQuota",
+		Metadata:   objectMeta{Name: ResourceQuotaName},
+		Spec: resourceQuotaSpec{
+			Hard: map[string]string{
+				"requests.cpu":    cpu,
+				"limits.cpu":      cpu,
+				"requests.memory": memory,
+				"limits.memory":   memory,
+			},
+		},
+	})
+}
+
+// withHeadroom adds quotaHeadroomPercent to n, rounding up
+func withHeadroom(n int64) int64 {
+	return (n*(100+quotaHeadroomPercent) + 99) / 100
+}
+
+// objectMeta is the metadata of the resources the SDK models without their client libraries
+type objectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// podLabels returns the labels selecting the service's pods
+func podLabels(cfg *PlatformConfig) map[string]string {
+	return map[string]string{"app": cfg.Service.Name}
+}
+
+// GenerateNetworkPolicy renders a Kubernetes NetworkPolicy for the service that
+// denies all traffic except ingress on the service port and the allowed ingress
+// ports, and egress to the allowed hosts plus DNS. IPs and CIDRs become ipBlock
+// peers; hostnames are listed in the EgressHostsAnnotation.
+func GenerateNetworkPolicy(cfg *PlatformConfig) ([]byte, error) {
+	if cfg.Service.Name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+	np := cfg.Security.NetworkPolicy
+	if np == nil {
+		np = &NetworkPolicyConfig{}
+	}
+
+	ingressPorts := []int{cfg.Service.Port}
+	ingressPorts = append(ingressPorts, np.AllowedIngressPorts...)
+	var ports []networkPolicyPort
+	seen := make(map[int]bool)
+	for _, port := range ingressPorts {
+		if seen[port] {
+			continue
+		}
+		if !validPort(port) {
+			return nil, fmt.Errorf("invalid ingress port %d", port)
+		}
+		seen[port] = true
+		ports = append(ports, tcpPort(port))
+	}
+
+	var peers []networkPolicyPeer
+	var hostnames []string
+	for _, host := range np.AllowedEgressHosts {
+		cidr, ok := egressCIDR(host)
+		if !ok {
+			hostnames = append(hostnames, host)
+			continue
+		}
+		peers = append(peers, networkPolicyPeer{IPBlock: ipBlock{CIDR: cidr}})
+	}
+
+	// Pods need DNS to resolve the hosts they are allowed to reach
+	egress := []networkPolicyEgressRule{{
+		Ports: []networkPolicyPort{{Protocol: "UDP", Port: 53}, {Protocol: "TCP", Port: 53}},
+	}}
+	if len(peers) > 0 {
+		egress = append(egress, networkPolicyEgressRule{To: peers})
+	}
+
+	policy := networkPolicy{
+		APIVersion: "networking.k8s.io/v1",
+		Kind:       "NetworkPolicy",
+		Metadata:   objectMeta{Name: cfg.Service.Name, Labels: podLabels(cfg)},
+		Spec: networkPolicySpec{
+			PodSelector: labelSelector{MatchLabels: podLabels(cfg)},
+			PolicyTypes: []string{"Ingress", "Egress"},
+			Ingress:     []networkPolicyIngressRule{{Ports: ports}},
+			Egress:      egress,
+		},
+	}
+	if len(hostnames) > 0 {
+		sort.Strings(hostnames)
+		policy.Metadata.Annotations = map[string]string{EgressHostsAnnotation: strings.Join(hostnames, ",")}
+	}
+
+	return marshalManifest(policy)
+}
+
+// tcpPort returns a TCP network policy port
+func tcpPort(port int) networkPolicyPort {
+	return networkPolicyPort{Protocol: "TCP", Port: port}
+}
+
+// egressCIDR returns host as a CIDR if it is an IP address or CIDR
+func egressCIDR(host string) (string, bool) {
+	if _, network, err := net.ParseCIDR(host); err == nil {
+		return network.String(), true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", false
+	}
+	if ip.To4() != nil {
+		return ip.String() + "/32", true
+	}
+	return ip.String() + "/128", true
+}
+
+// marshalManifests renders Kubernetes objects as a multi-document YAML stream
+func marshalManifests(objs ...any) ([]byte, error) {
+	var out bytes.Buffer
+	for i, obj := range objs {
+		data, err := marshalManifest(obj)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(data)
+	}
+	return out.Bytes(), nil
+}
+
+// marshalManifest renders a Kubernetes object as YAML
+func marshalManifest(obj any) ([]byte, error) {
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(obj); err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	return out.Bytes(), nil
+}
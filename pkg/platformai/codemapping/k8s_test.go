@@ -0,0 +1,271 @@
+package codemapping
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeManifest parses a YAML manifest into v, rejecting fields v does not model
+func decodeManifest(t *testing.T, data []byte, v any) {
+	t.Helper()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(v); err != nil {
+		t.Fatalf("failed to parse manifest: %v\n%s", err, data)
+	}
+}
+
+func TestGenerateNetworkPolicy(t *testing.T) {
+	cfg := validConfig()
+	cfg.Service.Name = "orders-api"
+	cfg.Security.NetworkPolicy = &NetworkPolicyConfig{
+		AllowedIngressPorts: []int{9090, 8080},
+		AllowedEgressHosts:  []string{"10.0.0.0/16", "10.1.2.3", "api.stripe.com"},
+	}
+
+	data, err := GenerateNetworkPolicy(cfg)
+	if err != nil {
+		t.Fatalf("GenerateNetworkPolicy() error = %v", err)
+	}
+	var policy networkPolicy
+	decodeManifest(t, data, &policy)
+
+	if policy.Kind != "NetworkPolicy" || policy.APIVersion != "networking.k8s.io/v1" {
+		t.Errorf("type = %s %s, want networking.k8s.io/v1 NetworkPolicy", policy.APIVersion, policy.Kind)
+	}
+	if want := map[string]string{"app": "orders-api"}; !reflect.DeepEqual(policy.Spec.PodSelector.MatchLabels, want) {
+		t.Errorf("pod selector = %v, want %v", policy.Spec.PodSelector.MatchLabels, want)
+	}
+	if len(policy.Spec.PolicyTypes) != 2 {
+		t.Errorf("policy types = %v, want Ingress and Egress", policy.Spec.PolicyTypes)
+	}
+
+	if len(policy.Spec.Ingress) != 1 {
+		t.Fatalf("ingress rules = %d, want 1", len(policy.Spec.Ingress))
+	}
+	var ports []int
+	for _, port := range policy.Spec.Ingress[0].Ports {
+		if port.Protocol != "TCP" {
+			t.Errorf("ingress port %v protocol = %s, want TCP", port.Port, port.Protocol)
+		}
+		ports = append(ports, port.Port)
+	}
+	if want := []int{8080, 9090}; !reflect.DeepEqual(ports, want) {
+		t.Errorf("ingress ports = %v, want %v", ports, want)
+	}
+
+	if len(policy.Spec.Egress) != 2 {
+		t.Fatalf("egress rules = %d, want DNS and hosts", len(policy.Spec.Egress))
+	}
+	if dns := policy.Spec.Egress[0]; len(dns.To) != 0 || dns.Ports[0].Port != 53 {
+		t.Errorf("first egress rule = %+v, want DNS to anywhere", dns)
+	}
+	var cidrs []string
+	for _, peer := range policy.Spec.Egress[1].To {
+		cidrs = append(cidrs, peer.IPBlock.CIDR)
+	}
+	if want := []string{"10.0.0.0/16", "10.1.2.3/32"}; !reflect.DeepEqual(cidrs, want) {
+		t.Errorf("egress CIDRs = %v, want %v", cidrs, want)
+	}
+	if got := policy.Metadata.Annotations[EgressHostsAnnotation]; got != "api.stripe.com" {
+		t.Errorf("egress hosts annotation = %q, want api.stripe.com", got)
+	}
+}
+
+func TestGenerateNetworkPolicy_Defaults(t *testing.T) {
+	cfg := validConfig()
+
+	data, err := GenerateNetworkPolicy(cfg)
+	if err != nil {
+		t.Fatalf("GenerateNetworkPolicy() error = %v", err)
+	}
+	var policy networkPolicy
+	decodeManifest(t, data, &policy)
+	if ports := policy.Spec.Ingress[0].Ports; len(ports) != 1 || ports[0].Port != cfg.Service.Port {
+		t.Errorf("ingress ports = %v, want only the service port", ports)
+	}
+	if len(policy.Spec.Egress) != 1 {
+		t.Errorf("egress rules = %+v, want DNS only", policy.Spec.Egress)
+	}
+
+	cfg.Service.Name = ""
+	if _, err := GenerateNetworkPolicy(cfg); err == nil {
+		t.Error("GenerateNetworkPolicy() expected error without a service name")
+	}
+}
+
+func TestGeneratePodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		replicas           int
+		wantMinAvailable   bool
+		wantMaxUnavailable bool
+	}{
+		{replicas: 1},
+		{replicas: 2, wantMinAvailable: true},
+		{replicas: 3, wantMinAvailable: true},
+		{replicas: 4, wantMaxUnavailable: true},
+		{replicas: 10, wantMaxUnavailable: true},
+	}
+
+	for _, tt := range tests {
+		cfg := validConfig()
+		cfg.Resources.Scaling.MinReplicas = tt.replicas
+		cfg.Resources.Scaling.MaxReplicas = tt.replicas
+
+		data, err := GeneratePodDisruptionBudget(cfg)
+		if err != nil {
+			t.Fatalf("GeneratePodDisruptionBudget(%d replicas) error = %v", tt.replicas, err)
+		}
+		if !tt.wantMinAvailable && !tt.wantMaxUnavailable {
+			if data != nil {
+				t.Errorf("GeneratePodDisruptionBudget(%d replicas) = %s, want no PDB", tt.replicas, data)
+			}
+			continue
+		}
+
+		var pdb podDisruptionBudget
+		decodeManifest(t, data, &pdb)
+		if got := pdb.Spec.MinAvailable != nil; got != tt.wantMinAvailable {
+			t.Errorf("%d replicas: minAvailable set = %v, want %v", tt.replicas, got, tt.wantMinAvailable)
+		}
+		if got := pdb.Spec.MaxUnavailable != nil; got != tt.wantMaxUnavailable {
+			t.Errorf("%d replicas: maxUnavailable set = %v, want %v", tt.replicas, got, tt.wantMaxUnavailable)
+		}
+		for _, v := range []*int{pdb.Spec.MinAvailable, pdb.Spec.MaxUnavailable} {
+			if v != nil && *v != 1 {
+				t.Errorf("%d replicas: budget = %d, want 1", tt.replicas, *v)
+			}
+		}
+		if pdb.Spec.Selector.MatchLabels["app"] != cfg.Service.Name {
+			t.Errorf("selector = %v, want app=%s", pdb.Spec.Selector.MatchLabels, cfg.Service.Name)
+		}
+	}
+}
+
+func TestGenerateK8sManifests(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "manifests")
+	cfg := validConfig()
+
+	written, err := GenerateK8sManifests(cfg, dir)
+	if err != nil {
+		t.Fatalf("GenerateK8sManifests() error = %v", err)
+	}
+	if want := []string{filepath.Join(dir, "pdb.yaml")}; !reflect.DeepEqual(written, want) {
+		t.Errorf("GenerateK8sManifests() = %v, want %v", written, want)
+	}
+
+	cfg.Security.NetworkPolicy = &NetworkPolicyConfig{}
+	cfg.Resources.Scaling.MinReplicas = 1
+	written, err = GenerateK8sManifests(cfg, dir)
+	if err != nil {
+		t.Fatalf("GenerateK8sManifests() error = %v", err)
+	}
+	if want := []string{filepath.Join(dir, "networkpolicy.yaml")}; !reflect.DeepEqual(written, want) {
+		t.Errorf("GenerateK8sManifests() = %v, want %v", written, want)
+	}
+	if _, err := os.Stat(written[0]); err != nil {
+		t.Errorf("manifest not written: %v", err)
+	}
+}
+
+func TestGenerateIngress(t *testing.T) {
+	tests := []struct {
+		name      string
+		ingress   IngressConfig
+		wantTLS   bool
+		wantPath  string
+		wantNotes map[string]string
+	}{
+		{
+			name:      "http",
+			ingress:   IngressConfig{Host: "api.example.com", Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "8m"}},
+			wantPath:  "/",
+			wantNotes: map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "8m"},
+		},
+		{
+			name:      "https",
+			ingress:   IngressConfig{Host: "api.example.com", Path: "/v1", TLSSecret: "api-tls"},
+			wantTLS:   true,
+			wantPath:  "/v1",
+			wantNotes: map[string]string{SSLRedirectAnnotation: "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			cfg.Ingress = &tt.ingress
+			data, err := GenerateIngress(cfg)
+			if err != nil {
+				t.Fatalf("GenerateIngress() error = %v", err)
+			}
+
+			var ingress ingress
+			decodeManifest(t, data, &ingress)
+			if ingress.APIVersion != "networking.k8s.io/v1" || ingress.Kind != "Ingress" {
+				t.Errorf("type = %s %s, want networking.k8s.io/v1 Ingress", ingress.APIVersion, ingress.Kind)
+			}
+			if got := len(ingress.Spec.TLS) > 0; got != tt.wantTLS {
+				t.Errorf("spec.tls present = %v, want %v", got, tt.wantTLS)
+			}
+			if tt.wantTLS && ingress.Spec.TLS[0].SecretName != tt.ingress.TLSSecret {
+				t.Errorf("tls secret = %q, want %q", ingress.Spec.TLS[0].SecretName, tt.ingress.TLSSecret)
+			}
+			if !reflect.DeepEqual(ingress.Metadata.Annotations, tt.wantNotes) {
+				t.Errorf("annotations = %v, want %v", ingress.Metadata.Annotations, tt.wantNotes)
+			}
+
+			path := ingress.Spec.Rules[0].HTTP.Paths[0]
+			if path.Path != tt.wantPath || path.Backend.Service.Name != "api" || path.Backend.Service.Port.Number != 8080 {
+				t.Errorf("path = %s -> %s:%d, want %s -> api:8080", path.Path, path.Backend.Service.Name, path.Backend.Service.Port.Number, tt.wantPath)
+			}
+		})
+	}
+
+	if _, err := GenerateIngress(validConfig()); err == nil {
+		t.Error("GenerateIngress() expected error without ingress config")
+	}
+}
+
+func TestGenerateResourceQuota(t *testing.T) {
+	var configs []*PlatformConfig
+	for _, r := range []struct{ cpu, memory string }{{"500m", "512Mi"}, {"1", "1Gi"}, {"250m", "256Mi"}} {
+		cfg := validConfig()
+		cfg.Resources.CPU, cfg.Resources.Memory = r.cpu, r.memory
+		configs = append(configs, cfg)
+	}
+
+	data, err := GenerateResourceQuota(configs)
+	if err != nil {
+		t.Fatalf("GenerateResourceQuota() error = %v", err)
+	}
+	var quota resourceQuota
+	decodeManifest(t, data, &quota)
+	if quota.Kind != "ResourceQuota" || quota.Metadata.Name != ResourceQuotaName {
+		t.Errorf("manifest = %s %s, want ResourceQuota %s", quota.Kind, quota.Metadata.Name, ResourceQuotaName)
+	}
+
+	// 1750m CPU and 1792Mi memory, plus 20%
+	want := map[string]string{
+		"requests.cpu":    "2100m",
+		"limits.cpu":      "2100m",
+		"requests.memory": "2151Mi",
+		"limits.memory":   "2151Mi",
+	}
+	if !reflect.DeepEqual(quota.Spec.Hard, want) {
+		t.Errorf("spec.hard = %v, want %v", quota.Spec.Hard, want)
+	}
+
+	configs[1].Resources.CPU = "one core"
+	if _, err := GenerateResourceQuota(configs); err == nil {
+		t.Error("GenerateResourceQuota() expected error for unparsable CPU")
+	}
+	if _, err := GenerateResourceQuota(nil); err == nil {
+		t.Error("GenerateResourceQuota() expected error without configs")
+	}
+}
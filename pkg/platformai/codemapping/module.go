@@ -3,7 +3,11 @@ package codemapping
 import (
 	"context"
 	"fmt"
-	"strings"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
 )
@@ -14,16 +18,36 @@ type Module struct {
 	analyzer  *Analyzer
 	detector  *Detector
 	generator *ConfigGenerator
+
+	rulesMu sync.RWMutex // Guards rules against RegisterRecommendationRule during Analyze
+	rules   []RecommendationRule
+
+	analyzeTimeout time.Duration // Bounds each Analyze call; zero means no limit
+}
+
+// Option configures a Module
+type Option func(*Module)
+
+// WithAnalyzeTimeout bounds each Analyze call, including config generation, to d
+func WithAnalyzeTimeout(d time.Duration) Option {
+	return func(m *Module) {
+		m.analyzeTimeout = d
+	}
 }
 
 // NewModule creates a new code mapping module
-func NewModule(llmClient llm.Client) *Module {
-	return &Module{
+func NewModule(llmClient llm.Client, opts ...Option) *Module {
+	m := &Module{
 		llm:       llmClient,
 		analyzer:  NewAnalyzer(),
 		detector:  NewDetector(),
 		generator: NewConfigGenerator(llmClient),
+		rules:     defaultRecommendationRules(),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // AnalyzeRequest contains parameters for analysis
@@ -35,6 +59,23 @@ type AnalyzeRequest struct {
 // AnalyzeOptions contains optional parameters
 type AnalyzeOptions struct {
 	Verbose bool
+
+	// ExcludeDirs are skipped in addition to the built-in list (.git,
+	// node_modules, vendor, ...). Each entry matches a directory name or a
+	// slash-separated path relative to the repository root.
+	ExcludeDirs []string
+
+	// ExcludePatterns are filepath.Match globs, e.g. "*.generated.go", matched
+	// against each file and directory name and relative path
+	ExcludePatterns []string
+
+	// ExcludeExtensions skips files with these suffixes, e.g. ".log" or ".min.js"
+	ExcludeExtensions []string
+
+	// Baseline makes the analysis incremental: when nothing config generation
+	// reads has changed since this earlier result of the same repository, its
+	// config is reused instead of asking the LLM again
+	Baseline *AnalyzeResult
 }
 
 // AnalyzeResult contains the analysis results
@@ -42,12 +83,27 @@ type AnalyzeResult struct {
 	Analysis        *RepositoryAnalysis
 	Config          *PlatformConfig
 	Recommendations []Recommendation
+
+	// ConfigReused is set when Config was taken from AnalyzeOptions.Baseline
+	ConfigReused bool
 }
 
 // Analyze performs complete repository analysis and config generation
 func (m *Module) Analyze(ctx context.Context, req AnalyzeRequest) (*AnalyzeResult, error) {
+	if m.analyzeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.analyzeTimeout)
+		defer cancel()
+	}
+
+	// Each step checks for cancellation first, so a canceled request never
+	// returns a partial result
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// 1. Analyze repository
-	analysis, err := m.analyzer.Analyze(ctx, req.RepoPath)
+	analysis, err := m.analyzer.AnalyzeWithOptions(ctx, req.RepoPath, req.Options)
 	if err != nil {
 		return nil, fmt.Errorf("repository analysis failed: %w", err)
 	}
@@ -56,10 +112,25 @@ func (m *Module) Analyze(ctx context.Context, req AnalyzeRequest) (*AnalyzeResul
 	analysis.PrimaryLanguage = m.detector.DetectLanguage(analysis)
 	analysis.DetectedFramework = m.detector.DetectFramework(analysis)
 
-	// 3. Generate platform config
-	config, err := m.generator.Generate(ctx, analysis)
-	if err != nil {
-		return nil, fmt.Errorf("config generation failed: %w", err)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// 3. Generate platform config, unless the baseline's still applies
+	var config *PlatformConfig
+	baseline := req.Options.Baseline
+	reused := baseline != nil && baseline.Config != nil && sameGenerationInputs(baseline.Analysis, analysis)
+	if reused {
+		config = baseline.Config
+	} else {
+		config, err = m.generator.Generate(ctx, analysis)
+		if err != nil {
+			return nil, fmt.Errorf("config generation failed: %w", err)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
 	// 4. Generate recommendations
@@ -69,136 +140,101 @@ func (m *Module) Analyze(ctx context.Context, req AnalyzeRequest) (*AnalyzeResul
 		Analysis:        analysis,
 		Config:          config,
 		Recommendations: recommendations,
+		ConfigReused:    reused,
 	}, nil
 }
 
-// generateRecommendations creates actionable recommendations
-func (m *Module) generateRecommendations(analysis *RepositoryAnalysis, config *PlatformConfig) []Recommendation {
-	var recommendations []Recommendation
-
-	// Check for health endpoint
-	hasHealthCheck := false
-	for _, file := range analysis.Files {
-		// Simple heuristic - check for health in filenames
-		if strings.Contains(strings.ToLower(file), "health") {
-			hasHealthCheck = true
-			break
-		}
-	}
-
-	if !hasHealthCheck {
-		recommendations = append(recommendations, Recommendation{
-			Level:   "warning",
-			Title:   "No health check endpoint found",
-			Message: "Consider adding a /health endpoint for monitoring",
-		})
-	}
-
-	// Check for Dockerfile
-	if !analysis.HasDockerfile {
-		recommendations = append(recommendations, Recommendation{
-			Level:   "warning",
-			Title:   "No Dockerfile found",
-			Message: "Consider adding a Dockerfile for containerization",
-		})
+// sameGenerationInputs reports whether two analyses agree on everything
+// ConfigGenerator reads, so a config generated for one fits the other
+func sameGenerationInputs(a, b *RepositoryAnalysis) bool {
+	if a == nil || b == nil {
+		return false
 	}
+	return a.PrimaryLanguage == b.PrimaryLanguage &&
+		a.DetectedFramework == b.DetectedFramework &&
+		a.LanguageVersion == b.LanguageVersion &&
+		a.HasDockerfile == b.HasDockerfile &&
+		a.DockerfileContent == b.DockerfileContent &&
+		a.HasGoWorkspace == b.HasGoWorkspace &&
+		slices.Equal(a.Files, b.Files) &&
+		slices.Equal(a.WorkspaceModules, b.WorkspaceModules) &&
+		maps.Equal(a.Dependencies, b.Dependencies) &&
+		maps.Equal(a.EnvVars, b.EnvVars)
+}
 
-	// Check for tests
-	hasTests := false
-	for _, file := range analysis.Files {
-		lower := strings.ToLower(file)
-		if strings.Contains(lower, "test") || strings.Contains(lower, "spec") {
-			hasTests = true
-			break
-		}
-	}
+// RegisterRecommendationRule adds a rule evaluated after the built-in rules on every Analyze
+func (m *Module) RegisterRecommendationRule(rule RecommendationRule) {
+	m.rulesMu.Lock()
+	defer m.rulesMu.Unlock()
+	m.rules = append(m.rules, rule)
+}
 
-	if !hasTests {
-		recommendations = append(recommendations, Recommendation{
-			Level:   "info",
-			Title:   "No test files detected",
-			Message: "Consider adding tests for better code quality",
-		})
-	}
+// ListTemplates returns the names of the platform config templates
+func (m *Module) ListTemplates() []string {
+	return m.generator.templates.Names()
+}
 
-	// Add positive recommendations
-	if analysis.HasDockerfile {
-		recommendations = append(recommendations, Recommendation{
-			Level:   "info",
-			Title:   "Dockerfile present",
-			Message: "Good! Your service is ready for containerization",
-		})
-	}
+// GetTemplate returns a copy of the named platform config template
+func (m *Module) GetTemplate(name string) (*PlatformConfig, error) {
+	return m.generator.templates.Get(name)
+}
 
-	if len(analysis.Dependencies) > 0 {
-		recommendations = append(recommendations, Recommendation{
-			Level:   "info",
-			Title:   fmt.Sprintf("Detected %d dependencies", len(analysis.Dependencies)),
-			Message: "Dependencies configured in platform config",
-		})
-	}
+// Detector returns the module's detector, for registering custom languages and frameworks
+func (m *Module) Detector() *Detector {
+	return m.detector
+}
 
-	if hasTests {
-		recommendations = append(recommendations, Recommendation{
-			Level:   "info",
-			Title:   "Test files detected",
-			Message: "Great! Tests help ensure code quality",
-		})
-	}
+// generateRecommendations creates actionable recommendations, most severe first.
+// When several rules produce the same title only the most severe entry is kept.
+func (m *Module) generateRecommendations(analysis *RepositoryAnalysis, config *PlatformConfig) []Recommendation {
+	m.rulesMu.RLock()
+	rules := m.rules
+	m.rulesMu.RUnlock()
 
-	// Language-specific recommendations
-	switch analysis.PrimaryLanguage {
-	case "go":
-		// Check for go.sum
-		hasGoSum := false
-		for _, file := range analysis.Files {
-			if file == "go.sum" {
-				hasGoSum = true
-				break
+	var recommendations []Recommendation
+	index := make(map[string]int) // Title -> position in recommendations
+	for _, rule := range rules {
+		for _, rec := range rule.Evaluate(analysis, config) {
+			i, exists := index[rec.Title]
+			if !exists {
+				index[rec.Title] = len(recommendations)
+				recommendations = append(recommendations, rec)
+			} else if levelRank(rec.Level) < levelRank(recommendations[i].Level) {
+				recommendations[i] = rec
 			}
 		}
-		if !hasGoSum {
-			recommendations = append(recommendations, Recommendation{
-				Level:   "warning",
-				Title:   "No go.sum found",
-				Message: "Run 'go mod tidy' to generate go.sum for dependency verification",
-			})
-		}
+	}
 
-	case "nodejs":
-		// Check for lockfile
-		hasLockfile := false
-		for _, file := range analysis.Files {
-			if file == "package-lock.json" || file == "yarn.lock" || file == "pnpm-lock.yaml" {
-				hasLockfile = true
-				break
-			}
-		}
-		if !hasLockfile {
-			recommendations = append(recommendations, Recommendation{
-				Level:   "warning",
-				Title:   "No lockfile found",
-				Message: "Consider committing package-lock.json or yarn.lock for reproducible builds",
-			})
-		}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return levelRank(recommendations[i].Level) < levelRank(recommendations[j].Level)
+	})
+	return recommendations
+}
 
-	case "python":
-		// Check for virtual environment indicator
-		hasVenvConfig := false
-		for _, file := range analysis.Files {
-			if file == "requirements.txt" || file == "pyproject.toml" || file == "Pipfile" {
-				hasVenvConfig = true
+// FilterRecommendations returns the recommendations whose level is one of levels, preserving order
+func (m *Module) FilterRecommendations(results []Recommendation, levels ...string) []Recommendation {
+	filtered := []Recommendation{}
+	for _, rec := range results {
+		for _, level := range levels {
+			if rec.Level == level {
+				filtered = append(filtered, rec)
 				break
 			}
 		}
-		if hasVenvConfig {
-			recommendations = append(recommendations, Recommendation{
-				Level:   "info",
-				Title:   "Python dependency management detected",
-				Message: "Ensure you're using a virtual environment for development",
-			})
-		}
 	}
+	return filtered
+}
 
-	return recommendations
+// levelRank orders recommendation levels by severity; lower is more severe
+func levelRank(level string) int {
+	switch level {
+	case "critical":
+		return 0
+	case "warning":
+		return 1
+	case "info":
+		return 2
+	default:
+		return 3
+	}
 }
@@ -0,0 +1,324 @@
+package codemapping
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+func TestModule_RegisterRecommendationRule(t *testing.T) {
+	repo := t.TempDir()
+	goMod := "module example.com/svc\n\ngo 1.21\n\nrequire github.com/acme/legacy-queue v0.3.0\n"
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte(goMod), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	module := NewModule(llm.NewMockClient(testConfigJSON))
+	module.RegisterRecommendationRule(RecommendationRuleFunc(func(analysis *RepositoryAnalysis, _ *PlatformConfig) []Recommendation {
+		if !hasAnyDependency(analysis.Dependencies, "github.com/acme/legacy-queue") {
+			return nil
+		}
+		return []Recommendation{{Level: "warning", Title: "Legacy queue client", Message: "Migrate to the managed queue"}}
+	}))
+
+	result, err := module.Analyze(context.Background(), AnalyzeRequest{RepoPath: repo})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	var found, builtIn bool
+	for _, rec := range result.Recommendations {
+		switch rec.Title {
+		case "Legacy queue client":
+			found = true
+		case "No Dockerfile found":
+			builtIn = true
+		}
+	}
+	if !found {
+		t.Errorf("Recommendations = %+v, want custom rule recommendation", result.Recommendations)
+	}
+	if !builtIn {
+		t.Error("built-in rules should still run alongside registered rules")
+	}
+}
+
+func TestModule_RegisterRecommendationRuleConcurrent(t *testing.T) {
+	module := NewModule(llm.NewMockClient(testConfigJSON))
+	analysis := &RepositoryAnalysis{}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			module.RegisterRecommendationRule(RecommendationRuleFunc(func(*RepositoryAnalysis, *PlatformConfig) []Recommendation {
+				return nil
+			}))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		module.generateRecommendations(analysis, &PlatformConfig{})
+	}
+	<-done
+}
+
+func TestDetector_Register(t *testing.T) {
+	detector := NewDetector()
+	analysis := &RepositoryAnalysis{
+		Files:        []string{"build.zig", "src/main.zig"},
+		Dependencies: map[string]string{"zap": "0.8.0", "express": "4.18.0"},
+	}
+
+	if got := detector.DetectLanguage(analysis); got != "unknown" {
+		t.Errorf("DetectLanguage() before registration = %q, want unknown", got)
+	}
+	if got := detector.DetectFramework(analysis); got != "express" {
+		t.Errorf("DetectFramework() before registration = %q, want express", got)
+	}
+
+	detector.RegisterLanguageMarker("build.zig", "zig")
+	detector.RegisterFrameworkDependency("zap", "zap")
+
+	if got := detector.DetectLanguage(analysis); got != "zig" {
+		t.Errorf("DetectLanguage() = %q, want zig", got)
+	}
+	if got := detector.DetectFramework(analysis); got != "zap" {
+		t.Errorf("DetectFramework() = %q, want zap", got)
+	}
+}
+
+func TestModule_GenerateRecommendationsDedupAndSort(t *testing.T) {
+	module := NewModule(llm.NewMockClient(testConfigJSON))
+	module.rules = []RecommendationRule{
+		RecommendationRuleFunc(func(*RepositoryAnalysis, *PlatformConfig) []Recommendation {
+			return []Recommendation{
+				{Level: "info", Title: "Pin base image"},
+				{Level: "warning", Title: "No readiness probe"},
+			}
+		}),
+		RecommendationRuleFunc(func(*RepositoryAnalysis, *PlatformConfig) []Recommendation {
+			return []Recommendation{
+				{Level: "critical", Title: "Pin base image", Action: "pin-image"},
+				{Level: "info", Title: "No readiness probe"},
+				{Level: "info", Title: "Metrics enabled"},
+			}
+		}),
+	}
+
+	got := module.generateRecommendations(&RepositoryAnalysis{}, nil)
+	want := []Recommendation{
+		{Level: "critical", Title: "Pin base image", Action: "pin-image"},
+		{Level: "warning", Title: "No readiness probe"},
+		{Level: "info", Title: "Metrics enabled"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("generateRecommendations() = %+v, want %+v", got, want)
+	}
+}
+
+func TestModule_FilterRecommendations(t *testing.T) {
+	module := NewModule(nil)
+	recs := []Recommendation{
+		{Level: "critical", Title: "a"},
+		{Level: "warning", Title: "b"},
+		{Level: "info", Title: "c"},
+		{Level: "warning", Title: "d"},
+	}
+
+	tests := []struct {
+		name   string
+		levels []string
+		want   []string
+	}{
+		{"single level", []string{"warning"}, []string{"b", "d"}},
+		{"multiple levels", []string{"critical", "info"}, []string{"a", "c"}},
+		{"no match", []string{"debug"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			titles := []string{}
+			for _, rec := range module.FilterRecommendations(recs, tt.levels...) {
+				titles = append(titles, rec.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.want) {
+				t.Errorf("FilterRecommendations(%v) = %v, want %v", tt.levels, titles, tt.want)
+			}
+		})
+	}
+}
+
+// hostTransport sends every request to a test server instead of the real API
+type hostTransport struct {
+	target string
+}
+
+func (t hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = t.target
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestModule_AnalyzeTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client, err := llm.NewAnthropicClient(llm.Config{APIKey: "test", Timeout: time.Minute})
+	if err != nil {
+		t.Fatalf("NewAnthropicClient() error = %v", err)
+	}
+	client.SetHTTPClient(&http.Client{Transport: hostTransport{target: server.Listener.Addr().String()}})
+
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module example.com/svc\n\ngo 1.22\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	module := NewModule(client, WithAnalyzeTimeout(time.Second))
+	if _, err := module.Analyze(context.Background(), AnalyzeRequest{RepoPath: repo}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Analyze() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestModule_AnalyzeCanceledDuringGeneration(t *testing.T) {
+	// The mock ignores ctx and answers with a valid config after 100ms
+	client := llm.NewMockClient(testConfigJSON)
+	client.GenerateFunc = func(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+		time.Sleep(100 * time.Millisecond)
+		return &llm.GenerateResponse{Text: testConfigJSON, StopReason: "end_turn"}, nil
+	}
+
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module example.com/svc\n\ngo 1.22\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	defer cancel()
+
+	result, err := NewModule(client).Analyze(ctx, AnalyzeRequest{RepoPath: repo})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Analyze() error = %v, want context.Canceled", err)
+	}
+	if result != nil {
+		t.Errorf("Analyze() result = %+v, want nil", result)
+	}
+	if len(client.Requests()) != 1 {
+		t.Errorf("LLM requests = %d, want 1: cancellation happened during generation", len(client.Requests()))
+	}
+}
+
+func TestModule_AnalyzeCanceledBeforeWalk(t *testing.T) {
+	client := llm.NewMockClient(testConfigJSON)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewModule(client).Analyze(ctx, AnalyzeRequest{RepoPath: "testdata/sample-go-workspace"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Analyze() error = %v, want context.Canceled", err)
+	}
+	if len(client.Requests()) != 0 {
+		t.Errorf("LLM requests = %d, want 0 after cancellation", len(client.Requests()))
+	}
+}
+
+func TestModule_AnalyzeExclusions(t *testing.T) {
+	repo := t.TempDir()
+	files := []string{
+		"main.go",
+		"api.generated.go",
+		"secrets/prod.yaml",
+		"docs/internal/notes.md",
+		"docs/guide.md",
+		"logs/app.log",
+		"vendor/lib/lib.go",
+	}
+	for _, file := range files {
+		path := filepath.Join(repo, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	module := NewModule(llm.NewMockClient(testConfigJSON))
+	result, err := module.Analyze(context.Background(), AnalyzeRequest{
+		RepoPath: repo,
+		Options: AnalyzeOptions{
+			ExcludeDirs:       []string{"secrets", "docs/internal"},
+			ExcludePatterns:   []string{"*.generated.go"},
+			ExcludeExtensions: []string{"log"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := []string{"docs/guide.md", "main.go"}
+	got := make([]string, len(result.Analysis.Files))
+	for i, file := range result.Analysis.Files {
+		got[i] = filepath.ToSlash(file)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Analysis.Files = %v, want %v", got, want)
+	}
+}
+
+func TestModule_AnalyzeBaseline(t *testing.T) {
+	repo := t.TempDir()
+	writeFile := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("go.mod", "module example.com/svc\n\ngo 1.21\n")
+	writeFile("main.go", "package main\n")
+
+	mock := llm.NewMockClient(testConfigJSON)
+	module := NewModule(mock)
+	baseline, err := module.Analyze(context.Background(), AnalyzeRequest{RepoPath: repo})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	// Editing a source file changes nothing the generator reads
+	writeFile("main.go", "package main\n\nfunc main() {}\n")
+	result, err := module.Analyze(context.Background(), AnalyzeRequest{RepoPath: repo, Options: AnalyzeOptions{Baseline: baseline}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if !result.ConfigReused || result.Config != baseline.Config {
+		t.Error("Analyze() with an unchanged baseline should reuse its config")
+	}
+	if got := len(mock.Requests()); got != 1 {
+		t.Errorf("LLM requests = %d, want 1", got)
+	}
+
+	writeFile("go.mod", "module example.com/svc\n\ngo 1.21\n\nrequire github.com/lib/pq v1.10.9\n")
+	result, err = module.Analyze(context.Background(), AnalyzeRequest{RepoPath: repo, Options: AnalyzeOptions{Baseline: result}})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	if result.ConfigReused {
+		t.Error("Analyze() after a dependency change should regenerate the config")
+	}
+	if got := len(mock.Requests()); got != 2 {
+		t.Errorf("LLM requests = %d, want 2", got)
+	}
+}
@@ -0,0 +1,180 @@
+package codemapping
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RecommendationRule inspects an analysis and its generated config and returns
+// any recommendations that apply
+type RecommendationRule interface {
+	Evaluate(analysis *RepositoryAnalysis, config *PlatformConfig) []Recommendation
+}
+
+// RecommendationRuleFunc adapts a function to RecommendationRule
+type RecommendationRuleFunc func(analysis *RepositoryAnalysis, config *PlatformConfig) []Recommendation
+
+// Evaluate calls f
+func (f RecommendationRuleFunc) Evaluate(analysis *RepositoryAnalysis, config *PlatformConfig) []Recommendation {
+	return f(analysis, config)
+}
+
+// defaultRecommendationRules returns the built-in rules in evaluation order
+func defaultRecommendationRules() []RecommendationRule {
+	return []RecommendationRule{
+		healthCheckRule{},
+		dockerfileRule{},
+		testFilesRule{},
+		dependenciesRule{},
+		languageRule{},
+		gitActivityRule{},
+	}
+}
+
+// healthCheckRule warns when no health endpoint can be found
+type healthCheckRule struct{}
+
+// Evaluate implements RecommendationRule
+func (healthCheckRule) Evaluate(analysis *RepositoryAnalysis, _ *PlatformConfig) []Recommendation {
+	// Simple heuristic - check for health in filenames
+	for _, file := range analysis.Files {
+		if strings.Contains(strings.ToLower(file), "health") {
+			return nil
+		}
+	}
+	return []Recommendation{{
+		Level:   "warning",
+		Title:   "No health check endpoint found",
+		Message: "Consider adding a /health endpoint for monitoring",
+		Action:  "add-health-endpoint",
+	}}
+}
+
+// dockerfileRule reports whether the service can be containerized
+type dockerfileRule struct{}
+
+// Evaluate implements RecommendationRule
+func (dockerfileRule) Evaluate(analysis *RepositoryAnalysis, _ *PlatformConfig) []Recommendation {
+	if !analysis.HasDockerfile {
+		return []Recommendation{{
+			Level:   "warning",
+			Title:   "No Dockerfile found",
+			Message: "Consider adding a Dockerfile for containerization",
+			Action:  "add-dockerfile",
+		}}
+	}
+	return []Recommendation{{
+		Level:   "info",
+		Title:   "Dockerfile present",
+		Message: "Good! Your service is ready for containerization",
+	}}
+}
+
+// testFilesRule reports whether the repository contains tests
+type testFilesRule struct{}
+
+// Evaluate implements RecommendationRule
+func (testFilesRule) Evaluate(analysis *RepositoryAnalysis, _ *PlatformConfig) []Recommendation {
+	for _, file := range analysis.Files {
+		lower := strings.ToLower(file)
+		if strings.Contains(lower, "test") || strings.Contains(lower, "spec") {
+			return []Recommendation{{
+				Level:   "info",
+				Title:   "Test files detected",
+				Message: "Great! Tests help ensure code quality",
+			}}
+		}
+	}
+	return []Recommendation{{
+		Level:   "info",
+		Title:   "No test files detected",
+		Message: "Consider adding tests for better code quality",
+		Action:  "add-tests",
+	}}
+}
+
+// dependenciesRule summarizes the detected dependencies
+type dependenciesRule struct{}
+
+// Evaluate implements RecommendationRule
+func (dependenciesRule) Evaluate(analysis *RepositoryAnalysis, _ *PlatformConfig) []Recommendation {
+	if len(analysis.Dependencies) == 0 {
+		return nil
+	}
+	return []Recommendation{{
+		Level:   "info",
+		Title:   fmt.Sprintf("Detected %d dependencies", len(analysis.Dependencies)),
+		Message: "Dependencies configured in platform config",
+	}}
+}
+
+// languageRule applies language-specific dependency management checks
+type languageRule struct{}
+
+// Evaluate implements RecommendationRule
+func (languageRule) Evaluate(analysis *RepositoryAnalysis, _ *PlatformConfig) []Recommendation {
+	switch analysis.PrimaryLanguage {
+	case "go":
+		// Check for go.sum
+		if !hasAnyFile(analysis.Files, "go.sum") {
+			return []Recommendation{{
+				Level:   "warning",
+				Title:   "No go.sum found",
+				Message: "Run 'go mod tidy' to generate go.sum for dependency verification",
+				Action:  "run-go-mod-tidy",
+			}}
+		}
+
+	case "nodejs":
+		// Check for lockfile
+		if !hasAnyFile(analysis.Files, "package-lock.json", "yarn.lock", "pnpm-lock.yaml") {
+			return []Recommendation{{
+				Level:   "warning",
+				Title:   "No lockfile found",
+				Message: "Consider committing package-lock.json or yarn.lock for reproducible builds",
+				Action:  "commit-lockfile",
+			}}
+		}
+
+	case "python":
+		// Check for virtual environment indicator
+		if hasAnyFile(analysis.Files, "requirements.txt", "pyproject.toml", "Pipfile") {
+			return []Recommendation{{
+				Level:   "info",
+				Title:   "Python dependency management detected",
+				Message: "Ensure you're using a virtual environment for development",
+			}}
+		}
+	}
+	return nil
+}
+
+// hasAnyFile checks if any of the given files exist
+func hasAnyFile(files []string, names ...string) bool {
+	for _, file := range files {
+		for _, name := range names {
+			if file == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// gitActivityRule warns when the repository has had no commits for six months
+type gitActivityRule struct{}
+
+// Evaluate implements RecommendationRule
+func (gitActivityRule) Evaluate(analysis *RepositoryAnalysis, _ *PlatformConfig) []Recommendation {
+	git := analysis.GitSummary
+	if git == nil || git.LastCommitDate.IsZero() || time.Since(git.LastCommitDate) < gitStaleAfter {
+		return nil
+	}
+	return []Recommendation{{
+		Level:   "warning",
+		Title:   "Repository appears unmaintained",
+		Message: fmt.Sprintf("Last commit was on %s; confirm the service still has an owner before deploying it", git.LastCommitDate.Format("2006-01-02")),
+		Action:  "confirm-ownership",
+	}}
+}
@@ -0,0 +1,117 @@
+package codemapping
+
+import "fmt"
+
+// Secret providers accepted by GenerateSecretsManifests
+const (
+	SecretProviderKubernetes      = "k8s" // Plain Kubernetes Secret templates
+	SecretProviderExternalSecrets = "eso" // External Secrets Operator ExternalSecrets
+)
+
+// ExternalSecretStore is the ClusterSecretStore generated ExternalSecrets read from
+const ExternalSecretStore = "platform-secrets"
+
+// SecretPlaceholder is the value of every key in generated Secret templates
+const SecretPlaceholder = "REPLACE_ME"
+
+// External Secrets Operator resources. Only the fields the generator sets are modeled.
+type (
+	externalSecret struct {
+		APIVersion string             `yaml:"apiVersion"`
+		Kind       string             `yaml:"kind"`
+		Metadata   objectMeta         `yaml:"metadata"`
+		Spec       externalSecretSpec `yaml:"spec"`
+	}
+
+	externalSecretSpec struct {
+		RefreshInterval string                 `yaml:"refreshInterval"`
+		SecretStoreRef  externalSecretStoreRef `yaml:"secretStoreRef"`
+		Target          externalSecretTarget   `yaml:"target"`
+		Data            []externalSecretData   `yaml:"data"`
+	}
+
+	externalSecretStoreRef struct {
+		Name string `yaml:"name"`
+		Kind string `yaml:"kind"`
+	}
+
+	externalSecretTarget struct {
+		Name string `yaml:"name"`
+	}
+
+	externalSecretData struct {
+		SecretKey string                  `yaml:"secretKey"`
+		RemoteRef externalSecretRemoteRef `yaml:"remoteRef"`
+	}
+
+	externalSecretRemoteRef struct {
+		Key      string `yaml:"key"`
+		Property string `yaml:"property"`
+	}
+)
+
+// GenerateSecretsManifests renders one manifest per secret named in
+// cfg.Service.Secrets. Provider "k8s" produces Secret templates whose values are
+// SecretPlaceholder; "eso" produces ExternalSecrets that sync each key from the
+// ExternalSecretStore, at remote key "<service>/<secret>".
+func GenerateSecretsManifests(cfg *PlatformConfig, provider string) ([]byte, error) {
+	if cfg.Service.Name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+	if len(cfg.Service.Secrets) == 0 {
+		return nil, fmt.Errorf("service %s references no secrets", cfg.Service.Name)
+	}
+
+	// Group keys by secret, in the order secrets are first referenced
+	var names []string
+	keys := make(map[string][]string)
+	for _, ref := range cfg.Service.Secrets {
+		if ref.Name == "" || ref.Key == "" {
+			return nil, fmt.Errorf("secret reference for %s needs a name and key", ref.EnvVar)
+		}
+		if _, seen := keys[ref.Name]; !seen {
+			names = append(names, ref.Name)
+		}
+		keys[ref.Name] = append(keys[ref.Name], ref.Key)
+	}
+
+	var objs []any
+	for _, name := range names {
+		switch provider {
+		case SecretProviderKubernetes:
+			data := make(map[string]string, len(keys[name]))
+			for _, key := range keys[name] {
+				data[key] = SecretPlaceholder
+			}
+			objs = append(objs, secret{
+				APIVersion: "v1",
+				Kind:       "Secret",
+				Metadata:   objectMeta{Name: name, Labels: podLabels(cfg)},
+				Type:       "Opaque",
+				StringData: data,
+			})
+		case SecretProviderExternalSecrets:
+			var data []externalSecretData
+			for _, key := range keys[name] {
+				data = append(data, externalSecretData{
+					SecretKey: key,
+					RemoteRef: externalSecretRemoteRef{Key: cfg.Service.Name + "/" + name, Property: key},
+				})
+			}
+			objs = append(objs, externalSecret{
+				APIVersion: "external-secrets.io/v1",
+				Kind:       "ExternalSecret",
+				Metadata:   objectMeta{Name: name, Labels: podLabels(cfg)},
+				Spec: externalSecretSpec{
+					RefreshInterval: "1h",
+					SecretStoreRef:  externalSecretStoreRef{Name: ExternalSecretStore, Kind: "ClusterSecretStore"},
+					Target:          externalSecretTarget{Name: name},
+					Data:            data,
+				},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported secret provider %q (supported: %s, %s)", provider, SecretProviderKubernetes, SecretProviderExternalSecrets)
+		}
+	}
+	return marshalManifests(objs...)
+}
@@ -0,0 +1,88 @@
+package codemapping
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func secretsConfig() *PlatformConfig {
+	cfg := validConfig()
+	cfg.Service.Name = "orders"
+	cfg.Service.Secrets = []SecretRef{
+		{Name: "orders-db", Key: "url", EnvVar: "DATABASE_URL"},
+		{Name: "stripe", Key: "api-key", EnvVar: "STRIPE_API_KEY"},
+		{Name: "orders-db", Key: "password", EnvVar: "DB_PASSWORD"},
+	}
+	return cfg
+}
+
+func TestGenerateSecretsManifests_Kubernetes(t *testing.T) {
+	data, err := GenerateSecretsManifests(secretsConfig(), SecretProviderKubernetes)
+	if err != nil {
+		t.Fatalf("GenerateSecretsManifests() error = %v", err)
+	}
+
+	parts := strings.Split(string(data), "---\n")
+	if len(parts) != 2 {
+		t.Fatalf("manifests = %d, want one Secret per secret name:\n%s", len(parts), data)
+	}
+	var secrets []secret
+	for _, part := range parts {
+		var s secret
+		decodeManifest(t, []byte(part), &s)
+		secrets = append(secrets, s)
+	}
+
+	if secrets[0].Kind != "Secret" || secrets[0].Metadata.Name != "orders-db" || secrets[0].Type != "Opaque" {
+		t.Errorf("first manifest = %s %s (%s), want Opaque Secret orders-db", secrets[0].Kind, secrets[0].Metadata.Name, secrets[0].Type)
+	}
+	want := map[string]string{"url": SecretPlaceholder, "password": SecretPlaceholder}
+	if !reflect.DeepEqual(secrets[0].StringData, want) {
+		t.Errorf("orders-db data = %v, want %v", secrets[0].StringData, want)
+	}
+	if secrets[1].Metadata.Name != "stripe" || secrets[1].StringData["api-key"] != SecretPlaceholder {
+		t.Errorf("second Secret = %s %v, want stripe with api-key", secrets[1].Metadata.Name, secrets[1].StringData)
+	}
+}
+
+func TestGenerateSecretsManifests_ExternalSecrets(t *testing.T) {
+	data, err := GenerateSecretsManifests(secretsConfig(), SecretProviderExternalSecrets)
+	if err != nil {
+		t.Fatalf("GenerateSecretsManifests() error = %v", err)
+	}
+
+	var first externalSecret
+	decodeManifest(t, []byte(strings.Split(string(data), "---\n")[0]), &first)
+	if first.Kind != "ExternalSecret" || first.APIVersion != "external-secrets.io/v1" {
+		t.Errorf("type = %s %s, want external-secrets.io/v1 ExternalSecret", first.APIVersion, first.Kind)
+	}
+	if first.Spec.SecretStoreRef != (externalSecretStoreRef{Name: ExternalSecretStore, Kind: "ClusterSecretStore"}) {
+		t.Errorf("secretStoreRef = %+v", first.Spec.SecretStoreRef)
+	}
+	if first.Spec.Target.Name != "orders-db" {
+		t.Errorf("target = %q, want orders-db", first.Spec.Target.Name)
+	}
+	want := []externalSecretData{
+		{SecretKey: "url", RemoteRef: externalSecretRemoteRef{Key: "orders/orders-db", Property: "url"}},
+		{SecretKey: "password", RemoteRef: externalSecretRemoteRef{Key: "orders/orders-db", Property: "password"}},
+	}
+	if !reflect.DeepEqual(first.Spec.Data, want) {
+		t.Errorf("data = %+v, want %+v", first.Spec.Data, want)
+	}
+}
+
+func TestGenerateSecretsManifests_Errors(t *testing.T) {
+	if _, err := GenerateSecretsManifests(secretsConfig(), "vault"); err == nil {
+		t.Error("GenerateSecretsManifests() expected error for unknown provider")
+	}
+	if _, err := GenerateSecretsManifests(validConfig(), SecretProviderKubernetes); err == nil {
+		t.Error("GenerateSecretsManifests() expected error without secret references")
+	}
+
+	cfg := secretsConfig()
+	cfg.Service.Secrets[1].Key = ""
+	if _, err := GenerateSecretsManifests(cfg, SecretProviderKubernetes); err == nil {
+		t.Error("GenerateSecretsManifests() expected error for a reference without a key")
+	}
+}
@@ -0,0 +1,153 @@
+package codemapping
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Runtime types a service can be deployed as, set in ServiceConfig.RuntimeType
+const (
+	RuntimeTypeContainer     = "container"
+	RuntimeTypeLambda        = "lambda"
+	RuntimeTypeCloudFunction = "cloud-function"
+	RuntimeTypeCloudRun      = "cloud-run"
+)
+
+// serverlessTimeoutSeconds is the request timeout of generated functions
+const serverlessTimeoutSeconds = 30
+
+var runtimeVersionPattern = regexp.MustCompile(`^([a-z]+)([0-9.]*)$`)
+
+// GenerateServerlessConfig renders cfg as a function deployment. Provider
+// CloudAWS produces an AWS SAM template with one HTTP API function; CloudGCP
+// produces a Cloud Functions (2nd gen) function resource. Memory comes from
+// cfg.Resources.Memory, and secret references become environment variables read
+// from the cloud's secret manager.
+func GenerateServerlessConfig(cfg *PlatformConfig, provider string) (map[string]interface{}, error) {
+	name := cfg.Service.Name
+	if name == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+	memory, err := parseMemoryBytes(cfg.Resources.Memory)
+	if err != nil {
+		return nil, err
+	}
+	match := runtimeVersionPattern.FindStringSubmatch(strings.ToLower(cfg.Service.Runtime))
+	if match == nil {
+		return nil, fmt.Errorf("unsupported runtime %q", cfg.Service.Runtime)
+	}
+	language, version := match[1], match[2]
+
+	switch provider {
+	case CloudAWS:
+		return samTemplate(cfg, language, version, memory/mebibyte)
+	case CloudGCP:
+		return cloudFunction(cfg, language, version, memory)
+	default:
+		return nil, fmt.Errorf("unsupported serverless provider %q (supported: %s, %s)", provider, CloudAWS, CloudGCP)
+	}
+}
+
+// samTemplate builds an AWS SAM template deploying the service as a Lambda function
+func samTemplate(cfg *PlatformConfig, language, version string, memoryMiB int64) (map[string]interface{}, error) {
+	var runtime, handler string
+	switch language {
+	case "go":
+		// Go functions ship a "bootstrap" binary on the OS-only runtime
+		runtime, handler = "provided.al2023", "bootstrap"
+	case "node", "nodejs":
+		runtime, handler = "nodejs"+version+".x", "index.handler"
+	case "python":
+		runtime, handler = "python"+version, "app.handler"
+	case "java":
+		runtime, handler = "java"+version, "com.example.Handler::handleRequest"
+	default:
+		return nil, fmt.Errorf("unsupported Lambda runtime %q", cfg.Service.Runtime)
+	}
+	if memoryMiB < 128 || memoryMiB > 10240 {
+		return nil, fmt.Errorf("Lambda memory must be between 128 and 10240 MiB, got %d", memoryMiB)
+	}
+
+	variables := map[string]interface{}{}
+	for _, ref := range cfg.Service.Secrets {
+		variables[ref.EnvVar] = fmt.Sprintf("{{resolve:secretsmanager:%s/%s:SecretString:%s}}", cfg.Service.Name, ref.Name, ref.Key)
+	}
+
+	properties := map[string]interface{}{
+		"FunctionName": cfg.Service.Name,
+		"Runtime":      runtime,
+		"Handler":      handler,
+		"CodeUri":      ".",
+		"MemorySize":   memoryMiB,
+		"Timeout":      serverlessTimeoutSeconds,
+		"Events": map[string]interface{}{
+			"Api": map[string]interface{}{"Type": "HttpApi"},
+		},
+	}
+	if len(variables) > 0 {
+		properties["Environment"] = map[string]interface{}{"Variables": variables}
+	}
+
+	return map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Transform":                "AWS::Serverless-2016-10-31",
+		"Resources": map[string]interface{}{
+			logicalID(cfg.Service.Name) + "Function": map[string]interface{}{
+				"Type":       "AWS::Serverless::Function",
+				"Properties": properties,
+			},
+		},
+	}, nil
+}
+
+// cloudFunction builds a Cloud Functions v2 function resource for the service
+func cloudFunction(cfg *PlatformConfig, language, version string, memoryBytes int64) (map[string]interface{}, error) {
+	version = strings.ReplaceAll(version, ".", "")
+	var runtime string
+	switch language {
+	case "go", "python", "java":
+		runtime = language + version
+	case "node", "nodejs":
+		runtime = "nodejs" + version
+	default:
+		return nil, fmt.Errorf("unsupported Cloud Functions runtime %q", cfg.Service.Runtime)
+	}
+
+	serviceConfig := map[string]interface{}{
+		"availableMemory":  formatMemory(memoryBytes),
+		"timeoutSeconds":   serverlessTimeoutSeconds,
+		"maxInstanceCount": cfg.Resources.Scaling.MaxReplicas,
+	}
+	var secrets []interface{}
+	for _, ref := range cfg.Service.Secrets {
+		// Secret Manager names cannot contain "/", so the key is folded into the name
+		secrets = append(secrets, map[string]interface{}{
+			"key":     ref.EnvVar,
+			"secret":  cfg.Service.Name + "-" + ref.Name + "-" + ref.Key,
+			"version": "latest",
+		})
+	}
+	if len(secrets) > 0 {
+		serviceConfig["secretEnvironmentVariables"] = secrets
+	}
+
+	return map[string]interface{}{
+		"name":        fmt.Sprintf("projects/%s/locations/REGION/functions/%s", GCPProjectPlaceholder, cfg.Service.Name),
+		"environment": "GEN_2",
+		"buildConfig": map[string]interface{}{
+			"runtime":    runtime,
+			"entryPoint": "Handler",
+		},
+		"serviceConfig": serviceConfig,
+	}, nil
+}
+
+// logicalID turns a service name like "orders-api" into a CloudFormation logical ID like "OrdersApi"
+func logicalID(name string) string {
+	var builder strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return builder.String()
+}
@@ -0,0 +1,97 @@
+package codemapping
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+const serverlessConfigJSON = `{
+  "service": {"name": "thumbnail-api", "template": "api", "runtime": "python3.12", "framework": "fastapi", "port": 8000,
+    "runtime_type": "lambda", "secrets": [{"name": "storage", "key": "token", "env_var": "STORAGE_TOKEN"}]},
+  "resources": {"cpu": "500m", "memory": "1Gi", "scaling": {"min_replicas": 1, "max_replicas": 20, "target_cpu_percent": 70}},
+  "monitoring": {"metrics": true, "logs": true, "traces": false},
+  "security": {"health_check": {"path": "/health", "port": 8000}}
+}`
+
+func generateServerlessService(t *testing.T) *PlatformConfig {
+	t.Helper()
+	mock := llm.NewMockClient(serverlessConfigJSON)
+	cfg, err := NewConfigGenerator(mock).Generate(context.Background(), &RepositoryAnalysis{
+		PrimaryLanguage:   "python",
+		DetectedFramework: "fastapi",
+		Files:             []string{"app.py", "requirements.txt"},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if !strings.Contains(mock.Requests()[0].UserPrompt, "benefit from serverless") {
+		t.Error("Generate() prompt should ask whether the service benefits from serverless")
+	}
+	if cfg.Service.RuntimeType != RuntimeTypeLambda {
+		t.Fatalf("RuntimeType = %q, want %q", cfg.Service.RuntimeType, RuntimeTypeLambda)
+	}
+	return cfg
+}
+
+func TestGenerateServerlessConfig_AWS(t *testing.T) {
+	template, err := GenerateServerlessConfig(generateServerlessService(t), CloudAWS)
+	if err != nil {
+		t.Fatalf("GenerateServerlessConfig() error = %v", err)
+	}
+
+	if template["Transform"] != "AWS::Serverless-2016-10-31" {
+		t.Errorf("Transform = %v, want the SAM transform", template["Transform"])
+	}
+	function, ok := template["Resources"].(map[string]interface{})["ThumbnailApiFunction"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Resources = %v, want ThumbnailApiFunction", template["Resources"])
+	}
+	if function["Type"] != "AWS::Serverless::Function" {
+		t.Errorf("Type = %v, want AWS::Serverless::Function", function["Type"])
+	}
+	props := function["Properties"].(map[string]interface{})
+	if props["Runtime"] != "python3.12" || props["MemorySize"] != int64(1024) {
+		t.Errorf("Runtime, MemorySize = %v, %v, want python3.12, 1024", props["Runtime"], props["MemorySize"])
+	}
+	variables := props["Environment"].(map[string]interface{})["Variables"].(map[string]interface{})
+	if got, want := variables["STORAGE_TOKEN"], "{{resolve:secretsmanager:thumbnail-api/storage:SecretString:token}}"; got != want {
+		t.Errorf("STORAGE_TOKEN = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateServerlessConfig_GCP(t *testing.T) {
+	function, err := GenerateServerlessConfig(generateServerlessService(t), CloudGCP)
+	if err != nil {
+		t.Fatalf("GenerateServerlessConfig() error = %v", err)
+	}
+
+	if name := function["name"].(string); !strings.HasSuffix(name, "/functions/thumbnail-api") {
+		t.Errorf("name = %q, want .../functions/thumbnail-api", name)
+	}
+	if runtime := function["buildConfig"].(map[string]interface{})["runtime"]; runtime != "python312" {
+		t.Errorf("runtime = %v, want python312", runtime)
+	}
+	service := function["serviceConfig"].(map[string]interface{})
+	if service["availableMemory"] != "1Gi" || service["maxInstanceCount"] != 20 {
+		t.Errorf("serviceConfig = %v, want 1Gi memory and 20 instances", service)
+	}
+	secrets := service["secretEnvironmentVariables"].([]interface{})
+	if len(secrets) != 1 || secrets[0].(map[string]interface{})["key"] != "STORAGE_TOKEN" {
+		t.Errorf("secretEnvironmentVariables = %v, want STORAGE_TOKEN", secrets)
+	}
+}
+
+func TestGenerateServerlessConfig_Errors(t *testing.T) {
+	if _, err := GenerateServerlessConfig(validConfig(), CloudAzure); err == nil {
+		t.Error("GenerateServerlessConfig() expected error for unsupported provider")
+	}
+
+	cfg := validConfig()
+	cfg.Service.Runtime = "elixir1.16"
+	if _, err := GenerateServerlessConfig(cfg, CloudAWS); err == nil {
+		t.Error("GenerateServerlessConfig() expected error for unsupported runtime")
+	}
+}
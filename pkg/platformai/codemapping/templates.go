@@ -0,0 +1,144 @@
+package codemapping
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Names of the built-in platform config templates
+const (
+	TemplateGoMicroservice = "GoMicroservice"
+	TemplateNodejsAPI      = "NodejsAPI"
+	TemplatePythonFastAPI  = "PythonFastAPI"
+	TemplateJavaSpringBoot = "JavaSpringBoot"
+)
+
+// TemplateLibrary holds baseline platform configs for common stacks.
+// ConfigGenerator starts from the template matching a repository and lets the
+// LLM adjust only what is specific to it.
+type TemplateLibrary struct {
+	templates map[string]PlatformConfig
+	languages map[string]string // Detected language -> template name
+}
+
+// NewTemplateLibrary creates a library with the built-in templates
+func NewTemplateLibrary() *TemplateLibrary {
+	return &TemplateLibrary{
+		templates: map[string]PlatformConfig{
+			TemplateGoMicroservice: {
+				Service: ServiceConfig{Template: "microservice", Runtime: "go1.22", Port: 8080},
+				Resources: ResourceConfig{
+					CPU:     "250m",
+					Memory:  "256Mi",
+					Scaling: ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPUPercent: 70},
+				},
+				Monitoring: MonitoringConfig{Metrics: true, Logs: true, Traces: true},
+				Security:   SecurityConfig{HealthCheck: HealthCheckConfig{Path: "/health", Port: 8080}},
+			},
+			TemplateNodejsAPI: {
+				Service: ServiceConfig{Template: "api", Runtime: "node20", Framework: "express", Port: 3000},
+				Resources: ResourceConfig{
+					CPU:     "500m",
+					Memory:  "512Mi",
+					Scaling: ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPUPercent: 70},
+				},
+				Monitoring: MonitoringConfig{Metrics: true, Logs: true, Traces: true},
+				Security:   SecurityConfig{HealthCheck: HealthCheckConfig{Path: "/health", Port: 3000}},
+			},
+			TemplatePythonFastAPI: {
+				Service: ServiceConfig{Template: "api", Runtime: "python3.12", Framework: "fastapi", Port: 8000},
+				Resources: ResourceConfig{
+					CPU:     "500m",
+					Memory:  "512Mi",
+					Scaling: ScalingConfig{MinReplicas: 2, MaxReplicas: 8, TargetCPUPercent: 65},
+				},
+				Monitoring: MonitoringConfig{Metrics: true, Logs: true, Traces: true},
+				Security:   SecurityConfig{HealthCheck: HealthCheckConfig{Path: "/health", Port: 8000}},
+			},
+			TemplateJavaSpringBoot: {
+				Service: ServiceConfig{Template: "microservice", Runtime: "java21", Framework: "spring-boot", Port: 8080},
+				Resources: ResourceConfig{
+					CPU:     "1000m",
+					Memory:  "1Gi",
+					Scaling: ScalingConfig{MinReplicas: 2, MaxReplicas: 6, TargetCPUPercent: 75},
+				},
+				Monitoring: MonitoringConfig{Metrics: true, Logs: true, Traces: true},
+				Security:   SecurityConfig{HealthCheck: HealthCheckConfig{Path: "/actuator/health", Port: 8080}},
+			},
+		},
+		languages: map[string]string{
+			"go":     TemplateGoMicroservice,
+			"nodejs": TemplateNodejsAPI,
+			"python": TemplatePythonFastAPI,
+			"java":   TemplateJavaSpringBoot,
+		},
+	}
+}
+
+// Names returns the template names in alphabetical order
+func (l *TemplateLibrary) Names() []string {
+	names := make([]string, 0, len(l.templates))
+	for name := range l.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns a copy of the named template
+func (l *TemplateLibrary) Get(name string) (*PlatformConfig, error) {
+	template, ok := l.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q (available: %s)", name, strings.Join(l.Names(), ", "))
+	}
+	return cloneConfig(template), nil
+}
+
+// Match returns a copy of the template for the analyzed repository's language,
+// with the detected framework filled in, or nil if no template covers it
+func (l *TemplateLibrary) Match(analysis *RepositoryAnalysis) (string, *PlatformConfig) {
+	name, ok := l.languages[strings.ToLower(analysis.PrimaryLanguage)]
+	if !ok {
+		return "", nil
+	}
+	config := cloneConfig(l.templates[name])
+	if analysis.DetectedFramework != "" && analysis.DetectedFramework != "none" {
+		config.Service.Framework = analysis.DetectedFramework
+	}
+	return name, config
+}
+
+// cloneConfig returns a copy of config that shares no pointers with it
+func cloneConfig(config PlatformConfig) *PlatformConfig {
+	if config.Database != nil {
+		database := *config.Database
+		config.Database = &database
+	}
+	if config.Cache != nil {
+		cache := *config.Cache
+		config.Cache = &cache
+	}
+	if config.Security.NetworkPolicy != nil {
+		policy := *config.Security.NetworkPolicy
+		policy.AllowedIngressPorts = slices.Clone(policy.AllowedIngressPorts)
+		policy.AllowedEgressHosts = slices.Clone(policy.AllowedEgressHosts)
+		config.Security.NetworkPolicy = &policy
+	}
+	if config.ServiceMesh != nil {
+		mesh := *config.ServiceMesh
+		mesh.TrafficWeight = maps.Clone(mesh.TrafficWeight)
+		config.ServiceMesh = &mesh
+	}
+	if config.Ingress != nil {
+		ingress := *config.Ingress
+		ingress.Annotations = maps.Clone(ingress.Annotations)
+		config.Ingress = &ingress
+	}
+	config.Service.Secrets = slices.Clone(config.Service.Secrets)
+	config.Annotations = maps.Clone(config.Annotations)
+	config.NodeSelector = maps.Clone(config.NodeSelector)
+	return &config
+}
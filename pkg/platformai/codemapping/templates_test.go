@@ -0,0 +1,103 @@
+package codemapping
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// quantityPattern matches Kubernetes CPU ("250m", "1") and memory ("512Mi", "1Gi") quantities
+var quantityPattern = regexp.MustCompile(`^([0-9]+)(m|Mi|Gi)?$`)
+
+func positiveQuantity(q string) bool {
+	match := quantityPattern.FindStringSubmatch(q)
+	if match == nil {
+		return false
+	}
+	n, err := strconv.Atoi(match[1])
+	return err == nil && n > 0
+}
+
+func TestModule_Templates(t *testing.T) {
+	m := NewModule(llm.NewMockClient(""))
+
+	names := m.ListTemplates()
+	want := []string{TemplateGoMicroservice, TemplateJavaSpringBoot, TemplateNodejsAPI, TemplatePythonFastAPI}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("ListTemplates() = %v, want %v", names, want)
+	}
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			config, err := m.GetTemplate(name)
+			if err != nil {
+				t.Fatalf("GetTemplate() error = %v", err)
+			}
+			res := config.Resources
+			if !positiveQuantity(res.CPU) || !strings.HasSuffix(res.Memory, "i") || !positiveQuantity(res.Memory) {
+				t.Errorf("resources = %q CPU, %q memory, want positive quantities", res.CPU, res.Memory)
+			}
+			scaling := res.Scaling
+			if scaling.MinReplicas <= 0 || scaling.MaxReplicas < scaling.MinReplicas {
+				t.Errorf("replicas = %d..%d, want 0 < min <= max", scaling.MinReplicas, scaling.MaxReplicas)
+			}
+			if scaling.TargetCPUPercent <= 0 || scaling.TargetCPUPercent > 100 {
+				t.Errorf("target CPU = %d%%, want 1-100", scaling.TargetCPUPercent)
+			}
+			if config.Service.Port <= 0 || config.Service.Runtime == "" || config.Security.HealthCheck.Path == "" {
+				t.Errorf("service = %+v, health check = %+v, want port, runtime, and path set", config.Service, config.Security.HealthCheck)
+			}
+
+			// Callers get copies
+			config.Resources.CPU = "changed"
+			if again, _ := m.GetTemplate(name); again.Resources.CPU == "changed" {
+				t.Error("GetTemplate() returned a shared config")
+			}
+		})
+	}
+
+	if _, err := m.GetTemplate("RubyOnRails"); err == nil {
+		t.Error("GetTemplate() expected error for unknown template")
+	}
+}
+
+func TestConfigGenerator_GenerateFromTemplate(t *testing.T) {
+	// The LLM only returns the repository-specific fields
+	mock := llm.NewMockClient(`{"service": {"name": "billing", "runtime": "go1.23", "framework": "gin", "port": 8080}}`)
+	generator := NewConfigGenerator(mock)
+
+	config, err := generator.Generate(context.Background(), testAnalysis())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if config.Service.Name != "billing" || config.Service.Runtime != "go1.23" {
+		t.Errorf("service = %+v, want LLM values", config.Service)
+	}
+	if config.Service.Template != "microservice" {
+		t.Errorf("service template = %q, want template value kept", config.Service.Template)
+	}
+	template, _ := NewTemplateLibrary().Get(TemplateGoMicroservice)
+	if config.Resources != template.Resources || config.Security != template.Security {
+		t.Errorf("resources = %+v, want template values %+v", config.Resources, template.Resources)
+	}
+
+	prompt := mock.Requests()[0].UserPrompt
+	if !strings.Contains(prompt, "Baseline Template (GoMicroservice)") || !strings.Contains(prompt, `"cpu":"250m"`) {
+		t.Errorf("user prompt does not include the matching template:\n%s", prompt)
+	}
+
+	unknown := testAnalysis()
+	unknown.PrimaryLanguage = "rust"
+	// Without a template to fill in the rest, the partial response fails validation
+	if _, err := generator.Generate(context.Background(), unknown); !errors.Is(err, ErrInvalidResponse) {
+		t.Errorf("Generate() error = %v, want ErrInvalidResponse", err)
+	}
+	if prompt := mock.Requests()[1].UserPrompt; strings.Contains(prompt, "Baseline Template") {
+		t.Error("user prompt includes a template for a language without one")
+	}
+}
@@ -0,0 +1,5 @@
+defmodule SampleApp do
+  @moduledoc """
+  SampleApp keeps the contexts that define the domain and business logic.
+  """
+end
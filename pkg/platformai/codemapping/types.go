@@ -9,71 +9,127 @@ type RepositoryAnalysis struct {
 	HasDockerfile     bool
 	DockerfileContent string
 	LanguageVersion   string
+
+	// HasGoWorkspace is set when the repository has a go.work file. Its modules'
+	// dependencies are merged into Dependencies.
+	HasGoWorkspace   bool
+	WorkspaceModules []string // Module directories named by go.work use directives, e.g. "./api"
+
+	// EnvVars holds variables declared in .env files. Values of secret-looking
+	// keys are replaced with "*****".
+	EnvVars map[string]string
+
+	// GitSummary describes recent commit activity; nil when the repository has
+	// no git history or git is unavailable
+	GitSummary *GitSummary
 }
 
 // PlatformConfig represents the generated platform configuration
 type PlatformConfig struct {
-	Service    ServiceConfig    `yaml:"service" json:"service"`
-	Resources  ResourceConfig   `yaml:"resources" json:"resources"`
-	Database   *DatabaseConfig  `yaml:"database,omitempty" json:"database,omitempty"`
-	Cache      *CacheConfig     `yaml:"cache,omitempty" json:"cache,omitempty"`
-	Monitoring MonitoringConfig `yaml:"monitoring" json:"monitoring"`
-	Security   SecurityConfig   `yaml:"security" json:"security"`
+	Service    ServiceConfig    `yaml:"service" json:"service" toml:"service"`
+	Resources  ResourceConfig   `yaml:"resources" json:"resources" toml:"resources"`
+	Database   *DatabaseConfig  `yaml:"database,omitempty" json:"database,omitempty" toml:"database,omitempty"`
+	Cache      *CacheConfig     `yaml:"cache,omitempty" json:"cache,omitempty" toml:"cache,omitempty"`
+	Monitoring MonitoringConfig `yaml:"monitoring" json:"monitoring" toml:"monitoring"`
+	Security   SecurityConfig   `yaml:"security" json:"security" toml:"security"`
+
+	ServiceMesh *ServiceMeshConfig `yaml:"service_mesh,omitempty" json:"service_mesh,omitempty" toml:"service_mesh,omitempty"`
+	Ingress     *IngressConfig     `yaml:"ingress,omitempty" json:"ingress,omitempty" toml:"ingress,omitempty"`
+
+	// Cloud-specific settings, filled in by GenerateCloudConfig
+	Cloud        string            `yaml:"cloud,omitempty" json:"cloud,omitempty" toml:"cloud,omitempty"`                         // "aws", "gcp", or "azure"
+	Annotations  map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty" toml:"annotations,omitempty"`       // Service account annotations
+	NodeSelector map[string]string `yaml:"node_selector,omitempty" json:"node_selector,omitempty" toml:"node_selector,omitempty"` // Node labels pods are scheduled on
+}
+
+// ServiceMeshConfig contains service mesh traffic management configuration
+type ServiceMeshConfig struct {
+	Type          string         `yaml:"type" json:"type" toml:"type"`                                                             // "istio"
+	TrafficWeight map[string]int `yaml:"traffic_weight,omitempty" json:"traffic_weight,omitempty" toml:"traffic_weight,omitempty"` // Subset (pod "version" label) -> percent of traffic
+	RetryAttempts int            `yaml:"retry_attempts" json:"retry_attempts" toml:"retry_attempts"`                               // Retries per request; 0 disables retries
+}
+
+// IngressConfig exposes the service outside the cluster through an Ingress
+type IngressConfig struct {
+	Host        string            `yaml:"host" json:"host" toml:"host"`                                                    // External hostname, e.g. "orders.example.com"
+	Path        string            `yaml:"path,omitempty" json:"path,omitempty" toml:"path,omitempty"`                      // Path prefix routed to the service (default: "/")
+	TLSSecret   string            `yaml:"tls_secret,omitempty" json:"tls_secret,omitempty" toml:"tls_secret,omitempty"`    // Secret holding the TLS certificate; enables HTTPS
+	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty" toml:"annotations,omitempty"` // Ingress controller annotations
 }
 
 // ServiceConfig contains service configuration
 type ServiceConfig struct {
-	Name      string `yaml:"name" json:"name"`
-	Template  string `yaml:"template" json:"template"`
-	Runtime   string `yaml:"runtime" json:"runtime"`
-	Framework string `yaml:"framework" json:"framework"`
-	Port      int    `yaml:"port" json:"port"`
+	Name      string `yaml:"name" json:"name" toml:"name"`
+	Template  string `yaml:"template" json:"template" toml:"template"`
+	Runtime   string `yaml:"runtime" json:"runtime" toml:"runtime"`
+	Framework string `yaml:"framework" json:"framework" toml:"framework"`
+	Port      int    `yaml:"port" json:"port" toml:"port"`
+
+	// RuntimeType is how the service is deployed: "container" (the default when
+	// empty), "lambda", "cloud-function", or "cloud-run"
+	RuntimeType string `yaml:"runtime_type,omitempty" json:"runtime_type,omitempty" toml:"runtime_type,omitempty"`
+
+	Secrets []SecretRef `yaml:"secrets,omitempty" json:"secrets,omitempty" toml:"secrets,omitempty"`
+}
+
+// SecretRef exposes one key of a secret to the service as an environment variable
+type SecretRef struct {
+	Name   string `yaml:"name" json:"name" toml:"name"`          // Secret name, e.g. "orders-db"
+	Key    string `yaml:"key" json:"key" toml:"key"`             // Key within the secret, e.g. "url"
+	EnvVar string `yaml:"env_var" json:"env_var" toml:"env_var"` // Environment variable, e.g. "DATABASE_URL"
 }
 
 // ResourceConfig contains resource allocation configuration
 type ResourceConfig struct {
-	CPU     string        `yaml:"cpu" json:"cpu"`
-	Memory  string        `yaml:"memory" json:"memory"`
-	Scaling ScalingConfig `yaml:"scaling" json:"scaling"`
+	CPU     string        `yaml:"cpu" json:"cpu" toml:"cpu"`
+	Memory  string        `yaml:"memory" json:"memory" toml:"memory"`
+	Scaling ScalingConfig `yaml:"scaling" json:"scaling" toml:"scaling"`
 }
 
 // ScalingConfig contains auto-scaling configuration
 type ScalingConfig struct {
-	MinReplicas      int `yaml:"min_replicas" json:"min_replicas"`
-	MaxReplicas      int `yaml:"max_replicas" json:"max_replicas"`
-	TargetCPUPercent int `yaml:"target_cpu_percent" json:"target_cpu_percent"`
+	MinReplicas      int `yaml:"min_replicas" json:"min_replicas" toml:"min_replicas"`
+	MaxReplicas      int `yaml:"max_replicas" json:"max_replicas" toml:"max_replicas"`
+	TargetCPUPercent int `yaml:"target_cpu_percent" json:"target_cpu_percent" toml:"target_cpu_percent"`
 }
 
 // DatabaseConfig contains database configuration
 type DatabaseConfig struct {
-	Type    string `yaml:"type" json:"type"`
-	Version string `yaml:"version" json:"version"`
-	Storage string `yaml:"storage" json:"storage"`
+	Type    string `yaml:"type" json:"type" toml:"type"`
+	Version string `yaml:"version" json:"version" toml:"version"`
+	Storage string `yaml:"storage" json:"storage" toml:"storage"`
 }
 
 // CacheConfig contains cache configuration
 type CacheConfig struct {
-	Type    string `yaml:"type" json:"type"`
-	Version string `yaml:"version" json:"version"`
-	Memory  string `yaml:"memory" json:"memory"`
+	Type    string `yaml:"type" json:"type" toml:"type"`
+	Version string `yaml:"version" json:"version" toml:"version"`
+	Memory  string `yaml:"memory" json:"memory" toml:"memory"`
 }
 
 // MonitoringConfig contains monitoring configuration
 type MonitoringConfig struct {
-	Metrics bool `yaml:"metrics" json:"metrics"`
-	Logs    bool `yaml:"logs" json:"logs"`
-	Traces  bool `yaml:"traces" json:"traces"`
+	Metrics bool `yaml:"metrics" json:"metrics" toml:"metrics"`
+	Logs    bool `yaml:"logs" json:"logs" toml:"logs"`
+	Traces  bool `yaml:"traces" json:"traces" toml:"traces"`
 }
 
 // SecurityConfig contains security configuration
 type SecurityConfig struct {
-	HealthCheck HealthCheckConfig `yaml:"health_check" json:"health_check"`
+	HealthCheck   HealthCheckConfig    `yaml:"health_check" json:"health_check" toml:"health_check"`
+	NetworkPolicy *NetworkPolicyConfig `yaml:"network_policy,omitempty" json:"network_policy,omitempty" toml:"network_policy,omitempty"`
+}
+
+// NetworkPolicyConfig restricts the traffic a service accepts and sends
+type NetworkPolicyConfig struct {
+	AllowedIngressPorts []int    `yaml:"allowed_ingress_ports" json:"allowed_ingress_ports" toml:"allowed_ingress_ports"` // Ports accepted in addition to the service port
+	AllowedEgressHosts  []string `yaml:"allowed_egress_hosts" json:"allowed_egress_hosts" toml:"allowed_egress_hosts"`    // IPs, CIDRs, or hostnames the service may call
 }
 
 // HealthCheckConfig contains health check configuration
 type HealthCheckConfig struct {
-	Path string `yaml:"path" json:"path"`
-	Port int    `yaml:"port" json:"port"`
+	Path string `yaml:"path" json:"path" toml:"path"`
+	Port int    `yaml:"port" json:"port" toml:"port"`
 }
 
 // Recommendation represents an actionable recommendation
@@ -81,4 +137,6 @@ type Recommendation struct {
 	Level   string `json:"level"` // "info", "warning", "critical"
 	Title   string `json:"title"`
 	Message string `json:"message"`
+	Action  string `json:"action,omitempty"`   // Machine-readable remediation hint, e.g. "add-dockerfile"
+	DocsURL string `json:"docs_url,omitempty"` // Documentation for the remediation
 }
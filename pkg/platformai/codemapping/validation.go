@@ -0,0 +1,131 @@
+package codemapping
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	cpuPattern    = regexp.MustCompile(`^\d+m$|^\d+$`)
+	memoryPattern = regexp.MustCompile(`^\d+(Mi|Gi|Ki)$`)
+)
+
+// ValidationError is a semantic problem with one field of a PlatformConfig
+type ValidationError struct {
+	Field   string // JSON path of the field, e.g. "resources.scaling.min_replicas"
+	Message string
+}
+
+// Error implements error
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidatePlatformConfig checks the business rules JSON decoding cannot: replica
+// bounds, CPU target, ports, runtime type, and resource quantity formats. It returns nil for a
+// valid config.
+func ValidatePlatformConfig(cfg *PlatformConfig) []ValidationError {
+	var errs []ValidationError
+	add := func(field, format string, args ...any) {
+		errs = append(errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	scaling := cfg.Resources.Scaling
+	if scaling.MinReplicas < 1 {
+		add("resources.scaling.min_replicas", "must be at least 1, got %d", scaling.MinReplicas)
+	}
+	if scaling.MaxReplicas < scaling.MinReplicas {
+		add("resources.scaling.max_replicas", "must be at least min_replicas (%d), got %d", scaling.MinReplicas, scaling.MaxReplicas)
+	}
+	if scaling.TargetCPUPercent < 1 || scaling.TargetCPUPercent > 100 {
+		add("resources.scaling.target_cpu_percent", "must be between 1 and 100, got %d", scaling.TargetCPUPercent)
+	}
+
+	if !validPort(cfg.Service.Port) {
+		add("service.port", "must be between 1 and 65535, got %d", cfg.Service.Port)
+	}
+	if port := cfg.Security.HealthCheck.Port; port != 0 && !validPort(port) {
+		add("security.health_check.port", "must be between 1 and 65535, got %d", port)
+	}
+
+	switch cfg.Service.RuntimeType {
+	case "", RuntimeTypeContainer, RuntimeTypeLambda, RuntimeTypeCloudFunction, RuntimeTypeCloudRun:
+	default:
+		add("service.runtime_type", "must be container, lambda, cloud-function, or cloud-run, got %q", cfg.Service.RuntimeType)
+	}
+
+	if !cpuPattern.MatchString(cfg.Resources.CPU) {
+		add("resources.cpu", "must be whole cores or millicores like \"500m\", got %q", cfg.Resources.CPU)
+	}
+	if !memoryPattern.MatchString(cfg.Resources.Memory) {
+		add("resources.memory", "must be a Ki, Mi, or Gi quantity like \"512Mi\", got %q", cfg.Resources.Memory)
+	}
+	return errs
+}
+
+// validPort reports whether port is a usable TCP port number
+func validPort(port int) bool {
+	return port >= 1 && port <= 65535
+}
+
+// parseMillicores converts a CPU quantity such as "500m" or "2" to millicores
+func parseMillicores(cpu string) (int, error) {
+	if !cpuPattern.MatchString(cpu) {
+		return 0, fmt.Errorf("invalid CPU quantity %q", cpu)
+	}
+	if n, ok := strings.CutSuffix(cpu, "m"); ok {
+		return strconv.Atoi(n)
+	}
+	cores, err := strconv.Atoi(cpu)
+	return cores * 1000, err
+}
+
+// Binary memory units accepted by memoryPattern, in bytes
+const (
+	kibibyte = 1 << 10
+	mebibyte = 1 << 20
+	gibibyte = 1 << 30
+)
+
+// parseMemoryBytes converts a memory quantity such as "512Mi" or "1Gi" to bytes
+func parseMemoryBytes(memory string) (int64, error) {
+	if !memoryPattern.MatchString(memory) {
+		return 0, fmt.Errorf("invalid memory quantity %q", memory)
+	}
+	unit := int64(kibibyte)
+	switch memory[len(memory)-2:] {
+	case "Mi":
+		unit = mebibyte
+	case "Gi":
+		unit = gibibyte
+	}
+	n, err := strconv.ParseInt(memory[:len(memory)-2], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory quantity %q: %w", memory, err)
+	}
+	return n * unit, nil
+}
+
+// formatMillicores renders millicores as whole cores when possible, like "2" or "2100m"
+func formatMillicores(millicores int64) string {
+	if millicores%1000 == 0 {
+		return strconv.FormatInt(millicores/1000, 10)
+	}
+	return strconv.FormatInt(millicores, 10) + "m"
+}
+
+// formatMemory renders a byte count in the largest binary unit that divides it
+// exactly, like "1Gi" or "2151Mi"
+func formatMemory(bytes int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"Gi", gibibyte}, {"Mi", mebibyte}, {"Ki", kibibyte}} {
+		if bytes != 0 && bytes%unit.size == 0 {
+			return strconv.FormatInt(bytes/unit.size, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(bytes, 10)
+}
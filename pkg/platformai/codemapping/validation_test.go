@@ -0,0 +1,85 @@
+package codemapping
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+func validConfig() *PlatformConfig {
+	return &PlatformConfig{
+		Service: ServiceConfig{Name: "api", Port: 8080},
+		Resources: ResourceConfig{
+			CPU:     "500m",
+			Memory:  "512Mi",
+			Scaling: ScalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetCPUPercent: 70},
+		},
+		Security: SecurityConfig{HealthCheck: HealthCheckConfig{Path: "/health", Port: 8080}},
+	}
+}
+
+func TestValidatePlatformConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*PlatformConfig)
+		field  string // Expected failing field; empty means valid
+	}{
+		{"valid", func(c *PlatformConfig) {}, ""},
+		{"min replicas 1", func(c *PlatformConfig) { c.Resources.Scaling.MinReplicas = 1 }, ""},
+		{"min replicas 0", func(c *PlatformConfig) { c.Resources.Scaling.MinReplicas = 0 }, "resources.scaling.min_replicas"},
+		{"max equals min", func(c *PlatformConfig) { c.Resources.Scaling.MaxReplicas = 2 }, ""},
+		{"max below min", func(c *PlatformConfig) { c.Resources.Scaling.MaxReplicas = 1 }, "resources.scaling.max_replicas"},
+		{"target cpu 1", func(c *PlatformConfig) { c.Resources.Scaling.TargetCPUPercent = 1 }, ""},
+		{"target cpu 100", func(c *PlatformConfig) { c.Resources.Scaling.TargetCPUPercent = 100 }, ""},
+		{"target cpu 0", func(c *PlatformConfig) { c.Resources.Scaling.TargetCPUPercent = 0 }, "resources.scaling.target_cpu_percent"},
+		{"target cpu 101", func(c *PlatformConfig) { c.Resources.Scaling.TargetCPUPercent = 101 }, "resources.scaling.target_cpu_percent"},
+		{"port 65535", func(c *PlatformConfig) { c.Service.Port = 65535 }, ""},
+		{"port 0", func(c *PlatformConfig) { c.Service.Port = 0 }, "service.port"},
+		{"port 65536", func(c *PlatformConfig) { c.Service.Port = 65536 }, "service.port"},
+		{"health check port unset", func(c *PlatformConfig) { c.Security.HealthCheck.Port = 0 }, ""},
+		{"health check port 70000", func(c *PlatformConfig) { c.Security.HealthCheck.Port = 70000 }, "security.health_check.port"},
+		{"cpu whole cores", func(c *PlatformConfig) { c.Resources.CPU = "2" }, ""},
+		{"cpu fractional cores", func(c *PlatformConfig) { c.Resources.CPU = "0.5" }, "resources.cpu"},
+		{"cpu empty", func(c *PlatformConfig) { c.Resources.CPU = "" }, "resources.cpu"},
+		{"memory Gi", func(c *PlatformConfig) { c.Resources.Memory = "1Gi" }, ""},
+		{"memory Ki", func(c *PlatformConfig) { c.Resources.Memory = "65536Ki" }, ""},
+		{"memory MB", func(c *PlatformConfig) { c.Resources.Memory = "512MB" }, "resources.memory"},
+		{"memory without unit", func(c *PlatformConfig) { c.Resources.Memory = "512" }, "resources.memory"},
+		{"runtime type lambda", func(c *PlatformConfig) { c.Service.RuntimeType = RuntimeTypeLambda }, ""},
+		{"runtime type unknown", func(c *PlatformConfig) { c.Service.RuntimeType = "vm" }, "service.runtime_type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			errs := ValidatePlatformConfig(cfg)
+
+			if tt.field == "" {
+				if len(errs) != 0 {
+					t.Errorf("ValidatePlatformConfig() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Field != tt.field {
+				t.Errorf("ValidatePlatformConfig() = %v, want one error for %s", errs, tt.field)
+			}
+		})
+	}
+}
+
+func TestConfigGenerator_GenerateInvalidConfig(t *testing.T) {
+	invalid := strings.Replace(testConfigJSON, `"min_replicas": 2, "max_replicas": 10`, `"min_replicas": 5, "max_replicas": 3`, 1)
+	generator := NewConfigGenerator(llm.NewMockClient(invalid))
+
+	_, err := generator.Generate(context.Background(), testAnalysis())
+	if !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("Generate() error = %v, want ErrInvalidResponse", err)
+	}
+	if !strings.Contains(err.Error(), "resources.scaling.max_replicas") {
+		t.Errorf("Generate() error = %v, want the failing field named", err)
+	}
+}
@@ -1,24 +1,57 @@
 package platformai
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
 	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds SDK configuration
 type Config struct {
-	LLM LLMConfig
-	RAG *rag.Config // Optional RAG configuration
+	LLM      LLMConfig     `yaml:"llm"`
+	RAG      *rag.Config   `yaml:"rag,omitempty"` // Optional RAG configuration
+	Timeouts TimeoutConfig `yaml:"timeouts"`
 }
 
+// TimeoutConfig bounds SDK operations by type. Validate fills in zero values.
+type TimeoutConfig struct {
+	GenerateTimeout  time.Duration `yaml:"generate"`  // Per LLM API call (default: 2m)
+	EmbeddingTimeout time.Duration `yaml:"embedding"` // Per embedding API call, unless rag.timeout is set (default: 30s)
+	AnalyzeTimeout   time.Duration `yaml:"analyze"`   // Per CodeMapping().Analyze call (default: 5m)
+}
+
+// Default operation timeouts applied by Config.Validate
+const (
+	DefaultGenerateTimeout  = 2 * time.Minute
+	DefaultEmbeddingTimeout = 30 * time.Second
+	DefaultAnalyzeTimeout   = 5 * time.Minute
+)
+
 // LLMConfig holds LLM provider configuration
 type LLMConfig struct {
-	Provider    string // "anthropic"
-	APIKey      string
-	Model       string  // "claude-sonnet-4-5-20250929"
-	Temperature float32 // default: 0.3
-	MaxTokens   int     // default: 4096
+	Provider    string  `yaml:"provider"` // "anthropic"
+	APIKey      string  `yaml:"api_key"`
+	Model       string  `yaml:"model"`       // "claude-sonnet-4-5-20250929"
+	Temperature float32 `yaml:"temperature"` // default: 0.3
+	MaxTokens   int     `yaml:"max_tokens"`  // default: 4096
+
+	// SystemPromptPrefix is prepended to the system prompt of every LLM request
+	SystemPromptPrefix string `yaml:"system_prompt_prefix"`
+
+	ProxyURL string `yaml:"proxy_url"` // Optional HTTP(S) proxy; empty uses HTTP_PROXY/HTTPS_PROXY
+
+	// Deprecated: install the proxy's CA certificate instead; this option will be removed.
+	TLSInsecureSkipVerify bool `yaml:"tls_insecure_skip_verify"`
+
+	TLS llm.TLSConfig `yaml:"tls"` // Optional mutual TLS client certificate
+
+	ConnectionPool llm.ConnectionPoolConfig `yaml:"connection_pool"` // HTTP connection pool limits
 }
 
 // Validate validates the configuration
@@ -40,6 +73,83 @@ func (c *Config) Validate() error {
 	if c.LLM.MaxTokens == 0 {
 		c.LLM.MaxTokens = 4096
 	}
+	if c.Timeouts.GenerateTimeout <= 0 {
+		c.Timeouts.GenerateTimeout = DefaultGenerateTimeout
+	}
+	if c.Timeouts.EmbeddingTimeout <= 0 {
+		c.Timeouts.EmbeddingTimeout = DefaultEmbeddingTimeout
+	}
+	if c.Timeouts.AnalyzeTimeout <= 0 {
+		c.Timeouts.AnalyzeTimeout = DefaultAnalyzeTimeout
+	}
 
 	return nil
 }
+
+// LoadConfig reads a YAML or JSON config file. The result is not validated.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return parseConfig(data)
+}
+
+func parseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return &cfg, nil
+}
+
+// DefaultPollInterval is how often ConfigWatcher checks the config file when PollInterval is unset
+const DefaultPollInterval = 30 * time.Second
+
+// ConfigWatcher polls a config file for changes. The zero value is ready to use.
+type ConfigWatcher struct {
+	PollInterval time.Duration // default: 30s
+}
+
+// Watch calls onChange with the parsed config whenever the SHA-256 of the file at
+// path changes. It blocks until ctx is done and returns ctx.Err(). The file must be
+// readable when Watch starts; later read or parse failures are skipped until the
+// contents change again.
+func (w *ConfigWatcher) Watch(ctx context.Context, path string, onChange func(*Config)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	last := sha256.Sum256(data)
+
+	interval := w.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if sum == last {
+			continue
+		}
+		last = sum
+
+		cfg, err := parseConfig(data)
+		if err != nil {
+			continue
+		}
+		onChange(cfg)
+	}
+}
@@ -0,0 +1,206 @@
+package platformai
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `llm:
+  provider: anthropic
+  api_key: key-1
+  max_tokens: 1024
+rag:
+  embedding_provider: openai
+  api_key: embed-key
+  freshness:
+    max_age: 24h
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.LLM.Provider != "anthropic" || cfg.LLM.APIKey != "key-1" || cfg.LLM.MaxTokens != 1024 {
+		t.Errorf("LoadConfig() LLM = %+v", cfg.LLM)
+	}
+	if cfg.RAG == nil || cfg.RAG.EmbeddingProvider != "openai" || cfg.RAG.Freshness.MaxAge != 24*time.Hour {
+		t.Errorf("LoadConfig() RAG = %+v", cfg.RAG)
+	}
+
+	if _, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("LoadConfig() expected error for missing file")
+	}
+}
+
+func TestConfigWatcher_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"llm": {"provider": "anthropic", "api_key": "key-1"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &ConfigWatcher{PollInterval: 50 * time.Millisecond}
+	changes := make(chan *Config, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Watch(ctx, path, func(cfg *Config) { changes <- cfg })
+	}()
+
+	// Let the watcher hash the original contents before changing them
+	time.Sleep(w.PollInterval)
+	if err := os.WriteFile(path, []byte(`{"llm": {"provider": "anthropic", "api_key": "key-2"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case cfg := <-changes:
+		if cfg.LLM.APIKey != "key-2" {
+			t.Errorf("onChange() APIKey = %q, want key-2", cfg.LLM.APIKey)
+		}
+	case <-time.After(2 * w.PollInterval):
+		t.Fatal("onChange was not called within 2 * PollInterval")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch() error = %v, want context.Canceled", err)
+	}
+
+	if err := w.Watch(context.Background(), filepath.Join(t.TempDir(), "missing.json"), func(*Config) {}); err == nil {
+		t.Error("Watch() expected error for missing file")
+	}
+}
+
+func TestSDK_ApplyConfig(t *testing.T) {
+	cfg := &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "key-1"}}
+	sdk, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	original := sdk.provider()
+
+	// Key-only change rotates the existing client
+	next := &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "key-2"}}
+	if err := sdk.ApplyConfig(next); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if sdk.provider() != original {
+		t.Error("ApplyConfig() replaced the LLM client for a key-only change")
+	}
+
+	// Other changes create a new client and RAG module
+	next = &Config{
+		LLM: LLMConfig{Provider: "anthropic", APIKey: "key-2", Model: "claude-haiku-4-5"},
+		RAG: &rag.Config{EmbeddingProvider: "openai", APIKey: "embed-key"},
+	}
+	if err := sdk.ApplyConfig(next); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if sdk.provider() == original {
+		t.Error("ApplyConfig() kept the LLM client after a model change")
+	}
+	if sdk.RAG() == nil {
+		t.Error("ApplyConfig() did not create the RAG module")
+	}
+	if got := sdk.model(); got != "claude-haiku-4-5" {
+		t.Errorf("model() = %q, want claude-haiku-4-5", got)
+	}
+
+	// Invalid config leaves the SDK unchanged
+	if err := sdk.ApplyConfig(&Config{LLM: LLMConfig{Provider: "anthropic"}}); err == nil {
+		t.Error("ApplyConfig() expected error for missing API key")
+	}
+	if got := sdk.model(); got != "claude-haiku-4-5" {
+		t.Errorf("model() after failed apply = %q, want claude-haiku-4-5", got)
+	}
+}
+
+func TestSDK_ApplyConfigKeepsDocuments(t *testing.T) {
+	ctx := context.Background()
+	cfg := &Config{
+		LLM: LLMConfig{Provider: "anthropic", APIKey: "key"},
+		RAG: &rag.Config{EmbeddingProvider: "openai", APIKey: "embed-key"},
+	}
+	sdk, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	sdk.RAG().SetEmbeddingProvider(rag.NewMockEmbeddingProvider(4))
+	if err := sdk.RAG().AddDocument(ctx, "runbook", "restart the api pods", nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+
+	for _, next := range []*Config{
+		{LLM: cfg.LLM, RAG: cfg.RAG, Timeouts: TimeoutConfig{EmbeddingTimeout: time.Minute}},
+		{LLM: cfg.LLM, RAG: &rag.Config{EmbeddingProvider: "openai", APIKey: "embed-key", SimilarityMetric: rag.DotProduct}},
+	} {
+		if err := sdk.ApplyConfig(next); err != nil {
+			t.Fatalf("ApplyConfig() error = %v", err)
+		}
+		if n, err := sdk.RAG().Count(ctx); err != nil || n != 1 {
+			t.Errorf("Count() after ApplyConfig = %d, %v; want 1", n, err)
+		}
+	}
+}
+
+func TestSDK_CodeMappingCached(t *testing.T) {
+	cfg := &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "key"}}
+	sdk, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	module := sdk.CodeMapping()
+	if sdk.CodeMapping() != module {
+		t.Error("CodeMapping() created a new module on every call")
+	}
+	if err := sdk.ApplyConfig(&Config{LLM: cfg.LLM, Timeouts: TimeoutConfig{GenerateTimeout: time.Minute}}); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if sdk.CodeMapping() != module {
+		t.Error("ApplyConfig() replaced the code mapping module without an analyze timeout change")
+	}
+	if err := sdk.ApplyConfig(&Config{LLM: cfg.LLM, Timeouts: TimeoutConfig{AnalyzeTimeout: time.Minute}}); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+	if sdk.CodeMapping() == module {
+		t.Error("ApplyConfig() kept the code mapping module after an analyze timeout change")
+	}
+}
+
+func TestConfig_ValidateTimeouts(t *testing.T) {
+	cfg := &Config{
+		LLM:      LLMConfig{Provider: "anthropic", APIKey: "key"},
+		RAG:      &rag.Config{EmbeddingProvider: "openai"},
+		Timeouts: TimeoutConfig{EmbeddingTimeout: 5 * time.Second},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	want := TimeoutConfig{GenerateTimeout: DefaultGenerateTimeout, EmbeddingTimeout: 5 * time.Second, AnalyzeTimeout: DefaultAnalyzeTimeout}
+	if cfg.Timeouts != want {
+		t.Errorf("Timeouts = %+v, want %+v", cfg.Timeouts, want)
+	}
+	if got := llmConfig(cfg).Timeout; got != DefaultGenerateTimeout {
+		t.Errorf("LLM timeout = %v, want %v", got, DefaultGenerateTimeout)
+	}
+	if got := ragConfig(cfg).Timeout; got != 5*time.Second {
+		t.Errorf("embedding timeout = %v, want 5s", got)
+	}
+
+	cfg.RAG.Timeout = time.Second
+	if got := ragConfig(cfg).Timeout; got != time.Second {
+		t.Errorf("embedding timeout with rag.timeout set = %v, want 1s", got)
+	}
+}
@@ -1,24 +1,66 @@
 package platformai
 
-import "errors"
+import "github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+
+// SDKError is an error with a machine-readable code and, for API failures, the
+// HTTP status. Use errors.As or AsSDKError to inspect it.
+type SDKError = llm.SDKError
+
+// Error codes of the SDK's sentinel errors. API failures use the llm codes,
+// prefixed with the provider, e.g. "anthropic:rate_limit".
+const (
+	ErrCodeInvalidConfig    = "invalid_config"
+	ErrCodeGeneration       = "generation_failed"
+	ErrCodeAnalysisFailed   = "analysis_failed"
+	ErrCodeConfigGeneration = "config_generation_failed"
+	ErrCodeInvalidResponse  = "invalid_response"
+	ErrCodeRepoNotFound     = "repository_not_found"
+	ErrCodeShutdown         = "shutdown"
+	ErrCodeHealthCheck      = "health_check_failed"
+
+	ErrCodeRateLimit      = llm.ErrCodeRateLimit
+	ErrCodeAuthentication = llm.ErrCodeAuthentication
+	ErrCodeOverloaded     = llm.ErrCodeOverloaded
+)
 
 // Common error types for the Platform AI SDK
 var (
 	// ErrInvalidConfig indicates that the provided configuration is invalid
-	ErrInvalidConfig = errors.New("invalid configuration")
+	ErrInvalidConfig = llm.NewSDKError(ErrCodeInvalidConfig, "invalid configuration")
 
 	// ErrLLMGeneration indicates that LLM generation failed
-	ErrLLMGeneration = errors.New("LLM generation failed")
+	ErrLLMGeneration = llm.NewSDKError(ErrCodeGeneration, "LLM generation failed")
 
 	// ErrAnalysisFailed indicates that repository analysis failed
-	ErrAnalysisFailed = errors.New("repository analysis failed")
+	ErrAnalysisFailed = llm.NewSDKError(ErrCodeAnalysisFailed, "repository analysis failed")
 
 	// ErrConfigGeneration indicates that config generation failed
-	ErrConfigGeneration = errors.New("config generation failed")
+	ErrConfigGeneration = llm.NewSDKError(ErrCodeConfigGeneration, "config generation failed")
 
 	// ErrInvalidResponse indicates that the LLM response was invalid
-	ErrInvalidResponse = errors.New("invalid LLM response")
+	ErrInvalidResponse = llm.NewSDKError(ErrCodeInvalidResponse, "invalid LLM response")
 
 	// ErrRepositoryNotFound indicates that the repository path does not exist
-	ErrRepositoryNotFound = errors.New("repository not found")
+	ErrRepositoryNotFound = llm.NewSDKError(ErrCodeRepoNotFound, "repository not found")
+
+	// ErrSDKShutdown indicates that the SDK is shutting down and no longer accepts calls
+	ErrSDKShutdown = llm.NewSDKError(ErrCodeShutdown, "SDK is shut down")
+
+	// ErrHealthCheckFailed indicates that a provider or the vector store failed its health check
+	ErrHealthCheckFailed = llm.NewSDKError(ErrCodeHealthCheck, "health check failed")
 )
+
+// AsSDKError returns the first SDKError in err's chain
+func AsSDKError(err error) (*SDKError, bool) {
+	return llm.AsSDKError(err)
+}
+
+// ErrorCode returns the code of the first SDKError in err's chain, or ""
+func ErrorCode(err error) string {
+	return llm.ErrorCode(err)
+}
+
+// HTTPStatus returns the HTTP status of the API failure in err's chain, or 0
+func HTTPStatus(err error) int {
+	return llm.HTTPStatus(err)
+}
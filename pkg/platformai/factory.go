@@ -0,0 +1,127 @@
+package platformai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Factory builds SDK instances from named configurations, for example one per
+// customer, and caches each instance after it is first built. The zero value
+// is ready to use.
+type Factory struct {
+	mu        sync.Mutex // Guards configs and instances
+	configs   map[string]*Config
+	instances map[string]*factoryEntry
+}
+
+// factoryEntry is a cached SDK, or one being built; done is closed when the build finishes
+type factoryEntry struct {
+	done chan struct{}
+	sdk  *SDK
+	err  error
+}
+
+// Register validates cfg and adds it under name. A name can only be registered once.
+func (f *Factory) Register(name string, cfg *Config) error {
+	if name == "" {
+		return fmt.Errorf("%w: factory config name is required", ErrInvalidConfig)
+	}
+	if cfg == nil {
+		return fmt.Errorf("%w: config %s is nil", ErrInvalidConfig, name)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config %s: %w", name, err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.configs[name]; ok {
+		return fmt.Errorf("%w: config %s is already registered", ErrInvalidConfig, name)
+	}
+	if f.configs == nil {
+		f.configs = make(map[string]*Config)
+	}
+	f.configs[name] = cfg
+	return nil
+}
+
+// Build returns the SDK for the config registered under name, creating it on
+// the first call. Concurrent calls for the same name share one instance. A
+// failed build is not cached, so the next call retries.
+func (f *Factory) Build(ctx context.Context, name string) (*SDK, error) {
+	f.mu.Lock()
+	cfg, ok := f.configs[name]
+	if !ok {
+		f.mu.Unlock()
+		return nil, fmt.Errorf("%w: no config registered as %s", ErrInvalidConfig, name)
+	}
+	if entry, ok := f.instances[name]; ok {
+		f.mu.Unlock()
+		select {
+		case <-entry.done:
+			return entry.sdk, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	entry := &factoryEntry{done: make(chan struct{})}
+	if f.instances == nil {
+		f.instances = make(map[string]*factoryEntry)
+	}
+	f.instances[name] = entry
+	f.mu.Unlock()
+
+	entry.sdk, entry.err = New(ctx, cfg)
+	if entry.err != nil {
+		entry.err = fmt.Errorf("failed to build SDK %s: %w", name, entry.err)
+		f.mu.Lock()
+		if f.instances[name] == entry {
+			delete(f.instances, name)
+		}
+		f.mu.Unlock()
+	}
+	close(entry.done)
+	return entry.sdk, entry.err
+}
+
+// Preload builds every registered config in parallel, returning the joined
+// errors of the builds that failed
+func (f *Factory) Preload(ctx context.Context) error {
+	names := f.Names()
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = f.Build(ctx, name)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Reset evicts the cached SDK for name so the next Build creates a new one.
+// The evicted instance keeps working for callers still holding it.
+func (f *Factory) Reset(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.instances, name)
+}
+
+// Names returns the registered config names, sorted
+func (f *Factory) Names() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names := make([]string, 0, len(f.configs))
+	for name := range f.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package platformai
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFactory_Build(t *testing.T) {
+	var f Factory
+	if err := f.Register("acme", &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "acme-key", Model: "claude-haiku-4-5"}}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := f.Register("globex", &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "globex-key", Model: "claude-sonnet-4-5"}}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := f.Register("acme", &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "other"}}); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Register() duplicate error = %v, want ErrInvalidConfig", err)
+	}
+	if err := f.Register("broken", &Config{LLM: LLMConfig{Provider: "anthropic"}}); err == nil {
+		t.Error("Register() expected error for invalid config")
+	}
+
+	ctx := context.Background()
+	if err := f.Preload(ctx); err != nil {
+		t.Fatalf("Preload() error = %v", err)
+	}
+	acme, err := f.Build(ctx, "acme")
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	globex, err := f.Build(ctx, "globex")
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if acme == globex {
+		t.Fatal("Build() returned the same SDK for different configs")
+	}
+	if acme.model() != "claude-haiku-4-5" || globex.model() != "claude-sonnet-4-5" {
+		t.Errorf("models = %s, %s, want each SDK to use its own config", acme.model(), globex.model())
+	}
+
+	if again, _ := f.Build(ctx, "acme"); again != acme {
+		t.Error("Build() did not return the cached instance")
+	}
+	f.Reset("acme")
+	if rebuilt, _ := f.Build(ctx, "acme"); rebuilt == acme {
+		t.Error("Build() after Reset() returned the evicted instance")
+	}
+
+	if _, err := f.Build(ctx, "initech"); !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("Build() unregistered error = %v, want ErrInvalidConfig", err)
+	}
+}
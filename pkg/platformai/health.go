@@ -0,0 +1,90 @@
+package platformai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Components reported in HealthReport.Latencies
+const (
+	HealthComponentLLM         = "llm"
+	HealthComponentEmbedding   = "embedding"
+	HealthComponentVectorStore = "vector_store"
+)
+
+// HealthReport is the result of SDK.HealthCheck. Components that are not
+// configured, such as the embedding provider and vector store without RAG, are
+// reported unhealthy and have no latency.
+type HealthReport struct {
+	LLMHealthy         bool                     `json:"llm_healthy"`
+	EmbeddingHealthy   bool                     `json:"embedding_healthy"`
+	VectorStoreHealthy bool                     `json:"vector_store_healthy"`
+	Latencies          map[string]time.Duration `json:"latencies"` // Component -> time its check took
+}
+
+// pinger is implemented by LLM clients that can check their API without generating
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthCheck checks every configured component: the LLM provider by listing
+// its models, the embedding provider by embedding a single character, and the
+// vector store by counting its documents. LLM clients that cannot be pinged
+// are reported healthy. The report is always returned; the error wraps
+// ErrHealthCheckFailed and the first component failure.
+func (s *SDK) HealthCheck(ctx context.Context) (*HealthReport, error) {
+	report := &HealthReport{Latencies: make(map[string]time.Duration)}
+	var failure error
+	check := func(component string, fn func(context.Context) error) bool {
+		start := time.Now()
+		err := fn(ctx)
+		report.Latencies[component] = time.Since(start)
+		if err != nil && failure == nil {
+			failure = fmt.Errorf("%w: %s: %w", ErrHealthCheckFailed, component, err)
+		}
+		return err == nil
+	}
+
+	report.LLMHealthy = true
+	if p, ok := s.provider().(pinger); ok {
+		report.LLMHealthy = check(HealthComponentLLM, p.Ping)
+	}
+	if ragModule := s.RAG(); ragModule != nil {
+		report.EmbeddingHealthy = check(HealthComponentEmbedding, ragModule.PingEmbeddingProvider)
+		report.VectorStoreHealthy = check(HealthComponentVectorStore, func(ctx context.Context) error {
+			_, err := ragModule.Count(ctx)
+			return err
+		})
+	}
+	return report, failure
+}
+
+// ServeHealthHTTP serves the HealthReport as JSON at /health on addr. The
+// status is 200 when every configured component is healthy and 503 otherwise.
+// Like http.ListenAndServe, it blocks and always returns a non-nil error.
+func (s *SDK) ServeHealthHTTP(addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s.healthHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return server.ListenAndServe()
+}
+
+// healthHandler serves the health report at /health
+func (s *SDK) healthHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		report, err := s.HealthCheck(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if errors.Is(err, ErrHealthCheckFailed) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+	return mux
+}
@@ -0,0 +1,105 @@
+package platformai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// newHealthSDK returns an SDK whose Anthropic and OpenAI calls are answered
+// locally; the embedding API fails when embeddingUp is false
+func newHealthSDK(t *testing.T, embeddingUp bool) *SDK {
+	t.Helper()
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		status, body := http.StatusNotFound, `{}`
+		switch r.URL.Host + r.URL.Path {
+		case "api.anthropic.com/v1/models":
+			if r.Method == http.MethodGet && r.Header.Get("x-api-key") == "test" {
+				status, body = http.StatusOK, `{"data":[{"id":"claude-sonnet-4-5"}]}`
+			}
+		case "api.openai.com/v1/embeddings":
+			status, body = http.StatusInternalServerError, `{"error":{"message":"down"}}`
+			if embeddingUp {
+				status, body = http.StatusOK, `{"data":[{"embedding":[0.1,0.2]}]}`
+			}
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    r,
+		}, nil
+	})}
+
+	sdk, err := New(context.Background(), &Config{
+		LLM: LLMConfig{Provider: "anthropic", APIKey: "test"},
+		RAG: &rag.Config{EmbeddingProvider: "openai", APIKey: "test"},
+	}, WithHTTPClient(client))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return sdk
+}
+
+func TestSDK_HealthCheck(t *testing.T) {
+	report, err := newHealthSDK(t, true).HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("HealthCheck() error = %v", err)
+	}
+	if !report.LLMHealthy || !report.EmbeddingHealthy || !report.VectorStoreHealthy {
+		t.Errorf("HealthCheck() = %+v, want all components healthy", report)
+	}
+	for _, component := range []string{HealthComponentLLM, HealthComponentEmbedding, HealthComponentVectorStore} {
+		if _, ok := report.Latencies[component]; !ok {
+			t.Errorf("Latencies = %v, missing %s", report.Latencies, component)
+		}
+	}
+
+	report, err = newHealthSDK(t, false).HealthCheck(context.Background())
+	if !errors.Is(err, ErrHealthCheckFailed) {
+		t.Fatalf("HealthCheck() error = %v, want ErrHealthCheckFailed", err)
+	}
+	if !report.LLMHealthy || report.EmbeddingHealthy || !report.VectorStoreHealthy {
+		t.Errorf("HealthCheck() = %+v, want only the embedding provider unhealthy", report)
+	}
+}
+
+func TestSDK_HealthHandler(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		embeddingUp bool
+		wantStatus  int
+	}{
+		{"healthy", true, http.StatusOK},
+		{"unhealthy", false, http.StatusServiceUnavailable},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(newHealthSDK(t, tt.embeddingUp).healthHandler())
+			defer server.Close()
+
+			resp, err := http.Get(server.URL + "/health")
+			if err != nil {
+				t.Fatalf("GET /health error = %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			var report HealthReport
+			if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			if report.EmbeddingHealthy != tt.embeddingUp || !report.LLMHealthy {
+				t.Errorf("report = %+v, want embedding healthy = %v", report, tt.embeddingUp)
+			}
+		})
+	}
+}
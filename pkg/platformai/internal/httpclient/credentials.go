@@ -0,0 +1,58 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Credentials holds the API key and HTTP client a provider client sends requests
+// with. Both can be replaced while requests are in flight: requests already sent
+// complete with the values they started with, later ones use the new values.
+type Credentials struct {
+	mu         sync.RWMutex
+	apiKey     string
+	httpClient *http.Client
+}
+
+// NewCredentials creates credentials sending requests with apiKey over client
+func NewCredentials(apiKey string, client *http.Client) *Credentials {
+	return &Credentials{apiKey: apiKey, httpClient: client}
+}
+
+// RotateAPIKey replaces the API key used for new requests. It fails if newKey is empty.
+func (c *Credentials) RotateAPIKey(newKey string) error {
+	if newKey == "" {
+		return fmt.Errorf("API key is required")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.apiKey = newKey
+	return nil
+}
+
+// SetHTTPClient replaces the HTTP client used for new requests
+func (c *Credentials) SetHTTPClient(client *http.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.httpClient = client
+}
+
+// APIKey returns the API key for a new request
+func (c *Credentials) APIKey() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.apiKey
+}
+
+// HTTPClient returns the HTTP client for a new request
+func (c *Credentials) HTTPClient() *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.httpClient
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestCredentials(t *testing.T) {
+	original := &http.Client{}
+	creds := NewCredentials("key-1", original)
+
+	if err := creds.RotateAPIKey(""); err == nil {
+		t.Error("RotateAPIKey() expected error for empty key")
+	}
+	if got := creds.APIKey(); got != "key-1" {
+		t.Errorf("APIKey() after rejected rotation = %q, want key-1", got)
+	}
+
+	replacement := &http.Client{}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = creds.APIKey()
+			_ = creds.HTTPClient()
+		}()
+	}
+	if err := creds.RotateAPIKey("key-2"); err != nil {
+		t.Fatalf("RotateAPIKey() error = %v", err)
+	}
+	creds.SetHTTPClient(replacement)
+	wg.Wait()
+
+	if got := creds.APIKey(); got != "key-2" {
+		t.Errorf("APIKey() = %q, want key-2", got)
+	}
+	if creds.HTTPClient() != replacement {
+		t.Error("HTTPClient() did not return the replacement client")
+	}
+}
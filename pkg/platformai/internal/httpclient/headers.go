@@ -0,0 +1,34 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// protectedHeaders carry credentials and cannot be set as custom headers
+var protectedHeaders = map[string]bool{
+	"X-Api-Key":           true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+}
+
+// ValidateHeaders checks that custom headers have names and do not override
+// the credential headers the clients set themselves
+func ValidateHeaders(headers map[string]string) error {
+	for name := range headers {
+		if name == "" {
+			return fmt.Errorf("custom header name is required")
+		}
+		if protectedHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("custom header %q is not allowed: it carries credentials", name)
+		}
+	}
+	return nil
+}
+
+// SetHeaders sets custom headers on an outbound request's header
+func SetHeaders(h http.Header, headers map[string]string) {
+	for name, value := range headers {
+		h.Set(name, value)
+	}
+}
@@ -0,0 +1,137 @@
+// Package httpclient builds the HTTP transports shared by the LLM and embedding provider clients
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Options configures outbound connections to provider APIs
+type Options struct {
+	// ProxyURL routes requests through this proxy. Empty uses the HTTP_PROXY,
+	// HTTPS_PROXY, and NO_PROXY environment variables.
+	ProxyURL string
+
+	// TLSInsecureSkipVerify disables certificate verification for the API and proxy.
+	//
+	// Deprecated: install the proxy's CA certificate instead; this option will be removed.
+	TLSInsecureSkipVerify bool
+
+	// CertFile, KeyFile, and CAFile enable mutual TLS when all three are set: the
+	// client presents the certificate/key pair and trusts only servers signed by the CA bundle
+	CertFile string
+	KeyFile  string
+	CAFile   string
+
+	// Connection pool limits; zero values use the defaults below.
+	// MaxConnsPerHost zero means no limit.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+}
+
+// Connection pool defaults, sized for concurrent calls to a single provider API
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 100
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// NewTransport creates a transport for the options. TLS certificates of the API and
+// any HTTPS proxy are verified unless TLSInsecureSkipVerify is set.
+func NewTransport(o Options) (*http.Transport, error) {
+	proxy, err := o.proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := o.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy:               proxy,
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConns:        orDefault(o.MaxIdleConns, DefaultMaxIdleConns),
+		MaxIdleConnsPerHost: orDefault(o.MaxIdleConnsPerHost, DefaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:     o.MaxConnsPerHost,
+		IdleConnTimeout:     orDefault(o.IdleConnTimeout, DefaultIdleConnTimeout),
+	}, nil
+}
+
+// orDefault returns v, or def if v is not positive
+func orDefault[T int | time.Duration](v, def T) T {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
+
+// proxy returns the proxy function for the options
+func (o Options) proxy() (func(*http.Request) (*url.URL, error), error) {
+	if o.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(o.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if proxyURL.Scheme == "" || proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme and host are required", o.ProxyURL)
+	}
+	return http.ProxyURL(proxyURL), nil
+}
+
+// tlsConfig builds the client TLS configuration, loading mTLS files if configured
+func (o Options) tlsConfig() (*tls.Config, error) {
+	if o.TLSInsecureSkipVerify {
+		log.Printf("WARNING: TLSInsecureSkipVerify is deprecated and disables TLS certificate verification")
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: o.TLSInsecureSkipVerify, // #nosec G402 - explicit, deprecated opt-in
+	}
+
+	set := 0
+	for _, file := range []string{o.CertFile, o.KeyFile, o.CAFile} {
+		if file != "" {
+			set++
+		}
+	}
+	switch set {
+	case 0:
+		return config, nil
+	case 3:
+	default:
+		return nil, fmt.Errorf("mutual TLS requires CertFile, KeyFile, and CAFile to all be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate %s: %w", o.CertFile, err)
+	}
+
+	// #nosec G304 - CA bundle path comes from the caller's TLS configuration
+	caPEM, err := os.ReadFile(o.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("failed to parse CA bundle %s: no PEM certificates found", o.CAFile)
+	}
+
+	config.Certificates = []tls.Certificate{cert}
+	config.RootCAs = pool
+	return config, nil
+}
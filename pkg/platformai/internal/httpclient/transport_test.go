@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTransport_Proxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.Host
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(Options{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewTransport() error = %v", err)
+	}
+	resp, err := (&http.Client{Transport: transport}).Get("http://api.example.test/v1/ping")
+	if err != nil {
+		t.Fatalf("Get() through proxy error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if proxiedHost != "api.example.test" {
+		t.Errorf("proxy received host %q, want api.example.test", proxiedHost)
+	}
+}
+
+func TestNewTransport_TLS(t *testing.T) {
+	transport, err := NewTransport(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("NewTransport() should verify TLS certificates by default")
+	}
+
+	transport, err = NewTransport(Options{TLSInsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("NewTransport() should honor TLSInsecureSkipVerify")
+	}
+}
+
+func TestNewTransport_Errors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{"proxy missing scheme", Options{ProxyURL: "proxy.corp:3128"}, "scheme and host are required"},
+		{"proxy unparseable", Options{ProxyURL: "http://[::1"}, "invalid proxy URL"},
+		{"partial mTLS", Options{CertFile: "client.pem"}, "all be set"},
+		{"missing cert", Options{CertFile: "missing.pem", KeyFile: "missing.key", CAFile: notPEM}, "failed to load client certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTransport(tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewTransport() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewTransport_ConnectionPool(t *testing.T) {
+	transport, err := NewTransport(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.MaxIdleConns != DefaultMaxIdleConns || transport.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost ||
+		transport.IdleConnTimeout != DefaultIdleConnTimeout || transport.MaxConnsPerHost != 0 {
+		t.Errorf("default pool = %d idle, %d idle per host, %d per host, %v idle timeout",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout)
+	}
+
+	transport, err = NewTransport(Options{MaxIdleConns: 10, MaxIdleConnsPerHost: 5, MaxConnsPerHost: 20, IdleConnTimeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.MaxIdleConns != 10 || transport.MaxIdleConnsPerHost != 5 || transport.MaxConnsPerHost != 20 || transport.IdleConnTimeout != time.Second {
+		t.Errorf("configured pool = %d idle, %d idle per host, %d per host, %v idle timeout",
+			transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.IdleConnTimeout)
+	}
+}
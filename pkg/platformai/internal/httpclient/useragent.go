@@ -0,0 +1,19 @@
+package httpclient
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// SDKVersion is the SDK release. It lives here so the provider clients can
+// report it without importing the platformai package.
+const SDKVersion = "0.1.0"
+
+// userAgent identifies SDK traffic to provider APIs
+var userAgent = fmt.Sprintf("innominatus-ai-sdk/%s (go/%s; %s/%s)", SDKVersion, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+// UserAgent returns the User-Agent header value sent with every API request,
+// e.g. "innominatus-ai-sdk/0.1.0 (go/go1.24.1; linux/amd64)"
+func UserAgent() string {
+	return userAgent
+}
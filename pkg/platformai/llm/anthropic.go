@@ -2,13 +2,22 @@ package llm
 
 import (
 	"bytes"
+	"cmp"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
 )
 
 const (
@@ -19,38 +28,270 @@ const (
 
 // AnthropicClient implements the Client interface for Anthropic's Claude API
 type AnthropicClient struct {
-	apiKey     string
-	model      string
-	httpClient *http.Client
-	apiURL     string // Override for testing
-}
-
-// NewAnthropicClient creates a new Anthropic client
-func NewAnthropicClient(config Config) *AnthropicClient {
-	return &AnthropicClient{
-		apiKey: config.APIKey,
-		model:  config.Model,
-		apiURL: anthropicAPIURL,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-			Transport: &http.Transport{
-				Proxy:               http.ProxyFromEnvironment,
-				MaxIdleConns:        10,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
-		},
+	credentials *httpclient.Credentials // API key and HTTP client, replaceable while requests are in flight
+	model       string
+	apiURL      string // Override for testing
+
+	systemPromptPrefix string // Set from Config.SystemPromptPrefix or WithSystemPromptPrefix
+
+	pii        *PIIDetector          // Set by WithPIIRedaction
+	piiMu      sync.Mutex            // Guards piiReports and piiOrder
+	piiReports map[string][]PIIMatch // Response ID -> redacted matches
+	piiOrder   []string              // Report IDs, oldest first
+
+	moderator ContentModerator // Set by WithModeration
+	tokens    TokenCounter     // Counts prompt tokens for cost estimates; nil uses HeuristicTokenCounter
+
+	quota    QuotaManager                     // Set by WithQuotaManager
+	quotaKey func(ctx context.Context) string // Identifies the caller whose quota is charged
+
+	dlq *DeadLetterQueue // Set by WithDeadLetterQueue
+
+	idempotencyWindow time.Duration // How long responses are replayed for a repeated IdempotencyKey; 0 disables
+	idempotent        idempotencyCache
+
+	customHeaders map[string]string // Sent with every request; set from Config.CustomHeaders and WithCustomHeaders
+	betaFeatures  []string          // Sent as the anthropic-beta header
+}
+
+// maxPIIReports bounds how many redaction reports a client keeps
+const maxPIIReports = 1000
+
+// NewAnthropicClient creates a new Anthropic client.
+// Requests use config.ProxyURL if set, otherwise the proxy from the environment.
+// It fails if the proxy URL is invalid or the mutual TLS files cannot be loaded.
+func NewAnthropicClient(config Config, opts ...Option) (*AnthropicClient, error) {
+	transport, err := httpclient.NewTransport(config.transportOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP transport: %w", err)
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	client := &AnthropicClient{
+		credentials: httpclient.NewCredentials(config.APIKey, &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		}),
+		model:              config.Model,
+		apiURL:             anthropicAPIURL,
+		systemPromptPrefix: config.SystemPromptPrefix,
+		idempotencyWindow:  defaultIdempotencyWindow,
+		customHeaders:      maps.Clone(config.CustomHeaders),
+		betaFeatures:       slices.Clone(config.BetaFeatures),
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	if err := httpclient.ValidateHeaders(client.customHeaders); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// RotateAPIKey changes the x-api-key header of later requests. A message that is
+// still generating finishes under the old key.
+func (c *AnthropicClient) RotateAPIKey(newKey string) error {
+	return c.credentials.RotateAPIKey(newKey)
+}
+
+// SetHTTPClient replaces the HTTP client used for API calls made after it returns
+func (c *AnthropicClient) SetHTTPClient(client *http.Client) {
+	c.credentials.SetHTTPClient(client)
+}
+
+// Ping checks that the API is reachable and accepts the API key by listing
+// models, which costs no tokens
+func (c *AnthropicClient) Ping(ctx context.Context) error {
+	modelsURL := strings.TrimSuffix(c.apiURL, "/messages") + "/models"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, modelsURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("x-api-key", c.credentials.APIKey())
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+	httpReq.Header.Set("User-Agent", httpclient.UserAgent())
+	httpclient.SetHeaders(httpReq.Header, c.customHeaders)
+
+	httpResp, err := c.credentials.HTTPClient().Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return anthropicAPIError(httpResp.StatusCode, body)
+	}
+	return nil
+}
+
+// EstimateCost projects the cost of req from its prompt token count and the
+// model's list price. When req.MaxTokens is zero the model's default output limit is assumed.
+func (c *AnthropicClient) EstimateCost(req GenerateRequest) (EstimatedCost, error) {
+	return estimateCost(c.model, c.tokenCounter(), req)
+}
+
+// Capabilities returns the configured model's entry in CapabilityRegistry. Unknown
+// models are assumed to support tools, vision, streaming, and seeds.
+func (c *AnthropicClient) Capabilities() ModelCapabilities {
+	return capabilitiesOrDefault(c.model)
+}
+
+// checkQuota rejects req if key's remaining quota cannot cover its prompt
+func (c *AnthropicClient) checkQuota(ctx context.Context, key string, req GenerateRequest) error {
+	if key == "" {
+		return nil
+	}
+	remaining, err := c.quota.Remaining(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to check quota: %w", err)
+	}
+	prompt := c.tokenCounter().CountTokens(req.SystemPrompt) + c.tokenCounter().CountTokens(req.UserPrompt)
+	if remaining <= 0 || prompt > remaining {
+		return fmt.Errorf("%w: key %s has %d tokens left, prompt needs about %d", ErrQuotaExceeded, key, remaining, prompt)
+	}
+	return nil
+}
+
+// consumeQuota deducts a call's tokens from key's quota. A call that overruns the
+// quota still returns its response; the key is exhausted afterwards.
+func (c *AnthropicClient) consumeQuota(ctx context.Context, key string, usage anthropicUsage) error {
+	if key == "" {
+		return nil
+	}
+	if err := c.quota.Consume(ctx, key, usage.InputTokens+usage.OutputTokens); err != nil && !errors.Is(err, ErrQuotaExceeded) {
+		return fmt.Errorf("failed to consume quota: %w", err)
 	}
+	return nil
+}
+
+// tokenCounter returns the counter used for cost estimates
+func (c *AnthropicClient) tokenCounter() TokenCounter {
+	if c.tokens == nil {
+		return HeuristicTokenCounter{}
+	}
+	return c.tokens
+}
+
+// moderate runs a moderation check and converts a flagged result to ErrContentModerated
+func moderate(ctx context.Context, check func(context.Context, string) (*ModerationResult, error), what, text string) error {
+	result, err := check(ctx, text)
+	if err != nil {
+		return fmt.Errorf("failed to moderate %s: %w", what, err)
+	}
+	if result.Flagged {
+		return fmt.Errorf("%w: %s flagged for %s", ErrContentModerated, what, strings.Join(result.Categories, ", "))
+	}
+	return nil
+}
+
+// PIIRedactionReport returns what WithPIIRedaction removed from the request that
+// produced the response with the given ID. Match values are omitted so the report
+// does not retain the sensitive data itself.
+func (c *AnthropicClient) PIIRedactionReport(requestID string) []PIIMatch {
+	c.piiMu.Lock()
+	defer c.piiMu.Unlock()
+
+	return c.piiReports[requestID]
+}
+
+// redactRequest redacts the system and user prompts and returns the matches removed
+func (c *AnthropicClient) redactRequest(req GenerateRequest) (GenerateRequest, []PIIMatch) {
+	var systemMatches, userMatches []PIIMatch
+	req.SystemPrompt, systemMatches = c.pii.redact(req.SystemPrompt)
+	req.UserPrompt, userMatches = c.pii.redact(req.UserPrompt)
+	return req, append(systemMatches, userMatches...)
+}
+
+// redactToolsRequest redacts the system prompt and the text and tool result
+// blocks of every message, leaving req.Messages itself unchanged
+func (c *AnthropicClient) redactToolsRequest(req GenerateWithToolsRequest) (GenerateWithToolsRequest, []PIIMatch) {
+	var matches, found []PIIMatch
+	req.SystemPrompt, matches = c.pii.redact(req.SystemPrompt)
+
+	messages := make([]Message, len(req.Messages))
+	for i, msg := range req.Messages {
+		msg.Content = slices.Clone(msg.Content)
+		for j := range msg.Content {
+			block := &msg.Content[j]
+			switch block.Type {
+			case "text":
+				block.Text, found = c.pii.redact(block.Text)
+			case "tool_result":
+				block.Content, found = c.pii.redact(block.Content)
+			default:
+				continue
+			}
+			matches = append(matches, found...)
+		}
+		messages[i] = msg
+	}
+	req.Messages = messages
+	return req, matches
+}
+
+// recordPIIReport stores the redacted matches for a response, evicting the oldest report when full
+func (c *AnthropicClient) recordPIIReport(id string, matches []PIIMatch) {
+	if id == "" {
+		return
+	}
+	report := make([]PIIMatch, len(matches))
+	for i, m := range matches {
+		m.Value = ""
+		report[i] = m
+	}
+
+	c.piiMu.Lock()
+	defer c.piiMu.Unlock()
+
+	if len(c.piiOrder) >= maxPIIReports {
+		delete(c.piiReports, c.piiOrder[0])
+		c.piiOrder = c.piiOrder[1:]
+	}
+	c.piiReports[id] = report
+	c.piiOrder = append(c.piiOrder, id)
 }
 
 // anthropicRequest represents the request format for Anthropic API
 type anthropicRequest struct {
 	Model       string             `json:"model"`
 	MaxTokens   int                `json:"max_tokens"`
-	Temperature float32            `json:"temperature,omitempty"`
-	System      string             `json:"system,omitempty"`
+	Temperature *float32           `json:"temperature,omitempty"`
+	System      any                `json:"system,omitempty"` // string or []anthropicSystemBlock
 	Messages    []anthropicMessage `json:"messages"`
 	Tools       []Tool             `json:"tools,omitempty"`
+	TopP        float32            `json:"top_p,omitempty"`
+	TopK        int                `json:"top_k,omitempty"`
+	Seed        *int               `json:"seed,omitempty"`
+
+	StopSequences []string `json:"stop_sequences,omitempty"`
+
+	Thinking *anthropicThinking `json:"thinking,omitempty"`
+}
+
+// anthropicSystemBlock is a system prompt block, used to mark it for prompt caching
+type anthropicSystemBlock struct {
+	Type         string                 `json:"type"` // "text"
+	Text         string                 `json:"text"`
+	CacheControl *anthropicCacheControl `json:"cache_control,omitempty"`
+}
+
+// anthropicCacheControl marks the prompt up to and including a block as cacheable
+type anthropicCacheControl struct {
+	Type string `json:"type"` // "ephemeral"
+}
+
+// anthropicThinking enables extended thinking in a request
+type anthropicThinking struct {
+	Type         string `json:"type"` // "enabled"
+	BudgetTokens int    `json:"budget_tokens"`
 }
 
 // anthropicMessage represents a message in the conversation
@@ -61,14 +302,23 @@ type anthropicMessage struct {
 
 // anthropicContentBlock represents content in a message
 type anthropicContentBlock struct {
-	Type      string                 `json:"type"` // "text", "tool_use", "tool_result"
+	Type      string                 `json:"type"` // "text", "thinking", "tool_use", "tool_result"
 	Text      string                 `json:"text,omitempty"`
+	Thinking  string                 `json:"thinking,omitempty"`
 	ID        string                 `json:"id,omitempty"`
 	Name      string                 `json:"name,omitempty"`
 	Input     map[string]interface{} `json:"-"` // Custom marshaling - must be present for tool_use
 	ToolUseID string                 `json:"tool_use_id,omitempty"`
 	Content   string                 `json:"content,omitempty"`
 	IsError   bool                   `json:"is_error,omitempty"`
+	Source    *anthropicImageSource  `json:"source,omitempty"`
+}
+
+// anthropicImageSource holds base64-encoded image data for an image content block
+type anthropicImageSource struct {
+	Type      string `json:"type"` // "base64"
+	MediaType string `json:"media_type"`
+	Data      string `json:"data"`
 }
 
 // MarshalJSON implements custom JSON marshaling to ensure input is always included for tool_use
@@ -80,6 +330,9 @@ func (b anthropicContentBlock) MarshalJSON() ([]byte, error) {
 	if b.Text != "" {
 		result["text"] = b.Text
 	}
+	if b.Thinking != "" {
+		result["thinking"] = b.Thinking
+	}
 	if b.ID != "" {
 		result["id"] = b.ID
 	}
@@ -107,10 +360,29 @@ func (b anthropicContentBlock) MarshalJSON() ([]byte, error) {
 	if b.IsError {
 		result["is_error"] = b.IsError
 	}
+	if b.Source != nil {
+		result["source"] = b.Source
+	}
 
 	return json.Marshal(result)
 }
 
+// UnmarshalJSON decodes a block, including the tool_use input that the json:"-"
+// tag leaves to MarshalJSON
+func (b *anthropicContentBlock) UnmarshalJSON(data []byte) error {
+	type plain anthropicContentBlock
+	var decoded struct {
+		plain
+		Input map[string]interface{} `json:"input"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*b = anthropicContentBlock(decoded.plain)
+	b.Input = decoded.Input
+	return nil
+}
+
 // anthropicResponse represents the response format from Anthropic API
 type anthropicResponse struct {
 	ID           string                  `json:"id"`
@@ -120,10 +392,21 @@ type anthropicResponse struct {
 	Model        string                  `json:"model"`
 	StopReason   string                  `json:"stop_reason"`
 	StopSequence string                  `json:"stop_sequence,omitempty"`
-	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+	Usage        anthropicUsage          `json:"usage"`
+}
+
+// anthropicUsage is the token usage of a response. InputTokens excludes tokens
+// read from or written to the prompt cache.
+type anthropicUsage struct {
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens,omitempty"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens,omitempty"`
+}
+
+// cacheStats returns the prompt cache part of u
+func (u anthropicUsage) cacheStats() CacheStats {
+	return CacheStats{CacheReadTokens: u.CacheReadInputTokens, CacheWriteTokens: u.CacheCreationInputTokens}
 }
 
 // anthropicError represents an error response from Anthropic API
@@ -135,21 +418,111 @@ type anthropicError struct {
 	} `json:"error"`
 }
 
-// Generate sends a request to the Anthropic API and returns the response
+// anthropicAPIError converts a non-200 response into an SDKError coded "anthropic:<kind>"
+func anthropicAPIError(status int, body []byte) *SDKError {
+	var apiErr anthropicError
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return apiError("anthropic", status, fmt.Sprintf("API error (status %d): %s", status, string(body)))
+	}
+	return apiError("anthropic", status, fmt.Sprintf("API error: %s - %s", apiErr.Error.Type, apiErr.Error.Message))
+}
+
+// systemParam returns the system field for prompt: nil when empty, a single
+// cache-marked text block when cache is set, otherwise the plain string
+func systemParam(prompt string, cache bool) any {
+	switch {
+	case prompt == "":
+		return nil
+	case cache:
+		return []anthropicSystemBlock{{Type: "text", Text: prompt, CacheControl: &anthropicCacheControl{Type: "ephemeral"}}}
+	default:
+		return prompt
+	}
+}
+
+// systemPrompt returns prompt with the configured prefix prepended
+func (c *AnthropicClient) systemPrompt(prompt string) string {
+	if c.systemPromptPrefix == "" {
+		return prompt
+	}
+	if prompt == "" {
+		return c.systemPromptPrefix
+	}
+	return c.systemPromptPrefix + "\n\n" + prompt
+}
+
+// Generate sends a request to the Anthropic API and returns the response.
+// A request repeating the IdempotencyKey of a successful one within the
+// idempotency window returns that response without calling the API.
 func (c *AnthropicClient) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	dedupe := req.IdempotencyKey != "" && c.idempotencyWindow > 0
+	if dedupe {
+		if resp, ok := c.idempotent.get(req.IdempotencyKey, c.model); ok {
+			return resp, nil
+		}
+	}
+
+	resp, err := c.generate(ctx, req)
+	if err != nil && c.dlq != nil && shouldDeadLetter(ctx, err) {
+		c.dlq.Enqueue(req, err, map[string]string{"model": c.model})
+	}
+	if err == nil && dedupe {
+		c.idempotent.put(req.IdempotencyKey, c.model, resp, c.idempotencyWindow)
+	}
+	return resp, err
+}
+
+// generate implements Generate
+func (c *AnthropicClient) generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	if err := checkCostThreshold(c.model, c.tokenCounter(), req); err != nil {
+		return nil, err
+	}
+	var quotaKey string
+	if c.quota != nil {
+		quotaKey = c.quotaKey(ctx)
+		if err := c.checkQuota(ctx, quotaKey, req); err != nil {
+			return nil, err
+		}
+	}
+
+	var redacted []PIIMatch
+	if c.pii != nil {
+		req, redacted = c.redactRequest(req)
+	}
+	if c.moderator != nil {
+		input := strings.TrimSpace(req.SystemPrompt + "\n\n" + req.UserPrompt)
+		if err := moderate(ctx, c.moderator.CheckInput, "prompt", input); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Seed != nil && !c.Capabilities().SupportsSeed {
+		log.Printf("llm: warning: model %s does not support seeds; ignoring Seed", c.model)
+		req.Seed = nil
+	}
+
 	// Build request payload
 	payload := anthropicRequest{
 		Model:       c.model,
 		MaxTokens:   req.MaxTokens,
-		Temperature: req.Temperature,
-		System:      req.SystemPrompt,
-		Messages: []anthropicMessage{
-			{
-				Role:    "user",
-				Content: req.UserPrompt,
-			},
-		},
-		Tools: req.Tools,
+		Temperature: temperatureParam(req.Temperature, req.Seed != nil),
+		System:      systemParam(c.systemPrompt(req.SystemPrompt), req.CacheSystemPrompt),
+		Messages: append(anthropicMessages(fewShotMessages(req.FewShotExamples)), anthropicMessage{
+			Role:    "user",
+			Content: userContent(req),
+		}),
+		Tools:         req.Tools,
+		TopP:          req.TopP,
+		TopK:          req.TopK,
+		Seed:          req.Seed,
+		StopSequences: req.StopSequences,
+	}
+	if req.ThinkingBudget > 0 {
+		payload.Temperature = nil // Thinking requires the default temperature
+		payload.Thinking = &anthropicThinking{Type: "enabled", BudgetTokens: req.ThinkingBudget}
 	}
 
 	// Marshal to JSON
@@ -165,12 +538,20 @@ func (c *AnthropicClient) Generate(ctx context.Context, req GenerateRequest) (*G
 	}
 
 	// Set headers
-	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("x-api-key", c.credentials.APIKey())
 	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+	httpReq.Header.Set("User-Agent", httpclient.UserAgent())
+	httpclient.SetHeaders(httpReq.Header, c.customHeaders)
 	httpReq.Header.Set("content-type", "application/json")
+	if len(c.betaFeatures) > 0 {
+		httpReq.Header.Set("anthropic-beta", strings.Join(c.betaFeatures, ","))
+	}
+	if req.IdempotencyKey != "" {
+		httpReq.Header.Set(IdempotencyKeyHeader, req.IdempotencyKey)
+	}
 
 	// Send request
-	httpResp, err := c.httpClient.Do(httpReq)
+	httpResp, err := c.credentials.HTTPClient().Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -184,11 +565,7 @@ func (c *AnthropicClient) Generate(ctx context.Context, req GenerateRequest) (*G
 
 	// Handle non-200 status codes
 	if httpResp.StatusCode != http.StatusOK {
-		var apiErr anthropicError
-		if err := json.Unmarshal(body, &apiErr); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", httpResp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Error.Type, apiErr.Error.Message)
+		return nil, anthropicAPIError(httpResp.StatusCode, body)
 	}
 
 	// Parse response
@@ -197,12 +574,14 @@ func (c *AnthropicClient) Generate(ctx context.Context, req GenerateRequest) (*G
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Extract text and tool uses from content
-	var text string
+	// Extract text, thinking, and tool uses from content
+	var text, thinking string
 	var toolUses []ToolUse
 	for _, content := range apiResp.Content {
 		if content.Type == "text" {
 			text += content.Text
+		} else if content.Type == "thinking" {
+			thinking += content.Thinking
 		} else if content.Type == "tool_use" {
 			toolUses = append(toolUses, ToolUse{
 				ID:    content.ID,
@@ -215,15 +594,31 @@ func (c *AnthropicClient) Generate(ctx context.Context, req GenerateRequest) (*G
 	// Clean up potential markdown formatting from LLM response
 	text = cleanLLMResponse(text)
 
+	if len(redacted) > 0 {
+		c.recordPIIReport(apiResp.ID, redacted)
+	}
+	if err := c.consumeQuota(ctx, quotaKey, apiResp.Usage); err != nil {
+		return nil, err
+	}
+	if c.moderator != nil {
+		if err := moderate(ctx, c.moderator.CheckOutput, "response", text); err != nil {
+			return nil, err
+		}
+	}
+
 	return &GenerateResponse{
-		Text:       text,
-		ToolUses:   toolUses,
-		StopReason: apiResp.StopReason,
+		ID:           apiResp.ID,
+		Model:        cmp.Or(apiResp.Model, c.model),
+		Text:         text,
+		ThinkingText: thinking,
+		ToolUses:     toolUses,
+		StopReason:   apiResp.StopReason,
 		Usage: Usage{
 			PromptTokens:     apiResp.Usage.InputTokens,
 			CompletionTokens: apiResp.Usage.OutputTokens,
 			TotalTokens:      apiResp.Usage.InputTokens + apiResp.Usage.OutputTokens,
 		},
+		CacheStats: apiResp.Usage.cacheStats(),
 	}, nil
 }
 
@@ -235,12 +630,11 @@ func (c *AnthropicClient) GenerateWithContext(ctx context.Context, req GenerateR
 		enhancedPrompt = additionalContext + "\n\n" + req.UserPrompt
 	}
 
-	// Create new request with enhanced prompt
-	enhancedReq := GenerateRequest{
-		SystemPrompt: req.SystemPrompt,
-		UserPrompt:   enhancedPrompt,
-		Temperature:  req.Temperature,
-		MaxTokens:    req.MaxTokens,
+	// Copy the request so sampling options like Seed carry over
+	enhancedReq := req
+	enhancedReq.UserPrompt = enhancedPrompt
+	if strings.Contains(additionalContext, CitationReferencesHeader+"\n") {
+		enhancedReq.SystemPrompt = strings.TrimSpace(req.SystemPrompt + "\n\n" + citationInstruction)
 	}
 
 	return c.Generate(ctx, enhancedReq)
@@ -248,35 +642,35 @@ func (c *AnthropicClient) GenerateWithContext(ctx context.Context, req GenerateR
 
 // GenerateWithTools sends a multi-turn conversation request with tool support
 func (c *AnthropicClient) GenerateWithTools(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
-	// Convert messages to anthropic format
-	var messages []anthropicMessage
-	for _, msg := range req.Messages {
-		// Convert content blocks
-		var content interface{}
-		if len(msg.Content) == 1 && msg.Content[0].Type == "text" {
-			// Simple text message
-			content = msg.Content[0].Text
-		} else {
-			// Complex message with multiple content blocks
-			var blocks []anthropicContentBlock
-			for _, block := range msg.Content {
-				blocks = append(blocks, anthropicContentBlock(block))
-			}
-			content = blocks
+	if !c.Capabilities().SupportsTools {
+		return nil, fmt.Errorf("%w: %s", ErrToolsNotSupported, c.model)
+	}
+	var quotaKey string
+	if c.quota != nil {
+		quotaKey = c.quotaKey(ctx)
+		if err := c.checkQuota(ctx, quotaKey, GenerateRequest{UserPrompt: toolsRequestText(req)}); err != nil {
+			return nil, err
 		}
+	}
 
-		messages = append(messages, anthropicMessage{
-			Role:    msg.Role,
-			Content: content,
-		})
+	var redacted []PIIMatch
+	if c.pii != nil {
+		req, redacted = c.redactToolsRequest(req)
 	}
+	if c.moderator != nil {
+		if err := moderate(ctx, c.moderator.CheckInput, "prompt", toolsRequestText(req)); err != nil {
+			return nil, err
+		}
+	}
+
+	messages := anthropicMessages(append(fewShotMessages(req.FewShotExamples), req.Messages...))
 
 	// Build request payload
 	payload := anthropicRequest{
 		Model:       c.model,
 		MaxTokens:   req.MaxTokens,
-		Temperature: req.Temperature,
-		System:      req.SystemPrompt,
+		Temperature: temperatureParam(req.Temperature, false),
+		System:      systemParam(c.systemPrompt(req.SystemPrompt), req.CacheSystemPrompt),
 		Messages:    messages,
 		Tools:       req.Tools,
 	}
@@ -294,12 +688,17 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, req GenerateWit
 	}
 
 	// Set headers
-	httpReq.Header.Set("x-api-key", c.apiKey)
+	httpReq.Header.Set("x-api-key", c.credentials.APIKey())
 	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+	httpReq.Header.Set("User-Agent", httpclient.UserAgent())
+	httpclient.SetHeaders(httpReq.Header, c.customHeaders)
 	httpReq.Header.Set("content-type", "application/json")
+	if len(c.betaFeatures) > 0 {
+		httpReq.Header.Set("anthropic-beta", strings.Join(c.betaFeatures, ","))
+	}
 
 	// Send request
-	httpResp, err := c.httpClient.Do(httpReq)
+	httpResp, err := c.credentials.HTTPClient().Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -313,11 +712,7 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, req GenerateWit
 
 	// Handle non-200 status codes
 	if httpResp.StatusCode != http.StatusOK {
-		var apiErr anthropicError
-		if err := json.Unmarshal(body, &apiErr); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", httpResp.StatusCode, string(body))
-		}
-		return nil, fmt.Errorf("API error: %s - %s", apiErr.Error.Type, apiErr.Error.Message)
+		return nil, anthropicAPIError(httpResp.StatusCode, body)
 	}
 
 	// Parse response
@@ -341,7 +736,21 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, req GenerateWit
 		}
 	}
 
+	if len(redacted) > 0 {
+		c.recordPIIReport(apiResp.ID, redacted)
+	}
+	if err := c.consumeQuota(ctx, quotaKey, apiResp.Usage); err != nil {
+		return nil, err
+	}
+	if c.moderator != nil {
+		if err := moderate(ctx, c.moderator.CheckOutput, "response", text); err != nil {
+			return nil, err
+		}
+	}
+
 	return &GenerateResponse{
+		ID:         apiResp.ID,
+		Model:      cmp.Or(apiResp.Model, c.model),
 		Text:       text,
 		ToolUses:   toolUses,
 		StopReason: apiResp.StopReason,
@@ -350,9 +759,92 @@ func (c *AnthropicClient) GenerateWithTools(ctx context.Context, req GenerateWit
 			CompletionTokens: apiResp.Usage.OutputTokens,
 			TotalTokens:      apiResp.Usage.InputTokens + apiResp.Usage.OutputTokens,
 		},
+		CacheStats: apiResp.Usage.cacheStats(),
 	}, nil
 }
 
+// toolsRequestText joins the system prompt and the text and tool result blocks
+// of a tools request, for moderation
+func toolsRequestText(req GenerateWithToolsRequest) string {
+	parts := []string{req.SystemPrompt}
+	for _, msg := range req.Messages {
+		for _, block := range msg.Content {
+			switch block.Type {
+			case "text":
+				parts = append(parts, block.Text)
+			case "tool_result":
+				parts = append(parts, block.Content)
+			}
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n\n"))
+}
+
+// anthropicMessages converts messages to the Anthropic format
+func anthropicMessages(msgs []Message) []anthropicMessage {
+	var messages []anthropicMessage
+	for _, msg := range msgs {
+		// Convert content blocks
+		var content interface{}
+		if len(msg.Content) == 1 && msg.Content[0].Type == "text" {
+			// Simple text message
+			content = msg.Content[0].Text
+		} else {
+			// Complex message with multiple content blocks
+			var blocks []anthropicContentBlock
+			for _, block := range msg.Content {
+				blocks = append(blocks, anthropicContentBlock{
+					Type:      block.Type,
+					Text:      block.Text,
+					ID:        block.ID,
+					Name:      block.Name,
+					Input:     block.Input,
+					ToolUseID: block.ToolUseID,
+					Content:   block.Content,
+					IsError:   block.IsError,
+				})
+			}
+			content = blocks
+		}
+
+		messages = append(messages, anthropicMessage{
+			Role:    msg.Role,
+			Content: content,
+		})
+	}
+	return messages
+
+}
+
+// userContent builds the user message content, prepending any images as content blocks
+func userContent(req GenerateRequest) interface{} {
+	if len(req.Images) == 0 {
+		return req.UserPrompt
+	}
+
+	blocks := make([]anthropicContentBlock, 0, len(req.Images)+1)
+	for _, img := range req.Images {
+		blocks = append(blocks, anthropicContentBlock{
+			Type: "image",
+			Source: &anthropicImageSource{
+				Type:      "base64",
+				MediaType: img.MediaType,
+				Data:      base64.StdEncoding.EncodeToString(img.Data),
+			},
+		})
+	}
+	return append(blocks, anthropicContentBlock{Type: "text", Text: req.UserPrompt})
+}
+
+// temperatureParam returns the temperature to serialize. Zero normally means
+// "use the API default", but seeded requests need an explicit zero to be reproducible.
+func temperatureParam(temperature float32, explicit bool) *float32 {
+	if temperature == 0 && !explicit {
+		return nil
+	}
+	return &temperature
+}
+
 // cleanLLMResponse removes markdown code blocks and extra whitespace
 func cleanLLMResponse(text string) string {
 	// Remove markdown code blocks (```json ... ```)
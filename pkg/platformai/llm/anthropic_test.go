@@ -1,12 +1,25 @@
 package llm
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
 )
 
 func TestAnthropicClient_Generate(t *testing.T) {
@@ -32,10 +45,7 @@ func TestAnthropicClient_Generate(t *testing.T) {
 				},
 				Model:      "claude-sonnet-4-5-20250929",
 				StopReason: "end_turn",
-				Usage: struct {
-					InputTokens  int `json:"input_tokens"`
-					OutputTokens int `json:"output_tokens"`
-				}{
+				Usage: anthropicUsage{
 					InputTokens:  10,
 					OutputTokens: 15,
 				},
@@ -64,10 +74,7 @@ func TestAnthropicClient_Generate(t *testing.T) {
 				},
 				Model:      "claude-sonnet-4-5-20250929",
 				StopReason: "end_turn",
-				Usage: struct {
-					InputTokens  int `json:"input_tokens"`
-					OutputTokens int `json:"output_tokens"`
-				}{
+				Usage: anthropicUsage{
 					InputTokens:  5,
 					OutputTokens: 10,
 				},
@@ -138,12 +145,9 @@ func TestAnthropicClient_Generate(t *testing.T) {
 
 			// Create client with mock server URL
 			client := &AnthropicClient{
-				apiKey: "test-key",
-				model:  "claude-sonnet-4-5-20250929",
-				apiURL: server.URL,
-				httpClient: &http.Client{
-					Timeout: defaultTimeout,
-				},
+				credentials: httpclient.NewCredentials("test-key", &http.Client{Timeout: defaultTimeout}),
+				model:       "claude-sonnet-4-5-20250929",
+				apiURL:      server.URL,
 			}
 
 			ctx := context.Background()
@@ -181,10 +185,7 @@ func TestAnthropicClient_GenerateWithContext(t *testing.T) {
 			},
 			Model:      "claude-sonnet-4-5-20250929",
 			StopReason: "end_turn",
-			Usage: struct {
-				InputTokens  int `json:"input_tokens"`
-				OutputTokens int `json:"output_tokens"`
-			}{
+			Usage: anthropicUsage{
 				InputTokens:  20,
 				OutputTokens: 10,
 			},
@@ -196,12 +197,9 @@ func TestAnthropicClient_GenerateWithContext(t *testing.T) {
 	defer server.Close()
 
 	client := &AnthropicClient{
-		apiKey: "test-key",
-		model:  "claude-sonnet-4-5-20250929",
-		apiURL: server.URL,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
+		credentials: httpclient.NewCredentials("test-key", &http.Client{Timeout: defaultTimeout}),
+		model:       "claude-sonnet-4-5-20250929",
+		apiURL:      server.URL,
 	}
 
 	ctx := context.Background()
@@ -241,10 +239,7 @@ func TestAnthropicClient_GenerateWithTools(t *testing.T) {
 			},
 			Model:      "claude-sonnet-4-5-20250929",
 			StopReason: "tool_use",
-			Usage: struct {
-				InputTokens  int `json:"input_tokens"`
-				OutputTokens int `json:"output_tokens"`
-			}{
+			Usage: anthropicUsage{
 				InputTokens:  15,
 				OutputTokens: 20,
 			},
@@ -256,12 +251,9 @@ func TestAnthropicClient_GenerateWithTools(t *testing.T) {
 	defer server.Close()
 
 	client := &AnthropicClient{
-		apiKey: "test-key",
-		model:  "claude-sonnet-4-5-20250929",
-		apiURL: server.URL,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
+		credentials: httpclient.NewCredentials("test-key", &http.Client{Timeout: defaultTimeout}),
+		model:       "claude-sonnet-4-5-20250929",
+		apiURL:      server.URL,
 	}
 
 	ctx := context.Background()
@@ -308,6 +300,9 @@ func TestAnthropicClient_GenerateWithTools(t *testing.T) {
 	if resp.ToolUses[0].Name != "calculator" {
 		t.Errorf("GenerateWithTools() tool name = %v, want calculator", resp.ToolUses[0].Name)
 	}
+	if got := resp.ToolUses[0].Input["operation"]; got != "add" {
+		t.Errorf("GenerateWithTools() tool input operation = %v, want add", got)
+	}
 }
 
 func TestAnthropicClient_ContextTimeout(t *testing.T) {
@@ -319,12 +314,9 @@ func TestAnthropicClient_ContextTimeout(t *testing.T) {
 	defer server.Close()
 
 	client := &AnthropicClient{
-		apiKey: "test-key",
-		model:  "claude-sonnet-4-5-20250929",
-		apiURL: server.URL,
-		httpClient: &http.Client{
-			Timeout: 1 * time.Second,
-		},
+		credentials: httpclient.NewCredentials("test-key", &http.Client{Timeout: 1 * time.Second}),
+		model:       "claude-sonnet-4-5-20250929",
+		apiURL:      server.URL,
 	}
 
 	ctx := context.Background()
@@ -391,22 +383,886 @@ func TestNewAnthropicClient(t *testing.T) {
 		MaxTokens:   1000,
 	}
 
-	client := NewAnthropicClient(config)
+	client, err := NewAnthropicClient(config)
+	if err != nil {
+		t.Fatalf("NewAnthropicClient() error = %v", err)
+	}
 
-	if client.apiKey != config.APIKey {
-		t.Errorf("NewAnthropicClient() apiKey = %v, want %v", client.apiKey, config.APIKey)
+	if got := client.credentials.APIKey(); got != config.APIKey {
+		t.Errorf("NewAnthropicClient() apiKey = %v, want %v", got, config.APIKey)
 	}
 
 	if client.model != config.Model {
 		t.Errorf("NewAnthropicClient() model = %v, want %v", client.model, config.Model)
 	}
 
-	if client.httpClient == nil {
-		t.Error("NewAnthropicClient() httpClient is nil")
+	httpClient := client.credentials.HTTPClient()
+	if httpClient == nil {
+		t.Fatal("NewAnthropicClient() httpClient is nil")
 	}
 
-	if client.httpClient.Timeout != defaultTimeout {
+	if httpClient.Timeout != defaultTimeout {
 		t.Errorf("NewAnthropicClient() timeout = %v, want %v",
-			client.httpClient.Timeout, defaultTimeout)
+			httpClient.Timeout, defaultTimeout)
+	}
+}
+
+// newCaptureServer starts a server that records the last request body and replies with text
+func newCaptureServer(t *testing.T, body *[]byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read request body: %v", err)
+		}
+		*body = data
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(anthropicResponse{
+			ID:         "msg_capture",
+			Type:       "message",
+			Role:       "assistant",
+			Content:    []anthropicContentBlock{{Type: "text", Text: "ok"}},
+			StopReason: "end_turn",
+		}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient creates a client pointed at a test server
+func newTestClient(serverURL string) *AnthropicClient {
+	return &AnthropicClient{
+		credentials: httpclient.NewCredentials("test-key", &http.Client{Timeout: defaultTimeout}),
+		model:       "claude-sonnet-4-5-20250929",
+		apiURL:      serverURL,
+	}
+}
+
+func TestAnthropicClient_GenerateSeed(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	client := newTestClient(server.URL)
+
+	req := GenerateRequest{UserPrompt: "test", Temperature: 0.7, MaxTokens: 50}.Deterministic()
+	if _, err := client.Generate(context.Background(), req); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to parse request body: %v", err)
+	}
+	if payload["seed"] != float64(defaultSeed) {
+		t.Errorf("request seed = %v, want %d", payload["seed"], defaultSeed)
+	}
+	if temp, ok := payload["temperature"]; !ok || temp != float64(0) {
+		t.Errorf("request temperature = %v (present: %v), want explicit 0", temp, ok)
+	}
+
+	if _, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "test", MaxTokens: 50}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(string(body), `"seed"`) {
+		t.Errorf("unseeded request should not include seed, got %s", body)
+	}
+}
+
+func TestAnthropicClient_GenerateSeedUnsupported(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	client := newTestClient(server.URL)
+	client.model = "claude-2.1"
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	req := GenerateRequest{UserPrompt: "test", MaxTokens: 50}.Deterministic()
+	if _, err := client.Generate(context.Background(), req); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(string(body), `"seed"`) {
+		t.Errorf("request for a model without seed support should not include seed, got %s", body)
+	}
+	if !strings.Contains(logs.String(), "does not support seeds") {
+		t.Errorf("Generate() logged %q, want a seed warning", logs.String())
+	}
+}
+
+func TestGenerateRequest_Deterministic(t *testing.T) {
+	original := GenerateRequest{UserPrompt: "test", Temperature: 0.7, MaxTokens: 50}
+	req := original.Deterministic()
+
+	if req.Temperature != 0 {
+		t.Errorf("Deterministic() temperature = %v, want 0", req.Temperature)
+	}
+	if req.Seed == nil || *req.Seed != defaultSeed {
+		t.Errorf("Deterministic() seed = %v, want %d", req.Seed, defaultSeed)
+	}
+	if original.Seed != nil || original.Temperature != 0.7 {
+		t.Error("Deterministic() should not modify the original request")
+	}
+	if req.UserPrompt != original.UserPrompt || req.MaxTokens != original.MaxTokens {
+		t.Error("Deterministic() should copy the remaining fields")
+	}
+}
+
+func TestAnthropicClient_GenerateSampling(t *testing.T) {
+	tests := []struct {
+		name    string
+		request GenerateRequest
+		want    []string
+		absent  []string
+	}{
+		{
+			name:    "top_p and top_k set",
+			request: GenerateRequest{UserPrompt: "test", MaxTokens: 50, TopP: 0.9, TopK: 40},
+			want:    []string{`"top_p":0.9`, `"top_k":40`},
+		},
+		{
+			name:    "unset sampling parameters omitted",
+			request: GenerateRequest{UserPrompt: "test", MaxTokens: 50},
+			absent:  []string{`"top_p"`, `"top_k"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			server := newCaptureServer(t, &body)
+			client := newTestClient(server.URL)
+
+			if _, err := client.Generate(context.Background(), tt.request); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(string(body), s) {
+					t.Errorf("request body missing %s: %s", s, body)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(string(body), s) {
+					t.Errorf("request body should not contain %s: %s", s, body)
+				}
+			}
+		})
+	}
+}
+
+func TestAnthropicClient_GenerateTemperatureAndTopP(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	client := newTestClient(server.URL)
+
+	_, err := client.Generate(context.Background(), GenerateRequest{
+		UserPrompt:  "test",
+		MaxTokens:   50,
+		Temperature: 0.7,
+		TopP:        0.9,
+	})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Generate() error = %v, want ErrInvalidRequest", err)
+	}
+	if body != nil {
+		t.Error("Generate() should not call the API for an invalid request")
+	}
+}
+
+func TestAnthropicClient_GenerateWithImages(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	client := newTestClient(server.URL)
+
+	imageData := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a}
+	_, err := client.Generate(context.Background(), GenerateRequest{
+		UserPrompt: "Describe this diagram",
+		MaxTokens:  50,
+		Images:     []ImageInput{{MediaType: "image/png", Data: imageData}},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var payload struct {
+		Messages []struct {
+			Content []struct {
+				Type   string `json:"type"`
+				Text   string `json:"text"`
+				Source struct {
+					Type      string `json:"type"`
+					MediaType string `json:"media_type"`
+					Data      string `json:"data"`
+				} `json:"source"`
+			} `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to parse request body: %v", err)
+	}
+
+	content := payload.Messages[0].Content
+	if len(content) != 2 {
+		t.Fatalf("content blocks = %d, want 2", len(content))
+	}
+	if content[0].Type != "image" || content[0].Source.Type != "base64" || content[0].Source.MediaType != "image/png" {
+		t.Errorf("first block = %+v, want base64 png image", content[0])
+	}
+	if content[0].Source.Data != base64.StdEncoding.EncodeToString(imageData) {
+		t.Errorf("image data = %s, want base64 of input", content[0].Source.Data)
+	}
+	if content[1].Type != "text" || content[1].Text != "Describe this diagram" {
+		t.Errorf("second block = %+v, want user prompt text", content[1])
+	}
+}
+
+func TestFromFile(t *testing.T) {
+	dir := t.TempDir()
+	pngPath := filepath.Join(dir, "diagram.PNG")
+	if err := os.WriteFile(pngPath, []byte("png-bytes"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := FromFile(pngPath)
+	if err != nil {
+		t.Fatalf("FromFile() error = %v", err)
+	}
+	if img.MediaType != "image/png" || string(img.Data) != "png-bytes" {
+		t.Errorf("FromFile() = %+v, want png with file contents", img)
+	}
+
+	if _, err := FromFile(filepath.Join(dir, "notes.txt")); err == nil {
+		t.Error("FromFile() expected error for unsupported extension")
+	}
+	if _, err := FromFile(filepath.Join(dir, "missing.jpg")); err == nil {
+		t.Error("FromFile() expected error for missing file")
+	}
+}
+
+func TestAnthropicClient_GenerateWithContextCitations(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	client := newTestClient(server.URL)
+
+	req := GenerateRequest{SystemPrompt: "You are helpful", UserPrompt: "How do I scale?", MaxTokens: 50}
+	citedContext := "--- [1] (Relevance: 0.90) ---\nUse an autoscaler\n\n" + CitationReferencesHeader + "\n[1] k8s-scaling\n"
+
+	if _, err := client.GenerateWithContext(context.Background(), req, citedContext); err != nil {
+		t.Fatalf("GenerateWithContext() error = %v", err)
+	}
+	if !strings.Contains(string(body), "You are helpful") || !strings.Contains(string(body), "bracketed numbers") {
+		t.Errorf("system prompt should keep the original prompt and add the citation note: %s", body)
+	}
+
+	if _, err := client.GenerateWithContext(context.Background(), req, "Context: plain"); err != nil {
+		t.Fatalf("GenerateWithContext() error = %v", err)
+	}
+	if strings.Contains(string(body), "bracketed numbers") {
+		t.Errorf("citation note should only be added for cited context: %s", body)
+	}
+}
+
+func TestNewAnthropicClient_ProxyURL(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.Host
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"content":[{"type":"text","text":"via proxy"}],"stop_reason":"end_turn"}`)
+	}))
+	defer proxy.Close()
+
+	client, err := NewAnthropicClient(Config{APIKey: "test-key", Model: "claude-sonnet-4-5-20250929", ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewAnthropicClient() error = %v", err)
+	}
+	client.apiURL = "http://api.anthropic.test/v1/messages"
+
+	resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi", MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("Generate() through proxy error = %v", err)
+	}
+	if resp.Text != "via proxy" {
+		t.Errorf("Generate() text = %q, want %q", resp.Text, "via proxy")
+	}
+	if proxiedHost != "api.anthropic.test" {
+		t.Errorf("proxy received host %q, want api.anthropic.test", proxiedHost)
+	}
+}
+
+func TestNewClient_InvalidProxyURL(t *testing.T) {
+	if _, err := NewClient(Config{Provider: "anthropic", APIKey: "k", ProxyURL: "not a url"}); err == nil {
+		t.Error("NewClient() expected error for invalid proxy URL")
+	}
+}
+
+func TestAnthropicClient_RotateAPIKey(t *testing.T) {
+	const oldKey, newKey = "old-key", "new-key"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key := r.Header.Get("x-api-key"); key != oldKey && key != newKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(anthropicResponse{
+			Content: []anthropicContentBlock{{Type: "text", Text: r.Header.Get("x-api-key")}},
+		})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	if err := client.RotateAPIKey(oldKey); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if _, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "test"}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if err := client.RotateAPIKey(newKey); err != nil {
+		t.Fatalf("RotateAPIKey() error = %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("in-flight requests did not drain within 1s")
+	}
+	close(errs)
+	for err := range errs {
+		t.Errorf("Generate() during rotation error = %v", err)
+	}
+
+	resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "test"})
+	if err != nil {
+		t.Fatalf("Generate() after rotation error = %v", err)
+	}
+	if resp.Text != newKey {
+		t.Errorf("request after rotation used key %q, want %q", resp.Text, newKey)
+	}
+
+	if err := client.RotateAPIKey(""); err == nil {
+		t.Error("RotateAPIKey() expected error for empty key")
+	}
+}
+
+func TestAnthropicClient_GenerateFewShot(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	client := newTestClient(server.URL)
+
+	req := GenerateRequest{
+		UserPrompt: "real prompt",
+		MaxTokens:  50,
+		FewShotExamples: []FewShotExample{
+			{UserMessage: "example 1", AssistantMessage: "answer 1"},
+			{UserMessage: "example 2", AssistantMessage: "answer 2"},
+		},
+	}
+	if _, err := client.Generate(context.Background(), req); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	var payload struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to parse request body: %v", err)
+	}
+	want := []string{"user:example 1", "assistant:answer 1", "user:example 2", "assistant:answer 2", "user:real prompt"}
+	if len(payload.Messages) != len(want) {
+		t.Fatalf("request has %d messages, want %d: %s", len(payload.Messages), len(want), body)
+	}
+	for i, msg := range payload.Messages {
+		if got := msg.Role + ":" + msg.Content; got != want[i] {
+			t.Errorf("message %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestAnthropicClient_GenerateWithToolsFewShot(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	client := newTestClient(server.URL)
+
+	req := GenerateWithToolsRequest{
+		MaxTokens: 50,
+		Messages:  []Message{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "real prompt"}}}},
+		FewShotExamples: []FewShotExample{{
+			UserMessage:      "how many files?",
+			AssistantMessage: "There are 2 files.",
+			ToolUses:         []ToolUse{{ID: "toolu_ex", Name: "list_files", Input: map[string]interface{}{"path": "."}}},
+			ToolResults:      []ToolResult{{ToolUseID: "toolu_ex", Content: "a.go\nb.go"}},
+		}},
+	}
+	if _, err := client.GenerateWithTools(context.Background(), req); err != nil {
+		t.Fatalf("GenerateWithTools() error = %v", err)
+	}
+
+	var payload struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to parse request body: %v", err)
+	}
+	wantRoles := []string{"user", "assistant", "user", "assistant", "user"}
+	if len(payload.Messages) != len(wantRoles) {
+		t.Fatalf("request has %d messages, want %d: %s", len(payload.Messages), len(wantRoles), body)
+	}
+	for i, msg := range payload.Messages {
+		if msg.Role != wantRoles[i] {
+			t.Errorf("message %d role = %q, want %q", i, msg.Role, wantRoles[i])
+		}
+	}
+	if !strings.Contains(string(payload.Messages[1].Content), `"type":"tool_use"`) {
+		t.Errorf("example tool use not sent as a content block: %s", payload.Messages[1].Content)
+	}
+	if !strings.Contains(string(payload.Messages[2].Content), `"tool_use_id":"toolu_ex"`) {
+		t.Errorf("example tool result not sent as a content block: %s", payload.Messages[2].Content)
+	}
+	if string(payload.Messages[4].Content) != `"real prompt"` {
+		t.Errorf("last message = %s, want the real prompt", payload.Messages[4].Content)
+	}
+}
+
+func TestAnthropicClient_SystemPromptPrefix(t *testing.T) {
+	systemField := func(t *testing.T, body []byte) (string, bool) {
+		t.Helper()
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("failed to parse request body: %v", err)
+		}
+		system, ok := payload["system"].(string)
+		return system, ok
+	}
+
+	var body []byte
+	server := newCaptureServer(t, &body)
+	const prefix = "You are a financial services assistant."
+
+	client := newTestClient(server.URL)
+	WithSystemPromptPrefix(prefix)(client)
+
+	if _, err := client.Generate(context.Background(), GenerateRequest{SystemPrompt: "Answer briefly.", UserPrompt: "hi", MaxTokens: 10}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if system, _ := systemField(t, body); system != prefix+"\n\nAnswer briefly." {
+		t.Errorf("Generate() system = %q, want prefix first", system)
+	}
+
+	if _, err := client.GenerateWithTools(context.Background(), GenerateWithToolsRequest{
+		SystemPrompt: "Use tools.",
+		Messages:     []Message{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}}},
+		MaxTokens:    10,
+	}); err != nil {
+		t.Fatalf("GenerateWithTools() error = %v", err)
+	}
+	if system, _ := systemField(t, body); system != prefix+"\n\nUse tools." {
+		t.Errorf("GenerateWithTools() system = %q, want prefix first", system)
+	}
+
+	if _, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi", MaxTokens: 10}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if system, _ := systemField(t, body); system != prefix {
+		t.Errorf("Generate() without system prompt: system = %q, want %q", system, prefix)
+	}
+
+	configured, err := NewAnthropicClient(Config{APIKey: "test-key", Model: "claude-sonnet-4-5-20250929", SystemPromptPrefix: prefix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configured.systemPromptPrefix != prefix {
+		t.Errorf("Config.SystemPromptPrefix not applied, got %q", configured.systemPromptPrefix)
+	}
+
+	plain := newTestClient(server.URL)
+	if _, err := plain.Generate(context.Background(), GenerateRequest{SystemPrompt: "Answer briefly.", UserPrompt: "hi", MaxTokens: 10}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if system, _ := systemField(t, body); system != "Answer briefly." {
+		t.Errorf("Generate() without prefix: system = %q, want unchanged", system)
+	}
+}
+
+func TestNewAnthropicClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewAnthropicClient(Config{APIKey: "test-key", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewAnthropicClient() error = %v", err)
+	}
+	client.apiURL = server.URL
+
+	start := time.Now()
+	_, err = client.Generate(context.Background(), GenerateRequest{UserPrompt: "hello"})
+	var netErr interface{ Timeout() bool }
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("Generate() error = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 1900*time.Millisecond {
+		t.Errorf("Generate() returned after %v, want about 1s", elapsed)
+	}
+}
+
+// BenchmarkAnthropicClient_ConnectionPool fires 100 concurrent requests per
+// iteration and reports their mean latency, comparing the default pool with
+// the previous limit of 10 idle connections
+func BenchmarkAnthropicClient_ConnectionPool(b *testing.B) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		time.Sleep(time.Millisecond)
+		_ = json.NewEncoder(w).Encode(anthropicResponse{
+			ID:         "msg_bench",
+			Type:       "message",
+			Role:       "assistant",
+			Content:    []anthropicContentBlock{{Type: "text", Text: "ok"}},
+			StopReason: "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	const concurrency = 100
+	pools := []struct {
+		name string
+		pool ConnectionPoolConfig
+	}{
+		{"default", ConnectionPoolConfig{}},
+		{"max_idle_10", ConnectionPoolConfig{MaxIdle: 10, MaxIdlePerHost: 10}},
+	}
+	for _, p := range pools {
+		b.Run(p.name, func(b *testing.B) {
+			client, err := NewAnthropicClient(Config{APIKey: "test-key", ConnectionPool: p.pool})
+			if err != nil {
+				b.Fatal(err)
+			}
+			client.apiURL = server.URL
+
+			var total time.Duration
+			var mu sync.Mutex
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var wg sync.WaitGroup
+				for j := 0; j < concurrency; j++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						start := time.Now()
+						if _, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "ping"}); err != nil {
+							b.Error(err)
+						}
+						mu.Lock()
+						total += time.Since(start)
+						mu.Unlock()
+					}()
+				}
+				wg.Wait()
+			}
+			b.ReportMetric(float64(total.Nanoseconds())/float64(b.N*concurrency), "ns/req")
+		})
+	}
+}
+
+func TestAnthropicClient_UserAgent(t *testing.T) {
+	agents := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents <- r.Header.Get("User-Agent")
+		_ = json.NewEncoder(w).Encode(anthropicResponse{Content: []anthropicContentBlock{{Type: "text", Text: "ok"}}})
+	}))
+	defer server.Close()
+
+	if _, err := newTestClient(server.URL).Generate(context.Background(), GenerateRequest{UserPrompt: "hi"}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	want := fmt.Sprintf("innominatus-ai-sdk/%s (go/%s; %s/%s)", httpclient.SDKVersion, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if got := <-agents; got != want {
+		t.Errorf("User-Agent = %q, want %q", got, want)
+	}
+}
+
+func TestAnthropicClient_GenerateIdempotencyKey(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		n := len(keys)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(anthropicResponse{Content: []anthropicContentBlock{{Type: "text", Text: fmt.Sprintf("reply %d", n)}}})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	WithIdempotencyWindow(time.Minute)(client)
+	ctx := context.Background()
+
+	first, err := client.Generate(ctx, GenerateRequest{UserPrompt: "hi", IdempotencyKey: "order-42"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	second, err := client.Generate(ctx, GenerateRequest{UserPrompt: "hi", IdempotencyKey: "order-42"})
+	if err != nil {
+		t.Fatalf("Generate() retry error = %v", err)
+	}
+	if second.Text != first.Text {
+		t.Errorf("Generate() retry text = %q, want cached %q", second.Text, first.Text)
+	}
+	if len(keys) != 1 || keys[0] != "order-42" {
+		t.Fatalf("requests sent with keys %q, want one with %q", keys, "order-42")
+	}
+
+	other, err := client.Generate(ctx, GenerateRequest{UserPrompt: "hi", IdempotencyKey: "order-43"})
+	if err != nil {
+		t.Fatalf("Generate() other key error = %v", err)
+	}
+	if other.Text != "reply 2" || len(keys) != 2 {
+		t.Errorf("Generate() other key text = %q after %d requests, want a fresh reply", other.Text, len(keys))
+	}
+
+	if _, err := client.Generate(ctx, GenerateRequest{UserPrompt: "hi"}); err != nil {
+		t.Fatalf("Generate() without key error = %v", err)
+	}
+	if len(keys) != 3 || keys[2] != "" {
+		t.Errorf("request without key sent header %q, want none", keys[len(keys)-1])
+	}
+}
+
+func TestIdempotencyCache_Expiry(t *testing.T) {
+	var cache idempotencyCache
+	cache.put("key", "model", &GenerateResponse{Text: "ok"}, -time.Second)
+	if _, ok := cache.get("key", "model"); ok {
+		t.Error("get() returned an expired response")
+	}
+
+	cache.put("key", "model", &GenerateResponse{Text: "ok"}, time.Minute)
+	if _, ok := cache.get("key", "other-model"); ok {
+		t.Error("get() returned a response cached for another model")
+	}
+	if resp, ok := cache.get("key", "model"); !ok || resp.Text != "ok" {
+		t.Errorf("get() = %v, %v, want cached response", resp, ok)
+	}
+}
+
+func TestAnthropicClient_CustomHeaders(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		_ = json.NewEncoder(w).Encode(anthropicResponse{Content: []anthropicContentBlock{{Type: "text", Text: "ok"}}})
+	}))
+	defer server.Close()
+
+	client, err := NewAnthropicClient(
+		Config{APIKey: "test-key", CustomHeaders: map[string]string{"X-Team": "platform"}},
+		WithCustomHeaders(map[string]string{"X-Request-Source": "audit"}),
+	)
+	if err != nil {
+		t.Fatalf("NewAnthropicClient() error = %v", err)
+	}
+	client.apiURL = server.URL
+
+	if _, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi"}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	got := <-headers
+	if got.Get("X-Team") != "platform" || got.Get("X-Request-Source") != "audit" {
+		t.Errorf("request headers = %v, want X-Team and X-Request-Source", got)
+	}
+	if got.Get("x-api-key") != "test-key" {
+		t.Errorf("x-api-key = %q, want the API key", got.Get("x-api-key"))
+	}
+}
+
+func TestNewAnthropicClient_CustomHeadersRejectsCredentials(t *testing.T) {
+	for _, name := range []string{"x-api-key", "Authorization", "proxy-authorization"} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAnthropicClient(Config{APIKey: "test-key"}, WithCustomHeaders(map[string]string{name: "other"})); err == nil {
+				t.Errorf("NewAnthropicClient() with custom header %s succeeded, want error", name)
+			}
+		})
+	}
+}
+
+func TestAnthropicClient_GenerateThinking(t *testing.T) {
+	type captured struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan captured, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- captured{r.Header, body}
+		_, _ = io.WriteString(w, `{
+			"id": "msg_thinking",
+			"type": "message",
+			"role": "assistant",
+			"content": [
+				{"type": "thinking", "thinking": "The service is stateless, so", "signature": "sig"},
+				{"type": "text", "text": "Use 3 replicas."}
+			],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 12, "output_tokens": 40}
+		}`)
+	}))
+	defer server.Close()
+
+	client, err := NewAnthropicClient(Config{
+		APIKey:       "test-key",
+		Model:        "claude-sonnet-4-5-20250929",
+		BetaFeatures: []string{"interleaved-thinking-2025-05-14", "token-efficient-tools-2025-02-19"},
+	})
+	if err != nil {
+		t.Fatalf("NewAnthropicClient() error = %v", err)
+	}
+	client.apiURL = server.URL
+
+	resp, err := client.Generate(context.Background(), GenerateRequest{
+		UserPrompt:     "How many replicas?",
+		MaxTokens:      4096,
+		ThinkingBudget: 2048,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.ThinkingText != "The service is stateless, so" {
+		t.Errorf("ThinkingText = %q, want the thinking block", resp.ThinkingText)
+	}
+	if resp.Text != "Use 3 replicas." {
+		t.Errorf("Text = %q, want only the text block", resp.Text)
+	}
+
+	req := <-requests
+	if got, want := req.header.Get("anthropic-beta"), "interleaved-thinking-2025-05-14,token-efficient-tools-2025-02-19"; got != want {
+		t.Errorf("anthropic-beta = %q, want %q", got, want)
+	}
+	var payload struct {
+		Thinking *anthropicThinking `json:"thinking"`
+	}
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("failed to parse request body: %v", err)
+	}
+	if payload.Thinking == nil || *payload.Thinking != (anthropicThinking{Type: "enabled", BudgetTokens: 2048}) {
+		t.Errorf("thinking = %+v, want enabled with budget 2048", payload.Thinking)
+	}
+}
+
+func TestGenerateRequest_ValidateThinking(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     GenerateRequest
+		wantErr bool
+	}{
+		{"valid", GenerateRequest{MaxTokens: 4096, ThinkingBudget: 1024}, false},
+		{"budget too small", GenerateRequest{MaxTokens: 4096, ThinkingBudget: 512}, true},
+		{"budget not below max tokens", GenerateRequest{MaxTokens: 2048, ThinkingBudget: 2048}, true},
+		{"with temperature", GenerateRequest{MaxTokens: 4096, ThinkingBudget: 1024, Temperature: 0.5}, true},
+		{"with top k", GenerateRequest{MaxTokens: 4096, ThinkingBudget: 1024, TopK: 5}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRequest) {
+				t.Errorf("validate() error = %v, want ErrInvalidRequest", err)
+			}
+		})
+	}
+}
+
+func TestAnthropicClient_CacheSystemPrompt(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		_, _ = io.WriteString(w, `{
+			"id": "msg_cached",
+			"type": "message",
+			"role": "assistant",
+			"content": [{"type": "text", "text": "ok"}],
+			"stop_reason": "end_turn",
+			"usage": {
+				"input_tokens": 20,
+				"output_tokens": 5,
+				"cache_creation_input_tokens": 0,
+				"cache_read_input_tokens": 4096
+			}
+		}`)
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL)
+
+	resp, err := client.Generate(context.Background(), GenerateRequest{
+		SystemPrompt:      "Platform engineering guidelines...",
+		UserPrompt:        "hi",
+		MaxTokens:         10,
+		CacheSystemPrompt: true,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.CacheStats != (CacheStats{CacheReadTokens: 4096}) {
+		t.Errorf("CacheStats = %+v, want 4096 cache read tokens", resp.CacheStats)
+	}
+	if resp.Usage.PromptTokens != 20 {
+		t.Errorf("Usage.PromptTokens = %d, want 20", resp.Usage.PromptTokens)
+	}
+
+	var payload struct {
+		System []anthropicSystemBlock `json:"system"`
+	}
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatalf("system = %v, want a list of blocks", err)
+	}
+	want := []anthropicSystemBlock{{Type: "text", Text: "Platform engineering guidelines...", CacheControl: &anthropicCacheControl{Type: "ephemeral"}}}
+	if len(payload.System) != 1 || payload.System[0].Text != want[0].Text ||
+		payload.System[0].CacheControl == nil || *payload.System[0].CacheControl != *want[0].CacheControl {
+		t.Errorf("system = %+v, want one ephemeral cache-marked text block", payload.System)
+	}
+
+	if _, err := client.GenerateWithTools(context.Background(), GenerateWithToolsRequest{
+		SystemPrompt:      "Platform engineering guidelines...",
+		Messages:          []Message{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}}},
+		MaxTokens:         10,
+		CacheSystemPrompt: true,
+	}); err != nil {
+		t.Fatalf("GenerateWithTools() error = %v", err)
+	}
+	payload.System = nil
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatalf("GenerateWithTools() system = %v, want a list of blocks", err)
+	}
+	if len(payload.System) != 1 || payload.System[0].CacheControl == nil {
+		t.Errorf("GenerateWithTools() system = %+v, want one cache-marked text block", payload.System)
 	}
 }
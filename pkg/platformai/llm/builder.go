@@ -0,0 +1,81 @@
+package llm
+
+import "fmt"
+
+// GenerateRequestBuilder assembles a GenerateRequest with chained calls:
+//
+//	req, err := llm.NewGenerateRequestBuilder().System(sys).User(prompt).Temp(0.3).Tokens(4096).Build()
+type GenerateRequestBuilder struct {
+	req GenerateRequest
+}
+
+// NewGenerateRequestBuilder creates a builder for an empty request
+func NewGenerateRequestBuilder() *GenerateRequestBuilder {
+	return &GenerateRequestBuilder{}
+}
+
+// System sets the system prompt
+func (b *GenerateRequestBuilder) System(prompt string) *GenerateRequestBuilder {
+	b.req.SystemPrompt = prompt
+	return b
+}
+
+// User sets the user prompt
+func (b *GenerateRequestBuilder) User(prompt string) *GenerateRequestBuilder {
+	b.req.UserPrompt = prompt
+	return b
+}
+
+// Temp sets the sampling temperature
+func (b *GenerateRequestBuilder) Temp(t float32) *GenerateRequestBuilder {
+	b.req.Temperature = t
+	return b
+}
+
+// Tokens sets the maximum number of tokens to generate
+func (b *GenerateRequestBuilder) Tokens(n int) *GenerateRequestBuilder {
+	b.req.MaxTokens = n
+	return b
+}
+
+// WithTool adds a tool the model may call
+func (b *GenerateRequestBuilder) WithTool(t Tool) *GenerateRequestBuilder {
+	b.req.Tools = append(b.req.Tools, t)
+	return b
+}
+
+// StopAt adds sequences that end generation when produced
+func (b *GenerateRequestBuilder) StopAt(seqs ...string) *GenerateRequestBuilder {
+	b.req.StopSequences = append(b.req.StopSequences, seqs...)
+	return b
+}
+
+// Examples adds few-shot examples sent before the user prompt
+func (b *GenerateRequestBuilder) Examples(examples ...FewShotExample) *GenerateRequestBuilder {
+	b.req.FewShotExamples = append(b.req.FewShotExamples, examples...)
+	return b
+}
+
+// WithSeed sets the sampling seed
+func (b *GenerateRequestBuilder) WithSeed(n int) *GenerateRequestBuilder {
+	b.req.Seed = &n
+	return b
+}
+
+// Build returns the assembled request. It fails with ErrInvalidRequest if no
+// user prompt was set.
+func (b *GenerateRequestBuilder) Build() (GenerateRequest, error) {
+	if b.req.UserPrompt == "" {
+		return GenerateRequest{}, fmt.Errorf("%w: user prompt is required", ErrInvalidRequest)
+	}
+
+	req := b.req
+	req.Tools = append([]Tool(nil), b.req.Tools...)
+	req.StopSequences = append([]string(nil), b.req.StopSequences...)
+	req.FewShotExamples = append([]FewShotExample(nil), b.req.FewShotExamples...)
+	if b.req.Seed != nil {
+		seed := *b.req.Seed
+		req.Seed = &seed
+	}
+	return req, nil
+}
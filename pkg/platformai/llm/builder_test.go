@@ -0,0 +1,45 @@
+package llm
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateRequestBuilder_Build(t *testing.T) {
+	tool := Tool{Name: "lookup", Description: "Look up a value"}
+	seed := 7
+
+	got, err := NewGenerateRequestBuilder().
+		System("system").
+		User("user").
+		Temp(0.3).
+		Tokens(256).
+		WithTool(tool).
+		StopAt("END", "STOP").
+		WithSeed(seed).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	want := GenerateRequest{
+		SystemPrompt:  "system",
+		UserPrompt:    "user",
+		Temperature:   0.3,
+		MaxTokens:     256,
+		Tools:         []Tool{tool},
+		StopSequences: []string{"END", "STOP"},
+		Seed:          &seed,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGenerateRequestBuilder_BuildRequiresUserPrompt(t *testing.T) {
+	_, err := NewGenerateRequestBuilder().System("system").Tokens(100).Build()
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Build() error = %v, want ErrInvalidRequest", err)
+	}
+}
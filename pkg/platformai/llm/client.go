@@ -10,13 +10,15 @@ type Client interface {
 	Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error)
 	GenerateWithContext(ctx context.Context, req GenerateRequest, additionalContext string) (*GenerateResponse, error)
 	GenerateWithTools(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error)
+	EstimateCost(req GenerateRequest) (EstimatedCost, error)
+	Capabilities() ModelCapabilities
 }
 
 // NewClient creates a new LLM client based on config
-func NewClient(config Config) (Client, error) {
+func NewClient(config Config, opts ...Option) (Client, error) {
 	switch config.Provider {
 	case "anthropic":
-		return NewAnthropicClient(config), nil
+		return NewAnthropicClient(config, opts...)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
 	}
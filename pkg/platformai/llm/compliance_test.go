@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Every Client implementation; add new providers here
+var (
+	_ Client = (*AnthropicClient)(nil)
+	_ Client = (*MockClient)(nil)
+	_ Client = (*FallbackClient)(nil)
+	_ Client = (*RoutingClient)(nil)
+)
+
+func TestClientCompliance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(anthropicResponse{
+			Content: []anthropicContentBlock{{Type: "text", Text: "ok"}},
+		})
+	}))
+	defer server.Close()
+
+	// pointAt directs a client created by NewClient to the test server
+	providers := map[string]func(client Client, url string){
+		"anthropic": func(client Client, url string) { client.(*AnthropicClient).apiURL = url },
+	}
+
+	for provider, pointAt := range providers {
+		t.Run(provider, func(t *testing.T) {
+			client, err := NewClient(Config{Provider: provider, APIKey: "test-key", Model: "test-model"})
+			if err != nil {
+				t.Fatalf("NewClient(%q) error = %v", provider, err)
+			}
+			pointAt(client, server.URL)
+
+			resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi"})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if resp == nil || resp.Text != "ok" {
+				t.Errorf("Generate() = %+v, want text %q", resp, "ok")
+			}
+		})
+	}
+}
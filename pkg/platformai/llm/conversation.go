@@ -0,0 +1,444 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// conversationFormatVersion is the version written by ConversationSession.Export
+const conversationFormatVersion = 1
+
+// defaultConversationMaxTokens is used when a Send request has no MaxTokens
+const defaultConversationMaxTokens = 4096
+
+// contextWindowUsage is the fraction of ContextWindowLimit a request may fill
+const contextWindowUsage = 0.9
+
+// defaultMaxToolRounds is used when MaxToolRounds is unset
+const defaultMaxToolRounds = 10
+
+// conversationSummaryPrefix starts the message that replaces a summarized history
+const conversationSummaryPrefix = "[Conversation summary]: "
+
+const summarizePrompt = `Summarize this conversation so far. Keep every fact, decision, open question, and tool result the rest of the conversation may rely on. Reply with the summary only.
+
+Conversation:
+`
+
+// ConversationSession keeps the message history of a multi-turn conversation
+// and sends it with every request. It is safe for concurrent use, though turns
+// are sent one at a time.
+type ConversationSession struct {
+	Tools []Tool // Tools offered to the model on every turn
+
+	// SummarizeThreshold makes Send summarize the history first when it holds more
+	// than this many messages; 0 disables automatic summarization
+	SummarizeThreshold int
+
+	// ContextWindowLimit is the model's context window in tokens. When set, Send
+	// summarizes the history if it plus the new prompt and MaxTokens would fill
+	// more than 90% of the window, then drops the oldest messages if it still does.
+	ContextWindowLimit int
+
+	// TokenCounter counts tokens for ContextWindowLimit (default: HeuristicTokenCounter)
+	TokenCounter TokenCounter
+
+	// MaxToolRounds bounds how many times SendWithAutoTools answers tool calls
+	// before failing with ErrToolLoopExceeded (default: 10)
+	MaxToolRounds int
+
+	client       Client
+	systemPrompt string
+
+	mu           sync.Mutex
+	messages     []Message
+	turns        int
+	toolHandlers map[string]ToolHandler
+}
+
+// ToolHandler runs a tool call for SendWithAutoTools. It returns the result
+// sent back to the model and whether the call succeeded; failed results are
+// marked as errors.
+type ToolHandler func(input map[string]interface{}) (string, bool)
+
+// NewConversationSession creates an empty conversation
+func NewConversationSession(client Client, systemPrompt string) *ConversationSession {
+	return &ConversationSession{client: client, systemPrompt: systemPrompt}
+}
+
+// Send appends req.UserPrompt to the history as a user message, sends the full
+// history, and appends the reply. req.SystemPrompt overrides the session's
+// system prompt for this turn when set.
+func (s *ConversationSession) Send(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	if req.UserPrompt == "" {
+		return nil, fmt.Errorf("%w: user prompt is required", ErrInvalidRequest)
+	}
+
+	return s.send(ctx, req, Message{
+		Role:    "user",
+		Content: []ContentBlock{{Type: "text", Text: req.UserPrompt}},
+	})
+}
+
+// SetToolHandler registers fn to run calls of the named tool in SendWithAutoTools
+func (s *ConversationSession) SetToolHandler(name string, fn ToolHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.toolHandlers == nil {
+		s.toolHandlers = make(map[string]ToolHandler)
+	}
+	s.toolHandlers[name] = fn
+}
+
+// SendWithAutoTools sends req like Send, then keeps answering tool calls: while
+// the reply stops for tool use, it runs each call with its registered handler
+// and sends the results. It returns the first reply that does not call tools,
+// or ErrToolLoopExceeded after MaxToolRounds rounds of tool results, in which
+// case the history is restored to what it was before the call. Calls of tools
+// without a handler are answered with an error result.
+func (s *ConversationSession) SendWithAutoTools(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	maxRounds := s.MaxToolRounds
+	if maxRounds <= 0 {
+		maxRounds = defaultMaxToolRounds
+	}
+
+	s.mu.Lock()
+	history, turns := s.copyMessages(), s.turns
+	s.mu.Unlock()
+
+	resp, err := s.Send(ctx, req)
+	for round := 0; err == nil && resp.StopReason == "tool_use"; round++ {
+		if round == maxRounds {
+			// Drop the unanswered tool call, which the API would reject on the next Send
+			s.mu.Lock()
+			s.messages, s.turns = history, turns
+			s.mu.Unlock()
+			return nil, fmt.Errorf("%w: model still calling tools after %d rounds", ErrToolLoopExceeded, maxRounds)
+		}
+		resp, err = s.send(ctx, req, s.runTools(resp.ToolUses))
+	}
+	return resp, err
+}
+
+// runTools runs tool calls with their handlers and returns the user message
+// holding their results
+func (s *ConversationSession) runTools(uses []ToolUse) Message {
+	s.mu.Lock()
+	handlers := maps.Clone(s.toolHandlers)
+	s.mu.Unlock()
+
+	results := make([]ContentBlock, len(uses))
+	for i, use := range uses {
+		result := ContentBlock{Type: "tool_result", ToolUseID: use.ID}
+		if handler, ok := handlers[use.Name]; ok {
+			var succeeded bool
+			result.Content, succeeded = handler(use.Input)
+			result.IsError = !succeeded
+		} else {
+			result.Content = fmt.Sprintf("no handler registered for tool %s", use.Name)
+			result.IsError = true
+		}
+		results[i] = result
+	}
+	return Message{Role: "user", Content: results}
+}
+
+// send appends prompt to the history, sends the full history with req's
+// settings, and appends the reply. While a tool call is unanswered the history
+// is neither summarized nor trimmed, since the tool results refer to it.
+func (s *ConversationSession) send(ctx context.Context, req GenerateRequest, prompt Message) (*GenerateResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	toolExchange := s.awaitingToolResults()
+	if !toolExchange && s.SummarizeThreshold > 0 && len(s.messages) > s.SummarizeThreshold {
+		if err := s.summarize(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	systemPrompt := req.SystemPrompt
+	if systemPrompt == "" {
+		systemPrompt = s.systemPrompt
+	}
+	maxTokens := req.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = defaultConversationMaxTokens
+	}
+
+	var trimmed int
+	if !toolExchange {
+		var err error
+		if trimmed, err = s.fitContextWindow(ctx, systemPrompt, prompt, maxTokens); err != nil {
+			return nil, err
+		}
+	}
+
+	messages := append(s.copyMessages(), prompt)
+	resp, err := s.client.GenerateWithTools(ctx, GenerateWithToolsRequest{
+		SystemPrompt: systemPrompt,
+		Messages:     messages,
+		Temperature:  req.Temperature,
+		MaxTokens:    maxTokens,
+		Tools:        s.Tools,
+
+		CacheSystemPrompt: req.CacheSystemPrompt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.messages = append(messages, assistantMessage(resp))
+	s.turns++
+	resp.TrimmedMessages = trimmed
+	return resp, nil
+}
+
+// awaitingToolResults reports whether the history ends with a reply calling
+// tools; s.mu must be held
+func (s *ConversationSession) awaitingToolResults() bool {
+	if len(s.messages) == 0 {
+		return false
+	}
+	last := s.messages[len(s.messages)-1]
+	return last.Role == "assistant" && slices.ContainsFunc(last.Content, func(b ContentBlock) bool {
+		return b.Type == "tool_use"
+	})
+}
+
+// fitContextWindow summarizes and then trims the history until it, the system
+// prompt, the new prompt, and maxTokens fit in the context window budget. It
+// returns the number of messages trimmed; s.mu must be held.
+func (s *ConversationSession) fitContextWindow(ctx context.Context, systemPrompt string, prompt Message, maxTokens int) (int, error) {
+	if s.ContextWindowLimit <= 0 {
+		return 0, nil
+	}
+	counter := s.TokenCounter
+	if counter == nil {
+		counter = HeuristicTokenCounter{}
+	}
+
+	budget := int(float64(s.ContextWindowLimit) * contextWindowUsage)
+	fixed := counter.CountTokens(systemPrompt) + messageTokens(counter, prompt) + maxTokens
+	fits := func() bool {
+		total := fixed
+		for _, msg := range s.messages {
+			total += messageTokens(counter, msg)
+		}
+		return total <= budget
+	}
+
+	if fits() {
+		return 0, nil
+	}
+	if fixed > budget {
+		return 0, fmt.Errorf("%w: prompt exceeds the context window", ErrInvalidRequest)
+	}
+	if err := s.summarize(ctx); err != nil {
+		return 0, err
+	}
+
+	trimmed := 0
+	for !fits() && len(s.messages) > 0 {
+		s.messages = s.messages[1:]
+		trimmed++
+		// Never start the history with a reply or an orphaned tool result
+		for len(s.messages) > 0 && !startsConversation(s.messages[0]) {
+			s.messages = s.messages[1:]
+			trimmed++
+		}
+	}
+	return trimmed, nil
+}
+
+// messageTokens counts the tokens in a message's content blocks
+func messageTokens(counter TokenCounter, msg Message) int {
+	tokens := 0
+	for _, block := range msg.Content {
+		tokens += counter.CountTokens(block.Text) + counter.CountTokens(block.Content) + counter.CountTokens(block.Name)
+		if len(block.Input) > 0 {
+			input, _ := json.Marshal(block.Input)
+			tokens += counter.CountTokens(string(input))
+		}
+	}
+	return tokens
+}
+
+// startsConversation reports whether msg may be the first message of a history
+func startsConversation(msg Message) bool {
+	if msg.Role != "user" {
+		return false
+	}
+	for _, block := range msg.Content {
+		if block.Type == "tool_result" {
+			return false
+		}
+	}
+	return true
+}
+
+// Summarize asks the model to summarize the history and replaces it with a
+// single user message holding the summary. The turn count is reset.
+func (s *ConversationSession) Summarize(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.summarize(ctx)
+}
+
+// summarize implements Summarize; s.mu must be held
+func (s *ConversationSession) summarize(ctx context.Context) error {
+	if len(s.messages) == 0 {
+		return nil
+	}
+
+	resp, err := s.client.Generate(ctx, GenerateRequest{
+		SystemPrompt: s.systemPrompt,
+		UserPrompt:   summarizePrompt + transcript(s.messages),
+		MaxTokens:    defaultConversationMaxTokens,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+
+	s.messages = []Message{{
+		Role:    "user",
+		Content: []ContentBlock{{Type: "text", Text: conversationSummaryPrefix + resp.Text}},
+	}}
+	s.turns = 0
+	return nil
+}
+
+// Append adds messages to the history without sending them, such as tool
+// results answering the last reply
+func (s *ConversationSession) Append(messages ...Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, messages...)
+}
+
+// Messages returns a copy of the history
+func (s *ConversationSession) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.copyMessages()
+}
+
+// Turns returns the number of turns sent since the session started
+func (s *ConversationSession) Turns() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.turns
+}
+
+// conversationFile is the stable JSON format of an exported conversation.
+// Fields may be added in later versions; existing ones will not change meaning.
+type conversationFile struct {
+	Version  int       `json:"version"`
+	Messages []Message `json:"messages"`
+}
+
+// Export serializes the history as JSON:
+//
+//	{"version": 1, "messages": [{"role": "user", "content": [{"type": "text", "text": "..."}]}]}
+//
+// The format is stable; Import accepts it from any release that writes version 1.
+func (s *ConversationSession) Export() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(conversationFile{Version: conversationFormatVersion, Messages: s.messages})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal conversation: %w", err)
+	}
+	return data, nil
+}
+
+// Import replaces the history with one produced by Export
+func (s *ConversationSession) Import(data []byte) error {
+	var file conversationFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to unmarshal conversation: %w", err)
+	}
+	if file.Version != conversationFormatVersion {
+		return fmt.Errorf("unsupported conversation format version: %d", file.Version)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = file.Messages
+	return nil
+}
+
+// ExportToFile writes the exported history to path
+func (s *ConversationSession) ExportToFile(path string) error {
+	data, err := s.Export()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write conversation file: %w", err)
+	}
+	return nil
+}
+
+// LoadConversationFromFile creates a session whose history is read from a file
+// written by ExportToFile
+func LoadConversationFromFile(path string, client Client, systemPrompt string) (*ConversationSession, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversation file: %w", err)
+	}
+	session := NewConversationSession(client, systemPrompt)
+	if err := session.Import(data); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// copyMessages returns a copy of the history; s.mu must be held
+func (s *ConversationSession) copyMessages() []Message {
+	return append([]Message(nil), s.messages...)
+}
+
+// transcript renders messages as plain text for the summarization prompt
+func transcript(messages []Message) string {
+	var b strings.Builder
+	for _, msg := range messages {
+		for _, block := range msg.Content {
+			switch block.Type {
+			case "tool_use":
+				input, _ := json.Marshal(block.Input)
+				fmt.Fprintf(&b, "%s: [called tool %s with %s]\n", msg.Role, block.Name, input)
+			case "tool_result":
+				fmt.Fprintf(&b, "%s: [tool result: %s]\n", msg.Role, block.Content)
+			default:
+				fmt.Fprintf(&b, "%s: %s\n", msg.Role, block.Text)
+			}
+		}
+	}
+	return b.String()
+}
+
+// assistantMessage converts a reply into the history message that records it
+func assistantMessage(resp *GenerateResponse) Message {
+	var content []ContentBlock
+	if resp.Text != "" {
+		content = append(content, ContentBlock{Type: "text", Text: resp.Text})
+	}
+	for _, use := range resp.ToolUses {
+		content = append(content, ContentBlock{Type: "tool_use", ID: use.ID, Name: use.Name, Input: use.Input})
+	}
+	return Message{Role: "assistant", Content: content}
+}
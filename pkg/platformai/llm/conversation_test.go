@@ -0,0 +1,389 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConversationSession_Send(t *testing.T) {
+	mock := NewMockClient("hello")
+	session := NewConversationSession(mock, "be brief")
+
+	for _, prompt := range []string{"hi", "again"} {
+		if _, err := session.Send(context.Background(), GenerateRequest{UserPrompt: prompt}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	if got := len(session.Messages()); got != 4 {
+		t.Errorf("Messages() length = %d, want 4", got)
+	}
+	if got := session.Turns(); got != 2 {
+		t.Errorf("Turns() = %d, want 2", got)
+	}
+	reqs := mock.ToolRequests()
+	if last := reqs[len(reqs)-1]; len(last.Messages) != 3 || last.SystemPrompt != "be brief" {
+		t.Errorf("second turn sent %d messages with system %q, want 3 with session prompt", len(last.Messages), last.SystemPrompt)
+	}
+
+	if _, err := session.Send(context.Background(), GenerateRequest{}); err == nil {
+		t.Error("Send() expected error for empty prompt")
+	}
+
+	if _, err := session.Send(context.Background(), GenerateRequest{UserPrompt: "cached", CacheSystemPrompt: true}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	reqs = mock.ToolRequests()
+	if !reqs[len(reqs)-1].CacheSystemPrompt || reqs[0].CacheSystemPrompt {
+		t.Error("Send() should pass CacheSystemPrompt through to GenerateWithTools")
+	}
+}
+
+func TestConversationSession_ExportImport(t *testing.T) {
+	session := NewConversationSession(NewMockClient(""), "")
+	session.Append(
+		Message{Role: "user", Content: []ContentBlock{{Type: "text", Text: "List the files"}}},
+		Message{Role: "assistant", Content: []ContentBlock{
+			{Type: "text", Text: "Let me check."},
+			{Type: "tool_use", ID: "toolu_1", Name: "list_files", Input: map[string]interface{}{"path": "."}},
+		}},
+		Message{Role: "user", Content: []ContentBlock{
+			{Type: "tool_result", ToolUseID: "toolu_1", Content: "main.go", IsError: false},
+		}},
+	)
+
+	data, err := session.Export()
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	restored := NewConversationSession(NewMockClient(""), "")
+	if err := restored.Import(data); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if !reflect.DeepEqual(restored.Messages(), session.Messages()) {
+		t.Errorf("Import() messages = %+v, want %+v", restored.Messages(), session.Messages())
+	}
+
+	path := filepath.Join(t.TempDir(), "conversation.json")
+	if err := session.ExportToFile(path); err != nil {
+		t.Fatalf("ExportToFile() error = %v", err)
+	}
+	loaded, err := LoadConversationFromFile(path, NewMockClient(""), "")
+	if err != nil {
+		t.Fatalf("LoadConversationFromFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Messages(), session.Messages()) {
+		t.Errorf("LoadConversationFromFile() messages = %+v, want %+v", loaded.Messages(), session.Messages())
+	}
+
+	if err := restored.Import([]byte(`{"version": 2, "messages": []}`)); err == nil {
+		t.Error("Import() expected error for unknown version")
+	}
+}
+
+func TestConversationSession_Summarize(t *testing.T) {
+	mock := NewMockClient("the user said hi three times")
+	session := NewConversationSession(mock, "")
+	for i := 0; i < 3; i++ {
+		if _, err := session.Send(context.Background(), GenerateRequest{UserPrompt: "hi"}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+	if got := len(session.Messages()); got != 6 {
+		t.Fatalf("Messages() length = %d, want 6", got)
+	}
+
+	if err := session.Summarize(context.Background()); err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	messages := session.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("Messages() after Summarize length = %d, want 1", len(messages))
+	}
+	if want := "[Conversation summary]: the user said hi three times"; messages[0].Content[0].Text != want {
+		t.Errorf("summary message = %q, want %q", messages[0].Content[0].Text, want)
+	}
+	if got := session.Turns(); got != 0 {
+		t.Errorf("Turns() after Summarize = %d, want 0", got)
+	}
+	if reqs := mock.Requests(); len(reqs) != 1 || !strings.Contains(reqs[0].UserPrompt, "user: hi") {
+		t.Errorf("summarization request = %+v, want transcript of the history", reqs)
+	}
+}
+
+func TestConversationSession_SummarizeThreshold(t *testing.T) {
+	mock := NewMockClient("reply")
+	session := NewConversationSession(mock, "")
+	session.SummarizeThreshold = 4
+
+	for i := 0; i < 3; i++ {
+		if _, err := session.Send(context.Background(), GenerateRequest{UserPrompt: "hi"}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	// Turns 1 and 2 leave 4 messages; turn 3 sees 4 (not above the threshold)
+	// and leaves 6, so nothing has been summarized yet
+	if got := len(mock.Requests()); got != 0 {
+		t.Fatalf("summarization calls = %d, want 0", got)
+	}
+
+	if _, err := session.Send(context.Background(), GenerateRequest{UserPrompt: "hi"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got := len(mock.Requests()); got != 1 {
+		t.Errorf("summarization calls = %d, want 1", got)
+	}
+	// Summary, then the new turn's prompt and reply
+	if got := len(session.Messages()); got != 3 {
+		t.Errorf("Messages() length = %d, want 3", got)
+	}
+}
+
+func TestConversationSession_ContextWindowLimit(t *testing.T) {
+	mock := NewMockClient("one two three four five")
+	mock.GenerateFunc = func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+		return &GenerateResponse{Text: "short"}, nil
+	}
+	session := NewConversationSession(mock, "")
+	session.ContextWindowLimit = 100 // 90 token budget
+	session.TokenCounter = wordCounter{}
+
+	// Each turn adds a 10 word prompt and a 5 word reply. Before turn n the request
+	// needs 15*(n-1) history + 10 prompt + 10 MaxTokens tokens, crossing 90 at n = 6.
+	prompt := strings.Repeat("word ", 10)
+	for turn := 1; turn <= 6; turn++ {
+		resp, err := session.Send(context.Background(), GenerateRequest{UserPrompt: prompt, MaxTokens: 10})
+		if err != nil {
+			t.Fatalf("turn %d: Send() error = %v", turn, err)
+		}
+		wantSummaries := 0
+		if turn == 6 {
+			wantSummaries = 1
+		}
+		if got := len(mock.Requests()); got != wantSummaries {
+			t.Errorf("turn %d: summarization calls = %d, want %d", turn, got, wantSummaries)
+		}
+		if resp.TrimmedMessages != 0 {
+			t.Errorf("turn %d: TrimmedMessages = %d, want 0", turn, resp.TrimmedMessages)
+		}
+	}
+}
+
+func TestConversationSession_ContextWindowTrim(t *testing.T) {
+	mock := NewMockClient("reply")
+	mock.GenerateFunc = func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+		return &GenerateResponse{Text: strings.Repeat("summary ", 50)}, nil
+	}
+	session := NewConversationSession(mock, "")
+	session.ContextWindowLimit = 100
+	session.TokenCounter = wordCounter{}
+	session.Append(
+		Message{Role: "user", Content: []ContentBlock{{Type: "text", Text: strings.Repeat("old ", 30)}}},
+		Message{Role: "assistant", Content: []ContentBlock{{Type: "text", Text: strings.Repeat("old ", 30)}}},
+	)
+
+	// 40 prompt + 10 MaxTokens leaves no room for the 52 word summary
+	resp, err := session.Send(context.Background(), GenerateRequest{UserPrompt: strings.Repeat("new ", 40), MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if resp.TrimmedMessages != 1 {
+		t.Errorf("TrimmedMessages = %d, want 1", resp.TrimmedMessages)
+	}
+	if got := len(session.Messages()); got != 2 {
+		t.Errorf("Messages() length = %d, want 2", got)
+	}
+
+	if _, err := session.Send(context.Background(), GenerateRequest{UserPrompt: strings.Repeat("huge ", 100)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("Send() error = %v, want ErrInvalidRequest for oversized prompt", err)
+	}
+}
+
+func TestConversationSession_SendWithAutoTools(t *testing.T) {
+	mock := NewMockClient("")
+	calls := 0
+	mock.GenerateWithToolsFunc = func(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
+		calls++
+		switch calls {
+		case 1:
+			return &GenerateResponse{StopReason: "tool_use", ToolUses: []ToolUse{
+				{ID: "t1", Name: "lookup", Input: map[string]interface{}{"key": "port"}},
+			}}, nil
+		case 2:
+			return &GenerateResponse{StopReason: "tool_use", ToolUses: []ToolUse{
+				{ID: "t2", Name: "lookup", Input: map[string]interface{}{"key": "missing"}},
+				{ID: "t3", Name: "unknown"},
+			}}, nil
+		default:
+			return &GenerateResponse{Text: "port is 8080", StopReason: "end_turn"}, nil
+		}
+	}
+
+	session := NewConversationSession(mock, "")
+	var keys []string
+	session.SetToolHandler("lookup", func(input map[string]interface{}) (string, bool) {
+		key, _ := input["key"].(string)
+		keys = append(keys, key)
+		if key == "port" {
+			return "8080", true
+		}
+		return "not found", false
+	})
+
+	resp, err := session.SendWithAutoTools(context.Background(), GenerateRequest{UserPrompt: "which port?"})
+	if err != nil {
+		t.Fatalf("SendWithAutoTools() error = %v", err)
+	}
+	if resp.Text != "port is 8080" {
+		t.Errorf("SendWithAutoTools() text = %q, want %q", resp.Text, "port is 8080")
+	}
+	if want := []string{"port", "missing"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("handler keys = %v, want %v", keys, want)
+	}
+
+	// user, assistant, tool results, assistant, tool results, assistant
+	messages := session.Messages()
+	if len(messages) != 6 {
+		t.Fatalf("history length = %d, want 6", len(messages))
+	}
+	want := []ContentBlock{
+		{Type: "tool_result", ToolUseID: "t2", Content: "not found", IsError: true},
+		{Type: "tool_result", ToolUseID: "t3", Content: "no handler registered for tool unknown", IsError: true},
+	}
+	if got := messages[4].Content; !reflect.DeepEqual(got, want) {
+		t.Errorf("second tool results = %+v, want %+v", got, want)
+	}
+	first := messages[2].Content
+	if len(first) != 1 || first[0].Content != "8080" || first[0].IsError {
+		t.Errorf("first tool results = %+v, want one successful result", first)
+	}
+	if got := len(mock.ToolRequests()[2].Messages); got != 5 {
+		t.Errorf("final request messages = %d, want 5", got)
+	}
+}
+
+func TestConversationSession_SendWithAutoToolsAPI(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			_, _ = io.WriteString(w, `{"role": "assistant", "stop_reason": "tool_use", "content": [`+
+				`{"type": "tool_use", "id": "t1", "name": "weather", "input": {"city": "Bern"}}]}`)
+			return
+		}
+		var body struct {
+			Messages []struct {
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || len(body.Messages) != 3 {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		// The assistant's tool call is sent back with its input
+		if echoed := string(body.Messages[1].Content); !strings.Contains(echoed, `"input":{"city":"Bern"}`) {
+			http.Error(w, "tool_use sent back as "+echoed, http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, `{"role": "assistant", "stop_reason": "end_turn", "content": [{"type": "text", "text": "sunny"}]}`)
+	}))
+	defer server.Close()
+
+	session := NewConversationSession(newTestClient(server.URL), "")
+	var city interface{}
+	session.SetToolHandler("weather", func(input map[string]interface{}) (string, bool) {
+		city = input["city"]
+		return "sunny", true
+	})
+
+	resp, err := session.SendWithAutoTools(context.Background(), GenerateRequest{UserPrompt: "weather in Bern?"})
+	if err != nil {
+		t.Fatalf("SendWithAutoTools() error = %v", err)
+	}
+	if city != "Bern" {
+		t.Errorf("handler input city = %v, want Bern", city)
+	}
+	if resp.Text != "sunny" {
+		t.Errorf("SendWithAutoTools() text = %q, want sunny", resp.Text)
+	}
+}
+
+func TestConversationSession_SendWithAutoToolsExceeded(t *testing.T) {
+	mock := NewMockClient("")
+	mock.GenerateWithToolsFunc = func(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
+		return &GenerateResponse{StopReason: "tool_use", ToolUses: []ToolUse{{ID: "t", Name: "loop"}}}, nil
+	}
+
+	session := NewConversationSession(mock, "")
+	session.MaxToolRounds = 2
+	session.SetToolHandler("loop", func(map[string]interface{}) (string, bool) { return "again", true })
+	before := []Message{
+		{Role: "user", Content: []ContentBlock{{Type: "text", Text: "hi"}}},
+		{Role: "assistant", Content: []ContentBlock{{Type: "text", Text: "hello"}}},
+	}
+	session.Append(before...)
+
+	_, err := session.SendWithAutoTools(context.Background(), GenerateRequest{UserPrompt: "go"})
+	if !errors.Is(err, ErrToolLoopExceeded) {
+		t.Fatalf("SendWithAutoTools() error = %v, want ErrToolLoopExceeded", err)
+	}
+	if got := len(mock.ToolRequests()); got != 3 {
+		t.Errorf("requests = %d, want 3", got)
+	}
+
+	// The unanswered tool call is rolled back, so the session stays usable
+	if got := session.Messages(); !reflect.DeepEqual(got, before) {
+		t.Errorf("Messages() after overflow = %+v, want %+v", got, before)
+	}
+	if got := session.Turns(); got != 0 {
+		t.Errorf("Turns() after overflow = %d, want 0", got)
+	}
+}
+
+func TestConversationSession_SendWithAutoToolsKeepsExchange(t *testing.T) {
+	mock := NewMockClient("summary")
+	rounds := 0
+	mock.GenerateWithToolsFunc = func(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
+		// Every request after a tool call must still carry that call
+		if last := req.Messages[len(req.Messages)-1]; last.Content[0].Type == "tool_result" {
+			call := req.Messages[len(req.Messages)-2]
+			if call.Role != "assistant" || call.Content[0].ID != last.Content[0].ToolUseID {
+				t.Errorf("tool result %s sent without its tool call", last.Content[0].ToolUseID)
+			}
+		}
+		rounds++
+		if rounds < 3 {
+			return &GenerateResponse{StopReason: "tool_use", ToolUses: []ToolUse{{ID: fmt.Sprintf("t%d", rounds), Name: "noop"}}}, nil
+		}
+		return &GenerateResponse{Text: "done", StopReason: "end_turn"}, nil
+	}
+
+	session := NewConversationSession(mock, "")
+	session.SummarizeThreshold = 1
+	// The second tool result pushes the history past the window, which would
+	// otherwise summarize and trim it
+	session.ContextWindowLimit = 60
+	session.TokenCounter = wordCounter{}
+	session.SetToolHandler("noop", func(map[string]interface{}) (string, bool) { return strings.Repeat("data ", 30), true })
+
+	if _, err := session.SendWithAutoTools(context.Background(), GenerateRequest{UserPrompt: "go", MaxTokens: 10}); err != nil {
+		t.Fatalf("SendWithAutoTools() error = %v", err)
+	}
+	if got := len(mock.Requests()); got != 0 {
+		t.Errorf("summarization calls = %d, want 0 during a tool exchange", got)
+	}
+	// user, tool call, result, tool call, result, reply
+	if got := len(session.Messages()); got != 6 {
+		t.Errorf("Messages() length = %d, want 6", got)
+	}
+}
@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ModelPricing is a model's list price and default output limit
+type ModelPricing struct {
+	InputPerMTok      float64 // USD per million input tokens
+	OutputPerMTok     float64 // USD per million output tokens
+	CacheReadPerMTok  float64 // USD per million input tokens read from the prompt cache
+	CacheWritePerMTok float64 // USD per million input tokens written to the prompt cache (5 minute TTL)
+	DefaultMaxTokens  int     // Output limit assumed when a request sets no MaxTokens
+}
+
+// modelPricing maps model ID prefixes to pricing; dated IDs such as
+// claude-sonnet-4-5-20250929 match their family prefix
+var modelPricing = map[string]ModelPricing{
+	"claude-opus-4-1":   {InputPerMTok: 15, OutputPerMTok: 75, CacheReadPerMTok: 1.5, CacheWritePerMTok: 18.75, DefaultMaxTokens: 32000},
+	"claude-opus-4":     {InputPerMTok: 15, OutputPerMTok: 75, CacheReadPerMTok: 1.5, CacheWritePerMTok: 18.75, DefaultMaxTokens: 32000},
+	"claude-sonnet-4-5": {InputPerMTok: 3, OutputPerMTok: 15, CacheReadPerMTok: 0.3, CacheWritePerMTok: 3.75, DefaultMaxTokens: 64000},
+	"claude-sonnet-4":   {InputPerMTok: 3, OutputPerMTok: 15, CacheReadPerMTok: 0.3, CacheWritePerMTok: 3.75, DefaultMaxTokens: 64000},
+	"claude-haiku-4-5":  {InputPerMTok: 1, OutputPerMTok: 5, CacheReadPerMTok: 0.1, CacheWritePerMTok: 1.25, DefaultMaxTokens: 64000},
+	"claude-3-7-sonnet": {InputPerMTok: 3, OutputPerMTok: 15, CacheReadPerMTok: 0.3, CacheWritePerMTok: 3.75, DefaultMaxTokens: 64000},
+	"claude-3-5-haiku":  {InputPerMTok: 0.8, OutputPerMTok: 4, CacheReadPerMTok: 0.08, CacheWritePerMTok: 1, DefaultMaxTokens: 8192},
+}
+
+// PricingFor returns the pricing of the longest model prefix matching model
+func PricingFor(model string) (ModelPricing, error) {
+	var best string
+	for prefix := range modelPricing {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return ModelPricing{}, fmt.Errorf("no pricing known for model %q", model)
+	}
+	return modelPricing[best], nil
+}
+
+// EstimatedCost is the projected cost of a request. Output cost assumes the
+// model uses its full output allowance, so it is an upper bound.
+type EstimatedCost struct {
+	InputTokens       int
+	InputCostUSD      float64
+	MaxOutputTokens   int
+	MaxOutputCostUSD  float64
+	TotalEstimatedUSD float64
+}
+
+// estimateCost prices req for model, counting the prompts and tool definitions with counter
+func estimateCost(model string, counter TokenCounter, req GenerateRequest) (EstimatedCost, error) {
+	pricing, err := PricingFor(model)
+	if err != nil {
+		return EstimatedCost{}, err
+	}
+
+	inputTokens := counter.CountTokens(req.SystemPrompt) + counter.CountTokens(req.UserPrompt)
+	if len(req.Tools) > 0 {
+		tools, err := json.Marshal(req.Tools)
+		if err != nil {
+			return EstimatedCost{}, fmt.Errorf("failed to marshal tools: %w", err)
+		}
+		inputTokens += counter.CountTokens(string(tools))
+	}
+
+	maxOutput := req.MaxTokens
+	if maxOutput <= 0 {
+		maxOutput = pricing.DefaultMaxTokens
+	}
+
+	cost := EstimatedCost{
+		InputTokens:      inputTokens,
+		InputCostUSD:     float64(inputTokens) * pricing.InputPerMTok / 1e6,
+		MaxOutputTokens:  maxOutput,
+		MaxOutputCostUSD: float64(maxOutput) * pricing.OutputPerMTok / 1e6,
+	}
+	cost.TotalEstimatedUSD = cost.InputCostUSD + cost.MaxOutputCostUSD
+	return cost, nil
+}
+
+// checkCostThreshold fails with ErrCostThresholdExceeded when req sets a
+// CostThreshold its estimate exceeds
+func checkCostThreshold(model string, counter TokenCounter, req GenerateRequest) error {
+	if req.CostThreshold <= 0 {
+		return nil
+	}
+	cost, err := estimateCost(model, counter, req)
+	if err != nil {
+		return fmt.Errorf("failed to estimate cost: %w", err)
+	}
+	if cost.TotalEstimatedUSD > req.CostThreshold {
+		return fmt.Errorf("%w: estimated $%.4f exceeds threshold $%.4f",
+			ErrCostThresholdExceeded, cost.TotalEstimatedUSD, req.CostThreshold)
+	}
+	return nil
+}
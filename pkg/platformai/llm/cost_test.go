@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+// wordCounter counts whitespace-separated words, giving tests exact token counts
+type wordCounter struct{}
+
+func (wordCounter) CountTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestAnthropicClient_EstimateCost(t *testing.T) {
+	client := newTestClient("http://unused")
+	client.model = "claude-sonnet-4-5-20250929"
+	client.tokens = wordCounter{}
+
+	tests := []struct {
+		name       string
+		req        GenerateRequest
+		wantInput  int
+		wantOutput int
+	}{
+		{"explicit max tokens", GenerateRequest{SystemPrompt: "one two three", UserPrompt: "four five", MaxTokens: 1000}, 5, 1000},
+		{"default max tokens", GenerateRequest{UserPrompt: "one two three four"}, 4, 64000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cost, err := client.EstimateCost(tt.req)
+			if err != nil {
+				t.Fatalf("EstimateCost() error = %v", err)
+			}
+			if cost.InputTokens != tt.wantInput || cost.MaxOutputTokens != tt.wantOutput {
+				t.Errorf("EstimateCost() tokens = %d in / %d out, want %d / %d",
+					cost.InputTokens, cost.MaxOutputTokens, tt.wantInput, tt.wantOutput)
+			}
+			wantTotal := float64(tt.wantInput)*3/1e6 + float64(tt.wantOutput)*15/1e6
+			if math.Abs(cost.TotalEstimatedUSD-wantTotal) > 1e-12 {
+				t.Errorf("EstimateCost() total = %v, want %v", cost.TotalEstimatedUSD, wantTotal)
+			}
+			if math.Abs(cost.InputCostUSD+cost.MaxOutputCostUSD-cost.TotalEstimatedUSD) > 1e-12 {
+				t.Errorf("EstimateCost() total %v is not input + output", cost.TotalEstimatedUSD)
+			}
+		})
+	}
+
+	client.model = "unknown-model"
+	if _, err := client.EstimateCost(GenerateRequest{UserPrompt: "hi"}); err == nil {
+		t.Error("EstimateCost() expected error for model without pricing")
+	}
+}
+
+func TestAnthropicClient_CostThreshold(t *testing.T) {
+	var body []byte
+	client := newTestClient(newCaptureServer(t, &body).URL)
+	client.tokens = wordCounter{}
+
+	// 1000 output tokens at $15/MTok is $0.015
+	req := GenerateRequest{UserPrompt: "hello", MaxTokens: 1000, CostThreshold: 0.01}
+	if _, err := client.Generate(context.Background(), req); !errors.Is(err, ErrCostThresholdExceeded) {
+		t.Errorf("Generate() error = %v, want ErrCostThresholdExceeded", err)
+	}
+	if body != nil {
+		t.Error("request over the cost threshold was sent")
+	}
+
+	req.CostThreshold = 0.02
+	if _, err := client.Generate(context.Background(), req); err != nil {
+		t.Errorf("Generate() under threshold error = %v", err)
+	}
+}
+
+func TestHeuristicTokenCounter_CountTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 2},
+		{"12345", 2},
+		{"func main() {}", 5},
+	}
+	for _, tt := range tests {
+		if got := (HeuristicTokenCounter{}).CountTokens(tt.text); got != tt.want {
+			t.Errorf("CountTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultDLQMaxRetries is how often Replay retries an entry before leaving it for inspection
+const DefaultDLQMaxRetries = 3
+
+// DLQEntry is a failed request held by a DeadLetterQueue
+type DLQEntry struct {
+	Request    GenerateRequest
+	Error      string // Most recent failure
+	Timestamp  time.Time
+	RetryCount int // Replays attempted so far
+	Metadata   map[string]string
+}
+
+// DeadLetterQueue keeps failed generation requests so they can be inspected or replayed
+type DeadLetterQueue struct {
+	MaxRetries int // Replays per entry; entries at the limit are skipped by Replay
+
+	mu      sync.Mutex
+	entries []*DLQEntry
+	now     func() time.Time
+}
+
+// NewDeadLetterQueue creates an empty queue with DefaultDLQMaxRetries
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{
+		MaxRetries: DefaultDLQMaxRetries,
+		now:        time.Now,
+	}
+}
+
+// Enqueue adds a failed request
+func (q *DeadLetterQueue) Enqueue(req GenerateRequest, err error, metadata map[string]string) {
+	entry := &DLQEntry{
+		Request:   req,
+		Timestamp: q.now(),
+		Metadata:  metadata,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries = append(q.entries, entry)
+}
+
+// Dequeue removes and returns the oldest entry
+func (q *DeadLetterQueue) Dequeue() (*DLQEntry, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.entries) == 0 {
+		return nil, false
+	}
+	entry := q.entries[0]
+	q.entries = q.entries[1:]
+	return entry, true
+}
+
+// Len returns the number of queued entries
+func (q *DeadLetterQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.entries)
+}
+
+// Replay resends every entry below MaxRetries with client, oldest first.
+// Successful entries are removed and their responses returned; failed entries
+// stay queued with their retry count incremented. The returned error joins the
+// replay failures.
+func (q *DeadLetterQueue) Replay(ctx context.Context, client Client) ([]*GenerateResponse, error) {
+	q.mu.Lock()
+	pending := make([]*DLQEntry, 0, len(q.entries))
+	for _, entry := range q.entries {
+		if entry.RetryCount < q.MaxRetries {
+			pending = append(pending, entry)
+		}
+	}
+	q.mu.Unlock()
+
+	// Failures during replay are tracked on the entry, not enqueued again
+	ctx = context.WithValue(ctx, replayingKey{}, true)
+
+	var responses []*GenerateResponse
+	var errs []error
+	for _, entry := range pending {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+
+		resp, err := client.Generate(ctx, entry.Request)
+
+		q.mu.Lock()
+		if err != nil {
+			entry.RetryCount++
+			entry.Error = err.Error()
+			errs = append(errs, fmt.Errorf("replay %d of request queued at %s failed: %w",
+				entry.RetryCount, entry.Timestamp.Format(time.RFC3339), err))
+		} else {
+			q.remove(entry)
+			responses = append(responses, resp)
+		}
+		q.mu.Unlock()
+	}
+
+	return responses, errors.Join(errs...)
+}
+
+// remove deletes entry from the queue. Callers must hold the lock.
+func (q *DeadLetterQueue) remove(entry *DLQEntry) {
+	for i, e := range q.entries {
+		if e == entry {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// replayingKey marks contexts of replayed requests
+type replayingKey struct{}
+
+// shouldDeadLetter reports whether a failed Generate call belongs in the dead
+// letter queue: it was sent or attempted and might succeed on retry, and the
+// caller did not give up on it
+func shouldDeadLetter(ctx context.Context, err error) bool {
+	if replaying, _ := ctx.Value(replayingKey{}).(bool); replaying {
+		return false
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+	return !isRejection(err) && !errors.Is(err, ErrToolsNotSupported)
+}
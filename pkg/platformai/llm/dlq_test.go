@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeadLetterQueue_ReplayCycle(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(anthropicResponse{Content: []anthropicContentBlock{{Type: "text", Text: "recovered"}}})
+	}))
+	defer server.Close()
+
+	dlq := NewDeadLetterQueue()
+	dlq.MaxRetries = 2
+	client := newTestClient(server.URL)
+	WithDeadLetterQueue(dlq)(client)
+	ctx := context.Background()
+
+	for _, prompt := range []string{"first", "second"} {
+		if _, err := client.Generate(ctx, GenerateRequest{UserPrompt: prompt, MaxTokens: 10}); err == nil {
+			t.Fatal("Generate() expected error from unavailable API")
+		}
+	}
+	// Requests that cannot succeed on retry or that the caller canceled are not queued
+	_, _ = client.Generate(ctx, GenerateRequest{UserPrompt: "bad", Temperature: 0.5, TopP: 0.9})
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _ = client.Generate(canceled, GenerateRequest{UserPrompt: "canceled", MaxTokens: 10})
+	if dlq.Len() != 2 {
+		t.Fatalf("Len() after failures = %d, want 2", dlq.Len())
+	}
+
+	// Replaying while the API is still down keeps the entries and counts the retry
+	responses, err := dlq.Replay(ctx, client)
+	if err == nil || len(responses) != 0 {
+		t.Errorf("Replay() while down = %d responses, err %v; want none and an error", len(responses), err)
+	}
+	if dlq.Len() != 2 {
+		t.Errorf("Len() after failed replay = %d, want 2; replay failures must not be requeued", dlq.Len())
+	}
+
+	healthy.Store(true)
+	responses, err = dlq.Replay(ctx, client)
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if len(responses) != 2 || responses[0].Text != "recovered" {
+		t.Errorf("Replay() = %d responses, want 2 recovered", len(responses))
+	}
+	if dlq.Len() != 0 {
+		t.Errorf("Len() after successful replay = %d, want 0", dlq.Len())
+	}
+}
+
+func TestDeadLetterQueue_MaxRetries(t *testing.T) {
+	dlq := NewDeadLetterQueue()
+	dlq.MaxRetries = 1
+	dlq.Enqueue(GenerateRequest{UserPrompt: "x"}, nil, map[string]string{"job": "batch-7"})
+
+	failing := NewMockClient("")
+	failing.GenerateFunc = func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+		return nil, context.DeadlineExceeded
+	}
+	for i := 0; i < 3; i++ {
+		_, _ = dlq.Replay(context.Background(), failing)
+	}
+	if got := len(failing.Requests()); got != 1 {
+		t.Errorf("replay attempts = %d, want 1 with MaxRetries 1", got)
+	}
+
+	entry, ok := dlq.Dequeue()
+	if !ok {
+		t.Fatal("Dequeue() found no entry")
+	}
+	if entry.RetryCount != 1 || entry.Metadata["job"] != "batch-7" || entry.Error == "" {
+		t.Errorf("Dequeue() = %+v, want retry count 1, metadata, and the last error", entry)
+	}
+	if _, ok := dlq.Dequeue(); ok {
+		t.Error("Dequeue() on empty queue returned an entry")
+	}
+}
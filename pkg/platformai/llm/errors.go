@@ -0,0 +1,39 @@
+package llm
+
+import "errors"
+
+// Common error types for LLM clients
+var (
+	// ErrInvalidRequest indicates that a generate request has invalid or conflicting parameters
+	ErrInvalidRequest = NewSDKError(ErrCodeInvalidRequest, "invalid generate request")
+
+	// ErrContentModerated indicates that a prompt or response was flagged by the content moderator
+	ErrContentModerated = NewSDKError(ErrCodeContentModerated, "content flagged by moderation")
+
+	// ErrCostThresholdExceeded indicates that a request's estimated cost is above its CostThreshold
+	ErrCostThresholdExceeded = NewSDKError(ErrCodeCostThreshold, "estimated cost exceeds threshold")
+
+	// ErrQuotaExceeded indicates that the caller's token quota is used up
+	ErrQuotaExceeded = NewSDKError(ErrCodeQuotaExceeded, "token quota exceeded")
+
+	// ErrPromptNotFound indicates that a TemplateLibrary has no prompt or version with the requested name or ID
+	ErrPromptNotFound = NewSDKError(ErrCodePromptNotFound, "prompt not found")
+
+	// ErrToolsNotSupported indicates a tools request to a model without function calling
+	ErrToolsNotSupported = NewSDKError(ErrCodeToolsNotSupported, "model does not support tools")
+
+	// ErrToolLoopExceeded indicates that a model kept calling tools for more than MaxToolRounds rounds
+	ErrToolLoopExceeded = NewSDKError(ErrCodeToolLoopExceeded, "tool call rounds exceeded")
+)
+
+// isRejection reports whether err is a request refused by the client's own
+// validation, moderation, cost, or quota checks rather than a provider failure.
+// Sending the request again, or to another provider, must not get around it.
+func isRejection(err error) bool {
+	for _, rejected := range []error{ErrInvalidRequest, ErrContentModerated, ErrCostThresholdExceeded, ErrQuotaExceeded} {
+		if errors.Is(err, rejected) {
+			return true
+		}
+	}
+	return false
+}
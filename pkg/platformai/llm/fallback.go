@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultCooldownPeriod is how long FallbackClient skips a failed client when CooldownPeriod is unset
+const DefaultCooldownPeriod = 30 * time.Second
+
+// FallbackClient sends requests to a primary client and, when it fails, to
+// fallback clients in order. A client that fails is skipped for CooldownPeriod,
+// after which it is tried again. Clients still cooling down are tried last
+// rather than not at all. Requests a client rejects itself, such as
+// ErrContentModerated or ErrQuotaExceeded, are returned without falling back.
+//
+// GenerateResponse.UsedProvider reports the client that answered: its Name()
+// when it has one, otherwise "primary" or "fallback-N".
+type FallbackClient struct {
+	CooldownPeriod time.Duration // default: 30s
+
+	clients []Client
+	now     func() time.Time
+
+	mu      sync.Mutex
+	retryAt []time.Time
+}
+
+// NewFallbackClient creates a fallback client
+func NewFallbackClient(primary Client, fallbacks ...Client) *FallbackClient {
+	clients := append([]Client{primary}, fallbacks...)
+	return &FallbackClient{
+		CooldownPeriod: DefaultCooldownPeriod,
+		clients:        clients,
+		now:            time.Now,
+		retryAt:        make([]time.Time, len(clients)),
+	}
+}
+
+// Generate generates with the first available client that succeeds
+func (c *FallbackClient) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	return c.do(ctx, func(client Client) (*GenerateResponse, error) {
+		return client.Generate(ctx, req)
+	})
+}
+
+// GenerateWithContext generates with the first available client that succeeds
+func (c *FallbackClient) GenerateWithContext(ctx context.Context, req GenerateRequest, additionalContext string) (*GenerateResponse, error) {
+	return c.do(ctx, func(client Client) (*GenerateResponse, error) {
+		return client.GenerateWithContext(ctx, req, additionalContext)
+	})
+}
+
+// GenerateWithTools generates with the first available client that succeeds
+func (c *FallbackClient) GenerateWithTools(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
+	return c.do(ctx, func(client Client) (*GenerateResponse, error) {
+		return client.GenerateWithTools(ctx, req)
+	})
+}
+
+// EstimateCost estimates the cost on the primary client
+func (c *FallbackClient) EstimateCost(req GenerateRequest) (EstimatedCost, error) {
+	return c.clients[0].EstimateCost(req)
+}
+
+// Capabilities returns the capabilities of the primary client
+func (c *FallbackClient) Capabilities() ModelCapabilities {
+	return c.clients[0].Capabilities()
+}
+
+// RotateAPIKey replaces the API key of the primary client
+func (c *FallbackClient) RotateAPIKey(newKey string) error {
+	rotator, ok := c.clients[0].(interface{ RotateAPIKey(newKey string) error })
+	if !ok {
+		return fmt.Errorf("%w: primary client does not support key rotation", errors.ErrUnsupported)
+	}
+	return rotator.RotateAPIKey(newKey)
+}
+
+// SetHTTPClient replaces the HTTP client of every client that supports it
+func (c *FallbackClient) SetHTTPClient(httpClient *http.Client) {
+	for _, client := range c.clients {
+		if setter, ok := client.(interface{ SetHTTPClient(*http.Client) }); ok {
+			setter.SetHTTPClient(httpClient)
+		}
+	}
+}
+
+func (c *FallbackClient) do(ctx context.Context, call func(Client) (*GenerateResponse, error)) (*GenerateResponse, error) {
+	var errs []error
+	for _, i := range c.candidates() {
+		resp, err := call(c.clients[i])
+		if err == nil {
+			c.recordSuccess(i)
+			resp.UsedProvider = c.name(i)
+			return resp, nil
+		}
+		// A request the client itself refused would be refused by the fallbacks too,
+		// or worse, sent to one without the same checks
+		if isRejection(err) {
+			return nil, fmt.Errorf("%s: %w", c.name(i), err)
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", c.name(i), err))
+		// The caller giving up says nothing about the client's health
+		if ctx.Err() != nil {
+			break
+		}
+		c.recordFailure(i)
+	}
+	return nil, fmt.Errorf("all LLM providers failed: %w", errors.Join(errs...))
+}
+
+// name returns the label UsedProvider reports for client i
+func (c *FallbackClient) name(i int) string {
+	if named, ok := c.clients[i].(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	if i == 0 {
+		return "primary"
+	}
+	return fmt.Sprintf("fallback-%d", i)
+}
+
+// candidates returns client indexes to try in order: those not cooling down
+// first, then those still cooling down
+func (c *FallbackClient) candidates() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	ready := make([]int, 0, len(c.clients))
+	var waiting []int
+	for i := range c.clients {
+		if now.Before(c.retryAt[i]) {
+			waiting = append(waiting, i)
+		} else {
+			ready = append(ready, i)
+		}
+	}
+	return append(ready, waiting...)
+}
+
+func (c *FallbackClient) recordSuccess(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retryAt[i] = time.Time{}
+}
+
+func (c *FallbackClient) recordFailure(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cooldown := c.CooldownPeriod
+	if cooldown <= 0 {
+		cooldown = DefaultCooldownPeriod
+	}
+	c.retryAt[i] = c.now().Add(cooldown)
+}
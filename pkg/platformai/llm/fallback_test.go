@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFallbackClient_Generate(t *testing.T) {
+	start := time.Now()
+	now := start
+
+	// The primary is down for the first 2 seconds
+	primary := NewMockClient("primary")
+	primary.GenerateFunc = func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+		if now.Sub(start) < 2*time.Second {
+			return nil, errors.New("service unavailable")
+		}
+		return &GenerateResponse{Text: "primary"}, nil
+	}
+	secondary := NewMockClient("secondary")
+
+	client := NewFallbackClient(primary, secondary)
+	client.CooldownPeriod = time.Second
+	client.now = func() time.Time { return now }
+
+	steps := []struct {
+		name         string
+		at           time.Duration
+		wantProvider string
+		wantPrimary  int // Calls the primary has received after the step
+	}{
+		{"primary fails", 0, "fallback-1", 1},
+		{"primary cooling down", 500 * time.Millisecond, "fallback-1", 1},
+		{"primary retried after cooldown, still down", 1500 * time.Millisecond, "fallback-1", 2},
+		{"primary recovered", 3 * time.Second, "primary", 3},
+	}
+	for _, step := range steps {
+		now = start.Add(step.at)
+		resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi"})
+		if err != nil {
+			t.Fatalf("%s: Generate() error = %v", step.name, err)
+		}
+		if resp.UsedProvider != step.wantProvider {
+			t.Errorf("%s: UsedProvider = %q, want %q", step.name, resp.UsedProvider, step.wantProvider)
+		}
+		if got := len(primary.Requests()); got != step.wantPrimary {
+			t.Errorf("%s: primary calls = %d, want %d", step.name, got, step.wantPrimary)
+		}
+	}
+}
+
+func TestFallbackClient_AllFail(t *testing.T) {
+	unavailable := errors.New("service unavailable")
+	failing := func() *MockClient {
+		m := NewMockClient("")
+		m.GenerateFunc = func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+			return nil, unavailable
+		}
+		return m
+	}
+	primary, secondary := failing(), failing()
+	client := NewFallbackClient(primary, secondary)
+
+	_, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi"})
+	if !errors.Is(err, unavailable) {
+		t.Errorf("Generate() error = %v, want service unavailable", err)
+	}
+	if len(primary.Requests()) != 1 || len(secondary.Requests()) != 1 {
+		t.Errorf("calls = %d, %d; want both clients tried", len(primary.Requests()), len(secondary.Requests()))
+	}
+}
+
+func TestFallbackClient_Rejection(t *testing.T) {
+	for _, rejection := range []error{ErrInvalidRequest, ErrContentModerated, ErrCostThresholdExceeded, ErrQuotaExceeded} {
+		primary := NewMockClient("")
+		primary.GenerateFunc = func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+			return nil, fmt.Errorf("%w: guarded", rejection)
+		}
+		secondary := NewMockClient("secondary")
+		client := NewFallbackClient(primary, secondary)
+
+		if _, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi"}); !errors.Is(err, rejection) {
+			t.Errorf("Generate() error = %v, want %v", err, rejection)
+		}
+		if got := len(secondary.Requests()); got != 0 {
+			t.Errorf("%v: fallback calls = %d, want 0", rejection, got)
+		}
+		if !client.retryAt[0].IsZero() {
+			t.Errorf("%v: primary put into cooldown", rejection)
+		}
+	}
+}
+
+func TestFallbackClient_CallerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	primary := NewMockClient("")
+	primary.GenerateFunc = func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+		cancel()
+		return nil, ctx.Err()
+	}
+	client := NewFallbackClient(primary, NewMockClient("secondary"))
+
+	if _, err := client.Generate(ctx, GenerateRequest{UserPrompt: "hi"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Generate() error = %v, want context.Canceled", err)
+	}
+	if !client.retryAt[0].IsZero() {
+		t.Error("primary put into cooldown by the caller's cancellation")
+	}
+}
@@ -0,0 +1,46 @@
+//go:build fuzz
+
+package llm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// bodyTransport answers every request with status 200 and body
+type bodyTransport struct {
+	body *string
+}
+
+func (t bodyTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(*t.body)),
+	}, nil
+}
+
+// FuzzAnthropicResponseParsing feeds arbitrary response bodies through
+// Generate's parsing. Run with: go test -tags fuzz -fuzz FuzzAnthropicResponseParsing ./pkg/platformai/llm
+func FuzzAnthropicResponseParsing(f *testing.F) {
+	f.Add(`{"content":[{"type":"text","text":"via proxy"}],"stop_reason":"end_turn"}`)
+	f.Add(`{"id":"msg_capture","type":"message","role":"assistant","content":[{"type":"text","text":"ok"}],"model":"claude-sonnet-4-5-20250929","stop_reason":"end_turn","usage":{"input_tokens":12,"output_tokens":3}}`)
+	f.Add("{\"content\":[{\"type\":\"text\",\"text\":\"```json\\n{\\\"a\\\":1}\\n```\"}]}")
+	f.Add(`{"content":[{"type":"tool_use","id":"toolu_1","name":"lookup","input":{"q":"x"}}],"stop_reason":"tool_use"}`)
+	f.Add(`{"content":null,"usage":{"input_tokens":-1}}`)
+
+	var body string
+	client := newTestClient("http://api.test/v1/messages")
+	client.SetHTTPClient(&http.Client{Transport: bodyTransport{body: &body}})
+
+	f.Fuzz(func(t *testing.T, input string) {
+		body = input
+		resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi"})
+		if err == nil && resp == nil {
+			t.Fatal("Generate() returned neither a response nor an error")
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader carries GenerateRequest.IdempotencyKey to the provider
+const IdempotencyKeyHeader = "X-Idempotency-Key"
+
+// defaultIdempotencyWindow is how long responses are replayed for a repeated idempotency key
+const defaultIdempotencyWindow = 10 * time.Minute
+
+// idempotencyCache remembers responses by idempotency key so a retried request
+// is answered without generating again. The zero value is ready to use.
+type idempotencyCache struct {
+	entries sync.Map // Cache key -> idempotencyEntry
+}
+
+// idempotencyEntry is a cached response and when it stops being replayed
+type idempotencyEntry struct {
+	resp    *GenerateResponse
+	expires time.Time
+}
+
+// idempotencyCacheKey scopes an idempotency key to a model
+func idempotencyCacheKey(key, model string) string {
+	sum := sha256.Sum256([]byte(key + "\x00" + model))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns a copy of the unexpired response cached for key and model
+func (c *idempotencyCache) get(key, model string) (*GenerateResponse, bool) {
+	cacheKey := idempotencyCacheKey(key, model)
+	value, ok := c.entries.Load(cacheKey)
+	if !ok {
+		return nil, false
+	}
+	entry := value.(idempotencyEntry)
+	if time.Now().After(entry.expires) {
+		c.entries.CompareAndDelete(cacheKey, value)
+		return nil, false
+	}
+	resp := *entry.resp
+	return &resp, true
+}
+
+// put caches resp for key and model for window, dropping expired entries
+func (c *idempotencyCache) put(key, model string, resp *GenerateResponse, window time.Duration) {
+	now := time.Now()
+	c.entries.Range(func(k, v any) bool {
+		if now.After(v.(idempotencyEntry).expires) {
+			c.entries.CompareAndDelete(k, v)
+		}
+		return true
+	})
+
+	cached := *resp
+	c.entries.Store(idempotencyCacheKey(key, model), idempotencyEntry{resp: &cached, expires: now.Add(window)})
+}
@@ -0,0 +1,40 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageInput is an image sent alongside the user prompt to a multimodal model
+type ImageInput struct {
+	MediaType string // e.g. "image/png"
+	Data      []byte // Raw image bytes
+}
+
+// imageMediaTypes maps file extensions to the media types accepted by vision models
+var imageMediaTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// FromFile reads an image file and detects its media type from the extension
+func FromFile(path string) (ImageInput, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	mediaType, ok := imageMediaTypes[ext]
+	if !ok {
+		return ImageInput{}, fmt.Errorf("unsupported image extension: %q", ext)
+	}
+
+	// #nosec G304 - path is provided by the caller to read an image they want to send
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ImageInput{}, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	return ImageInput{MediaType: mediaType, Data: data}, nil
+}
@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"context"
+	"sync"
+)
+
+// MockClient is a Client that returns canned responses without calling an API.
+// It records every request so tests can assert on what was sent.
+type MockClient struct {
+	// GenerateFunc handles Generate and GenerateWithContext calls
+	GenerateFunc func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error)
+
+	// GenerateWithToolsFunc handles GenerateWithTools calls
+	GenerateWithToolsFunc func(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error)
+
+	mu           sync.Mutex
+	requests     []GenerateRequest
+	toolRequests []GenerateWithToolsRequest
+}
+
+// NewMockClient creates a mock client that always responds with text
+func NewMockClient(text string) *MockClient {
+	respond := func() *GenerateResponse {
+		return &GenerateResponse{Text: text, StopReason: "end_turn"}
+	}
+	return &MockClient{
+		GenerateFunc: func(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+			return respond(), nil
+		},
+		GenerateWithToolsFunc: func(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
+			return respond(), nil
+		},
+	}
+}
+
+// Generate records the request and delegates to GenerateFunc
+func (m *MockClient) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, req)
+	m.mu.Unlock()
+
+	if m.GenerateFunc == nil {
+		return &GenerateResponse{StopReason: "end_turn"}, nil
+	}
+	return m.GenerateFunc(ctx, req)
+}
+
+// GenerateWithContext prepends the context to the user prompt and calls Generate
+func (m *MockClient) GenerateWithContext(ctx context.Context, req GenerateRequest, additionalContext string) (*GenerateResponse, error) {
+	if additionalContext != "" {
+		req.UserPrompt = additionalContext + "\n\n" + req.UserPrompt
+	}
+	return m.Generate(ctx, req)
+}
+
+// EstimateCost prices req as claude-sonnet-4-5 would
+func (m *MockClient) EstimateCost(req GenerateRequest) (EstimatedCost, error) {
+	return estimateCost("claude-sonnet-4-5", HeuristicTokenCounter{}, req)
+}
+
+// Capabilities returns the capabilities of claude-sonnet-4-5
+func (m *MockClient) Capabilities() ModelCapabilities {
+	return capabilitiesOrDefault("claude-sonnet-4-5")
+}
+
+// GenerateWithTools records the request and delegates to GenerateWithToolsFunc
+func (m *MockClient) GenerateWithTools(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
+	m.mu.Lock()
+	m.toolRequests = append(m.toolRequests, req)
+	m.mu.Unlock()
+
+	if m.GenerateWithToolsFunc == nil {
+		return &GenerateResponse{StopReason: "end_turn"}, nil
+	}
+	return m.GenerateWithToolsFunc(ctx, req)
+}
+
+// Requests returns the Generate requests received so far
+func (m *MockClient) Requests() []GenerateRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]GenerateRequest(nil), m.requests...)
+}
+
+// ToolRequests returns the GenerateWithTools requests received so far
+func (m *MockClient) ToolRequests() []GenerateWithToolsRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]GenerateWithToolsRequest(nil), m.toolRequests...)
+}
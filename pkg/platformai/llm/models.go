@@ -0,0 +1,88 @@
+package llm
+
+import "strings"
+
+// ModelCapabilities describes what a model supports
+type ModelCapabilities struct {
+	MaxContextTokens       int  // Input plus output tokens per request
+	SupportsTools          bool // Function calling, as used by GenerateWithTools
+	SupportsVision         bool // Image inputs
+	SupportsStreaming      bool // Streamed responses
+	SupportedStopSequences int  // Stop sequences per request; 0 when the provider documents no limit
+	SupportsSeed           bool // GenerateRequest.Seed
+}
+
+// CapabilityRegistry maps model ID prefixes to capabilities; like pricing, dated
+// IDs such as claude-sonnet-4-5-20250929 match their family prefix
+var CapabilityRegistry = map[string]ModelCapabilities{
+	// Anthropic
+	"claude-opus-4-1":    {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-opus-4":      {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-sonnet-4-5":  {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-sonnet-4":    {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-haiku-4-5":   {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-3-7-sonnet":  {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-3-5-sonnet":  {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-3-5-haiku":   {MaxContextTokens: 200000, SupportsTools: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-3-opus":      {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-3-haiku":     {MaxContextTokens: 200000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"claude-2.1":         {MaxContextTokens: 200000, SupportsStreaming: true},
+	"claude-2.0":         {MaxContextTokens: 100000, SupportsStreaming: true},
+	"claude-instant-1.2": {MaxContextTokens: 100000, SupportsStreaming: true},
+
+	// OpenAI
+	"gpt-4.1":       {MaxContextTokens: 1047576, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 4, SupportsSeed: true},
+	"gpt-4o":        {MaxContextTokens: 128000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 4, SupportsSeed: true},
+	"gpt-4-turbo":   {MaxContextTokens: 128000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 4, SupportsSeed: true},
+	"gpt-4":         {MaxContextTokens: 8192, SupportsTools: true, SupportsStreaming: true, SupportedStopSequences: 4, SupportsSeed: true},
+	"gpt-3.5-turbo": {MaxContextTokens: 16385, SupportsTools: true, SupportsStreaming: true, SupportedStopSequences: 4, SupportsSeed: true},
+
+	// Google Gemini
+	"gemini-2.5-pro":   {MaxContextTokens: 1048576, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 5, SupportsSeed: true},
+	"gemini-2.5-flash": {MaxContextTokens: 1048576, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 5, SupportsSeed: true},
+	"gemini-2.0-flash": {MaxContextTokens: 1048576, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 5, SupportsSeed: true},
+	"gemini-1.5-pro":   {MaxContextTokens: 2097152, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 5, SupportsSeed: true},
+	"gemini-1.5-flash": {MaxContextTokens: 1048576, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportedStopSequences: 5, SupportsSeed: true},
+
+	// Mistral
+	"mistral-large":     {MaxContextTokens: 128000, SupportsTools: true, SupportsStreaming: true, SupportsSeed: true},
+	"mistral-medium":    {MaxContextTokens: 128000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"mistral-small":     {MaxContextTokens: 128000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"pixtral-large":     {MaxContextTokens: 128000, SupportsTools: true, SupportsVision: true, SupportsStreaming: true, SupportsSeed: true},
+	"codestral":         {MaxContextTokens: 256000, SupportsTools: true, SupportsStreaming: true, SupportsSeed: true},
+	"open-mistral-nemo": {MaxContextTokens: 128000, SupportsTools: true, SupportsStreaming: true, SupportsSeed: true},
+}
+
+// defaultCapabilities are assumed for models missing from CapabilityRegistry, so
+// new model IDs keep working before the registry knows them
+var defaultCapabilities = ModelCapabilities{
+	MaxContextTokens:  200000,
+	SupportsTools:     true,
+	SupportsVision:    true,
+	SupportsStreaming: true,
+	SupportsSeed:      true,
+}
+
+// CapabilitiesFor returns the capabilities of the longest model prefix matching
+// model. ok is false when no prefix matches.
+func CapabilitiesFor(model string) (caps ModelCapabilities, ok bool) {
+	var best string
+	for prefix := range CapabilityRegistry {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return ModelCapabilities{}, false
+	}
+	return CapabilityRegistry[best], true
+}
+
+// capabilitiesOrDefault returns the registered capabilities of model, or
+// defaultCapabilities for unknown models
+func capabilitiesOrDefault(model string) ModelCapabilities {
+	if caps, ok := CapabilitiesFor(model); ok {
+		return caps
+	}
+	return defaultCapabilities
+}
@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCapabilitiesFor(t *testing.T) {
+	tests := []struct {
+		model     string
+		wantOK    bool
+		wantTools bool
+		wantCtx   int
+	}{
+		{"claude-sonnet-4-5-20250929", true, true, 200000},
+		{"claude-2.1", true, false, 200000},
+		{"gpt-4o-mini", true, true, 128000}, // Matches gpt-4o, not gpt-4
+		{"gpt-4-0613", true, true, 8192},
+		{"gemini-1.5-pro-002", true, true, 2097152},
+		{"mistral-large-latest", true, true, 128000},
+		{"unknown-model", false, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			caps, ok := CapabilitiesFor(tt.model)
+			if ok != tt.wantOK || caps.SupportsTools != tt.wantTools || caps.MaxContextTokens != tt.wantCtx {
+				t.Errorf("CapabilitiesFor() = %+v, %v, want tools %v, context %d, %v",
+					caps, ok, tt.wantTools, tt.wantCtx, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestAnthropicClient_Capabilities(t *testing.T) {
+	client := newTestClient("http://unused")
+	if caps := client.Capabilities(); caps != CapabilityRegistry["claude-sonnet-4-5"] {
+		t.Errorf("Capabilities() = %+v, want the claude-sonnet-4-5 entry", caps)
+	}
+
+	client.model = "claude-next"
+	if caps := client.Capabilities(); caps != defaultCapabilities {
+		t.Errorf("Capabilities() of unknown model = %+v, want defaults", caps)
+	}
+}
+
+func TestAnthropicClient_GenerateWithToolsUnsupported(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL)
+	client.model = "claude-2.1"
+
+	_, err := client.GenerateWithTools(context.Background(), GenerateWithToolsRequest{
+		Messages: []Message{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "list pods"}}}},
+		Tools:    []Tool{{Name: "list_pods"}},
+	})
+	if !errors.Is(err, ErrToolsNotSupported) {
+		t.Errorf("GenerateWithTools() error = %v, want ErrToolsNotSupported", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("API calls = %d, want 0", calls.Load())
+	}
+}
@@ -0,0 +1,122 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
+)
+
+const (
+	openAIModerationURL   = "https://api.openai.com/v1/moderations"
+	openAIModerationModel = "omni-moderation-latest"
+)
+
+// ContentModerator screens text sent to and received from an LLM
+type ContentModerator interface {
+	CheckInput(ctx context.Context, text string) (*ModerationResult, error)
+	CheckOutput(ctx context.Context, text string) (*ModerationResult, error)
+}
+
+// ModerationResult is the verdict for one piece of text
+type ModerationResult struct {
+	Flagged    bool
+	Categories []string // Categories the text was flagged for, sorted
+	Score      float32  // Highest category score, from 0 to 1
+}
+
+// OpenAIModerationClient implements ContentModerator with OpenAI's moderation API
+type OpenAIModerationClient struct {
+	apiKey     string
+	model      string
+	httpClient *http.Client
+	apiURL     string // Override for testing
+}
+
+// NewOpenAIModerationClient creates a moderation client using the omni-moderation-latest model
+func NewOpenAIModerationClient(apiKey string) *OpenAIModerationClient {
+	return &OpenAIModerationClient{
+		apiKey: apiKey,
+		model:  openAIModerationModel,
+		apiURL: openAIModerationURL,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// CheckInput moderates a prompt before it is sent
+func (c *OpenAIModerationClient) CheckInput(ctx context.Context, text string) (*ModerationResult, error) {
+	return c.check(ctx, text)
+}
+
+// CheckOutput moderates a model response before it is returned
+func (c *OpenAIModerationClient) CheckOutput(ctx context.Context, text string) (*ModerationResult, error) {
+	return c.check(ctx, text)
+}
+
+// openAIModerationResponse represents the response format from OpenAI's moderation API
+type openAIModerationResponse struct {
+	Results []struct {
+		Flagged        bool               `json:"flagged"`
+		Categories     map[string]bool    `json:"categories"`
+		CategoryScores map[string]float32 `json:"category_scores"`
+	} `json:"results"`
+}
+
+// check sends text to the moderation endpoint
+func (c *OpenAIModerationClient) check(ctx context.Context, text string) (*ModerationResult, error) {
+	jsonData, err := json.Marshal(map[string]string{"model": c.model, "input": text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("User-Agent", httpclient.UserAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp openAIModerationResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if len(apiResp.Results) == 0 {
+		return nil, fmt.Errorf("moderation API returned no results")
+	}
+
+	moderation := apiResp.Results[0]
+	result := &ModerationResult{Flagged: moderation.Flagged, Categories: []string{}}
+	for category, flagged := range moderation.Categories {
+		if flagged {
+			result.Categories = append(result.Categories, category)
+		}
+	}
+	sort.Strings(result.Categories)
+	for _, score := range moderation.CategoryScores {
+		result.Score = max(result.Score, score)
+	}
+	return result, nil
+}
@@ -0,0 +1,163 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newModerationServer flags any input containing "forbidden" as violence
+func newModerationServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mod-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Input string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode moderation request: %v", err)
+		}
+
+		flagged := strings.Contains(req.Input, "forbidden")
+		score := float32(0.01)
+		if flagged {
+			score = 0.97
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{{
+				"flagged":         flagged,
+				"categories":      map[string]bool{"violence": flagged, "harassment": false},
+				"category_scores": map[string]float32{"violence": score, "harassment": 0.002},
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestModerator(serverURL string) *OpenAIModerationClient {
+	moderator := NewOpenAIModerationClient("mod-key")
+	moderator.apiURL = serverURL
+	return moderator
+}
+
+func TestOpenAIModerationClient_Check(t *testing.T) {
+	moderator := newTestModerator(newModerationServer(t).URL)
+
+	result, err := moderator.CheckInput(context.Background(), "something forbidden")
+	if err != nil {
+		t.Fatalf("CheckInput() error = %v", err)
+	}
+	want := &ModerationResult{Flagged: true, Categories: []string{"violence"}, Score: 0.97}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("CheckInput() = %+v, want %+v", result, want)
+	}
+
+	result, err = moderator.CheckOutput(context.Background(), "a friendly answer")
+	if err != nil {
+		t.Fatalf("CheckOutput() error = %v", err)
+	}
+	if result.Flagged || len(result.Categories) != 0 {
+		t.Errorf("CheckOutput() = %+v, want not flagged", result)
+	}
+
+	moderator.apiKey = "wrong"
+	if _, err := moderator.CheckInput(context.Background(), "text"); err == nil {
+		t.Error("CheckInput() expected error for rejected API key")
+	}
+}
+
+func TestAnthropicClient_WithModeration(t *testing.T) {
+	moderator := newTestModerator(newModerationServer(t).URL)
+
+	t.Run("flagged input is not sent", func(t *testing.T) {
+		var body []byte
+		client := newTestClient(newCaptureServer(t, &body).URL)
+		WithModeration(moderator)(client)
+
+		_, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "do something forbidden", MaxTokens: 10})
+		if !errors.Is(err, ErrContentModerated) {
+			t.Errorf("Generate() error = %v, want ErrContentModerated", err)
+		}
+		if body != nil {
+			t.Error("flagged prompt was sent to the LLM API")
+		}
+	})
+
+	t.Run("flagged output is not returned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(anthropicResponse{
+				Content: []anthropicContentBlock{{Type: "text", Text: "forbidden answer"}},
+			})
+		}))
+		defer server.Close()
+		client := newTestClient(server.URL)
+		WithModeration(moderator)(client)
+
+		resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hello", MaxTokens: 10})
+		if !errors.Is(err, ErrContentModerated) || resp != nil {
+			t.Errorf("Generate() = %v, %v; want nil, ErrContentModerated", resp, err)
+		}
+	})
+
+	t.Run("clean content passes", func(t *testing.T) {
+		var body []byte
+		client := newTestClient(newCaptureServer(t, &body).URL)
+		WithModeration(moderator)(client)
+
+		resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hello", MaxTokens: 10})
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if resp.Text != "ok" {
+			t.Errorf("Generate() text = %q, want ok", resp.Text)
+		}
+	})
+}
+
+func TestConversationSession_WithModeration(t *testing.T) {
+	moderator := newTestModerator(newModerationServer(t).URL)
+
+	t.Run("flagged input is not sent", func(t *testing.T) {
+		var body []byte
+		client := newTestClient(newCaptureServer(t, &body).URL)
+		WithModeration(moderator)(client)
+		session := NewConversationSession(client, "")
+
+		if _, err := session.Send(context.Background(), GenerateRequest{UserPrompt: "hello"}); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		body = nil
+		_, err := session.Send(context.Background(), GenerateRequest{UserPrompt: "now do something forbidden"})
+		if !errors.Is(err, ErrContentModerated) {
+			t.Errorf("Send() error = %v, want ErrContentModerated", err)
+		}
+		if body != nil {
+			t.Error("flagged conversation was sent to the LLM API")
+		}
+	})
+
+	t.Run("flagged output is not returned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(anthropicResponse{
+				Content: []anthropicContentBlock{{Type: "text", Text: "forbidden answer"}},
+			})
+		}))
+		defer server.Close()
+		client := newTestClient(server.URL)
+		WithModeration(moderator)(client)
+
+		resp, err := NewConversationSession(client, "").Send(context.Background(), GenerateRequest{UserPrompt: "hello"})
+		if !errors.Is(err, ErrContentModerated) || resp != nil {
+			t.Errorf("Send() = %v, %v; want nil, ErrContentModerated", resp, err)
+		}
+	})
+}
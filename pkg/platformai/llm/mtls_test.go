@@ -0,0 +1,177 @@
+package llm
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testPKI holds an ephemeral CA with a server and a client certificate signed by it
+type testPKI struct {
+	caPool     *x509.CertPool
+	serverCert tls.Certificate
+	certFile   string // Client certificate PEM
+	keyFile    string // Client key PEM
+	caFile     string // CA certificate PEM
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der, key
+	}
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	marshalKey := func(key *ecdsa.PrivateKey) []byte {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return der
+	}
+
+	serverDER, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	clientDER, clientKey := issue(3, x509.ExtKeyUsageClientAuth)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+	return &testPKI{
+		caPool:     pool,
+		serverCert: tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey},
+		certFile:   writePEM("client.pem", "CERTIFICATE", clientDER),
+		keyFile:    writePEM("client.key", "EC PRIVATE KEY", marshalKey(clientKey)),
+		caFile:     writePEM("ca.pem", "CERTIFICATE", caDER),
+	}
+}
+
+func TestNewAnthropicClient_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+
+	var clientCN string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientCN = r.TLS.PeerCertificates[0].Subject.CommonName
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"content":[{"type":"text","text":"hello gateway"}],"stop_reason":"end_turn"}`)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pki.caPool,
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client, err := NewAnthropicClient(Config{
+		APIKey: "test-key",
+		Model:  "claude-sonnet-4-5-20250929",
+		TLS:    TLSConfig{CertFile: pki.certFile, KeyFile: pki.keyFile, CAFile: pki.caFile},
+	})
+	if err != nil {
+		t.Fatalf("NewAnthropicClient() error = %v", err)
+	}
+	client.apiURL = server.URL + "/v1/messages"
+
+	resp, err := client.Generate(context.Background(), GenerateRequest{UserPrompt: "hi", MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("Generate() over mTLS error = %v", err)
+	}
+	if resp.Text != "hello gateway" || clientCN != "localhost" {
+		t.Errorf("Generate() text = %q, client CN = %q; want hello gateway from localhost", resp.Text, clientCN)
+	}
+
+	// Without a client certificate the gateway rejects the handshake
+	plain, err := NewAnthropicClient(Config{APIKey: "test-key", Model: "claude-sonnet-4-5-20250929"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain.apiURL = client.apiURL
+	if _, err := plain.Generate(context.Background(), GenerateRequest{UserPrompt: "hi", MaxTokens: 10}); err == nil {
+		t.Error("Generate() without client certificate expected error")
+	}
+}
+
+func TestNewAnthropicClient_MutualTLSErrors(t *testing.T) {
+	pki := newTestPKI(t)
+
+	tests := []struct {
+		name    string
+		tls     TLSConfig
+		wantErr string
+	}{
+		{"missing key file", TLSConfig{CertFile: pki.certFile, KeyFile: "/nonexistent/client.key", CAFile: pki.caFile}, "client certificate"},
+		{"missing CA file", TLSConfig{CertFile: pki.certFile, KeyFile: pki.keyFile, CAFile: "/nonexistent/ca.pem"}, "CA bundle"},
+		{"partial", TLSConfig{CertFile: pki.certFile}, "CertFile, KeyFile, and CAFile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAnthropicClient(Config{APIKey: "k", TLS: tt.tls})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewAnthropicClient() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package llm
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// Option configures an AnthropicClient
+type Option func(*AnthropicClient)
+
+// WithPIIRedaction redacts emails, IP addresses, AWS keys, and private keys from
+// the system and user prompts of every Generate call, and from the system prompt,
+// text, and tool results of every GenerateWithTools call, before they are sent.
+// What was redacted is available from PIIRedactionReport.
+func WithPIIRedaction() Option {
+	return func(c *AnthropicClient) {
+		c.pii = NewPIIDetector()
+		c.piiReports = make(map[string][]PIIMatch)
+	}
+}
+
+// WithQuotaManager enforces token quotas on Generate calls. keyFn identifies the
+// caller from the request context; an empty key is not subject to quotas. A call
+// is rejected with ErrQuotaExceeded, before it is sent, when its estimated prompt
+// tokens exceed the remaining quota. Tokens actually used are consumed afterwards.
+func WithQuotaManager(qm QuotaManager, keyFn func(ctx context.Context) string) Option {
+	return func(c *AnthropicClient) {
+		c.quota = qm
+		c.quotaKey = keyFn
+	}
+}
+
+// WithDeadLetterQueue enqueues Generate requests that fail in a way a retry might
+// fix, such as network errors, rate limits, and server errors, so they can be replayed
+func WithDeadLetterQueue(dlq *DeadLetterQueue) Option {
+	return func(c *AnthropicClient) {
+		c.dlq = dlq
+	}
+}
+
+// WithModeration screens the prompts of every Generate call, and the system
+// prompt and messages of every GenerateWithTools call, with m before they are
+// sent and the response text before it is returned. Flagged content fails the
+// call with ErrContentModerated.
+func WithModeration(m ContentModerator) Option {
+	return func(c *AnthropicClient) {
+		c.moderator = m
+	}
+}
+
+// WithSystemPromptPrefix prepends prefix, followed by a blank line, to the system
+// prompt of every Generate and GenerateWithTools call. It overrides Config.SystemPromptPrefix.
+func WithSystemPromptPrefix(prefix string) Option {
+	return func(c *AnthropicClient) {
+		c.systemPromptPrefix = prefix
+	}
+}
+
+// WithIdempotencyWindow sets how long a response is replayed for a repeated
+// GenerateRequest.IdempotencyKey. The default is 10 minutes; zero or less
+// disables client-side deduplication, though the header is still sent.
+func WithIdempotencyWindow(d time.Duration) Option {
+	return func(c *AnthropicClient) {
+		c.idempotencyWindow = d
+	}
+}
+
+// WithCustomHeaders sends headers with every API request, in addition to
+// Config.CustomHeaders. NewAnthropicClient rejects credential headers such as
+// x-api-key and authorization.
+func WithCustomHeaders(headers map[string]string) Option {
+	return func(c *AnthropicClient) {
+		if c.customHeaders == nil {
+			c.customHeaders = make(map[string]string, len(headers))
+		}
+		maps.Copy(c.customHeaders, headers)
+	}
+}
@@ -0,0 +1,104 @@
+package llm
+
+import (
+	"regexp"
+	"sort"
+)
+
+// PII types reported by PIIDetector
+const (
+	PIIEmail        = "email"
+	PIIIPAddress    = "ip-address"
+	PIIAWSAccessKey = "aws-access-key"
+	PIIPrivateKey   = "private-key"
+)
+
+// PIIMatch is a piece of sensitive data found in text
+type PIIMatch struct {
+	Type  string // One of the PII* constants
+	Value string // Matched text
+	Start int    // Byte offset of the match
+	End   int    // Byte offset just past the match
+}
+
+// piiPattern pairs a PII type with the expression that finds it
+type piiPattern struct {
+	piiType string
+	re      *regexp.Regexp
+}
+
+// defaultPIIPatterns are checked in order; earlier patterns win when matches overlap
+var defaultPIIPatterns = []piiPattern{
+	{PIIPrivateKey, regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?-----END [A-Z ]*PRIVATE KEY-----)?`)},
+	{PIIAWSAccessKey, regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`)},
+	{PIIEmail, regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+	{PIIIPAddress, regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`)},
+}
+
+// PIIDetector finds secrets and personal data in text
+type PIIDetector struct {
+	patterns []piiPattern
+}
+
+// NewPIIDetector creates a detector for emails, IPv4 addresses, AWS access key
+// IDs, and PEM private keys
+func NewPIIDetector() *PIIDetector {
+	return &PIIDetector{patterns: defaultPIIPatterns}
+}
+
+// Detect returns the non-overlapping matches in text, in order of position
+func (d *PIIDetector) Detect(text string) []PIIMatch {
+	var matches []PIIMatch
+	for _, pattern := range d.patterns {
+		for _, loc := range pattern.re.FindAllStringIndex(text, -1) {
+			if overlapsAny(matches, loc[0], loc[1]) {
+				continue
+			}
+			matches = append(matches, PIIMatch{
+				Type:  pattern.piiType,
+				Value: text[loc[0]:loc[1]],
+				Start: loc[0],
+				End:   loc[1],
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return matches
+}
+
+// Redact replaces every match in text with [REDACTED-type]
+func (d *PIIDetector) Redact(text string) string {
+	redacted, _ := d.redact(text)
+	return redacted
+}
+
+// redact returns the redacted text and the matches that were replaced
+func (d *PIIDetector) redact(text string) (string, []PIIMatch) {
+	matches := d.Detect(text)
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		out = append(out, text[last:m.Start]...)
+		out = append(out, "[REDACTED-"+m.Type+"]"...)
+		last = m.End
+	}
+	out = append(out, text[last:]...)
+	return string(out), matches
+}
+
+// overlapsAny reports whether [start, end) overlaps an existing match
+func overlapsAny(matches []PIIMatch, start, end int) bool {
+	for _, m := range matches {
+		if start < m.End && m.Start < end {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package llm
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// PromptVersion is one revision of a named prompt
+type PromptVersion struct {
+	ID        string // "v1", "v2", ... in the order versions were added
+	Text      string
+	Notes     string // Why this version was written
+	CreatedAt time.Time
+}
+
+// TemplateLibrary stores named prompts with their full version history so
+// callers can pin, compare, or roll back to an earlier prompt, along with a
+// few-shot library of example exchanges per name. It is safe for concurrent use.
+type TemplateLibrary struct {
+	mu       sync.RWMutex
+	versions map[string][]PromptVersion
+	examples map[string][]FewShotExample
+	now      func() time.Time
+}
+
+// NewTemplateLibrary creates an empty library
+func NewTemplateLibrary() *TemplateLibrary {
+	return &TemplateLibrary{
+		versions: make(map[string][]PromptVersion),
+		examples: make(map[string][]FewShotExample),
+		now:      time.Now,
+	}
+}
+
+// AddVersion stores text as the newest version of the prompt called name
+func (l *TemplateLibrary) AddVersion(name, text, notes string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("%w: prompt name is required", ErrInvalidRequest)
+	}
+	if text == "" {
+		return "", fmt.Errorf("%w: prompt text is required", ErrInvalidRequest)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := fmt.Sprintf("v%d", len(l.versions[name])+1)
+	l.versions[name] = append(l.versions[name], PromptVersion{
+		ID:        id,
+		Text:      text,
+		Notes:     notes,
+		CreatedAt: l.now(),
+	})
+	return id, nil
+}
+
+// Get returns the latest version of the prompt called name
+func (l *TemplateLibrary) Get(name string) (PromptVersion, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	versions := l.versions[name]
+	if len(versions) == 0 {
+		return PromptVersion{}, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+	return versions[len(versions)-1], nil
+}
+
+// GetVersion returns a specific version of the prompt called name
+func (l *TemplateLibrary) GetVersion(name, versionID string) (PromptVersion, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for _, v := range l.versions[name] {
+		if v.ID == versionID {
+			return v, nil
+		}
+	}
+	return PromptVersion{}, fmt.Errorf("%w: %s version %s", ErrPromptNotFound, name, versionID)
+}
+
+// Versions returns every version of the prompt called name, oldest first
+func (l *TemplateLibrary) Versions(name string) []PromptVersion {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]PromptVersion(nil), l.versions[name]...)
+}
+
+// AddExample adds a few-shot example for the prompt called name
+func (l *TemplateLibrary) AddExample(name string, example FewShotExample) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.examples[name] = append(l.examples[name], example)
+}
+
+// GetExamples returns the few-shot examples for the prompt called name, in the order they were added
+func (l *TemplateLibrary) GetExamples(name string) []FewShotExample {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return append([]FewShotExample(nil), l.examples[name]...)
+}
+
+// ABTest splits traffic between two prompts
+type ABTest struct {
+	PromptA             string
+	PromptB             string
+	TrafficSplitPercent int // Share of requests that get PromptA (0-100)
+}
+
+// Select picks PromptA or PromptB for a request. The same requestID always gets
+// the same prompt, so retries and follow-ups see a consistent variant.
+func (t ABTest) Select(requestID string) string {
+	h := fnv.New32a()
+	h.Write([]byte(requestID))
+	if int(h.Sum32()%100) < t.TrafficSplitPercent {
+		return t.PromptA
+	}
+	return t.PromptB
+}
@@ -0,0 +1,91 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestTemplateLibrary(t *testing.T) {
+	lib := NewTemplateLibrary()
+
+	v1, err := lib.AddVersion("analyze", "Analyze this repo", "initial")
+	if err != nil {
+		t.Fatalf("AddVersion() error = %v", err)
+	}
+	v2, err := lib.AddVersion("analyze", "Analyze this repository in detail", "more detail")
+	if err != nil {
+		t.Fatalf("AddVersion() error = %v", err)
+	}
+	if v1 == v2 {
+		t.Fatalf("AddVersion() returned duplicate ID %q", v1)
+	}
+
+	latest, err := lib.Get("analyze")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if latest.ID != v2 || latest.Text != "Analyze this repository in detail" {
+		t.Errorf("Get() = %+v, want version %s", latest, v2)
+	}
+
+	first, err := lib.GetVersion("analyze", v1)
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if first.Text != "Analyze this repo" || first.Notes != "initial" {
+		t.Errorf("GetVersion() = %+v, want first version", first)
+	}
+
+	if _, err := lib.Get("missing"); !errors.Is(err, ErrPromptNotFound) {
+		t.Errorf("Get() error = %v, want ErrPromptNotFound", err)
+	}
+	if _, err := lib.GetVersion("analyze", "v9"); !errors.Is(err, ErrPromptNotFound) {
+		t.Errorf("GetVersion() error = %v, want ErrPromptNotFound", err)
+	}
+	if _, err := lib.AddVersion("analyze", "", ""); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("AddVersion() error = %v, want ErrInvalidRequest", err)
+	}
+}
+
+func TestABTest_Select(t *testing.T) {
+	test := ABTest{PromptA: "A", PromptB: "B", TrafficSplitPercent: 50}
+
+	const requests = 1000
+	countA := 0
+	for i := 0; i < requests; i++ {
+		id := fmt.Sprintf("request-%d", i)
+		got := test.Select(id)
+		if got != test.Select(id) {
+			t.Fatalf("Select(%q) is not deterministic", id)
+		}
+		if got == "A" {
+			countA++
+		}
+	}
+	if share := float64(countA) / requests; math.Abs(share-0.5) > 0.05 {
+		t.Errorf("PromptA share = %.3f, want 0.50 ± 0.05", share)
+	}
+
+	if got := (ABTest{PromptA: "A", PromptB: "B", TrafficSplitPercent: 100}).Select("x"); got != "A" {
+		t.Errorf("Select() with 100%% split = %q, want A", got)
+	}
+	if got := (ABTest{PromptA: "A", PromptB: "B"}).Select("x"); got != "B" {
+		t.Errorf("Select() with 0%% split = %q, want B", got)
+	}
+}
+
+func TestTemplateLibrary_Examples(t *testing.T) {
+	lib := NewTemplateLibrary()
+	lib.AddExample("config", FewShotExample{UserMessage: "q1", AssistantMessage: "a1"})
+	lib.AddExample("config", FewShotExample{UserMessage: "q2", AssistantMessage: "a2"})
+
+	examples := lib.GetExamples("config")
+	if len(examples) != 2 || examples[0].UserMessage != "q1" || examples[1].UserMessage != "q2" {
+		t.Errorf("GetExamples() = %+v, want both examples in order", examples)
+	}
+	if got := lib.GetExamples("missing"); len(got) != 0 {
+		t.Errorf("GetExamples() for unknown name = %+v, want none", got)
+	}
+}
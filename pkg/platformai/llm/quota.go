@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// QuotaManager enforces per-key token quotas. Implementations must be safe for concurrent use.
+type QuotaManager interface {
+	// Consume deducts tokens from key's quota. It returns ErrQuotaExceeded if the
+	// quota did not cover them; the tokens are deducted regardless.
+	Consume(ctx context.Context, key string, tokens int) error
+
+	// Remaining returns the tokens left for key in the current period
+	Remaining(ctx context.Context, key string) (int, error)
+}
+
+// InMemoryQuotaManager is a QuotaManager for a single process. Each key's usage
+// resets once resetPeriod has passed since its period started.
+type InMemoryQuotaManager struct {
+	quotas      map[string]int
+	resetPeriod time.Duration
+	usage       sync.Map // Key -> *quotaUsage
+	now         func() time.Time
+}
+
+// quotaUsage is one key's consumption in the current period
+type quotaUsage struct {
+	mu     sync.Mutex
+	used   int
+	period time.Time // Start of the current period
+}
+
+// NewInMemoryQuotaManager creates a quota manager with a token quota per key.
+// Keys without a quota are unlimited; resetPeriod <= 0 never resets usage.
+func NewInMemoryQuotaManager(quotas map[string]int, resetPeriod time.Duration) *InMemoryQuotaManager {
+	copied := make(map[string]int, len(quotas))
+	for key, quota := range quotas {
+		copied[key] = quota
+	}
+	return &InMemoryQuotaManager{
+		quotas:      copied,
+		resetPeriod: resetPeriod,
+		now:         time.Now,
+	}
+}
+
+// Consume deducts tokens from key's quota
+func (m *InMemoryQuotaManager) Consume(ctx context.Context, key string, tokens int) error {
+	quota, limited := m.quotas[key]
+	if !limited {
+		return nil
+	}
+
+	usage := m.current(key)
+	defer usage.mu.Unlock()
+
+	remaining := quota - usage.used
+	usage.used += tokens
+	if tokens > remaining {
+		return fmt.Errorf("%w: key %s used %d of %d tokens", ErrQuotaExceeded, key, usage.used, quota)
+	}
+	return nil
+}
+
+// Remaining returns the tokens left for key, never less than zero
+func (m *InMemoryQuotaManager) Remaining(ctx context.Context, key string) (int, error) {
+	quota, limited := m.quotas[key]
+	if !limited {
+		return math.MaxInt, nil
+	}
+
+	usage := m.current(key)
+	defer usage.mu.Unlock()
+
+	return max(quota-usage.used, 0), nil
+}
+
+// current returns key's usage, locked and reset if its period has ended
+func (m *InMemoryQuotaManager) current(key string) *quotaUsage {
+	now := m.now()
+	value, _ := m.usage.LoadOrStore(key, &quotaUsage{period: now})
+	usage := value.(*quotaUsage)
+
+	usage.mu.Lock()
+	if m.resetPeriod > 0 && now.Sub(usage.period) >= m.resetPeriod {
+		usage.used = 0
+		usage.period = now
+	}
+	return usage
+}
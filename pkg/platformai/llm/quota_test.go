@@ -0,0 +1,127 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type quotaKeyType struct{}
+
+func withQuotaKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, quotaKeyType{}, key)
+}
+
+func quotaKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(quotaKeyType{}).(string)
+	return key
+}
+
+func TestInMemoryQuotaManager(t *testing.T) {
+	ctx := context.Background()
+	qm := NewInMemoryQuotaManager(map[string]int{"alice": 100}, time.Hour)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	qm.now = func() time.Time { return now }
+
+	if err := qm.Consume(ctx, "alice", 60); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+	if got, _ := qm.Remaining(ctx, "alice"); got != 40 {
+		t.Errorf("Remaining() = %d, want 40", got)
+	}
+	if err := qm.Consume(ctx, "alice", 50); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Consume() over quota error = %v, want ErrQuotaExceeded", err)
+	}
+	if got, _ := qm.Remaining(ctx, "alice"); got != 0 {
+		t.Errorf("Remaining() after overrun = %d, want 0", got)
+	}
+
+	now = now.Add(time.Hour)
+	if got, _ := qm.Remaining(ctx, "alice"); got != 100 {
+		t.Errorf("Remaining() after reset period = %d, want 100", got)
+	}
+
+	if err := qm.Consume(ctx, "bob", 1_000_000); err != nil {
+		t.Errorf("Consume() for key without quota error = %v, want nil", err)
+	}
+}
+
+func TestAnthropicClient_WithQuotaManager(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		resp := anthropicResponse{Content: []anthropicContentBlock{{Type: "text", Text: "ok"}}}
+		resp.Usage.InputTokens = 30
+		resp.Usage.OutputTokens = 20
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	qm := NewInMemoryQuotaManager(map[string]int{"alice": 120}, 0)
+	client := newTestClient(server.URL)
+	WithQuotaManager(qm, quotaKeyFromContext)(client)
+
+	ctx := withQuotaKey(context.Background(), "alice")
+	req := GenerateRequest{UserPrompt: "hello", MaxTokens: 50}
+	for i, want := range []int{70, 20} {
+		if _, err := client.Generate(ctx, req); err != nil {
+			t.Fatalf("Generate() #%d error = %v", i+1, err)
+		}
+		if got, _ := qm.Remaining(ctx, "alice"); got != want {
+			t.Errorf("Remaining() after call %d = %d, want %d", i+1, got, want)
+		}
+	}
+
+	// The third call overruns the quota but still succeeds; the key is then exhausted
+	if _, err := client.Generate(ctx, req); err != nil {
+		t.Fatalf("Generate() #3 error = %v", err)
+	}
+	if _, err := client.Generate(ctx, req); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Generate() with exhausted quota error = %v, want ErrQuotaExceeded", err)
+	}
+	if calls != 3 {
+		t.Errorf("API calls = %d, want 3; the exhausted call must not be sent", calls)
+	}
+
+	if _, err := client.Generate(context.Background(), req); err != nil {
+		t.Errorf("Generate() without quota key error = %v, want nil", err)
+	}
+}
+
+func TestAnthropicClient_WithQuotaManagerTools(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		resp := anthropicResponse{Content: []anthropicContentBlock{{Type: "text", Text: "ok"}}}
+		resp.Usage.InputTokens = 40
+		resp.Usage.OutputTokens = 20
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	qm := NewInMemoryQuotaManager(map[string]int{"alice": 100}, 0)
+	client := newTestClient(server.URL)
+	WithQuotaManager(qm, quotaKeyFromContext)(client)
+
+	// Conversation turns go through GenerateWithTools and count against the quota
+	ctx := withQuotaKey(context.Background(), "alice")
+	session := NewConversationSession(client, "")
+	for i := 0; i < 2; i++ {
+		if _, err := session.Send(ctx, GenerateRequest{UserPrompt: "hello", MaxTokens: 50}); err != nil {
+			t.Fatalf("Send() #%d error = %v", i+1, err)
+		}
+	}
+	if got, _ := qm.Remaining(ctx, "alice"); got != 0 {
+		t.Errorf("Remaining() = %d, want 0", got)
+	}
+	if _, err := session.Send(ctx, GenerateRequest{UserPrompt: "hello", MaxTokens: 50}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Send() with exhausted quota error = %v, want ErrQuotaExceeded", err)
+	}
+	if calls != 2 {
+		t.Errorf("API calls = %d, want 2; the exhausted call must not be sent", calls)
+	}
+}
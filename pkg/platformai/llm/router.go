@@ -0,0 +1,205 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// shortPromptLength is the user prompt length below which ShortPromptRule matches
+const shortPromptLength = 200
+
+// RoutingRule sends requests matching Condition to Provider and Model. An empty
+// Provider or Model keeps the base client's value.
+type RoutingRule struct {
+	Condition func(GenerateRequest) bool
+	Provider  string
+	Model     string
+}
+
+// RouterConfig holds the rules a RoutingClient evaluates in order
+type RouterConfig struct {
+	Rules []RoutingRule
+}
+
+// ShortPromptRule routes user prompts shorter than 200 characters to Claude Haiku
+func ShortPromptRule() RoutingRule {
+	return RoutingRule{
+		Condition: func(req GenerateRequest) bool { return len(req.UserPrompt) < shortPromptLength },
+		Provider:  "anthropic",
+		Model:     "claude-haiku-4-5",
+	}
+}
+
+// RoutingClient is a Client that sends each request to the client of the first
+// matching rule, or to the base client when no rule matches. Rule clients are
+// created from the base config on first use and reused afterwards.
+type RoutingClient struct {
+	base      Client
+	rules     []RoutingRule
+	newClient func(Config) (Client, error)
+
+	mu         sync.Mutex
+	config     Config
+	clients    map[string]Client
+	httpClient *http.Client
+}
+
+// NewRoutingClient creates a routing client. config and opts are the configuration
+// and options base was created with; rule clients inherit everything but the
+// provider and model from them, so routed requests keep guards such as
+// WithPIIRedaction and WithQuotaManager.
+func NewRoutingClient(base Client, config Config, router RouterConfig, opts ...Option) *RoutingClient {
+	return &RoutingClient{
+		base:      base,
+		rules:     router.Rules,
+		newClient: func(c Config) (Client, error) { return NewClient(c, opts...) },
+		config:    config,
+		clients:   make(map[string]Client),
+	}
+}
+
+// Generate sends the request to the routed client
+func (c *RoutingClient) Generate(ctx context.Context, req GenerateRequest) (*GenerateResponse, error) {
+	client, err := c.route(req)
+	if err != nil {
+		return nil, err
+	}
+	return client.Generate(ctx, req)
+}
+
+// GenerateWithContext sends the request to the routed client
+func (c *RoutingClient) GenerateWithContext(ctx context.Context, req GenerateRequest, additionalContext string) (*GenerateResponse, error) {
+	client, err := c.route(req)
+	if err != nil {
+		return nil, err
+	}
+	return client.GenerateWithContext(ctx, req, additionalContext)
+}
+
+// GenerateWithTools sends the request to the routed client. Rules see the text of
+// all user messages as the user prompt.
+func (c *RoutingClient) GenerateWithTools(ctx context.Context, req GenerateWithToolsRequest) (*GenerateResponse, error) {
+	client, err := c.route(toolsRoutingRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	return client.GenerateWithTools(ctx, req)
+}
+
+// EstimateCost estimates the cost on the routed client
+func (c *RoutingClient) EstimateCost(req GenerateRequest) (EstimatedCost, error) {
+	client, err := c.route(req)
+	if err != nil {
+		return EstimatedCost{}, err
+	}
+	return client.EstimateCost(req)
+}
+
+// Capabilities returns the capabilities of the base client; routed requests may
+// reach other models
+func (c *RoutingClient) Capabilities() ModelCapabilities {
+	return c.base.Capabilities()
+}
+
+// RotateAPIKey replaces the API key of the base client and every rule client
+func (c *RoutingClient) RotateAPIKey(newKey string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, client := range append([]Client{c.base}, c.clientList()...) {
+		rotator, ok := client.(interface{ RotateAPIKey(newKey string) error })
+		if !ok {
+			continue
+		}
+		if err := rotator.RotateAPIKey(newKey); err != nil {
+			return err
+		}
+	}
+	c.config.APIKey = newKey
+	return nil
+}
+
+// SetHTTPClient replaces the HTTP client of the base client and every rule client,
+// including those created later
+func (c *RoutingClient) SetHTTPClient(httpClient *http.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.httpClient = httpClient
+	for _, client := range append([]Client{c.base}, c.clientList()...) {
+		if setter, ok := client.(interface{ SetHTTPClient(*http.Client) }); ok {
+			setter.SetHTTPClient(httpClient)
+		}
+	}
+}
+
+// route returns the client for req, creating it if needed
+func (c *RoutingClient) route(req GenerateRequest) (Client, error) {
+	for _, rule := range c.rules {
+		if rule.Condition == nil || !rule.Condition(req) {
+			continue
+		}
+		return c.ruleClient(rule)
+	}
+	return c.base, nil
+}
+
+func (c *RoutingClient) ruleClient(rule RoutingRule) (Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	config := c.config
+	if rule.Provider != "" {
+		config.Provider = rule.Provider
+	}
+	if rule.Model != "" {
+		config.Model = rule.Model
+	}
+	key := config.Provider + "/" + config.Model
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+
+	client, err := c.newClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for %s: %w", key, err)
+	}
+	if c.httpClient != nil {
+		if setter, ok := client.(interface{ SetHTTPClient(*http.Client) }); ok {
+			setter.SetHTTPClient(c.httpClient)
+		}
+	}
+	c.clients[key] = client
+	return client, nil
+}
+
+// clientList returns the rule clients created so far; c.mu must be held
+func (c *RoutingClient) clientList() []Client {
+	clients := make([]Client, 0, len(c.clients))
+	for _, client := range c.clients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// toolsRoutingRequest describes a tools request as a GenerateRequest for rule conditions
+func toolsRoutingRequest(req GenerateWithToolsRequest) GenerateRequest {
+	var prompt strings.Builder
+	for _, msg := range req.Messages {
+		if msg.Role != "user" {
+			continue
+		}
+		for _, block := range msg.Content {
+			prompt.WriteString(block.Text)
+		}
+	}
+	return GenerateRequest{
+		SystemPrompt: req.SystemPrompt,
+		UserPrompt:   prompt.String(),
+		Temperature:  req.Temperature,
+		MaxTokens:    req.MaxTokens,
+	}
+}
@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRoutingClient_Generate(t *testing.T) {
+	base := NewMockClient("base")
+	router := NewRoutingClient(base, Config{Provider: "anthropic", APIKey: "key", Model: "claude-sonnet-4-5"}, RouterConfig{
+		Rules: []RoutingRule{ShortPromptRule()},
+	})
+
+	created := map[string]*MockClient{}
+	router.newClient = func(c Config) (Client, error) {
+		client := NewMockClient(c.Model)
+		created[c.Model] = client
+		return client, nil
+	}
+
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{"short prompt uses haiku", "What is Go?", "claude-haiku-4-5"},
+		{"long prompt uses base", strings.Repeat("analyze this repository ", 20), "base"},
+		{"short prompt reuses haiku", "Hi", "claude-haiku-4-5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := router.Generate(context.Background(), GenerateRequest{UserPrompt: tt.prompt})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if resp.Text != tt.want {
+				t.Errorf("Generate() routed to %q, want %q", resp.Text, tt.want)
+			}
+		})
+	}
+
+	if len(created) != 1 {
+		t.Errorf("created %d rule clients, want 1", len(created))
+	}
+	if got := len(created["claude-haiku-4-5"].Requests()); got != 2 {
+		t.Errorf("haiku client received %d requests, want 2", got)
+	}
+	if got := len(base.Requests()); got != 1 {
+		t.Errorf("base client received %d requests, want 1", got)
+	}
+}
+
+func TestRoutingClient_GenerateWithTools(t *testing.T) {
+	base := NewMockClient("base")
+	router := NewRoutingClient(base, Config{Provider: "anthropic", APIKey: "key"}, RouterConfig{
+		Rules: []RoutingRule{ShortPromptRule()},
+	})
+	router.newClient = func(c Config) (Client, error) { return NewMockClient(c.Model), nil }
+
+	resp, err := router.GenerateWithTools(context.Background(), GenerateWithToolsRequest{
+		Messages: []Message{{Role: "user", Content: []ContentBlock{{Type: "text", Text: "List files"}}}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateWithTools() error = %v", err)
+	}
+	if resp.Text != "claude-haiku-4-5" {
+		t.Errorf("GenerateWithTools() routed to %q, want claude-haiku-4-5", resp.Text)
+	}
+}
+
+func TestRoutingClient_RuleClientOptions(t *testing.T) {
+	var body []byte
+	server := newCaptureServer(t, &body)
+	testURL := func(c *AnthropicClient) { c.apiURL = server.URL }
+	base := NewMockClient("base")
+	router := NewRoutingClient(base, Config{Provider: "anthropic", APIKey: "key", Model: "claude-sonnet-4-5"}, RouterConfig{
+		Rules: []RoutingRule{ShortPromptRule()},
+	}, WithPIIRedaction(), testURL)
+
+	if _, err := router.Generate(context.Background(), GenerateRequest{UserPrompt: "Mail lead@example.com", MaxTokens: 50}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(base.Requests()) != 0 {
+		t.Fatal("short prompt was not routed to the rule client")
+	}
+	if sent := string(body); strings.Contains(sent, "lead@example.com") || !strings.Contains(sent, "[REDACTED-email]") {
+		t.Errorf("routed request was not redacted: %s", sent)
+	}
+}
@@ -0,0 +1,134 @@
+package llm
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Error codes carried by SDKError. Errors from a provider API prefix the code
+// with the provider, e.g. "anthropic:rate_limit".
+const (
+	ErrCodeInvalidRequest    = "invalid_request"
+	ErrCodeAuthentication    = "authentication"
+	ErrCodePermission        = "permission"
+	ErrCodeNotFound          = "not_found"
+	ErrCodeRequestTooLarge   = "request_too_large"
+	ErrCodeRateLimit         = "rate_limit"
+	ErrCodeOverloaded        = "overloaded"
+	ErrCodeAPIError          = "api_error"
+	ErrCodeContentModerated  = "content_moderated"
+	ErrCodeCostThreshold     = "cost_threshold_exceeded"
+	ErrCodeQuotaExceeded     = "quota_exceeded"
+	ErrCodePromptNotFound    = "prompt_not_found"
+	ErrCodeToolsNotSupported = "tools_not_supported"
+	ErrCodeToolLoopExceeded  = "tool_loop_exceeded"
+)
+
+// SDKError is an error with a machine-readable code. The SDK's sentinel errors
+// are SDKErrors, and errors.Is matches any SDKError with the same code, so
+// errors.Is(err, ErrQuotaExceeded) holds for every quota failure.
+type SDKError struct {
+	Code    string // One of the ErrCode* constants, optionally prefixed with "provider:"
+	Message string // Human-readable description
+	Cause   error  // Underlying error, if any
+	HTTP    int    // HTTP status returned by the API, or 0
+}
+
+// NewSDKError creates an error with a code and message
+func NewSDKError(code, message string) *SDKError {
+	return &SDKError{Code: code, Message: message}
+}
+
+// Error implements error
+func (e *SDKError) Error() string {
+	if e.Cause != nil {
+		return e.Message + ": " + e.Cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the cause
+func (e *SDKError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is an SDKError with the same code, ignoring any provider prefix
+func (e *SDKError) Is(target error) bool {
+	t, ok := target.(*SDKError)
+	if !ok {
+		return false
+	}
+	return e.Kind() == t.Kind()
+}
+
+// Kind returns the code without its provider prefix
+func (e *SDKError) Kind() string {
+	if _, kind, found := strings.Cut(e.Code, ":"); found {
+		return kind
+	}
+	return e.Code
+}
+
+// AsSDKError returns the first SDKError in err's chain
+func AsSDKError(err error) (*SDKError, bool) {
+	var sdkErr *SDKError
+	if errors.As(err, &sdkErr) {
+		return sdkErr, true
+	}
+	return nil, false
+}
+
+// ErrorCode returns the code of the first SDKError in err's chain, or ""
+func ErrorCode(err error) string {
+	if sdkErr, ok := AsSDKError(err); ok {
+		return sdkErr.Code
+	}
+	return ""
+}
+
+// HTTPStatus returns the HTTP status of the first SDKError in err's chain that has one, or 0
+func HTTPStatus(err error) int {
+	for err != nil {
+		sdkErr, ok := AsSDKError(err)
+		if !ok {
+			return 0
+		}
+		if sdkErr.HTTP != 0 {
+			return sdkErr.HTTP
+		}
+		err = sdkErr.Cause
+	}
+	return 0
+}
+
+// httpStatusCode maps an API status code to an error code
+func httpStatusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrCodeInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrCodeAuthentication
+	case http.StatusForbidden:
+		return ErrCodePermission
+	case http.StatusNotFound:
+		return ErrCodeNotFound
+	case http.StatusRequestEntityTooLarge:
+		return ErrCodeRequestTooLarge
+	case http.StatusTooManyRequests:
+		return ErrCodeRateLimit
+	case 529:
+		return ErrCodeOverloaded
+	default:
+		return ErrCodeAPIError
+	}
+}
+
+// apiError builds the SDKError for a failed API response
+func apiError(provider string, status int, message string) *SDKError {
+	return &SDKError{
+		Code:    provider + ":" + httpStatusCode(status),
+		Message: message,
+		HTTP:    status,
+	}
+}
@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAnthropicClient_APIErrorCodes(t *testing.T) {
+	tests := []struct {
+		status   int
+		wantCode string
+	}{
+		{http.StatusTooManyRequests, "anthropic:" + ErrCodeRateLimit},
+		{http.StatusUnauthorized, "anthropic:" + ErrCodeAuthentication},
+		{529, "anthropic:" + ErrCodeOverloaded},
+		{http.StatusInternalServerError, "anthropic:" + ErrCodeAPIError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wantCode, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{
+					"type":  "error",
+					"error": map[string]string{"type": "some_error", "message": "try later"},
+				})
+			}))
+			defer server.Close()
+
+			_, err := newTestClient(server.URL).Generate(context.Background(), GenerateRequest{UserPrompt: "hi", MaxTokens: 10})
+			wrapped := fmt.Errorf("failed to generate: %w", err)
+
+			var sdkErr *SDKError
+			if !errors.As(wrapped, &sdkErr) {
+				t.Fatalf("errors.As() found no SDKError in %v", wrapped)
+			}
+			if sdkErr.Code != tt.wantCode || sdkErr.HTTP != tt.status {
+				t.Errorf("SDKError = {Code: %s, HTTP: %d}, want {Code: %s, HTTP: %d}", sdkErr.Code, sdkErr.HTTP, tt.wantCode, tt.status)
+			}
+			if got := HTTPStatus(wrapped); got != tt.status {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.status)
+			}
+			if got := ErrorCode(wrapped); got != tt.wantCode {
+				t.Errorf("ErrorCode() = %s, want %s", got, tt.wantCode)
+			}
+			if !errors.Is(wrapped, &SDKError{Code: sdkErr.Kind()}) {
+				t.Errorf("errors.Is() should match an SDKError with code %s", sdkErr.Kind())
+			}
+		})
+	}
+}
+
+func TestSDKError_Is(t *testing.T) {
+	cause := errors.New("disk full")
+	err := fmt.Errorf("outer: %w", &SDKError{Code: ErrCodeQuotaExceeded, Message: "quota", Cause: cause})
+
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Error("errors.Is() should match the sentinel with the same code")
+	}
+	if errors.Is(err, ErrContentModerated) {
+		t.Error("errors.Is() should not match a sentinel with another code")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("errors.Is() should reach the cause through Unwrap")
+	}
+	if got, want := err.Error(), "outer: quota: disk full"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if HTTPStatus(errors.New("plain")) != 0 || ErrorCode(errors.New("plain")) != "" {
+		t.Error("HTTPStatus() and ErrorCode() should be zero for non-SDK errors")
+	}
+}
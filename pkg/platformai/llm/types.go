@@ -1,5 +1,12 @@
 package llm
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
+)
+
 // Config holds LLM client configuration
 type Config struct {
 	Provider    string
@@ -7,23 +14,176 @@ type Config struct {
 	Model       string
 	Temperature float32
 	MaxTokens   int
+
+	// SystemPromptPrefix is prepended, followed by a blank line, to the system
+	// prompt of every request, e.g. to set a domain for all call sites
+	SystemPromptPrefix string
+
+	// ProxyURL routes API requests through an HTTP(S) proxy. Empty uses the
+	// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+	ProxyURL string
+
+	// TLSInsecureSkipVerify disables TLS certificate verification.
+	//
+	// Deprecated: install the proxy's CA certificate instead; this option will be removed.
+	TLSInsecureSkipVerify bool
+
+	// TLS configures mutual TLS, for example towards an enterprise LLM gateway
+	TLS TLSConfig
+
+	// Timeout bounds each API call, including reading the response (default: 60s)
+	Timeout time.Duration
+
+	// ConnectionPool sizes the HTTP connection pool to the API
+	ConnectionPool ConnectionPoolConfig
+
+	// CustomHeaders are sent with every API request, e.g. for audit trails or
+	// gateway routing. Credential headers such as x-api-key are rejected.
+	CustomHeaders map[string]string
+
+	// BetaFeatures opts in to Anthropic beta features, sent comma-separated as
+	// the anthropic-beta header, e.g. "interleaved-thinking-2025-05-14"
+	BetaFeatures []string
+}
+
+// ConnectionPoolConfig limits pooled HTTP connections. Zero values use the
+// defaults: 100 idle connections in total and per host, closed after 90s idle,
+// and no limit on connections per host.
+type ConnectionPoolConfig struct {
+	MaxIdle         int           `yaml:"max_idle"`
+	MaxIdlePerHost  int           `yaml:"max_idle_per_host"`
+	MaxConnsPerHost int           `yaml:"max_conns_per_host"`
+	IdleConnTimeout time.Duration `yaml:"idle_conn_timeout"`
+}
+
+// TLSConfig holds PEM files for mutual TLS. All three must be set to enable it.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"` // Client certificate
+	KeyFile  string `yaml:"key_file"`  // Client private key
+	CAFile   string `yaml:"ca_file"`   // CA bundle used to verify the server
+}
+
+// transportOptions returns the HTTP transport settings of the config
+func (c Config) transportOptions() httpclient.Options {
+	return httpclient.Options{
+		ProxyURL:              c.ProxyURL,
+		TLSInsecureSkipVerify: c.TLSInsecureSkipVerify,
+		CertFile:              c.TLS.CertFile,
+		KeyFile:               c.TLS.KeyFile,
+		CAFile:                c.TLS.CAFile,
+
+		MaxIdleConns:        c.ConnectionPool.MaxIdle,
+		MaxIdleConnsPerHost: c.ConnectionPool.MaxIdlePerHost,
+		MaxConnsPerHost:     c.ConnectionPool.MaxConnsPerHost,
+		IdleConnTimeout:     c.ConnectionPool.IdleConnTimeout,
+	}
 }
 
 // GenerateRequest represents a request to generate text
 type GenerateRequest struct {
-	SystemPrompt string
-	UserPrompt   string
-	Temperature  float32
-	MaxTokens    int
-	Tools        []Tool // Optional tools for function calling
+	SystemPrompt  string
+	UserPrompt    string
+	Temperature   float32
+	MaxTokens     int
+	Tools         []Tool       // Optional tools for function calling
+	Images        []ImageInput // Optional images, sent before the user prompt
+	StopSequences []string     // Optional sequences that end generation when produced
+
+	FewShotExamples []FewShotExample // Optional example exchanges, sent before the user prompt
+
+	// Nucleus and top-k sampling. Zero leaves the provider default in place.
+	// TopP cannot be combined with a nonzero Temperature.
+	TopP float32
+	TopK int
+
+	// Seed requests reproducible sampling. It only makes output repeatable
+	// together with Temperature 0; see Deterministic.
+	Seed *int
+
+	// CostThreshold rejects the request with ErrCostThresholdExceeded, before
+	// it is sent, when its estimated cost in USD is higher. Zero disables the check.
+	CostThreshold float64
+
+	// IdempotencyKey identifies a logical request across retries. It is sent as
+	// the X-Idempotency-Key header, and a repeated key is answered from the
+	// client's cache of recent responses instead of generating again.
+	IdempotencyKey string
+
+	// ThinkingBudget enables extended thinking with up to this many tokens of
+	// reasoning, returned in GenerateResponse.ThinkingText. It must be at least
+	// 1024 and below MaxTokens, and cannot be combined with Temperature or TopK.
+	ThinkingBudget int
+
+	// CacheSystemPrompt marks the system prompt for Anthropic prompt caching, so
+	// repeated requests with the same prompt read it from the cache at a discount.
+	// Prompts shorter than the model's minimum cacheable length are not cached.
+	CacheSystemPrompt bool
+}
+
+// minThinkingBudget is the smallest extended thinking budget the API accepts
+const minThinkingBudget = 1024
+
+// CitationReferencesHeader starts the references section of citation-formatted context.
+// GenerateWithContext asks the model to cite sources when the context contains it.
+const CitationReferencesHeader = "References:"
+
+const citationInstruction = "Cite the sources you use with their bracketed numbers from the context, e.g. [1] or [2]."
+
+// defaultSeed is the seed used by Deterministic
+var defaultSeed = 42
+
+// Deterministic returns a copy of the request with Temperature 0 and a fixed seed,
+// which makes outputs as reproducible as the provider allows
+func (r GenerateRequest) Deterministic() GenerateRequest {
+	seed := defaultSeed
+	r.Temperature = 0
+	r.Seed = &seed
+	return r
+}
+
+// validate checks for conflicting sampling parameters
+func (r GenerateRequest) validate() error {
+	if r.Temperature != 0 && r.TopP != 0 {
+		return fmt.Errorf("%w: temperature (%.2f) and top_p (%.2f) cannot both be set; use one or the other",
+			ErrInvalidRequest, r.Temperature, r.TopP)
+	}
+	if r.ThinkingBudget != 0 {
+		if r.ThinkingBudget < minThinkingBudget {
+			return fmt.Errorf("%w: thinking budget must be at least %d tokens, got %d",
+				ErrInvalidRequest, minThinkingBudget, r.ThinkingBudget)
+		}
+		if r.MaxTokens <= r.ThinkingBudget {
+			return fmt.Errorf("%w: max tokens (%d) must exceed the thinking budget (%d)",
+				ErrInvalidRequest, r.MaxTokens, r.ThinkingBudget)
+		}
+		if r.Temperature != 0 || r.TopK != 0 {
+			return fmt.Errorf("%w: temperature and top_k cannot be set with extended thinking", ErrInvalidRequest)
+		}
+	}
+	return nil
 }
 
 // GenerateResponse represents the response from the LLM
 type GenerateResponse struct {
-	Text       string
-	Usage      Usage
+	ID    string // Provider message ID
+	Model string // Model that generated the response
+	Text  string
+	Usage Usage
+
+	ThinkingText string     // Extended thinking output, when GenerateRequest.ThinkingBudget is set
+	CacheStats   CacheStats // Prompt cache usage, in addition to Usage.PromptTokens
+
 	ToolUses   []ToolUse // Tool use requests from the LLM
 	StopReason string    // Why generation stopped (end_turn, tool_use, etc.)
+
+	UsedProvider    string // Client that answered, set by FallbackClient
+	TrimmedMessages int    // History messages ConversationSession dropped to fit the context window
+}
+
+// CacheStats counts prompt tokens served from or written to the prompt cache
+type CacheStats struct {
+	CacheReadTokens  int
+	CacheWriteTokens int
 }
 
 // Usage tracks token usage
@@ -61,6 +221,47 @@ type GenerateWithToolsRequest struct {
 	Temperature  float32
 	MaxTokens    int
 	Tools        []Tool
+
+	FewShotExamples []FewShotExample // Optional example exchanges, sent before Messages
+
+	// CacheSystemPrompt marks the system prompt for prompt caching, as in GenerateRequest
+	CacheSystemPrompt bool
+}
+
+// FewShotExample is an example exchange sent ahead of the real prompt to show the
+// model what a good answer looks like
+type FewShotExample struct {
+	UserMessage      string
+	AssistantMessage string
+
+	// ToolUses and ToolResults show the assistant calling tools before answering
+	// with AssistantMessage. Each tool use needs a result with a matching ToolUseID.
+	ToolUses    []ToolUse
+	ToolResults []ToolResult
+}
+
+// fewShotMessages expands examples into alternating user and assistant messages
+func fewShotMessages(examples []FewShotExample) []Message {
+	var messages []Message
+	for _, ex := range examples {
+		messages = append(messages, Message{Role: "user", Content: []ContentBlock{{Type: "text", Text: ex.UserMessage}}})
+		if len(ex.ToolUses) > 0 {
+			uses := make([]ContentBlock, 0, len(ex.ToolUses))
+			for _, use := range ex.ToolUses {
+				uses = append(uses, ContentBlock{Type: "tool_use", ID: use.ID, Name: use.Name, Input: use.Input})
+			}
+			results := make([]ContentBlock, 0, len(ex.ToolResults))
+			for _, result := range ex.ToolResults {
+				results = append(results, ContentBlock{Type: "tool_result", ToolUseID: result.ToolUseID, Content: result.Content, IsError: result.IsError})
+			}
+			messages = append(messages,
+				Message{Role: "assistant", Content: uses},
+				Message{Role: "user", Content: results},
+			)
+		}
+		messages = append(messages, Message{Role: "assistant", Content: []ContentBlock{{Type: "text", Text: ex.AssistantMessage}}})
+	}
+	return messages
 }
 
 // Message represents a conversation message
@@ -0,0 +1,196 @@
+package platformai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the SDK's spans
+const tracerName = "github.com/philipsahli/innominatus-ai-sdk/pkg/platformai"
+
+// Option configures an SDK. Options run after the LLM client and RAG module are created.
+type Option func(*SDK) error
+
+// Logger receives diagnostic messages. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+// AuditEvent describes one completed SDK call
+type AuditEvent struct {
+	Operation string        // e.g. "llm.Generate", "rag.Retrieve"
+	Start     time.Time     // When the call started
+	Duration  time.Duration // How long the call took
+	Err       error         // Error returned by the call, if any
+}
+
+// AuditLogger records every LLM and retrieval call made through the SDK
+type AuditLogger interface {
+	LogAudit(ctx context.Context, event AuditEvent)
+}
+
+// RateLimiter throttles SDK calls. Wait blocks until a call may proceed.
+// *rate.Limiter from golang.org/x/time/rate satisfies it.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithHTTPClient makes the LLM client and embedding provider send their requests with c
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *SDK) error {
+		if c == nil {
+			return fmt.Errorf("%w: HTTP client is nil", ErrInvalidConfig)
+		}
+		setter, ok := s.baseLLM.(interface{ SetHTTPClient(*http.Client) })
+		if !ok {
+			return fmt.Errorf("%w: LLM client does not accept an HTTP client", ErrInvalidConfig)
+		}
+		setter.SetHTTPClient(c)
+		s.httpClient = c
+		if s.ragModule != nil {
+			s.ragModule.SetHTTPClient(c)
+		}
+		return nil
+	}
+}
+
+// WithRouter sends each LLM request to the model of the first matching routing
+// rule; requests matching no rule use the configured model
+func WithRouter(cfg llm.RouterConfig) Option {
+	return func(s *SDK) error {
+		s.wrapLLM(func(client llm.Client, config *Config) llm.Client {
+			router := llm.NewRoutingClient(client, llmConfig(config), cfg)
+			if s.httpClient != nil {
+				router.SetHTTPClient(s.httpClient)
+			}
+			return router
+		})
+		return nil
+	}
+}
+
+// WithFallback sends LLM requests to the given clients, in order, when the
+// configured provider fails
+func WithFallback(providers ...llm.Client) Option {
+	return func(s *SDK) error {
+		if len(providers) == 0 {
+			return fmt.Errorf("%w: no fallback providers", ErrInvalidConfig)
+		}
+		s.wrapLLM(func(client llm.Client, config *Config) llm.Client {
+			return llm.NewFallbackClient(client, providers...)
+		})
+		return nil
+	}
+}
+
+// wrapLLM wraps the provider client and records wrap for clients ApplyConfig creates
+func (s *SDK) wrapLLM(wrap llmWrapper) {
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+
+	s.wrappers = append(s.wrappers, wrap)
+	s.baseLLM = wrap(s.baseLLM, s.config)
+}
+
+// WithLogger sets the logger that failed calls are reported to
+func WithLogger(l Logger) Option {
+	return func(s *SDK) error {
+		s.logger = l
+		return nil
+	}
+}
+
+// WithTracer records a span for every LLM and retrieval call
+func WithTracer(t trace.TracerProvider) Option {
+	return func(s *SDK) error {
+		if t == nil {
+			return fmt.Errorf("%w: tracer provider is nil", ErrInvalidConfig)
+		}
+		s.tracer = t.Tracer(tracerName)
+		return nil
+	}
+}
+
+// WithAuditLogger sets the audit logger that every call is reported to
+func WithAuditLogger(al AuditLogger) Option {
+	return func(s *SDK) error {
+		s.auditLogger = al
+		return nil
+	}
+}
+
+// WithRateLimiter throttles LLM and retrieval calls with rl
+func WithRateLimiter(rl RateLimiter) Option {
+	return func(s *SDK) error {
+		s.rateLimiter = rl
+		return nil
+	}
+}
+
+// trackedCall is an SDK call observed by track; it reports the tokens it used
+type trackedCall func(ctx context.Context) (llm.Usage, error)
+
+// track runs call as an in-flight operation, applying the rate limiter, tracer,
+// audit logger, and logger configured with options, and records it in Analytics
+func (s *SDK) track(ctx context.Context, operation string, call trackedCall) error {
+	if err := s.begin(); err != nil {
+		return err
+	}
+	defer s.inFlight.Done()
+
+	if s.rateLimiter != nil {
+		if err := s.rateLimiter.Wait(ctx); err != nil {
+			return fmt.Errorf("failed to wait for rate limiter: %w", err)
+		}
+	}
+
+	if s.tracer != nil {
+		var span trace.Span
+		ctx, span = s.tracer.Start(ctx, operation)
+		defer span.End()
+		call = recordSpanError(span, call)
+	}
+
+	start := time.Now()
+	usage, err := call(ctx)
+	s.analytics.Record(AnalyticsEvent{
+		Timestamp:    start,
+		Operation:    analyticsOperation(operation),
+		Model:        s.model(),
+		InputTokens:  usage.PromptTokens,
+		OutputTokens: usage.CompletionTokens,
+		DurationMs:   time.Since(start).Milliseconds(),
+		Error:        err != nil,
+	})
+
+	if s.auditLogger != nil {
+		s.auditLogger.LogAudit(ctx, AuditEvent{
+			Operation: operation,
+			Start:     start,
+			Duration:  time.Since(start),
+			Err:       err,
+		})
+	}
+	if err != nil && s.logger != nil {
+		s.logger.Printf("%s failed: %v", operation, err)
+	}
+	return err
+}
+
+// recordSpanError wraps call so its error is recorded on span
+func recordSpanError(span trace.Span, call trackedCall) trackedCall {
+	return func(ctx context.Context) (llm.Usage, error) {
+		usage, err := call(ctx)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return usage, err
+	}
+}
@@ -0,0 +1,117 @@
+package platformai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// recordingAuditLogger collects audit events
+type recordingAuditLogger struct {
+	events []AuditEvent
+}
+
+func (l *recordingAuditLogger) LogAudit(ctx context.Context, event AuditEvent) {
+	l.events = append(l.events, event)
+}
+
+func TestNew_WithHTTPClient(t *testing.T) {
+	var hosts []string
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		hosts = append(hosts, r.URL.Host)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"content":[{"type":"text","text":"hello"}]}`)),
+			Request:    r,
+		}, nil
+	})}
+	audit := &recordingAuditLogger{}
+
+	sdk, err := New(context.Background(), &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "test"}},
+		WithHTTPClient(client), WithAuditLogger(audit))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	resp, err := sdk.LLM().Generate(context.Background(), llm.GenerateRequest{UserPrompt: "hi", MaxTokens: 10})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Text != "hello" {
+		t.Errorf("Generate() text = %q, want hello", resp.Text)
+	}
+	if len(hosts) != 1 || hosts[0] != "api.anthropic.com" {
+		t.Errorf("custom HTTP client saw requests to %v, want one to api.anthropic.com", hosts)
+	}
+	if len(audit.events) != 1 || audit.events[0].Operation != "llm.Generate" {
+		t.Errorf("audit events = %+v, want one llm.Generate event", audit.events)
+	}
+}
+
+func TestNew_OptionError(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"nil HTTP client", WithHTTPClient(nil)},
+		{"nil tracer", WithTracer(nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(context.Background(), &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "test"}}, tt.opt)
+			if !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("New() error = %v, want ErrInvalidConfig", err)
+			}
+		})
+	}
+}
+
+func TestWithRouter(t *testing.T) {
+	sdk, err := New(context.Background(), &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "key"}},
+		WithRouter(llm.RouterConfig{Rules: []llm.RoutingRule{llm.ShortPromptRule()}}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, ok := sdk.provider().(*llm.RoutingClient); !ok {
+		t.Errorf("provider() = %T, want *llm.RoutingClient", sdk.provider())
+	}
+	if err := sdk.RotateLLMKey("new-key"); err != nil {
+		t.Errorf("RotateLLMKey() error = %v", err)
+	}
+}
+
+func TestWithFallback(t *testing.T) {
+	sdk, err := New(context.Background(), &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "key"}},
+		WithHTTPClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})}),
+		WithFallback(llm.NewMockClient("from fallback")))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	resp, err := sdk.LLM().Generate(context.Background(), llm.GenerateRequest{UserPrompt: "hi"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if resp.Text != "from fallback" || resp.UsedProvider != "fallback-1" {
+		t.Errorf("Generate() = %q from %q, want fallback response", resp.Text, resp.UsedProvider)
+	}
+
+	if _, err := New(context.Background(), &Config{LLM: LLMConfig{Provider: "anthropic", APIKey: "key"}}, WithFallback()); err == nil {
+		t.Error("New() expected error for WithFallback without providers")
+	}
+}
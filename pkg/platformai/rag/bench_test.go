@@ -0,0 +1,71 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// benchmarkDims are the embedding sizes of common models, from small local
+// models up to text-embedding-3-large
+var benchmarkDims = []int{128, 256, 512, 1536, 3072}
+
+// randomVector returns dim normally distributed values
+func randomVector(rng *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = float32(rng.NormFloat64())
+	}
+	return v
+}
+
+var similaritySink float32
+
+func benchmarkSimilarity(b *testing.B, fn func(a, b []float32) float32) {
+	for _, dim := range benchmarkDims {
+		b.Run(fmt.Sprintf("dim%d", dim), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			x, y := randomVector(rng, dim), randomVector(rng, dim)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				similaritySink = fn(x, y)
+			}
+		})
+	}
+}
+
+func BenchmarkCosineSimilarity(b *testing.B) {
+	benchmarkSimilarity(b, cosineSimilarity)
+}
+
+func BenchmarkCosineSimilarityGeneric(b *testing.B) {
+	benchmarkSimilarity(b, cosineSimilarityGeneric)
+}
+
+// benchmarkSearch measures topK=10 search over 10000 clustered documents; compare
+// the InMemory and HNSW results to see what the index saves over a full scan
+func benchmarkSearch(b *testing.B, store VectorStore) {
+	const dim = 64
+	ctx := context.Background()
+	if err := store.AddBatch(ctx, randomDocuments(10000, dim, 1)); err != nil {
+		b.Fatal(err)
+	}
+	queries := randomDocuments(100, dim, 2)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.Search(ctx, queries[i%len(queries)].Embedding, 10, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInMemoryVectorStore_Search(b *testing.B) {
+	benchmarkSearch(b, NewInMemoryVectorStore())
+}
+
+func BenchmarkHNSWVectorStore_Search(b *testing.B) {
+	benchmarkSearch(b, NewHNSWVectorStore(64, 16, 100))
+}
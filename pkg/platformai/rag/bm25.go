@@ -0,0 +1,267 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Default BM25 parameters
+const (
+	DefaultBM25K1 = 1.5
+	DefaultBM25B  = 0.75
+)
+
+// BM25Index is an inverted keyword index that scores documents with Okapi BM25.
+// It complements dense retrieval by rewarding exact term matches such as error
+// codes and identifiers that embeddings tend to blur.
+type BM25Index struct {
+	K1 float64 // Term frequency saturation
+	B  float64 // Document length normalization (0 = none, 1 = full)
+
+	mu          sync.RWMutex
+	docs        map[string]bm25Entry
+	postings    map[string]map[string]int // Term -> document ID -> term frequency
+	totalLength int
+}
+
+// bm25Entry is an indexed document and its token count
+type bm25Entry struct {
+	doc    Document
+	length int
+}
+
+// NewBM25Index creates an empty index with the default parameters
+func NewBM25Index() *BM25Index {
+	return &BM25Index{
+		K1:       DefaultBM25K1,
+		B:        DefaultBM25B,
+		docs:     make(map[string]bm25Entry),
+		postings: make(map[string]map[string]int),
+	}
+}
+
+// Add indexes a document, replacing any document with the same ID
+func (i *BM25Index) Add(doc Document) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(doc.ID)
+
+	tokens := tokenize(doc.Content)
+	for _, token := range tokens {
+		if i.postings[token] == nil {
+			i.postings[token] = make(map[string]int)
+		}
+		i.postings[token][doc.ID]++
+	}
+
+	doc.Embedding = nil
+	i.docs[doc.ID] = bm25Entry{doc: doc, length: len(tokens)}
+	i.totalLength += len(tokens)
+}
+
+// Remove deletes a document from the index
+func (i *BM25Index) Remove(id string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+}
+
+// remove deletes a document's postings. Callers must hold the write lock.
+func (i *BM25Index) remove(id string) {
+	entry, exists := i.docs[id]
+	if !exists {
+		return
+	}
+
+	for _, token := range tokenize(entry.doc.Content) {
+		delete(i.postings[token], id)
+		if len(i.postings[token]) == 0 {
+			delete(i.postings, token)
+		}
+	}
+	i.totalLength -= entry.length
+	delete(i.docs, id)
+}
+
+// Search returns the documents matching any query term, highest BM25 score
+// first. topK <= 0 returns every match.
+func (i *BM25Index) Search(query string, topK int) []SearchResult {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	if len(i.docs) == 0 {
+		return []SearchResult{}
+	}
+
+	n := float64(len(i.docs))
+	avgLength := float64(i.totalLength) / n
+	scores := make(map[string]float64)
+
+	seen := make(map[string]bool)
+	for _, token := range tokenize(query) {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+
+		postings := i.postings[token]
+		if len(postings) == 0 {
+			continue
+		}
+
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range postings {
+			length := float64(i.docs[id].length)
+			norm := i.K1 * (1 - i.B + i.B*length/avgLength)
+			scores[id] += idf * float64(tf) * (i.K1 + 1) / (float64(tf) + norm)
+		}
+	}
+
+	results := make([]SearchResult, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, SearchResult{Document: i.docs[id].doc, Score: float32(score)})
+	}
+	sortResults(results)
+
+	if topK > 0 && topK < len(results) {
+		results = results[:topK]
+	}
+	return results
+}
+
+// tokenize lowercases text and splits it into letter/digit runs, keeping
+// underscores and hyphens so identifiers like ERR_CONN-42 stay one token
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+}
+
+// rrfK dampens the influence of top ranks in reciprocal rank fusion
+const rrfK = 60
+
+// hybridCandidateFactor controls how many candidates each retriever contributes per requested result
+const hybridCandidateFactor = 4
+
+// HybridRetriever combines dense vector search with BM25 keyword search using
+// weighted reciprocal rank fusion
+type HybridRetriever struct {
+	dense *Retriever
+	index *BM25Index
+	alpha float32
+}
+
+// NewHybridRetriever creates a hybrid retriever. alpha weights the dense ranking
+// against BM25: 0 uses BM25 only, 1 dense only, 0.5 weighs them equally.
+func NewHybridRetriever(dense *Retriever, index *BM25Index, alpha float32) *HybridRetriever {
+	alpha = min(max(alpha, 0), 1)
+	return &HybridRetriever{
+		dense: dense,
+		index: index,
+		alpha: alpha,
+	}
+}
+
+// Retrieve runs dense and keyword search in parallel and fuses the rankings.
+// Result scores are the fused RRF scores; MinScore applies to the dense similarity.
+func (h *HybridRetriever) Retrieve(ctx context.Context, req RetrieveRequest) (*RetrieveResponse, error) {
+	if req.TopK <= 0 {
+		req.TopK = 3
+	}
+	candidates := req.TopK * hybridCandidateFactor
+
+	query, err := h.dense.rewriteQuery(ctx, req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	type denseResult struct {
+		embedding []float32
+		results   []SearchResult
+		err       error
+	}
+	denseCh := make(chan denseResult, 1)
+	go func() {
+		embedding, err := h.dense.embedder.GenerateEmbedding(WithQueryEmbedding(ctx), query)
+		if err != nil {
+			denseCh <- denseResult{err: fmt.Errorf("failed to generate query embedding: %w", err)}
+			return
+		}
+		embedding = h.dense.prepareEmbedding(embedding)
+		results, err := h.dense.store.SearchWithFilter(ctx, embedding, candidates, req.MinScore, req.Filters)
+		if err != nil {
+			err = fmt.Errorf("failed to search documents: %w", err)
+		}
+		denseCh <- denseResult{embedding: embedding, results: results, err: err}
+	}()
+
+	var keyword []SearchResult
+	for _, result := range h.index.Search(query, 0) {
+		if matchesFilters(result.Document, req.Filters) {
+			keyword = append(keyword, result)
+			if len(keyword) == candidates {
+				break
+			}
+		}
+	}
+
+	dense := <-denseCh
+	if dense.err != nil {
+		return nil, dense.err
+	}
+
+	results := fuseRankings(dense.results, keyword, h.alpha, req.TopK)
+	context, err := formatContext(results, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &RetrieveResponse{
+		Results:        results,
+		Context:        context,
+		QueryEmbedding: dense.embedding,
+	}
+	if h.dense.rewriter != nil {
+		resp.RewrittenQuery = query
+	}
+	return resp, nil
+}
+
+// fuseRankings merges two rankings with weighted reciprocal rank fusion,
+// scoring each document alpha/(k+rank) in dense plus (1-alpha)/(k+rank) in keyword
+func fuseRankings(dense, keyword []SearchResult, alpha float32, topK int) []SearchResult {
+	scores := make(map[string]float32)
+	docs := make(map[string]Document)
+	add := func(results []SearchResult, weight float32) {
+		if weight == 0 {
+			return
+		}
+		for rank, result := range results {
+			id := result.Document.ID
+			scores[id] += weight / float32(rrfK+rank+1)
+			if _, exists := docs[id]; !exists || len(result.Document.Embedding) > 0 {
+				docs[id] = result.Document
+			}
+		}
+	}
+	add(dense, alpha)
+	add(keyword, 1-alpha)
+
+	fused := make([]SearchResult, 0, len(scores))
+	for id, score := range scores {
+		fused = append(fused, SearchResult{Document: docs[id], Score: score})
+	}
+	sortResults(fused)
+
+	if topK > 0 && topK < len(fused) {
+		fused = fused[:topK]
+	}
+	return fused
+}
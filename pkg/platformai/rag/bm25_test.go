@@ -0,0 +1,103 @@
+package rag
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// keywordCorpus has one document with a rare error code and several that share
+// the query's common words, which dense search favors
+var keywordCorpus = []Document{
+	{ID: "general", Content: "troubleshooting error guide: general troubleshooting error steps for common troubleshooting error"},
+	{ID: "code", Content: "ERR_4921 means the database connection pool is exhausted"},
+	{ID: "handling", Content: "error handling troubleshooting tips"},
+	{ID: "codes", Content: "error codes troubleshooting overview"},
+	{ID: "network", Content: "troubleshooting network error"},
+}
+
+const keywordQuery = "troubleshooting error ERR_4921"
+
+func TestTokenize(t *testing.T) {
+	got := tokenize("Fix ERR_CONN-42, then retry (twice).")
+	want := []string{"fix", "err_conn-42", "then", "retry", "twice"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize() = %v, want %v", got, want)
+	}
+}
+
+func TestBM25Index_Search(t *testing.T) {
+	index := NewBM25Index()
+	for _, doc := range keywordCorpus {
+		index.Add(doc)
+	}
+
+	results := index.Search(keywordQuery, 0)
+	if len(results) != len(keywordCorpus) {
+		t.Fatalf("Search() = %d results, want %d", len(results), len(keywordCorpus))
+	}
+	if results[0].Document.ID != "code" {
+		t.Errorf("Search() top result = %s, want code", results[0].Document.ID)
+	}
+
+	if got := index.Search("kubernetes", 0); len(got) != 0 {
+		t.Errorf("Search() unmatched term = %+v, want no results", got)
+	}
+
+	index.Remove("code")
+	for _, r := range index.Search("ERR_4921", 0) {
+		t.Errorf("Search() after Remove returned %s", r.Document.ID)
+	}
+
+	index.Add(Document{ID: "general", Content: "kubernetes"})
+	if got := index.Search("kubernetes", 1); len(got) != 1 || got[0].Document.ID != "general" {
+		t.Errorf("Search() after replace = %+v, want general", got)
+	}
+}
+
+func TestModule_HybridSearch(t *testing.T) {
+	ctx := context.Background()
+	rank := func(m *Module, topK int) []string {
+		t.Helper()
+		if err := m.AddDocuments(ctx, keywordCorpus); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := m.Retrieve(ctx, RetrieveRequest{Query: keywordQuery, TopK: topK})
+		if err != nil {
+			t.Fatalf("Retrieve() error = %v", err)
+		}
+		var ids []string
+		for _, r := range resp.Results {
+			ids = append(ids, r.Document.ID)
+		}
+		return ids
+	}
+
+	dense := rank(newTestModule(), len(keywordCorpus))
+	keyword := rank(newTestModule(WithHybridSearch(0)), len(keywordCorpus))
+	if keyword[0] != "code" {
+		t.Errorf("BM25-only ranking = %v, want code first", keyword)
+	}
+	if indexOf(keyword, "code") >= indexOf(dense, "code") {
+		t.Errorf("exact keyword match ranked %d with BM25, %d with dense; want BM25 higher",
+			indexOf(keyword, "code"), indexOf(dense, "code"))
+	}
+
+	if got := rank(newTestModule(WithHybridSearch(1)), len(keywordCorpus)); !reflect.DeepEqual(got, dense) {
+		t.Errorf("dense-only hybrid ranking = %v, want %v", got, dense)
+	}
+
+	hybrid := rank(newTestModule(WithHybridSearch(0.5)), len(keywordCorpus))
+	if indexOf(hybrid, "code") >= indexOf(dense, "code") {
+		t.Errorf("hybrid ranking = %v, want code above its dense rank in %v", hybrid, dense)
+	}
+}
+
+func indexOf(ids []string, id string) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return len(ids)
+}
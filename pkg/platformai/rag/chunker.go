@@ -0,0 +1,98 @@
+package rag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Chunker splits document content into smaller pieces for embedding
+type Chunker interface {
+	Chunk(text string) []string
+}
+
+// FixedSizeChunker splits text into chunks of at most Size characters,
+// repeating Overlap characters between neighbouring chunks
+type FixedSizeChunker struct {
+	Size    int
+	Overlap int
+}
+
+// NewFixedSizeChunker creates a chunker with the given size and overlap in characters
+func NewFixedSizeChunker(size, overlap int) *FixedSizeChunker {
+	if size <= 0 {
+		size = 1000
+	}
+	if overlap < 0 || overlap >= size {
+		overlap = 0
+	}
+	return &FixedSizeChunker{Size: size, Overlap: overlap}
+}
+
+// Chunk splits text, preferring whitespace boundaries so words stay intact
+func (c *FixedSizeChunker) Chunk(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	runes := []rune(text)
+	if len(runes) <= c.Size {
+		return []string{text}
+	}
+
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := start + c.Size
+		if end >= len(runes) {
+			chunks = append(chunks, strings.TrimSpace(string(runes[start:])))
+			break
+		}
+
+		for i := end; i > start+c.Size/2; i-- {
+			if unicode.IsSpace(runes[i]) {
+				end = i
+				break
+			}
+		}
+		chunks = append(chunks, strings.TrimSpace(string(runes[start:end])))
+
+		next := end - c.Overlap
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+
+	return chunks
+}
+
+// chunkDocuments splits each document into chunks. Multi-chunk documents get
+// IDs of the form "<id>#<n>" and a "chunk" metadata entry.
+func chunkDocuments(chunker Chunker, docs []Document) []Document {
+	var chunked []Document
+	for _, doc := range docs {
+		parts := chunker.Chunk(doc.Content)
+		if len(parts) == 1 {
+			doc.Content = parts[0]
+			chunked = append(chunked, doc)
+			continue
+		}
+
+		for i, part := range parts {
+			metadata := make(map[string]string, len(doc.Metadata)+1)
+			for k, v := range doc.Metadata {
+				metadata[k] = v
+			}
+			metadata["chunk"] = strconv.Itoa(i)
+
+			chunked = append(chunked, Document{
+				ID:       fmt.Sprintf("%s#%d", doc.ID, i),
+				Content:  part,
+				Metadata: metadata,
+			})
+		}
+	}
+	return chunked
+}
@@ -0,0 +1,57 @@
+package rag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFixedSizeChunker_Chunk(t *testing.T) {
+	chunker := NewFixedSizeChunker(20, 5)
+
+	if got := chunker.Chunk("short text"); len(got) != 1 || got[0] != "short text" {
+		t.Errorf("Chunk() short text = %v, want single chunk", got)
+	}
+	if got := chunker.Chunk("   "); got != nil {
+		t.Errorf("Chunk() blank text = %v, want nil", got)
+	}
+
+	text := "alpha beta gamma delta epsilon zeta eta theta iota kappa"
+	chunks := chunker.Chunk(text)
+	if len(chunks) < 3 {
+		t.Fatalf("Chunk() = %d chunks, want at least 3", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if len([]rune(chunk)) > 20 {
+			t.Errorf("chunk %q exceeds size 20", chunk)
+		}
+		for _, word := range strings.Fields(chunk) {
+			if !strings.Contains(text, word) {
+				t.Errorf("chunk %q split a word", chunk)
+			}
+		}
+	}
+	if !strings.HasSuffix(chunks[len(chunks)-1], "kappa") {
+		t.Errorf("last chunk = %q, want it to end the text", chunks[len(chunks)-1])
+	}
+}
+
+func TestChunkDocuments(t *testing.T) {
+	docs := []Document{
+		{ID: "small", Content: "fits in one chunk", Metadata: map[string]string{"source": "a"}},
+		{ID: "large", Content: strings.Repeat("word ", 20), Metadata: map[string]string{"source": "b"}},
+	}
+
+	chunks := chunkDocuments(NewFixedSizeChunker(30, 0), docs)
+	if chunks[0].ID != "small" {
+		t.Errorf("single-chunk document ID = %s, want small", chunks[0].ID)
+	}
+	if len(chunks) < 3 {
+		t.Fatalf("chunkDocuments() = %d chunks, want at least 3", len(chunks))
+	}
+	if chunks[1].ID != "large#0" || chunks[1].Metadata["chunk"] != "0" || chunks[1].Metadata["source"] != "b" {
+		t.Errorf("first chunk of large document = %+v", chunks[1])
+	}
+	if _, ok := docs[1].Metadata["chunk"]; ok {
+		t.Error("chunkDocuments() should not modify the source metadata")
+	}
+}
@@ -0,0 +1,150 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// Every VectorStore implementation; add new stores here and call
+// testVectorStoreContract from their tests
+var (
+	_ VectorStore = (*InMemoryVectorStore)(nil)
+	_ VectorStore = (*HNSWVectorStore)(nil)
+)
+
+// testVectorStoreContract exercises every VectorStore method against the
+// documented behavior. store must be empty and accept 3-dimensional embeddings.
+func testVectorStoreContract(t *testing.T, store VectorStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	docs := []Document{
+		{ID: "a", Content: "alpha", Embedding: []float32{1, 0, 0}, Metadata: map[string]string{"team": "payments"}, SparseVector: SparseVector{1: 1}},
+		{ID: "b", Content: "beta", Embedding: []float32{0, 1, 0}, Metadata: map[string]string{"team": "search"}},
+		{ID: "c", Content: "gamma", Embedding: []float32{0, 0, 1}, Metadata: map[string]string{"team": "payments"}},
+	}
+
+	t.Run("Add", func(t *testing.T) {
+		if err := store.Add(ctx, Document{Embedding: []float32{1, 0, 0}}); err == nil {
+			t.Error("Add() with an empty ID succeeded, want error")
+		}
+		if err := store.Add(ctx, Document{ID: "empty"}); err == nil {
+			t.Error("Add() with an empty embedding succeeded, want error")
+		}
+		if err := store.Add(ctx, docs[0]); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	})
+
+	t.Run("AddBatch", func(t *testing.T) {
+		if err := store.AddBatch(ctx, []Document{{ID: "", Embedding: []float32{1, 1, 0}}}); err == nil {
+			t.Error("AddBatch() with an empty ID succeeded, want error")
+		}
+		if err := store.AddBatch(ctx, docs[1:]); err != nil {
+			t.Fatalf("AddBatch() error = %v", err)
+		}
+		if n, err := store.Count(ctx); err != nil || n != len(docs) {
+			t.Errorf("Count() = %d, %v, want %d", n, err, len(docs))
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		missing := Document{ID: "missing", Embedding: []float32{1, 0, 0}}
+		if err := store.Update(ctx, missing); !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("Update() of a missing document error = %v, want ErrDocumentNotFound", err)
+		}
+		updated := docs[1]
+		updated.Content = "beta v2"
+		if err := store.Update(ctx, updated); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		if doc, err := store.Get(ctx, "b"); err != nil || doc.Content != "beta v2" {
+			t.Errorf("Get() after Update() = %+v, %v, want updated content", doc, err)
+		}
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		if doc, err := store.Get(ctx, "a"); err != nil || doc.ID != "a" {
+			t.Errorf("Get() = %+v, %v, want document a", doc, err)
+		}
+		if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("Get() of a missing document error = %v, want ErrDocumentNotFound", err)
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		if _, err := store.Search(ctx, nil, 1, 0); err == nil {
+			t.Error("Search() with an empty query succeeded, want error")
+		}
+		results, err := store.Search(ctx, []float32{0, 0, 1}, 1, 0)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != 1 || results[0].Document.ID != "c" {
+			t.Errorf("Search() = %v, want document c", results)
+		}
+
+		results, err = store.SearchWithFilter(ctx, []float32{0, 1, 0}, 0, -1, map[string]string{"team": "payments"})
+		if err != nil {
+			t.Fatalf("SearchWithFilter() error = %v", err)
+		}
+		if len(results) != 2 {
+			t.Errorf("SearchWithFilter() = %v, want the 2 payments documents", results)
+		}
+		for _, r := range results {
+			if r.Document.Metadata["team"] != "payments" {
+				t.Errorf("SearchWithFilter() returned %s outside the filter", r.Document.ID)
+			}
+		}
+	})
+
+	t.Run("SparseSearch", func(t *testing.T) {
+		if _, err := store.SparseSearch(ctx, nil, 1); err == nil {
+			t.Error("SparseSearch() with an empty query succeeded, want error")
+		}
+		results, err := store.SparseSearch(ctx, SparseVector{1: 1}, 10)
+		if err != nil {
+			t.Fatalf("SparseSearch() error = %v", err)
+		}
+		if len(results) != 1 || results[0].Document.ID != "a" {
+			t.Errorf("SparseSearch() = %v, want only document a", results)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		page, err := store.List(ctx, 1, 1)
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(page) != 1 || page[0].ID != "b" {
+			t.Errorf("List(1, 1) = %v, want document b", page)
+		}
+		if _, err := store.List(ctx, -1, 0); err == nil {
+			t.Error("List() with a negative offset succeeded, want error")
+		}
+
+		matched, err := store.ListByMetadata(ctx, map[string]string{"team": "payments"}, 0, 0)
+		if err != nil {
+			t.Fatalf("ListByMetadata() error = %v", err)
+		}
+		if len(matched) != 2 || matched[0].ID != "a" || matched[1].ID != "c" {
+			t.Errorf("ListByMetadata() = %v, want documents a and c", matched)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := store.Delete(ctx, "missing"); !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("Delete() of a missing document error = %v, want ErrDocumentNotFound", err)
+		}
+		if err := store.Delete(ctx, "a"); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := store.Get(ctx, "a"); !errors.Is(err, ErrDocumentNotFound) {
+			t.Errorf("Get() after Delete() error = %v, want ErrDocumentNotFound", err)
+		}
+		if n, err := store.Count(ctx); err != nil || n != len(docs)-1 {
+			t.Errorf("Count() after Delete() = %d, %v, want %d", n, err, len(docs)-1)
+		}
+	})
+}
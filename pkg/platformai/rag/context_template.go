@@ -0,0 +1,78 @@
+package rag
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultContextTemplate renders each document the way the built-in context format does
+const DefaultContextTemplate = `--- Document {{.Index}} (Relevance: {{printf "%.2f" .Score}}) ---
+{{if .Title}}Title: {{.Title}}
+{{end}}{{if .Source}}Source: {{.Source}}
+{{end}}
+{{.Content}}
+
+`
+
+// CompactContextTemplate renders each document as one line: its title and a
+// one-sentence summary. The summary comes from the module's LLM when one is set
+// with WithLLM, otherwise from the document's first sentence.
+const CompactContextTemplate = `[{{.Index}}] {{if .Title}}{{.Title}}: {{end}}{{summary .Content}}
+`
+
+// ContextDocument is the data a ContextTemplate is executed with for each retrieved document
+type ContextDocument struct {
+	Index   int     // 1-based position in the results
+	ID      string  // Document ID
+	Score   float32 // Similarity score
+	Title   string  // "title" metadata
+	Source  string  // "source" metadata
+	Content string  // Document content
+}
+
+// summarizeFunc condenses document content to one sentence for the summary template function
+type summarizeFunc func(content string) (string, error)
+
+// executeContextTemplate renders text once per result and concatenates the output
+func executeContextTemplate(text string, results []SearchResult, summarize summarizeFunc) (string, error) {
+	if summarize == nil {
+		summarize = func(content string) (string, error) {
+			return firstSentence(content), nil
+		}
+	}
+
+	tmpl, err := template.New("context").Funcs(template.FuncMap{"summary": summarize}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse context template: %w", err)
+	}
+
+	var builder strings.Builder
+	for i, result := range results {
+		doc := ContextDocument{
+			Index:   i + 1,
+			ID:      result.Document.ID,
+			Score:   result.Score,
+			Title:   result.Document.Metadata["title"],
+			Source:  result.Document.Metadata["source"],
+			Content: result.Document.Content,
+		}
+		if err := tmpl.Execute(&builder, doc); err != nil {
+			return "", fmt.Errorf("failed to execute context template for document %s: %w", doc.ID, err)
+		}
+	}
+	return builder.String(), nil
+}
+
+// firstSentence returns text up to and including its first sentence terminator
+func firstSentence(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	for i, r := range text {
+		if r == '.' || r == '!' || r == '?' {
+			if i+1 == len(text) || text[i+1] == ' ' {
+				return text[:i+1]
+			}
+		}
+	}
+	return text
+}
@@ -6,15 +6,81 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
 )
 
+// defaultEmbeddingTimeout bounds embedding API calls when Config.Timeout is unset
+const defaultEmbeddingTimeout = 30 * time.Second
+
+// Default batch sizes, the largest inputs the APIs accept comfortably
+const (
+	defaultVoyageBatchSize = 128
+	defaultOpenAIBatchSize = 100
+	defaultCohereBatchSize = 96
+)
+
+// queryEmbeddingKey is the context key set by WithQueryEmbedding
+type queryEmbeddingKey struct{}
+
+// WithQueryEmbedding marks ctx as embedding a search query rather than a document.
+// The retrievers set it for query embeddings; providers that embed queries and
+// documents differently, like Cohere, read it with IsQueryEmbedding.
+func WithQueryEmbedding(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryEmbeddingKey{}, true)
+}
+
+// IsQueryEmbedding reports whether ctx was marked with WithQueryEmbedding
+func IsQueryEmbedding(ctx context.Context) bool {
+	isQuery, _ := ctx.Value(queryEmbeddingKey{}).(bool)
+	return isQuery
+}
+
+// EmbeddingBatchStats describes the API calls made for one GenerateEmbeddings call
+type EmbeddingBatchStats struct {
+	Batches       int // API calls made
+	TotalTexts    int // Texts requested
+	FailedBatches int // API calls that returned an error
+}
+
+// embedInBatches splits texts into chunks of at most size and concatenates the
+// embeddings. It stops at the first failed batch.
+func embedInBatches(ctx context.Context, texts []string, size int, embed func(ctx context.Context, texts []string) ([][]float32, error)) ([][]float32, EmbeddingBatchStats, error) {
+	stats := EmbeddingBatchStats{TotalTexts: len(texts)}
+	if size <= 0 {
+		size = len(texts)
+	}
+
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += size {
+		end := min(start+size, len(texts))
+		stats.Batches++
+		batch, err := embed(ctx, texts[start:end])
+		if err != nil {
+			stats.FailedBatches++
+			return nil, stats, fmt.Errorf("failed to embed batch %d (texts %d-%d): %w", stats.Batches, start, end-1, err)
+		}
+		if len(batch) != end-start {
+			stats.FailedBatches++
+			return nil, stats, fmt.Errorf("batch %d returned %d embeddings for %d texts", stats.Batches, len(batch), end-start)
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, stats, nil
+}
+
 // VoyageEmbeddingClient implements EmbeddingProvider using Voyage AI
 type VoyageEmbeddingClient struct {
-	apiKey     string
-	model      string
-	httpClient *http.Client
+	// MaxBatchSize caps the texts sent in one API call. GenerateEmbeddings splits
+	// larger inputs into several calls.
+	MaxBatchSize int
+
+	credentials   *httpclient.Credentials // API key and HTTP client, replaceable while requests are in flight
+	model         string
+	customHeaders map[string]string // Sent with every request
 }
 
 // NewVoyageEmbeddingClient creates a new Voyage AI embedding client
@@ -23,17 +89,28 @@ func NewVoyageEmbeddingClient(apiKey, model string) *VoyageEmbeddingClient {
 		model = "voyage-3" // Default model
 	}
 	return &VoyageEmbeddingClient{
-		apiKey: apiKey,
-		model:  model,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+		MaxBatchSize: defaultVoyageBatchSize,
+		credentials: httpclient.NewCredentials(apiKey, &http.Client{
+			Timeout: defaultEmbeddingTimeout,
 			Transport: &http.Transport{
 				Proxy: http.ProxyFromEnvironment,
 			},
-		},
+		}),
+		model: model,
 	}
 }
 
+// RotateAPIKey replaces the API key used for new requests. Requests already sent
+// complete with the previous key.
+func (c *VoyageEmbeddingClient) RotateAPIKey(newKey string) error {
+	return c.credentials.RotateAPIKey(newKey)
+}
+
+// SetHTTPClient replaces the HTTP client used for new API calls
+func (c *VoyageEmbeddingClient) SetHTTPClient(client *http.Client) {
+	c.credentials.SetHTTPClient(client)
+}
+
 // GenerateEmbedding generates an embedding for a single text
 func (c *VoyageEmbeddingClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
 	embeddings, err := c.GenerateEmbeddings(ctx, []string{text})
@@ -46,8 +123,29 @@ func (c *VoyageEmbeddingClient) GenerateEmbedding(ctx context.Context, text stri
 	return embeddings[0], nil
 }
 
-// GenerateEmbeddings generates embeddings for multiple texts
+// GenerateEmbeddingsStream embeds texts as they arrive, sending MaxBatchSize of
+// them per API call (128 by default)
+func (c *VoyageEmbeddingClient) GenerateEmbeddingsStream(ctx context.Context, texts <-chan string) (<-chan EmbeddingResult, error) {
+	return streamEmbeddings(ctx, texts, c.MaxBatchSize, c.GenerateEmbeddings)
+}
+
+func (c *VoyageEmbeddingClient) streamBatchSize() int {
+	return c.MaxBatchSize
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts, in batches of MaxBatchSize
 func (c *VoyageEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, _, err := c.GenerateEmbeddingsWithStats(ctx, texts)
+	return embeddings, err
+}
+
+// GenerateEmbeddingsWithStats is GenerateEmbeddings that also reports the API calls made
+func (c *VoyageEmbeddingClient) GenerateEmbeddingsWithStats(ctx context.Context, texts []string) ([][]float32, EmbeddingBatchStats, error) {
+	return embedInBatches(ctx, texts, c.MaxBatchSize, c.embedBatch)
+}
+
+// embedBatch embeds texts with a single API call
+func (c *VoyageEmbeddingClient) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	reqBody := map[string]interface{}{
 		"input": texts,
 		"model": c.model,
@@ -64,9 +162,11 @@ func (c *VoyageEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Authorization", "Bearer "+c.credentials.APIKey())
+	req.Header.Set("User-Agent", httpclient.UserAgent())
+	httpclient.SetHeaders(req.Header, c.customHeaders)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.credentials.HTTPClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -97,9 +197,13 @@ func (c *VoyageEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []
 
 // OpenAIEmbeddingClient implements EmbeddingProvider using OpenAI
 type OpenAIEmbeddingClient struct {
-	apiKey     string
-	model      string
-	httpClient *http.Client
+	// MaxBatchSize caps the texts sent in one API call. GenerateEmbeddings splits
+	// larger inputs into several calls.
+	MaxBatchSize int
+
+	credentials   *httpclient.Credentials // API key and HTTP client, replaceable while requests are in flight
+	model         string
+	customHeaders map[string]string // Sent with every request
 }
 
 // NewOpenAIEmbeddingClient creates a new OpenAI embedding client
@@ -108,17 +212,28 @@ func NewOpenAIEmbeddingClient(apiKey, model string) *OpenAIEmbeddingClient {
 		model = "text-embedding-3-small" // Default model
 	}
 	return &OpenAIEmbeddingClient{
-		apiKey: apiKey,
-		model:  model,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+		MaxBatchSize: defaultOpenAIBatchSize,
+		credentials: httpclient.NewCredentials(apiKey, &http.Client{
+			Timeout: defaultEmbeddingTimeout,
 			Transport: &http.Transport{
 				Proxy: http.ProxyFromEnvironment,
 			},
-		},
+		}),
+		model: model,
 	}
 }
 
+// RotateAPIKey replaces the bearer token sent to OpenAI. Batches already sent
+// finish with the old key.
+func (c *OpenAIEmbeddingClient) RotateAPIKey(newKey string) error {
+	return c.credentials.RotateAPIKey(newKey)
+}
+
+// SetHTTPClient replaces the HTTP client used for new API calls
+func (c *OpenAIEmbeddingClient) SetHTTPClient(client *http.Client) {
+	c.credentials.SetHTTPClient(client)
+}
+
 // GenerateEmbedding generates an embedding for a single text
 func (c *OpenAIEmbeddingClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
 	embeddings, err := c.GenerateEmbeddings(ctx, []string{text})
@@ -131,8 +246,29 @@ func (c *OpenAIEmbeddingClient) GenerateEmbedding(ctx context.Context, text stri
 	return embeddings[0], nil
 }
 
-// GenerateEmbeddings generates embeddings for multiple texts
+// GenerateEmbeddingsStream embeds texts as they arrive, up to MaxBatchSize per
+// API call (100 by default)
+func (c *OpenAIEmbeddingClient) GenerateEmbeddingsStream(ctx context.Context, texts <-chan string) (<-chan EmbeddingResult, error) {
+	return streamEmbeddings(ctx, texts, c.MaxBatchSize, c.GenerateEmbeddings)
+}
+
+func (c *OpenAIEmbeddingClient) streamBatchSize() int {
+	return c.MaxBatchSize
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts, in batches of MaxBatchSize
 func (c *OpenAIEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, _, err := c.GenerateEmbeddingsWithStats(ctx, texts)
+	return embeddings, err
+}
+
+// GenerateEmbeddingsWithStats is GenerateEmbeddings that also reports the API calls made
+func (c *OpenAIEmbeddingClient) GenerateEmbeddingsWithStats(ctx context.Context, texts []string) ([][]float32, EmbeddingBatchStats, error) {
+	return embedInBatches(ctx, texts, c.MaxBatchSize, c.embedBatch)
+}
+
+// embedBatch embeds texts with a single API call
+func (c *OpenAIEmbeddingClient) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
 	reqBody := map[string]interface{}{
 		"input": texts,
 		"model": c.model,
@@ -149,9 +285,11 @@ func (c *OpenAIEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	req.Header.Set("Authorization", "Bearer "+c.credentials.APIKey())
+	req.Header.Set("User-Agent", httpclient.UserAgent())
+	httpclient.SetHeaders(req.Header, c.customHeaders)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.credentials.HTTPClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -180,14 +318,255 @@ func (c *OpenAIEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []
 	return embeddings, nil
 }
 
-// NewEmbeddingProvider creates an embedding provider based on the config
+// CohereEmbeddingClient implements EmbeddingProvider using Cohere.
+//
+// Cohere v3 models embed documents and queries differently: texts are sent with
+// input_type "search_document", or "search_query" when the context is marked
+// with WithQueryEmbedding. Documents and queries embedded with the wrong type
+// still compare, but with noticeably worse retrieval quality.
+type CohereEmbeddingClient struct {
+	// MaxBatchSize caps the texts sent in one API call. GenerateEmbeddings splits
+	// larger inputs into several calls.
+	MaxBatchSize int
+
+	credentials   *httpclient.Credentials // API key and HTTP client, replaceable while requests are in flight
+	model         string
+	customHeaders map[string]string // Sent with every request
+}
+
+// NewCohereEmbeddingClient creates a new Cohere embedding client
+func NewCohereEmbeddingClient(apiKey, model string) *CohereEmbeddingClient {
+	if model == "" {
+		model = "embed-english-v3.0" // Default model
+	}
+	return &CohereEmbeddingClient{
+		MaxBatchSize: defaultCohereBatchSize,
+		credentials: httpclient.NewCredentials(apiKey, &http.Client{
+			Timeout: defaultEmbeddingTimeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			},
+		}),
+		model: model,
+	}
+}
+
+// RotateAPIKey switches to newKey for subsequent embed calls; calls in progress
+// keep the key they were sent with.
+func (c *CohereEmbeddingClient) RotateAPIKey(newKey string) error {
+	return c.credentials.RotateAPIKey(newKey)
+}
+
+// SetHTTPClient replaces the HTTP client used for new API calls
+func (c *CohereEmbeddingClient) SetHTTPClient(client *http.Client) {
+	c.credentials.SetHTTPClient(client)
+}
+
+// GenerateEmbedding generates an embedding for a single text
+func (c *CohereEmbeddingClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := c.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embeddings returned")
+	}
+	return embeddings[0], nil
+}
+
+// GenerateEmbeddingsStream embeds texts as they arrive. Each API call carries at
+// most MaxBatchSize texts, 96 by default, the limit of Cohere's embed endpoint.
+func (c *CohereEmbeddingClient) GenerateEmbeddingsStream(ctx context.Context, texts <-chan string) (<-chan EmbeddingResult, error) {
+	return streamEmbeddings(ctx, texts, c.MaxBatchSize, c.GenerateEmbeddings)
+}
+
+func (c *CohereEmbeddingClient) streamBatchSize() int {
+	return c.MaxBatchSize
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts, in batches of MaxBatchSize
+func (c *CohereEmbeddingClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, _, err := c.GenerateEmbeddingsWithStats(ctx, texts)
+	return embeddings, err
+}
+
+// GenerateEmbeddingsWithStats is GenerateEmbeddings that also reports the API calls made
+func (c *CohereEmbeddingClient) GenerateEmbeddingsWithStats(ctx context.Context, texts []string) ([][]float32, EmbeddingBatchStats, error) {
+	return embedInBatches(ctx, texts, c.MaxBatchSize, c.embedBatch)
+}
+
+// embedBatch embeds texts with a single API call
+func (c *CohereEmbeddingClient) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	inputType := "search_document"
+	if IsQueryEmbedding(ctx) {
+		inputType = "search_query"
+	}
+	reqBody := map[string]interface{}{
+		"texts":      texts,
+		"model":      c.model,
+		"input_type": inputType,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.cohere.ai/v1/embed", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.credentials.APIKey())
+	req.Header.Set("User-Agent", httpclient.UserAgent())
+	httpclient.SetHeaders(req.Header, c.customHeaders)
+
+	resp, err := c.credentials.HTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Embeddings, nil
+}
+
+// NewEmbeddingProvider creates an embedding provider based on the config.
+// With FallbackEmbeddingProviders set, it returns a FailoverEmbeddingProvider that
+// uses the primary provider first and the fallbacks in order. The fallbacks must
+// produce embeddings of the primary's dimension: OpenAI's text-embedding-3-small
+// (1536) cannot fall back to Voyage AI's voyage-3 (1024), for example. When
+// EmbeddingDim or the model's known dimension differs it returns ErrDimensionMismatch.
 func NewEmbeddingProvider(config Config) (EmbeddingProvider, error) {
+	if len(config.FallbackEmbeddingProviders) == 0 {
+		return newEmbeddingProvider(config)
+	}
+
+	providers := make([]NamedEmbeddingProvider, 0, len(config.FallbackEmbeddingProviders)+1)
+	var primaryDim int
+	for i, c := range append([]Config{config}, config.FallbackEmbeddingProviders...) {
+		c.ProxyURL = config.ProxyURL
+		c.TLSInsecureSkipVerify = config.TLSInsecureSkipVerify
+		c.Timeout = config.Timeout
+		c.ConnectionPool = config.ConnectionPool
+		c.CustomHeaders = config.CustomHeaders
+		provider, err := newEmbeddingProvider(c)
+		if err != nil {
+			return nil, err
+		}
+		dim := embeddingDimensions(c, provider)
+		if i == 0 {
+			primaryDim = dim
+		} else if dim > 0 && primaryDim > 0 && dim != primaryDim {
+			return nil, fmt.Errorf("%w: fallback embedding provider %s produces %d dimensions, primary %s produces %d",
+				ErrDimensionMismatch, c.EmbeddingProvider, dim, config.EmbeddingProvider, primaryDim)
+		}
+		providers = append(providers, NamedEmbeddingProvider{Name: c.EmbeddingProvider, Provider: provider})
+	}
+	return NewFailoverEmbeddingProvider(providers...), nil
+}
+
+// embeddingDimensions returns config's EmbeddingDim, or the dimension of the
+// provider's model when it is one of the known models, or 0 when unknown
+func embeddingDimensions(config Config, provider EmbeddingProvider) int {
+	if config.EmbeddingDim > 0 {
+		return config.EmbeddingDim
+	}
+	var model string
+	switch client := provider.(type) {
+	case *VoyageEmbeddingClient:
+		model = client.model
+	case *OpenAIEmbeddingClient:
+		model = client.model
+	case *CohereEmbeddingClient:
+		model = client.model
+	}
+	for _, info := range defaultEmbeddingModels {
+		if info.Model == model {
+			return info.Dimensions
+		}
+	}
+	return 0
+}
+
+// addCustomHeaders merges headers into those provider sends. Providers other
+// than the built-in clients are left unchanged.
+func addCustomHeaders(provider EmbeddingProvider, headers map[string]string) {
+	switch p := provider.(type) {
+	case *VoyageEmbeddingClient:
+		p.customHeaders = mergeHeaders(p.customHeaders, headers)
+	case *OpenAIEmbeddingClient:
+		p.customHeaders = mergeHeaders(p.customHeaders, headers)
+	case *CohereEmbeddingClient:
+		p.customHeaders = mergeHeaders(p.customHeaders, headers)
+	case *FailoverEmbeddingProvider:
+		for _, named := range p.providers {
+			addCustomHeaders(named.Provider, headers)
+		}
+	}
+}
+
+// mergeHeaders returns a copy of dst with src added, overriding same-named headers
+func mergeHeaders(dst, src map[string]string) map[string]string {
+	merged := make(map[string]string, len(dst)+len(src))
+	maps.Copy(merged, dst)
+	maps.Copy(merged, src)
+	return merged
+}
+
+// newEmbeddingProvider creates the single provider named by config.EmbeddingProvider
+func newEmbeddingProvider(config Config) (EmbeddingProvider, error) {
+	if err := httpclient.ValidateHeaders(config.CustomHeaders); err != nil {
+		return nil, err
+	}
+	transport, err := httpclient.NewTransport(httpclient.Options{
+		ProxyURL:              config.ProxyURL,
+		TLSInsecureSkipVerify: config.TLSInsecureSkipVerify,
+
+		MaxIdleConns:        config.ConnectionPool.MaxIdle,
+		MaxIdleConnsPerHost: config.ConnectionPool.MaxIdlePerHost,
+		MaxConnsPerHost:     config.ConnectionPool.MaxConnsPerHost,
+		IdleConnTimeout:     config.ConnectionPool.IdleConnTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP transport: %w", err)
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultEmbeddingTimeout
+	}
+	httpClient := &http.Client{Timeout: timeout, Transport: transport}
+
 	switch config.EmbeddingProvider {
 	case "voyageai", "voyage":
-		return NewVoyageEmbeddingClient(config.APIKey, config.Model), nil
+		client := NewVoyageEmbeddingClient(config.APIKey, config.Model)
+		client.SetHTTPClient(httpClient)
+		client.customHeaders = maps.Clone(config.CustomHeaders)
+		return client, nil
 	case "openai":
-		return NewOpenAIEmbeddingClient(config.APIKey, config.Model), nil
+		client := NewOpenAIEmbeddingClient(config.APIKey, config.Model)
+		client.SetHTTPClient(httpClient)
+		client.customHeaders = maps.Clone(config.CustomHeaders)
+		return client, nil
+	case "cohere":
+		client := NewCohereEmbeddingClient(config.APIKey, config.Model)
+		client.SetHTTPClient(httpClient)
+		client.customHeaders = maps.Clone(config.CustomHeaders)
+		return client, nil
 	default:
-		return nil, fmt.Errorf("unsupported embedding provider: %s (supported: voyageai, openai)", config.EmbeddingProvider)
+		return nil, fmt.Errorf("unsupported embedding provider: %s (supported: voyageai, openai, cohere)", config.EmbeddingProvider)
 	}
 }
@@ -0,0 +1,36 @@
+//go:build integration
+
+package rag
+
+import (
+	"os"
+	"testing"
+)
+
+// TestEmbeddingProviders_Contract runs the provider contract against the real
+// APIs. Run with: go test -tags integration ./pkg/platformai/rag
+func TestEmbeddingProviders_Contract(t *testing.T) {
+	providers := []struct {
+		name  string
+		env   string
+		model string
+	}{
+		{"openai", "OPENAI_API_KEY", "text-embedding-3-small"},
+		{"voyageai", "VOYAGE_API_KEY", "voyage-3"},
+		{"cohere", "COHERE_API_KEY", "embed-english-v3.0"},
+	}
+
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			apiKey := os.Getenv(p.env)
+			if apiKey == "" {
+				t.Skipf("%s is not set", p.env)
+			}
+			provider, err := NewEmbeddingProvider(Config{EmbeddingProvider: p.name, APIKey: apiKey, Model: p.model})
+			if err != nil {
+				t.Fatalf("NewEmbeddingProvider() error = %v", err)
+			}
+			testEmbeddingProviderContract(t, provider)
+		})
+	}
+}
@@ -0,0 +1,412 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
+)
+
+func TestNewEmbeddingProvider_ProxyURL(t *testing.T) {
+	connects := make(chan string, 2)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connects <- r.Method + " " + r.Host
+		// Refuse the tunnel; the test only checks that the request was routed here
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer proxy.Close()
+
+	for _, name := range []string{"openai", "voyageai"} {
+		t.Run(name, func(t *testing.T) {
+			provider, err := NewEmbeddingProvider(Config{EmbeddingProvider: name, APIKey: "test", ProxyURL: proxy.URL})
+			if err != nil {
+				t.Fatalf("NewEmbeddingProvider() error = %v", err)
+			}
+			if _, err := provider.GenerateEmbedding(context.Background(), "hello"); err == nil {
+				t.Fatal("GenerateEmbedding() expected error from refusing proxy")
+			}
+
+			select {
+			case got := <-connects:
+				if got[:len("CONNECT ")] != "CONNECT " {
+					t.Errorf("proxy received %q, want CONNECT tunnel", got)
+				}
+			default:
+				t.Error("request did not go through the proxy")
+			}
+		})
+	}
+
+	if _, err := NewEmbeddingProvider(Config{EmbeddingProvider: "openai", ProxyURL: "no-scheme"}); err == nil {
+		t.Error("NewEmbeddingProvider() expected error for invalid proxy URL")
+	}
+}
+
+func TestModule_RotateEmbeddingKey(t *testing.T) {
+	voyage := NewVoyageEmbeddingClient("old-key", "")
+	m := &Module{embedder: NewFailoverEmbeddingProvider(NamedEmbeddingProvider{Name: "voyageai", Provider: voyage})}
+
+	if err := m.RotateEmbeddingKey("new-key"); err != nil {
+		t.Fatalf("RotateEmbeddingKey() error = %v", err)
+	}
+	if got := voyage.credentials.APIKey(); got != "new-key" {
+		t.Errorf("API key after rotation = %q, want new-key", got)
+	}
+	if err := m.RotateEmbeddingKey(""); err == nil {
+		t.Error("RotateEmbeddingKey() expected error for empty key")
+	}
+
+	m = &Module{embedder: NewMockEmbeddingProvider(8)}
+	if err := m.RotateEmbeddingKey("new-key"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("RotateEmbeddingKey() on mock provider error = %v, want ErrUnsupported", err)
+	}
+}
+
+// redirectTransport sends every request to a test server instead of the real API
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newEmbeddingServer returns a server that embeds each input text as [index], and
+// counts the calls it receives
+func newEmbeddingServer(t *testing.T, calls *atomic.Int32) *http.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data := make([]map[string][]float32, len(req.Input))
+		for i := range req.Input {
+			data[i] = map[string][]float32{"embedding": {float32(i)}}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	target, _ := url.Parse(server.URL)
+	return &http.Client{Transport: redirectTransport{target: target}}
+}
+
+func TestEmbeddingClients_MaxBatchSize(t *testing.T) {
+	texts := make([]string, 250)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+
+	voyage := NewVoyageEmbeddingClient("test", "")
+	openai := NewOpenAIEmbeddingClient("test", "")
+	if voyage.MaxBatchSize != 128 || openai.MaxBatchSize != 100 {
+		t.Errorf("default MaxBatchSize = %d (voyage), %d (openai), want 128, 100", voyage.MaxBatchSize, openai.MaxBatchSize)
+	}
+
+	clients := map[string]interface {
+		SetHTTPClient(*http.Client)
+		GenerateEmbeddingsWithStats(context.Context, []string) ([][]float32, EmbeddingBatchStats, error)
+	}{"voyageai": voyage, "openai": openai}
+	voyage.MaxBatchSize = 50
+	openai.MaxBatchSize = 50
+
+	for name, client := range clients {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			client.SetHTTPClient(newEmbeddingServer(t, &calls))
+
+			embeddings, stats, err := client.GenerateEmbeddingsWithStats(context.Background(), texts)
+			if err != nil {
+				t.Fatalf("GenerateEmbeddingsWithStats() error = %v", err)
+			}
+			if got := calls.Load(); got != 5 {
+				t.Errorf("API calls = %d, want 5", got)
+			}
+			if want := (EmbeddingBatchStats{Batches: 5, TotalTexts: 250}); stats != want {
+				t.Errorf("stats = %+v, want %+v", stats, want)
+			}
+			if len(embeddings) != 250 {
+				t.Fatalf("len(embeddings) = %d, want 250", len(embeddings))
+			}
+			// The server embeds by position within the batch, so text 120 is the 21st of batch 3
+			if got := embeddings[120][0]; got != 20 {
+				t.Errorf("embeddings[120] = %v, want [20]", embeddings[120])
+			}
+		})
+	}
+}
+
+func TestEmbedInBatches_Failure(t *testing.T) {
+	calls := 0
+	_, stats, err := embedInBatches(context.Background(), make([]string, 10), 4, func(ctx context.Context, texts []string) ([][]float32, error) {
+		calls++
+		if calls == 2 {
+			return nil, errors.New("rate limited")
+		}
+		return make([][]float32, len(texts)), nil
+	})
+	if err == nil {
+		t.Fatal("embedInBatches() expected error")
+	}
+	if want := (EmbeddingBatchStats{Batches: 2, TotalTexts: 10, FailedBatches: 1}); stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestCohereEmbeddingClient_InputType(t *testing.T) {
+	inputTypes := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Texts     []string `json:"texts"`
+			Model     string   `json:"model"`
+			InputType string   `json:"input_type"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/v1/embed" || req.Model != "embed-english-v3.0" {
+			http.Error(w, "unexpected request "+r.URL.Path+" "+req.Model, http.StatusBadRequest)
+			return
+		}
+		inputTypes <- req.InputType
+		embeddings := make([][]float32, len(req.Texts))
+		for i := range embeddings {
+			embeddings[i] = []float32{1, 0}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	provider, err := NewEmbeddingProvider(Config{EmbeddingProvider: "cohere", APIKey: "test"})
+	if err != nil {
+		t.Fatalf("NewEmbeddingProvider() error = %v", err)
+	}
+	client := provider.(*CohereEmbeddingClient)
+	client.SetHTTPClient(&http.Client{Transport: redirectTransport{target: target}})
+
+	ctx := context.Background()
+	retriever := NewRetriever(client, NewInMemoryVectorStore())
+	if err := retriever.AddDocument(ctx, "doc", "deployment guide", nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+	if got := <-inputTypes; got != "search_document" {
+		t.Errorf("document input_type = %q, want search_document", got)
+	}
+
+	if _, err := retriever.Retrieve(ctx, RetrieveRequest{Query: "how to deploy"}); err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if got := <-inputTypes; got != "search_query" {
+		t.Errorf("query input_type = %q, want search_query", got)
+	}
+}
+
+func TestNewEmbeddingProvider_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	provider, err := NewEmbeddingProvider(Config{EmbeddingProvider: "openai", APIKey: "test", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewEmbeddingProvider() error = %v", err)
+	}
+	client := provider.(*OpenAIEmbeddingClient)
+	httpClient := client.credentials.HTTPClient()
+	if httpClient.Timeout != time.Second {
+		t.Fatalf("HTTP timeout = %v, want 1s", httpClient.Timeout)
+	}
+	httpClient.Transport = redirectTransport{target: target}
+
+	_, err = client.GenerateEmbedding(context.Background(), "hello")
+	var netErr interface{ Timeout() bool }
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("GenerateEmbedding() error = %v, want a timeout", err)
+	}
+}
+
+func TestEmbeddingClients_UserAgent(t *testing.T) {
+	agents := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agents <- r.Header.Get("User-Agent")
+		json.NewEncoder(w).Encode(map[string]any{"data": []map[string][]float32{{"embedding": {1}}}})
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	want := fmt.Sprintf("innominatus-ai-sdk/%s (go/%s; %s/%s)", httpclient.SDKVersion, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	for _, name := range []string{"openai", "voyageai"} {
+		t.Run(name, func(t *testing.T) {
+			provider, err := NewEmbeddingProvider(Config{EmbeddingProvider: name, APIKey: "test"})
+			if err != nil {
+				t.Fatalf("NewEmbeddingProvider() error = %v", err)
+			}
+			provider.(interface{ SetHTTPClient(*http.Client) }).SetHTTPClient(&http.Client{Transport: redirectTransport{target: target}})
+			if _, err := provider.GenerateEmbedding(context.Background(), "hello"); err != nil {
+				t.Fatalf("GenerateEmbedding() error = %v", err)
+			}
+			if got := <-agents; got != want {
+				t.Errorf("User-Agent = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestModule_CustomHeaders(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header
+		json.NewEncoder(w).Encode(map[string]any{"data": []map[string][]float32{{"embedding": {1}}}})
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	config := Config{EmbeddingProvider: "openai", APIKey: "test", CustomHeaders: map[string]string{"X-Team": "platform"}}
+	m, err := NewModule(config, WithCustomHeaders(map[string]string{"X-Request-Source": "audit"}))
+	if err != nil {
+		t.Fatalf("NewModule() error = %v", err)
+	}
+	m.embedder.(*OpenAIEmbeddingClient).SetHTTPClient(&http.Client{Transport: redirectTransport{target: target}})
+	if _, err := m.embedder.GenerateEmbedding(context.Background(), "hello"); err != nil {
+		t.Fatalf("GenerateEmbedding() error = %v", err)
+	}
+
+	got := <-headers
+	if got.Get("X-Team") != "platform" || got.Get("X-Request-Source") != "audit" {
+		t.Errorf("request headers = %v, want X-Team and X-Request-Source", got)
+	}
+	if got.Get("Authorization") != "Bearer test" {
+		t.Errorf("Authorization = %q, want the API key", got.Get("Authorization"))
+	}
+}
+
+func TestModule_CustomHeadersRejectsCredentials(t *testing.T) {
+	config := Config{EmbeddingProvider: "openai", APIKey: "test"}
+	if _, err := NewModule(config, WithCustomHeaders(map[string]string{"authorization": "Bearer other"})); err == nil {
+		t.Error("NewModule() with an authorization custom header succeeded, want error")
+	}
+
+	config.CustomHeaders = map[string]string{"X-API-Key": "other"}
+	if _, err := NewEmbeddingProvider(config); err == nil {
+		t.Error("NewEmbeddingProvider() with an x-api-key custom header succeeded, want error")
+	}
+}
+
+// testEmbeddingProviderContract checks the behavior every EmbeddingProvider
+// must have: non-empty embeddings, one embedding per input, an empty result
+// for no inputs, and the context error once ctx is canceled
+func testEmbeddingProviderContract(t *testing.T, provider EmbeddingProvider) {
+	t.Helper()
+	ctx := context.Background()
+
+	embedding, err := provider.GenerateEmbedding(ctx, "deploy the payments service")
+	if err != nil {
+		t.Fatalf("GenerateEmbedding() error = %v", err)
+	}
+	if len(embedding) == 0 {
+		t.Error("GenerateEmbedding() returned an empty embedding")
+	}
+
+	empty, err := provider.GenerateEmbeddings(ctx, []string{})
+	if err != nil {
+		t.Errorf("GenerateEmbeddings() with no texts error = %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("GenerateEmbeddings() with no texts = %d embeddings, want 0", len(empty))
+	}
+
+	inputs := []string{"first", "second", "third"}
+	embeddings, err := provider.GenerateEmbeddings(ctx, inputs)
+	if err != nil {
+		t.Fatalf("GenerateEmbeddings() error = %v", err)
+	}
+	if len(embeddings) != len(inputs) {
+		t.Errorf("GenerateEmbeddings() = %d embeddings, want %d", len(embeddings), len(inputs))
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := provider.GenerateEmbeddings(canceled, inputs); !errors.Is(err, canceled.Err()) {
+		t.Errorf("GenerateEmbeddings() with a canceled context error = %v, want %v", err, canceled.Err())
+	}
+}
+
+func TestMockEmbeddingProvider_Contract(t *testing.T) {
+	testEmbeddingProviderContract(t, NewMockEmbeddingProvider(8))
+}
+
+func TestOpenAIEmbeddingClient_Contract(t *testing.T) {
+	var calls atomic.Int32
+	client := NewOpenAIEmbeddingClient("test", "")
+	client.SetHTTPClient(newEmbeddingServer(t, &calls))
+	testEmbeddingProviderContract(t, client)
+}
+
+func TestCohereEmbeddingClient_StreamBatches(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Texts []string `json:"texts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		sizes = append(sizes, len(req.Texts))
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": make([][]float32, len(req.Texts))})
+	}))
+	defer server.Close()
+	target, _ := url.Parse(server.URL)
+
+	client := NewCohereEmbeddingClient("test", "embed-english-v3.0")
+	client.SetHTTPClient(&http.Client{Transport: redirectTransport{target: target}})
+
+	texts := make(chan string)
+	results, err := client.GenerateEmbeddingsStream(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("GenerateEmbeddingsStream() error = %v", err)
+	}
+	go func() {
+		defer close(texts)
+		for i := 0; i < 200; i++ {
+			texts <- fmt.Sprintf("text %d", i)
+		}
+	}()
+	for result := range results {
+		if result.Err != nil {
+			t.Fatalf("result error = %v", result.Err)
+		}
+	}
+
+	// Stream batches follow Cohere's 96-text limit rather than being split again
+	if want := []int{96, 96, 8}; !slices.Equal(sizes, want) {
+		t.Errorf("API call sizes = %v, want %v", sizes, want)
+	}
+}
@@ -0,0 +1,108 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// Entity types reported by EntityExtractor
+const (
+	EntityPerson  = "PERSON"
+	EntityOrg     = "ORG"
+	EntityTech    = "TECH"
+	EntityProduct = "PRODUCT"
+)
+
+// entityMetadataPrefix starts the metadata keys extracted entities are stored under, e.g. "entities_TECH"
+const entityMetadataPrefix = "entities_"
+
+// Entity is a named entity found in a document
+type Entity struct {
+	Text string `json:"text"`
+	Type string `json:"type"` // One of the Entity* constants
+}
+
+// EntityExtractor finds named entities in text
+type EntityExtractor interface {
+	Extract(ctx context.Context, text string) ([]Entity, error)
+}
+
+// LLMEntityExtractor extracts entities by asking the LLM
+type LLMEntityExtractor struct {
+	llm llm.Client
+}
+
+// NewLLMEntityExtractor creates a new LLM entity extractor
+func NewLLMEntityExtractor(llmClient llm.Client) *LLMEntityExtractor {
+	return &LLMEntityExtractor{llm: llmClient}
+}
+
+// Extract returns the people, organizations, technologies, and products named in text
+func (e *LLMEntityExtractor) Extract(ctx context.Context, text string) ([]Entity, error) {
+	systemPrompt := `Extract named entities from the text.
+
+Types: PERSON (people), ORG (companies, teams), TECH (languages, frameworks, tools), PRODUCT (products, services).
+
+Respond with ONLY valid JSON:
+[{"text": "...", "type": "PERSON|ORG|TECH|PRODUCT"}]`
+
+	resp, err := e.llm.Generate(ctx, llm.GenerateRequest{
+		SystemPrompt: systemPrompt,
+		UserPrompt:   text,
+		MaxTokens:    1024,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract entities: %w", err)
+	}
+
+	var entities []Entity
+	if err := json.Unmarshal([]byte(resp.Text), &entities); err != nil {
+		return nil, fmt.Errorf("failed to parse entities: %w (response: %s)", err, resp.Text)
+	}
+	return entities, nil
+}
+
+// WithEntityExtraction extracts entities from documents as they are added and
+// stores them in the document metadata under "entities_<TYPE>", comma-separated
+func WithEntityExtraction(ex EntityExtractor) Option {
+	return func(m *Module) {
+		m.entities = ex
+	}
+}
+
+// withEntities returns metadata extended with the entities extracted from content.
+// The input map is not modified.
+func (m *Module) withEntities(ctx context.Context, content string, metadata map[string]string) (map[string]string, error) {
+	if m.entities == nil {
+		return metadata, nil
+	}
+	entities, err := m.entities.Extract(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(entities) == 0 {
+		return metadata, nil
+	}
+
+	byType := make(map[string][]string)
+	for _, entity := range entities {
+		key := entityMetadataPrefix + strings.ToUpper(entity.Type)
+		if entity.Text != "" && !slices.Contains(byType[key], entity.Text) {
+			byType[key] = append(byType[key], entity.Text)
+		}
+	}
+
+	merged := make(map[string]string, len(metadata)+len(byType))
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	for key, texts := range byType {
+		merged[key] = strings.Join(texts, ",")
+	}
+	return merged, nil
+}
@@ -0,0 +1,48 @@
+package rag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+func TestModule_AddDocumentWithEntityExtraction(t *testing.T) {
+	mock := llm.NewMockClient(`[{"text": "Kubernetes", "type": "TECH"}, {"text": "Acme Corp", "type": "ORG"}]`)
+	module := newTestModule(WithEntityExtraction(NewLLMEntityExtractor(mock)))
+
+	ctx := context.Background()
+	metadata := map[string]string{"source": "wiki"}
+	if err := module.AddDocument(ctx, "doc", "Acme Corp runs everything on Kubernetes.", metadata); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+
+	doc, err := module.GetDocument(ctx, "doc")
+	if err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	want := map[string]string{"source": "wiki", "entities_TECH": "Kubernetes", "entities_ORG": "Acme Corp"}
+	for k, v := range want {
+		if doc.Metadata[k] != v {
+			t.Errorf("Metadata[%q] = %q, want %q", k, doc.Metadata[k], v)
+		}
+	}
+	if len(metadata) != 1 {
+		t.Errorf("AddDocument() modified the caller's metadata: %v", metadata)
+	}
+
+	matches, err := module.SearchByMetadata(ctx, map[string]string{"entities_TECH": "Kubernetes"})
+	if err != nil {
+		t.Fatalf("SearchByMetadata() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Errorf("SearchByMetadata() returned %d documents, want 1", len(matches))
+	}
+}
+
+func TestLLMEntityExtractor_InvalidJSON(t *testing.T) {
+	extractor := NewLLMEntityExtractor(llm.NewMockClient("not json"))
+	if _, err := extractor.Extract(context.Background(), "text"); err == nil {
+		t.Error("Extract() expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,46 @@
+package rag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// Error codes of the RAG module's sentinel errors
+const (
+	ErrCodeDocumentNotFound  = "document_not_found"
+	ErrCodeDimensionMismatch = "dimension_mismatch"
+	ErrCodeNoPath            = "no_path"
+)
+
+// Common error types for the RAG module
+var (
+	// ErrDocumentNotFound indicates that no document exists with the requested ID
+	ErrDocumentNotFound = llm.NewSDKError(ErrCodeDocumentNotFound, "document not found")
+
+	// ErrDimensionMismatch indicates an embedding whose length differs from the store's dimension
+	ErrDimensionMismatch = llm.NewSDKError(ErrCodeDimensionMismatch, "embedding dimension mismatch")
+
+	// ErrNoPath indicates that the document graph has no path between two documents
+	ErrNoPath = llm.NewSDKError(ErrCodeNoPath, "no path between documents")
+)
+
+// MultiError collects the independent failures of a batch operation
+type MultiError struct {
+	Errors []error
+}
+
+// Error implements error
+func (e *MultiError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors occurred: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the collected errors for errors.Is and errors.As
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
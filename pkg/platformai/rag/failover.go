@@ -0,0 +1,160 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Back-off applied to a provider after consecutive failures, doubling up to the maximum
+const (
+	failoverBaseBackoff = time.Second
+	failoverMaxBackoff  = 5 * time.Minute
+)
+
+// NamedEmbeddingProvider pairs an embedding provider with the name reported by ActiveProvider
+type NamedEmbeddingProvider struct {
+	Name     string
+	Provider EmbeddingProvider
+}
+
+// FailoverEmbeddingProvider tries embedding providers in order, moving to the next
+// one on error. A failing provider is skipped for a back-off period that doubles with
+// each consecutive failure, after which it is tried again. All providers must produce
+// embeddings of the same dimension; vectors from a provider with a different dimension
+// fail with ErrDimensionMismatch when stored or searched.
+type FailoverEmbeddingProvider struct {
+	providers []NamedEmbeddingProvider
+	now       func() time.Time
+
+	mu       sync.Mutex
+	active   int
+	failures []int
+	retryAt  []time.Time
+}
+
+// NewFailoverEmbeddingProvider creates a failover provider; the first provider is the primary
+func NewFailoverEmbeddingProvider(providers ...NamedEmbeddingProvider) *FailoverEmbeddingProvider {
+	return &FailoverEmbeddingProvider{
+		providers: providers,
+		now:       time.Now,
+		failures:  make([]int, len(providers)),
+		retryAt:   make([]time.Time, len(providers)),
+	}
+}
+
+// ActiveProvider returns the name of the provider that served the last successful request,
+// or the primary before any request
+func (p *FailoverEmbeddingProvider) ActiveProvider() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.providers) == 0 {
+		return ""
+	}
+	return p.providers[p.active].Name
+}
+
+// RotateAPIKey replaces the API key of the primary provider
+func (p *FailoverEmbeddingProvider) RotateAPIKey(newKey string) error {
+	if len(p.providers) == 0 {
+		return fmt.Errorf("no embedding providers configured")
+	}
+	rotator, ok := p.providers[0].Provider.(KeyRotator)
+	if !ok {
+		return fmt.Errorf("%w: %s provider does not support key rotation", errors.ErrUnsupported, p.providers[0].Name)
+	}
+	return rotator.RotateAPIKey(newKey)
+}
+
+// SetHTTPClient replaces the HTTP client of every provider that supports it
+func (p *FailoverEmbeddingProvider) SetHTTPClient(client *http.Client) {
+	for _, provider := range p.providers {
+		if setter, ok := provider.Provider.(interface{ SetHTTPClient(*http.Client) }); ok {
+			setter.SetHTTPClient(client)
+		}
+	}
+}
+
+// GenerateEmbedding generates an embedding for a single text
+func (p *FailoverEmbeddingProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := p.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) == 0 {
+		return nil, fmt.Errorf("no embeddings returned")
+	}
+	return embeddings[0], nil
+}
+
+// GenerateEmbeddings generates embeddings with the first available provider that succeeds
+func (p *FailoverEmbeddingProvider) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(p.providers) == 0 {
+		return nil, fmt.Errorf("no embedding providers configured")
+	}
+
+	var errs []error
+	for _, i := range p.candidates() {
+		embeddings, err := p.providers[i].Provider.GenerateEmbeddings(ctx, texts)
+		if err == nil {
+			p.recordSuccess(i)
+			return embeddings, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", p.providers[i].Name, err))
+		// The caller giving up says nothing about the provider's health
+		if ctx.Err() != nil {
+			break
+		}
+		p.recordFailure(i)
+	}
+	return nil, fmt.Errorf("all embedding providers failed: %w", errors.Join(errs...))
+}
+
+// GenerateEmbeddingsStream groups texts into runs of 100 as they arrive and hands
+// each run to the first available provider that succeeds, which may split it
+// further by its own batch size
+func (p *FailoverEmbeddingProvider) GenerateEmbeddingsStream(ctx context.Context, texts <-chan string) (<-chan EmbeddingResult, error) {
+	return streamEmbeddings(ctx, texts, embeddingStreamBatchSize, p.GenerateEmbeddings)
+}
+
+// candidates returns provider indexes to try in order: those not backing off first,
+// then those still backing off so a request is never refused outright
+func (p *FailoverEmbeddingProvider) candidates() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	ready := make([]int, 0, len(p.providers))
+	var waiting []int
+	for i := range p.providers {
+		if now.Before(p.retryAt[i]) {
+			waiting = append(waiting, i)
+		} else {
+			ready = append(ready, i)
+		}
+	}
+	return append(ready, waiting...)
+}
+
+func (p *FailoverEmbeddingProvider) recordSuccess(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.active = i
+	p.failures[i] = 0
+	p.retryAt[i] = time.Time{}
+}
+
+func (p *FailoverEmbeddingProvider) recordFailure(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failures[i]++
+	backoff := failoverBaseBackoff << min(p.failures[i]-1, 20)
+	p.retryAt[i] = p.now().Add(min(backoff, failoverMaxBackoff))
+}
@@ -0,0 +1,179 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flakyEmbeddingProvider fails while down is set and counts calls
+type flakyEmbeddingProvider struct {
+	*MockEmbeddingProvider
+	down  bool
+	calls int
+}
+
+func (p *flakyEmbeddingProvider) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	p.calls++
+	if p.down {
+		return nil, errors.New("service unavailable")
+	}
+	return p.MockEmbeddingProvider.GenerateEmbeddings(ctx, texts)
+}
+
+func TestFailoverEmbeddingProvider(t *testing.T) {
+	ctx := context.Background()
+	primary := &flakyEmbeddingProvider{MockEmbeddingProvider: NewMockEmbeddingProvider(8), down: true}
+	fallback := &flakyEmbeddingProvider{MockEmbeddingProvider: NewMockEmbeddingProvider(8)}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := NewFailoverEmbeddingProvider(
+		NamedEmbeddingProvider{Name: "openai", Provider: primary},
+		NamedEmbeddingProvider{Name: "voyageai", Provider: fallback},
+	)
+	provider.now = func() time.Time { return now }
+
+	if got := provider.ActiveProvider(); got != "openai" {
+		t.Errorf("ActiveProvider() initially = %q, want openai", got)
+	}
+
+	if _, err := provider.GenerateEmbedding(ctx, "hello"); err != nil {
+		t.Fatalf("GenerateEmbedding() error = %v", err)
+	}
+	if got := provider.ActiveProvider(); got != "voyageai" {
+		t.Errorf("ActiveProvider() after primary failure = %q, want voyageai", got)
+	}
+
+	// The primary is backing off, so the next call goes straight to the fallback
+	if _, err := provider.GenerateEmbedding(ctx, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if primary.calls != 1 || fallback.calls != 2 {
+		t.Errorf("calls during back-off: primary %d, fallback %d; want 1, 2", primary.calls, fallback.calls)
+	}
+
+	// After the back-off the primary is retried and becomes active once it recovers
+	primary.down = false
+	now = now.Add(failoverBaseBackoff)
+	if _, err := provider.GenerateEmbedding(ctx, "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if got := provider.ActiveProvider(); got != "openai" || primary.calls != 2 {
+		t.Errorf("after back-off ActiveProvider() = %q with %d primary calls, want openai with 2", got, primary.calls)
+	}
+
+	primary.down, fallback.down = true, true
+	if _, err := provider.GenerateEmbedding(ctx, "hello"); err == nil {
+		t.Error("GenerateEmbedding() expected error when every provider fails")
+	}
+}
+
+func TestFailoverEmbeddingProvider_BackoffDoubles(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	provider := NewFailoverEmbeddingProvider(NamedEmbeddingProvider{Name: "a", Provider: NewMockEmbeddingProvider(8)})
+	provider.now = func() time.Time { return now }
+
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		provider.recordFailure(0)
+		if got := provider.retryAt[0].Sub(now); got != want {
+			t.Errorf("failure %d back-off = %v, want %v", i+1, got, want)
+		}
+	}
+	for i := 0; i < 30; i++ {
+		provider.recordFailure(0)
+	}
+	if got := provider.retryAt[0].Sub(now); got != failoverMaxBackoff {
+		t.Errorf("back-off = %v, want capped at %v", got, failoverMaxBackoff)
+	}
+}
+
+func TestFailoverEmbeddingProvider_CallerCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	primary := &flakyEmbeddingProvider{MockEmbeddingProvider: NewMockEmbeddingProvider(8), down: true}
+	fallback := &flakyEmbeddingProvider{MockEmbeddingProvider: NewMockEmbeddingProvider(8)}
+	provider := NewFailoverEmbeddingProvider(
+		NamedEmbeddingProvider{Name: "openai", Provider: primary},
+		NamedEmbeddingProvider{Name: "voyageai", Provider: fallback},
+	)
+
+	if _, err := provider.GenerateEmbedding(ctx, "hello"); err == nil {
+		t.Fatal("GenerateEmbedding() expected error")
+	}
+	if fallback.calls != 0 {
+		t.Errorf("fallback calls = %d, want 0 after the caller canceled", fallback.calls)
+	}
+	if !provider.retryAt[0].IsZero() || provider.failures[0] != 0 {
+		t.Errorf("primary backing off until %v after %d failures, want no back-off", provider.retryAt[0], provider.failures[0])
+	}
+}
+
+func TestNewEmbeddingProvider_Fallbacks(t *testing.T) {
+	provider, err := NewEmbeddingProvider(Config{
+		EmbeddingProvider:          "openai",
+		APIKey:                     "test",
+		FallbackEmbeddingProviders: []Config{{EmbeddingProvider: "openai", APIKey: "backup", Model: "text-embedding-3-small"}},
+	})
+	if err != nil {
+		t.Fatalf("NewEmbeddingProvider() error = %v", err)
+	}
+	failover, ok := provider.(*FailoverEmbeddingProvider)
+	if !ok {
+		t.Fatalf("NewEmbeddingProvider() = %T, want *FailoverEmbeddingProvider", provider)
+	}
+	if got := failover.ActiveProvider(); got != "openai" {
+		t.Errorf("ActiveProvider() = %q, want openai", got)
+	}
+
+	if _, err := NewEmbeddingProvider(Config{
+		EmbeddingProvider:          "openai",
+		FallbackEmbeddingProviders: []Config{{EmbeddingProvider: "unknown"}},
+	}); err == nil {
+		t.Error("NewEmbeddingProvider() expected error for unknown fallback provider")
+	}
+}
+
+func TestNewEmbeddingProvider_FallbackDimensions(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name: "openai to voyageai",
+			config: Config{
+				EmbeddingProvider:          "openai",
+				FallbackEmbeddingProviders: []Config{{EmbeddingProvider: "voyageai"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "same dimension",
+			config: Config{
+				EmbeddingProvider:          "voyageai",
+				Model:                      "voyage-3-lite",
+				FallbackEmbeddingProviders: []Config{{EmbeddingProvider: "openai", EmbeddingDim: 512}},
+			},
+		},
+		{
+			name: "unknown model",
+			config: Config{
+				EmbeddingProvider:          "voyageai",
+				FallbackEmbeddingProviders: []Config{{EmbeddingProvider: "cohere"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEmbeddingProvider(tt.config)
+			if tt.wantErr != errors.Is(err, ErrDimensionMismatch) {
+				t.Errorf("NewEmbeddingProvider() error = %v, want dimension mismatch %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("NewEmbeddingProvider() error = %v", err)
+			}
+		})
+	}
+}
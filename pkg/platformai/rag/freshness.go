@@ -0,0 +1,79 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MetadataLastUpdated is the metadata key holding a document's last update time
+// (RFC 3339 or YYYY-MM-DD)
+const MetadataLastUpdated = "last_updated"
+
+// FreshnessConfig penalizes search scores of documents by age
+type FreshnessConfig struct {
+	MaxAge          time.Duration `yaml:"max_age"`           // Age at which the full penalty applies; 0 disables freshness scoring
+	AgeScorePenalty float32       `yaml:"age_score_penalty"` // Fraction of the score removed at MaxAge (0-1)
+}
+
+// adjust scales score by 1 - penalty * clamp(age/MaxAge, 0, 1). Documents without
+// a parseable last_updated timestamp keep their score.
+func (f FreshnessConfig) adjust(score float32, doc Document, now time.Time) float32 {
+	if f.MaxAge <= 0 || f.AgeScorePenalty == 0 {
+		return score
+	}
+	age, ok := documentAge(doc, now)
+	if !ok {
+		return score
+	}
+
+	ratio := min(max(float32(age)/float32(f.MaxAge), 0), 1)
+	return score * (1 - f.AgeScorePenalty*ratio)
+}
+
+// documentAge returns how long ago the document was last updated
+func documentAge(doc Document, now time.Time) (time.Duration, bool) {
+	value := doc.Metadata[MetadataLastUpdated]
+	if value == "" {
+		return 0, false
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if updated, err := time.Parse(layout, value); err == nil {
+			return now.Sub(updated), true
+		}
+	}
+	return 0, false
+}
+
+// FindStaleDocuments returns documents whose last_updated timestamp is older than maxAge
+func (m *Module) FindStaleDocuments(ctx context.Context, maxAge time.Duration) ([]Document, error) {
+	docs, err := m.store.List(ctx, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	now := time.Now()
+	stale := make([]Document, 0)
+	for _, doc := range docs {
+		if age, ok := documentAge(doc, now); ok && age > maxAge {
+			stale = append(stale, doc)
+		}
+	}
+	return stale, nil
+}
+
+// RefreshDocument replaces a document's content, re-embeds it, and sets last_updated to now
+func (m *Module) RefreshDocument(ctx context.Context, id, newContent string) error {
+	doc, err := m.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]string, len(doc.Metadata)+1)
+	for k, v := range doc.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataLastUpdated] = time.Now().UTC().Format(time.RFC3339)
+
+	return m.UpdateDocument(ctx, id, newContent, metadata)
+}
@@ -0,0 +1,83 @@
+package rag
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFreshnessConfig_Adjust(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	config := FreshnessConfig{MaxAge: 100 * 24 * time.Hour, AgeScorePenalty: 0.5}
+
+	tests := []struct {
+		name        string
+		lastUpdated string
+		want        float32
+	}{
+		{"no timestamp", "", 0.8},
+		{"unparseable", "last tuesday", 0.8},
+		{"fresh", now.Format(time.RFC3339), 0.8},
+		{"half max age", now.Add(-50 * 24 * time.Hour).Format(time.DateOnly), 0.6},
+		{"older than max age", "2020-01-01", 0.4},
+		{"future", now.Add(time.Hour).Format(time.RFC3339), 0.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := Document{Metadata: map[string]string{MetadataLastUpdated: tt.lastUpdated}}
+			if got := config.adjust(0.8, doc, now); math.Abs(float64(got-tt.want)) > 1e-6 {
+				t.Errorf("adjust() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := (FreshnessConfig{}).adjust(0.8, Document{Metadata: map[string]string{MetadataLastUpdated: "2020-01-01"}}, now); got != 0.8 {
+		t.Errorf("adjust() with zero config = %v, want 0.8", got)
+	}
+}
+
+func TestModule_FreshnessRanking(t *testing.T) {
+	ctx := context.Background()
+	module := newTestModule()
+	module.store = NewInMemoryVectorStoreWithConfig(InMemoryStoreConfig{
+		Freshness: FreshnessConfig{MaxAge: 30 * 24 * time.Hour, AgeScorePenalty: 0.5},
+	})
+	module.retriever = NewRetriever(module.embedder, module.store)
+
+	now := time.Now().UTC()
+	if err := module.AddDocuments(ctx, []Document{
+		{ID: "stale", Content: "rotate database credentials", Metadata: map[string]string{MetadataLastUpdated: now.AddDate(-1, 0, 0).Format(time.RFC3339)}},
+		{ID: "fresh", Content: "rotate database credentials", Metadata: map[string]string{MetadataLastUpdated: now.Format(time.RFC3339)}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "rotate database credentials", TopK: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Document.ID != "fresh" || resp.Results[1].Score >= resp.Results[0].Score {
+		t.Errorf("Retrieve() = %+v, want fresh ranked above stale", resp.Results)
+	}
+
+	stale, err := module.FindStaleDocuments(ctx, 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("FindStaleDocuments() error = %v", err)
+	}
+	if len(stale) != 1 || stale[0].ID != "stale" {
+		t.Fatalf("FindStaleDocuments() = %+v, want only stale", stale)
+	}
+
+	if err := module.RefreshDocument(ctx, "stale", "rotate database credentials with vault"); err != nil {
+		t.Fatalf("RefreshDocument() error = %v", err)
+	}
+	doc, _ := module.GetDocument(ctx, "stale")
+	if doc.Content != "rotate database credentials with vault" {
+		t.Errorf("RefreshDocument() content = %q", doc.Content)
+	}
+	if stale, _ := module.FindStaleDocuments(ctx, 90*24*time.Hour); len(stale) != 0 {
+		t.Errorf("FindStaleDocuments() after refresh = %+v, want none", stale)
+	}
+}
@@ -0,0 +1,42 @@
+//go:build fuzz
+
+package rag
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+// float32s decodes data as little-endian float32 values, dropping trailing bytes
+func float32s(data []byte) []float32 {
+	values := make([]float32, len(data)/4)
+	for i := range values {
+		values[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return values
+}
+
+// FuzzCosineSimilarity checks that cosineSimilarity stays within [-1, 1] for
+// any finite vectors. Run with: go test -tags fuzz -fuzz FuzzCosineSimilarity ./pkg/platformai/rag
+func FuzzCosineSimilarity(f *testing.F) {
+	f.Add([]byte{0, 0, 128, 63, 0, 0, 0, 0}, []byte{0, 0, 128, 63, 0, 0, 0, 0}) // [1 0] and [1 0]
+	f.Add([]byte{0, 0, 128, 63}, []byte{0, 0, 128, 191})                        // [1] and [-1]
+	f.Add([]byte{}, []byte{})
+	f.Add([]byte{0, 0, 128, 63}, []byte{0, 0, 128, 63, 0, 0, 128, 63}) // Different lengths
+	f.Add([]byte{255, 255, 127, 127}, []byte{1, 0, 0, 0})              // Max float32 and the smallest subnormal
+
+	f.Fuzz(func(t *testing.T, rawA, rawB []byte) {
+		a, b := float32s(rawA), float32s(rawB)
+		for _, v := range append(append([]float32{}, a...), b...) {
+			if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+				t.Skip("embeddings are finite")
+			}
+		}
+
+		score := cosineSimilarity(a, b)
+		if score < -1 || score > 1 || math.IsNaN(float64(score)) {
+			t.Fatalf("cosineSimilarity(%v, %v) = %v, want a value in [-1, 1]", a, b, score)
+		}
+	})
+}
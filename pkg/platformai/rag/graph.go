@@ -0,0 +1,158 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Edge types of the document graph. Any string is accepted; these are the
+// conventional ones.
+const (
+	EdgeCites     = "cites"      // The source document cites or links to the target
+	EdgeDependsOn = "depends_on" // The source document assumes knowledge of the target
+	EdgeSeeAlso   = "see_also"   // The target covers a related topic
+)
+
+// DocumentGraph is a directed graph of typed references between documents, for
+// dependency and citation tracking. Unlike the weighted RelationGraph it records
+// why documents are linked; Retrieve expands both with the same one-hop walk.
+// The zero value is an empty graph ready to use.
+type DocumentGraph struct {
+	mu    sync.RWMutex
+	edges map[string]map[GraphEdge]struct{}
+}
+
+// GraphEdge is a typed reference from one document to another
+type GraphEdge struct {
+	FromID string
+	ToID   string
+	Type   string
+}
+
+// AddEdge records a reference of edgeType from fromID to toID. Adding the same
+// edge twice has no effect.
+func (g *DocumentGraph) AddEdge(fromID, toID string, edgeType string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.edges == nil {
+		g.edges = make(map[string]map[GraphEdge]struct{})
+	}
+	if g.edges[fromID] == nil {
+		g.edges[fromID] = make(map[GraphEdge]struct{})
+	}
+	g.edges[fromID][GraphEdge{FromID: fromID, ToID: toID, Type: edgeType}] = struct{}{}
+}
+
+// RemoveNode drops a document and every edge to or from it
+func (g *DocumentGraph) RemoveNode(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.edges, id)
+	for _, edges := range g.edges {
+		for edge := range edges {
+			if edge.ToID == id {
+				delete(edges, edge)
+			}
+		}
+	}
+}
+
+// Neighbors returns the edges leaving id, ordered by target ID and then type
+func (g *DocumentGraph) Neighbors(id string) []GraphEdge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	edges := make([]GraphEdge, 0, len(g.edges[id]))
+	for edge := range g.edges[id] {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].ToID != edges[j].ToID {
+			return edges[i].ToID < edges[j].ToID
+		}
+		return edges[i].Type < edges[j].Type
+	})
+	return edges
+}
+
+// copyFrom adds every edge of other to g
+func (g *DocumentGraph) copyFrom(other *DocumentGraph) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	for _, edges := range other.edges {
+		for edge := range edges {
+			g.AddEdge(edge.FromID, edge.ToID, edge.Type)
+		}
+	}
+}
+
+// ShortestPath returns the document IDs on a shortest path following edges from
+// fromID to toID, both included. It returns ErrNoPath if toID is unreachable.
+func (g *DocumentGraph) ShortestPath(fromID, toID string) ([]string, error) {
+	if fromID == toID {
+		return []string{fromID}, nil
+	}
+
+	previous := map[string]string{fromID: ""}
+	frontier := []string{fromID}
+	for len(frontier) > 0 {
+		var next []string
+		for _, id := range frontier {
+			for _, edge := range g.Neighbors(id) {
+				if _, seen := previous[edge.ToID]; seen {
+					continue
+				}
+				previous[edge.ToID] = id
+				if edge.ToID == toID {
+					return tracePath(previous, fromID, toID), nil
+				}
+				next = append(next, edge.ToID)
+			}
+		}
+		frontier = next
+	}
+	return nil, fmt.Errorf("%w: from %s to %s", ErrNoPath, fromID, toID)
+}
+
+// tracePath follows the BFS predecessors from toID back to fromID
+func tracePath(previous map[string]string, fromID, toID string) []string {
+	path := []string{toID}
+	for id := toID; id != fromID; {
+		id = previous[id]
+		path = append(path, id)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Graph returns the module's document graph. Edges added to it are followed
+// by Retrieve when RetrieveRequest.TraverseGraph is set.
+func (m *Module) Graph() *DocumentGraph {
+	return &m.graph
+}
+
+// traverseGraph appends the documents one graph edge from results that are not
+// already in them, scored like the result referencing them
+func (m *Module) traverseGraph(ctx context.Context, results []SearchResult) ([]SearchResult, error) {
+	return m.expandOneHop(ctx, results, m.graph.relations)
+}
+
+// relations returns the targets of the edges leaving id as relations of weight 1,
+// each target once whatever the number of edge types to it
+func (g *DocumentGraph) relations(id string) []Relation {
+	var relations []Relation
+	for _, edge := range g.Neighbors(id) {
+		if n := len(relations); n > 0 && relations[n-1].ToID == edge.ToID {
+			continue
+		}
+		relations = append(relations, Relation{ToID: edge.ToID, Weight: 1})
+	}
+	return relations
+}
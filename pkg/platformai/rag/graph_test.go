@@ -0,0 +1,123 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// newGraphTestModule builds a five-document module whose graph points from each
+// document to the one it builds on:
+//
+//	policies -> networking -> overview
+//	dns -> ingress -> networking
+func newGraphTestModule(t *testing.T) *Module {
+	t.Helper()
+	m := newTestModule()
+	if err := m.AddDocuments(context.Background(), []Document{
+		{ID: "overview", Content: "Kubernetes platform overview"},
+		{ID: "networking", Content: "Kubernetes networking guide for services"},
+		{ID: "policies", Content: "Network policies restrict pod egress traffic"},
+		{ID: "ingress", Content: "Ingress controllers route external HTTP"},
+		{ID: "dns", Content: "CoreDNS resolves cluster names"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	graph := m.Graph()
+	graph.AddEdge("policies", "networking", EdgeCites)
+	graph.AddEdge("networking", "overview", EdgeDependsOn)
+	graph.AddEdge("ingress", "networking", EdgeCites)
+	graph.AddEdge("dns", "ingress", EdgeSeeAlso)
+	graph.AddEdge("dns", "ingress", EdgeSeeAlso)
+	return m
+}
+
+func TestDocumentGraph_Neighbors(t *testing.T) {
+	var g DocumentGraph
+	g.AddEdge("a", "c", EdgeSeeAlso)
+	g.AddEdge("a", "b", EdgeCites)
+	g.AddEdge("a", "b", EdgeDependsOn)
+	g.AddEdge("a", "b", EdgeCites)
+
+	want := []GraphEdge{
+		{FromID: "a", ToID: "b", Type: EdgeCites},
+		{FromID: "a", ToID: "b", Type: EdgeDependsOn},
+		{FromID: "a", ToID: "c", Type: EdgeSeeAlso},
+	}
+	if got := g.Neighbors("a"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbors() = %v, want %v", got, want)
+	}
+
+	g.RemoveNode("b")
+	if got := g.Neighbors("a"); len(got) != 1 || got[0].ToID != "c" {
+		t.Errorf("Neighbors() after RemoveNode = %v, want only c", got)
+	}
+}
+
+func TestDocumentGraph_ShortestPath(t *testing.T) {
+	graph := newGraphTestModule(t).Graph()
+
+	tests := []struct {
+		from, to string
+		want     []string
+	}{
+		{"dns", "overview", []string{"dns", "ingress", "networking", "overview"}},
+		{"policies", "networking", []string{"policies", "networking"}},
+		{"overview", "overview", []string{"overview"}},
+	}
+	for _, tt := range tests {
+		got, err := graph.ShortestPath(tt.from, tt.to)
+		if err != nil {
+			t.Errorf("ShortestPath(%s, %s) error = %v", tt.from, tt.to, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ShortestPath(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	if _, err := graph.ShortestPath("overview", "dns"); !errors.Is(err, ErrNoPath) {
+		t.Errorf("ShortestPath() against edge direction error = %v, want ErrNoPath", err)
+	}
+}
+
+func TestModule_RetrieveTraverseGraph(t *testing.T) {
+	m := newGraphTestModule(t)
+	ctx := context.Background()
+
+	ids := func(resp *RetrieveResponse) []string {
+		var ids []string
+		for _, result := range resp.Results {
+			ids = append(ids, result.Document.ID)
+		}
+		return ids
+	}
+
+	plain, err := m.Retrieve(ctx, RetrieveRequest{Query: "policies restrict pod egress", TopK: 1})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if got := ids(plain); !reflect.DeepEqual(got, []string{"policies"}) {
+		t.Fatalf("Retrieve() without traversal = %v, want [policies]", got)
+	}
+
+	resp, err := m.Retrieve(ctx, RetrieveRequest{Query: "policies restrict pod egress", TopK: 1, TraverseGraph: true})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if got := ids(resp); !reflect.DeepEqual(got, []string{"policies", "networking"}) {
+		t.Errorf("Retrieve() with traversal = %v, want [policies networking]", got)
+	}
+	if resp.Results[1].Score != resp.Results[0].Score {
+		t.Errorf("cited document score = %v, want citing score %v", resp.Results[1].Score, resp.Results[0].Score)
+	}
+
+	if err := m.DeleteDocument(ctx, "networking"); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Graph().Neighbors("policies"); len(got) != 0 {
+		t.Errorf("Neighbors() after DeleteDocument = %v, want none", got)
+	}
+}
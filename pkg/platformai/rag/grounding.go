@@ -0,0 +1,54 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// GroundingResult reports whether an answer is supported by its retrieval context
+type GroundingResult struct {
+	Supported         bool     `json:"supported"`
+	Confidence        float32  `json:"confidence"`         // 0-1
+	UnsupportedClaims []string `json:"unsupported_claims"` // Claims not backed by the context
+}
+
+// GroundingVerifier asks the LLM to check generated answers against the retrieved context
+type GroundingVerifier struct {
+	llm llm.Client
+}
+
+// NewGroundingVerifier creates a new grounding verifier
+func NewGroundingVerifier(llmClient llm.Client) *GroundingVerifier {
+	return &GroundingVerifier{llm: llmClient}
+}
+
+// Verify checks whether answer is supported by context, typically RetrieveResponse.Context
+func (v *GroundingVerifier) Verify(ctx context.Context, answer *llm.GenerateResponse, context string) (*GroundingResult, error) {
+	systemPrompt := `You are a fact checker for a retrieval-augmented assistant.
+
+Decide whether every claim in the answer is supported by the context.
+Claims that contradict the context or are absent from it are unsupported.
+
+Respond with ONLY valid JSON:
+{"supported": true|false, "confidence": 0.0-1.0, "unsupported_claims": ["..."]}`
+
+	userPrompt := fmt.Sprintf("Context:\n%s\n\nAnswer:\n%s", context, answer.Text)
+
+	resp, err := v.llm.Generate(ctx, llm.GenerateRequest{
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		MaxTokens:    1024,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify grounding: %w", err)
+	}
+
+	var result GroundingResult
+	if err := json.Unmarshal([]byte(resp.Text), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse grounding result: %w (response: %s)", err, resp.Text)
+	}
+	return &result, nil
+}
@@ -0,0 +1,51 @@
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+func TestModule_VerifyGrounding(t *testing.T) {
+	mock := llm.NewMockClient("")
+	mock.GenerateFunc = func(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+		if strings.Contains(req.UserPrompt, "port 9090") {
+			return &llm.GenerateResponse{Text: `{"supported": false, "confidence": 0.95, "unsupported_claims": ["The service listens on port 9090"]}`}, nil
+		}
+		return &llm.GenerateResponse{Text: `{"supported": true, "confidence": 0.9, "unsupported_claims": []}`}, nil
+	}
+
+	module := newTestModule()
+	WithLLM(mock)(module)
+
+	ctx := context.Background()
+	retrieved := "The payments service listens on port 8080."
+
+	result, err := module.VerifyGrounding(ctx, "The service listens on port 9090", retrieved)
+	if err != nil {
+		t.Fatalf("VerifyGrounding() error = %v", err)
+	}
+	if result.Supported || len(result.UnsupportedClaims) != 1 {
+		t.Errorf("VerifyGrounding() = %+v, want unsupported with one claim", result)
+	}
+
+	result, err = module.VerifyGrounding(ctx, "The service listens on port 8080", retrieved)
+	if err != nil {
+		t.Fatalf("VerifyGrounding() error = %v", err)
+	}
+	if !result.Supported {
+		t.Errorf("VerifyGrounding() = %+v, want supported", result)
+	}
+
+	if !strings.Contains(mock.Requests()[0].UserPrompt, retrieved) {
+		t.Error("verification prompt should include the retrieval context")
+	}
+}
+
+func TestModule_VerifyGroundingWithoutLLM(t *testing.T) {
+	if _, err := newTestModule().VerifyGrounding(context.Background(), "answer", "context"); err == nil {
+		t.Error("VerifyGrounding() expected error without an LLM client")
+	}
+}
@@ -0,0 +1,87 @@
+package rag
+
+import (
+	"sort"
+	"sync"
+)
+
+// RetrievalStats counts how often each document has been returned by a retrieval
+type RetrievalStats struct {
+	mu     sync.RWMutex
+	counts map[string]int
+}
+
+// NewRetrievalStats creates empty retrieval statistics
+func NewRetrievalStats() *RetrievalStats {
+	return &RetrievalStats{counts: make(map[string]int)}
+}
+
+// Count returns the number of retrievals recorded for a document
+func (s *RetrievalStats) Count(id string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.counts[id]
+}
+
+// record increments the count of every retrieved document
+func (s *RetrievalStats) record(results []SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, result := range results {
+		s.counts[result.Document.ID]++
+	}
+}
+
+// forget drops the count of a deleted document
+func (s *RetrievalStats) forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.counts, id)
+}
+
+// top returns up to n document IDs ordered by retrieval count, most retrieved first.
+// n <= 0 returns every retrieved document.
+func (s *RetrievalStats) top(n int) []string {
+	s.mu.RLock()
+	ids := make([]string, 0, len(s.counts))
+	for id := range s.counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if s.counts[ids[i]] != s.counts[ids[j]] {
+			return s.counts[ids[i]] > s.counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	s.mu.RUnlock()
+
+	if n > 0 && n < len(ids) {
+		ids = ids[:n]
+	}
+	return ids
+}
+
+// boost adds weight * (count / max count) to each result's score, re-ranks, and
+// keeps the topK best results
+func (s *RetrievalStats) boost(results []SearchResult, weight float32, topK int) []SearchResult {
+	s.mu.RLock()
+	var maxCount int
+	for _, count := range s.counts {
+		maxCount = max(maxCount, count)
+	}
+	if maxCount > 0 {
+		for i := range results {
+			results[i].Score += weight * float32(s.counts[results[i].Document.ID]) / float32(maxCount)
+		}
+	}
+	s.mu.RUnlock()
+
+	sortResults(results)
+	if topK > 0 && topK < len(results) {
+		results = results[:topK]
+	}
+	return results
+}
@@ -0,0 +1,66 @@
+package rag
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModule_ImportanceBoost(t *testing.T) {
+	ctx := context.Background()
+	module := newTestModule(WithImportanceBoost(0.5))
+
+	// Identical content gives both documents the same similarity score
+	docs := []Document{
+		{ID: "fresh", Content: "deploy services with helm charts", Metadata: map[string]string{"name": "fresh"}},
+		{ID: "popular", Content: "deploy services with helm charts", Metadata: map[string]string{"name": "popular"}},
+	}
+	if err := module.AddDocuments(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		if _, err := module.Retrieve(ctx, RetrieveRequest{Query: "helm", Filters: map[string]string{"name": "popular"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := module.RetrievalStats().Count("popular"); got != 20 {
+		t.Errorf("RetrievalStats().Count() = %d, want 20", got)
+	}
+
+	resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "deploy helm charts", TopK: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Document.ID != "popular" {
+		t.Errorf("Retrieve() with importance boost = %+v, want popular first", resp.Results)
+	}
+
+	popular, err := module.PopularDocuments(ctx, 1)
+	if err != nil {
+		t.Fatalf("PopularDocuments() error = %v", err)
+	}
+	if len(popular) != 1 || popular[0].ID != "popular" {
+		t.Errorf("PopularDocuments() = %+v, want popular", popular)
+	}
+
+	if err := module.DeleteDocument(ctx, "popular"); err != nil {
+		t.Fatal(err)
+	}
+	if popular, _ := module.PopularDocuments(ctx, 0); len(popular) != 0 {
+		t.Errorf("PopularDocuments() after delete = %+v, want none", popular)
+	}
+}
+
+func TestRetrievalStats_BoostTies(t *testing.T) {
+	stats := NewRetrievalStats()
+	results := []SearchResult{
+		{Document: Document{ID: "c"}, Score: 0.5},
+		{Document: Document{ID: "a"}, Score: 0.5},
+		{Document: Document{ID: "b"}, Score: 0.5},
+	}
+
+	got := stats.boost(results, 0.5, 2)
+	if len(got) != 2 || got[0].Document.ID != "a" || got[1].Document.ID != "b" {
+		t.Errorf("boost() = %+v, want a, b with ties broken by ID", got)
+	}
+}
@@ -0,0 +1,205 @@
+package rag
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// LanguageMetadataKey is the metadata key a document's detected language is stored
+// under; Document.Language mirrors it
+const LanguageMetadataKey = "language"
+
+// ErrLanguageUndetected indicates that text has too little content to identify its language
+var ErrLanguageUndetected = errors.New("language could not be detected")
+
+// LanguageDetector identifies the language of text as an ISO 639-1 code such as "en"
+type LanguageDetector interface {
+	Detect(text string) (langCode string, confidence float32, err error)
+}
+
+// NGramLanguageDetector detects English, Spanish, French, German, Portuguese,
+// Italian, Russian, Arabic, Chinese, and Japanese. Non-Latin scripts are
+// identified by their characters; Latin-script languages are scored against
+// compact character trigram models.
+type NGramLanguageDetector struct {
+	models map[string]trigramModel
+}
+
+// trigramModel holds the log-probability of each trigram seen in a language's
+// sample text, and the log-probability assigned to unseen trigrams
+type trigramModel struct {
+	logProb map[string]float64
+	unseen  float64
+}
+
+// NewNGramLanguageDetector creates a detector with the built-in language models
+func NewNGramLanguageDetector() *NGramLanguageDetector {
+	models := make(map[string]trigramModel, len(languageSamples))
+	for lang, sample := range languageSamples {
+		models[lang] = newTrigramModel(sample)
+	}
+	return &NGramLanguageDetector{models: models}
+}
+
+func newTrigramModel(sample string) trigramModel {
+	counts := make(map[string]int)
+	total := 0
+	for _, gram := range trigrams(sample) {
+		counts[gram]++
+		total++
+	}
+	// Add-one smoothing over the seen trigrams plus one bucket for unseen ones
+	denom := float64(total + len(counts) + 1)
+	model := trigramModel{logProb: make(map[string]float64, len(counts)), unseen: math.Log(1 / denom)}
+	for gram, n := range counts {
+		model.logProb[gram] = math.Log(float64(n+1) / denom)
+	}
+	return model
+}
+
+// Detect returns the most likely language of text. Confidence is the share of
+// letters in the detected script for non-Latin languages, and the posterior
+// probability of the best model for Latin-script ones.
+func (d *NGramLanguageDetector) Detect(text string) (string, float32, error) {
+	var latin, cyrillic, arabic, han, kana, letters int
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Arabic, r):
+			arabic++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		}
+	}
+	if letters == 0 {
+		return "", 0, ErrLanguageUndetected
+	}
+
+	share := func(n int) float32 { return float32(n) / float32(letters) }
+	switch {
+	case kana > 0 && kana+han > latin:
+		return "ja", share(kana + han), nil
+	case han > latin:
+		return "zh", share(han), nil
+	case cyrillic > latin:
+		return "ru", share(cyrillic), nil
+	case arabic > latin:
+		return "ar", share(arabic), nil
+	}
+
+	grams := trigrams(text)
+	if len(grams) == 0 {
+		return "", 0, ErrLanguageUndetected
+	}
+	scores := make(map[string]float64, len(d.models))
+	best, bestScore := "", math.Inf(-1)
+	for lang, model := range d.models {
+		score := 0.0
+		for _, gram := range grams {
+			if p, ok := model.logProb[gram]; ok {
+				score += p
+			} else {
+				score += model.unseen
+			}
+		}
+		scores[lang] = score
+		if score > bestScore || (score == bestScore && lang < best) {
+			best, bestScore = lang, score
+		}
+	}
+
+	// Posterior of the best language, assuming equal priors
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - bestScore)
+	}
+	return best, float32(1 / sum), nil
+}
+
+// WithLanguageDetection detects the language of documents as they are added,
+// storing it in Document.Language and Metadata[LanguageMetadataKey] so
+// RetrieveRequest.Language can filter on it. Documents whose language cannot be
+// detected are stored without one.
+func WithLanguageDetection(d LanguageDetector) Option {
+	return func(m *Module) {
+		m.language = d
+	}
+}
+
+// withLanguage returns metadata with the detected language of content added.
+// The input map is not modified.
+func (m *Module) withLanguage(content string, metadata map[string]string) map[string]string {
+	if m.language == nil {
+		return metadata
+	}
+	lang, _, err := m.language.Detect(content)
+	if err != nil || lang == "" {
+		return metadata
+	}
+
+	merged := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		merged[k] = v
+	}
+	merged[LanguageMetadataKey] = lang
+	return merged
+}
+
+// trigrams returns the character trigrams of each lowercased word, padded with spaces
+func trigrams(text string) []string {
+	var grams []string
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, word := range words {
+		runes := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+3]))
+		}
+	}
+	return grams
+}
+
+// languageSamples is the training text of the Latin-script trigram models
+var languageSamples = map[string]string{
+	"en": `The quick development of new technology has changed the way that people work and live.
+Most of the time we do not think about how the things around us are made, but there is always someone who
+designed them. This is why it is important to understand what you are building and who will use it.
+They would like to know where their data is stored and which services can read it. We have been working
+on this for a long time, and there are still many questions that need an answer before the end of the year.`,
+	"es": `El desarrollo rápido de la tecnología ha cambiado la forma en que las personas trabajan y viven.
+La mayoría de las veces no pensamos en cómo se hacen las cosas que nos rodean, pero siempre hay alguien que
+las diseñó. Por eso es importante entender lo que estás construyendo y quién lo va a usar. Los usuarios
+quieren saber dónde se guardan sus datos y qué servicios pueden leerlos. Hemos trabajado en esto durante
+mucho tiempo y todavía hay muchas preguntas que necesitan una respuesta antes del final del año.`,
+	"fr": `Le développement rapide de la technologie a changé la façon dont les gens travaillent et vivent.
+La plupart du temps, nous ne pensons pas à la manière dont les choses qui nous entourent sont fabriquées, mais
+il y a toujours quelqu'un qui les a conçues. C'est pourquoi il est important de comprendre ce que vous
+construisez et qui va l'utiliser. Les utilisateurs veulent savoir où leurs données sont stockées et quels
+services peuvent les lire. Nous travaillons sur ce sujet depuis longtemps et il reste encore beaucoup de
+questions qui demandent une réponse avant la fin de l'année.`,
+	"de": `Die schnelle Entwicklung der Technik hat die Art und Weise verändert, wie Menschen arbeiten und leben.
+Meistens denken wir nicht darüber nach, wie die Dinge um uns herum hergestellt werden, aber es gibt immer
+jemanden, der sie entworfen hat. Deshalb ist es wichtig zu verstehen, was man baut und wer es benutzen wird.
+Die Benutzer möchten wissen, wo ihre Daten gespeichert sind und welche Dienste sie lesen können. Wir arbeiten
+schon seit langer Zeit daran, und es gibt noch viele Fragen, die vor dem Ende des Jahres eine Antwort brauchen.`,
+	"pt": `O desenvolvimento rápido da tecnologia mudou a maneira como as pessoas trabalham e vivem.
+Na maioria das vezes não pensamos em como são feitas as coisas que estão ao nosso redor, mas sempre existe
+alguém que as projetou. Por isso é importante entender o que você está construindo e quem vai usar. Os
+usuários querem saber onde os seus dados são guardados e quais serviços podem lê-los. Estamos trabalhando
+nisso há muito tempo e ainda existem muitas perguntas que precisam de uma resposta antes do fim do ano.`,
+	"it": `Lo sviluppo rapido della tecnologia ha cambiato il modo in cui le persone lavorano e vivono.
+La maggior parte delle volte non pensiamo a come sono fatte le cose che ci circondano, ma c'è sempre
+qualcuno che le ha progettate. Per questo è importante capire che cosa stai costruendo e chi lo userà.
+Gli utenti vogliono sapere dove sono conservati i loro dati e quali servizi possono leggerli. Ci lavoriamo
+da molto tempo e ci sono ancora molte domande che hanno bisogno di una risposta prima della fine dell'anno.`,
+}
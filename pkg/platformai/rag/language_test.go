@@ -0,0 +1,81 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+var languageParagraphs = map[string]string{
+	"en": "Our platform team reviews every deployment request before it reaches production. Please describe the service, the expected traffic, and who is responsible when something goes wrong.",
+	"fr": "Notre équipe vérifie chaque demande de déploiement avant la mise en production. Merci de décrire le service, le trafic attendu et la personne responsable en cas de problème.",
+	"de": "Unser Plattformteam prüft jede Anfrage für eine Bereitstellung, bevor sie in die Produktion geht. Bitte beschreiben Sie den Dienst, den erwarteten Verkehr und wer verantwortlich ist, wenn etwas schiefgeht.",
+}
+
+func TestNGramLanguageDetector_Detect(t *testing.T) {
+	d := NewNGramLanguageDetector()
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", languageParagraphs["en"], "en"},
+		{"french", languageParagraphs["fr"], "fr"},
+		{"german", languageParagraphs["de"], "de"},
+		{"russian", "Команда платформы проверяет каждый запрос.", "ru"},
+		{"japanese", "プラットフォームチームはすべてのリクエストを確認します。", "ja"},
+		{"chinese", "平台团队会审核每一个部署请求。", "zh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence, err := d.Detect(tt.text)
+			if err != nil {
+				t.Fatalf("Detect() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+			if confidence <= 0.5 || confidence > 1 {
+				t.Errorf("Detect() confidence = %v, want (0.5, 1]", confidence)
+			}
+		})
+	}
+
+	if _, _, err := d.Detect("1234 !!"); !errors.Is(err, ErrLanguageUndetected) {
+		t.Errorf("Detect() error = %v, want ErrLanguageUndetected", err)
+	}
+}
+
+func TestModule_RetrieveLanguage(t *testing.T) {
+	ctx := context.Background()
+	m := newTestModule(WithLanguageDetection(NewNGramLanguageDetector()))
+	for lang, text := range languageParagraphs {
+		if err := m.AddDocument(ctx, "doc-"+lang, text, nil); err != nil {
+			t.Fatalf("AddDocument() error = %v", err)
+		}
+	}
+
+	for lang := range languageParagraphs {
+		doc, err := m.GetDocument(ctx, "doc-"+lang)
+		if err != nil {
+			t.Fatalf("GetDocument() error = %v", err)
+		}
+		if doc.Language != lang {
+			t.Errorf("document %s Language = %q, want %q", doc.ID, doc.Language, lang)
+		}
+	}
+
+	resp, err := m.Retrieve(ctx, RetrieveRequest{Query: "deployment service", TopK: 3, Language: "fr"})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Fatalf("Retrieve() returned %d results, want 1", len(resp.Results))
+	}
+	for _, result := range resp.Results {
+		if result.Document.Language != "fr" {
+			t.Errorf("Retrieve() returned %s document %s, want only fr", result.Document.Language, result.Document.ID)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package loaders
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// CSVOptions selects which columns go into document content and metadata.
+// An empty list means all columns.
+type CSVOptions struct {
+	ContentColumns  []string
+	MetadataColumns []string
+}
+
+// CSVLoader turns each data row of a CSV file into a document.
+// The first row is treated as column headers.
+type CSVLoader struct {
+	options CSVOptions
+}
+
+// NewCSVLoader creates a new CSV loader
+func NewCSVLoader(options CSVOptions) *CSVLoader {
+	return &CSVLoader{options: options}
+}
+
+// Load reads the CSV file at path, producing content like "column: value, column: value"
+func (l *CSVLoader) Load(ctx context.Context, path string) ([]rag.Document, error) {
+	// #nosec G304 - path is provided by the caller to index a document they own
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	contentColumns, err := columnIndexes(headers, l.options.ContentColumns)
+	if err != nil {
+		return nil, err
+	}
+	metadataColumns, err := columnIndexes(headers, l.options.MetadataColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []rag.Document
+	for row := 1; ; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", row, err)
+		}
+
+		parts := make([]string, 0, len(contentColumns))
+		for _, i := range contentColumns {
+			parts = append(parts, headers[i]+": "+record[i])
+		}
+
+		metadata := map[string]string{
+			"source": path,
+			"row":    strconv.Itoa(row),
+		}
+		for _, i := range metadataColumns {
+			metadata[headers[i]] = record[i]
+		}
+
+		docs = append(docs, rag.Document{
+			ID:       fmt.Sprintf("%s#row-%d", path, row),
+			Content:  strings.Join(parts, ", "),
+			Metadata: metadata,
+		})
+	}
+
+	return docs, nil
+}
+
+// columnIndexes maps column names to header positions, defaulting to all columns
+func columnIndexes(headers, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		indexes := make([]int, len(headers))
+		for i := range headers {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	positions := make(map[string]int, len(headers))
+	for i, h := range headers {
+		positions[h] = i
+	}
+
+	indexes := make([]int, 0, len(columns))
+	for _, column := range columns {
+		i, ok := positions[column]
+		if !ok {
+			return nil, fmt.Errorf("CSV column not found: %s", column)
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes, nil
+}
@@ -0,0 +1,49 @@
+package loaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCSVLoader_Load(t *testing.T) {
+	docs, err := NewCSVLoader(CSVOptions{}).Load(context.Background(), "testdata/services.csv")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(docs) != 3 {
+		t.Fatalf("Load() = %d documents, want 3", len(docs))
+	}
+
+	want := "name: search, team: discovery, language: python, description: Full-text search, with facets"
+	if docs[1].Content != want {
+		t.Errorf("row 2 content = %q, want %q", docs[1].Content, want)
+	}
+	if docs[1].Metadata["team"] != "discovery" || docs[1].Metadata["language"] != "python" {
+		t.Errorf("row 2 metadata = %v, want all cell values", docs[1].Metadata)
+	}
+}
+
+func TestCSVLoader_LoadColumns(t *testing.T) {
+	loader := NewCSVLoader(CSVOptions{
+		ContentColumns:  []string{"name", "description"},
+		MetadataColumns: []string{"team"},
+	})
+	docs, err := loader.Load(context.Background(), "testdata/services.csv")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if docs[0].Content != "name: payments, description: Processes card payments" {
+		t.Errorf("content = %q", docs[0].Content)
+	}
+	if docs[0].Metadata["team"] != "fintech" {
+		t.Errorf("team metadata = %q, want fintech", docs[0].Metadata["team"])
+	}
+	if _, ok := docs[0].Metadata["language"]; ok {
+		t.Error("language should not be in metadata when MetadataColumns excludes it")
+	}
+
+	if _, err := NewCSVLoader(CSVOptions{ContentColumns: []string{"missing"}}).Load(context.Background(), "testdata/services.csv"); err == nil {
+		t.Error("Load() expected error for unknown column")
+	}
+}
@@ -0,0 +1,185 @@
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// HTMLLoader extracts visible text from HTML files, one document per file.
+// Headings are kept as Markdown-style prefixes ("# ", "## ") to preserve hierarchy.
+type HTMLLoader struct{}
+
+// NewHTMLLoader creates a new HTML loader
+func NewHTMLLoader() *HTMLLoader {
+	return &HTMLLoader{}
+}
+
+// Load extracts the text of the HTML file at path
+func (l *HTMLLoader) Load(ctx context.Context, path string) ([]rag.Document, error) {
+	// #nosec G304 - path is provided by the caller to index a document they own
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open HTML file: %w", err)
+	}
+	defer file.Close()
+
+	return l.parse(file, path)
+}
+
+// parse extracts the text of an HTML document read from r
+func (l *HTMLLoader) parse(r io.Reader, source string) ([]rag.Document, error) {
+	root, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var builder strings.Builder
+	var title string
+	extractText(root, &builder, &title)
+
+	content := normalizeWhitespace(builder.String())
+	if content == "" {
+		return nil, nil
+	}
+
+	metadata := map[string]string{"source": source}
+	if title != "" {
+		metadata["title"] = title
+	}
+
+	return []rag.Document{{ID: source, Content: content, Metadata: metadata}}, nil
+}
+
+// skippedElements hold no visible content worth indexing
+var skippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Nav:      true,
+	atom.Noscript: true,
+	atom.Template: true,
+}
+
+// blockElements start a new line in the extracted text
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.Section: true, atom.Article: true, atom.Header: true, atom.Footer: true,
+	atom.Pre: true, atom.Blockquote: true, atom.Table: true, atom.Ul: true, atom.Ol: true,
+}
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// extractText writes the visible text below n to builder and records the page title
+func extractText(n *html.Node, builder *strings.Builder, title *string) {
+	switch n.Type {
+	case html.TextNode:
+		// Source line breaks are formatting, not content; block elements add their own
+		builder.WriteString(lineBreaks.Replace(n.Data))
+		return
+	case html.ElementNode:
+		if skippedElements[n.DataAtom] {
+			return
+		}
+		if n.DataAtom == atom.Title {
+			*title = strings.TrimSpace(nodeText(n))
+			return
+		}
+		if level, ok := headingLevels[n.DataAtom]; ok {
+			builder.WriteString("\n" + strings.Repeat("#", level) + " " + nodeText(n) + "\n")
+			return
+		}
+	}
+
+	isBlock := n.Type == html.ElementNode && blockElements[n.DataAtom]
+	if isBlock {
+		builder.WriteString("\n")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		extractText(c, builder, title)
+	}
+	if isBlock {
+		builder.WriteString("\n")
+	}
+}
+
+// nodeText returns the concatenated text below n, ignoring skipped elements
+func nodeText(n *html.Node) string {
+	var builder strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			builder.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && skippedElements[n.DataAtom] {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// normalizeWhitespace collapses runs of whitespace within lines and drops empty lines
+func normalizeWhitespace(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HTTPLoader fetches web pages and extracts their text with HTMLLoader
+type HTTPLoader struct {
+	html       *HTMLLoader
+	httpClient *http.Client
+}
+
+// NewHTTPLoader creates a new HTTP loader
+func NewHTTPLoader() *HTTPLoader {
+	return &HTTPLoader{
+		html: NewHTMLLoader(),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+			},
+		},
+	}
+}
+
+// Load fetches url and extracts the page text
+func (l *HTTPLoader) Load(ctx context.Context, url string) ([]rag.Document, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", url, resp.StatusCode)
+	}
+
+	return l.html.parse(resp.Body, url)
+}
@@ -0,0 +1,69 @@
+package loaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHTMLLoader_Load(t *testing.T) {
+	docs, err := NewHTMLLoader().Load(context.Background(), "testdata/page.html")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("Load() = %d documents, want 1", len(docs))
+	}
+
+	content := docs[0].Content
+	for _, absent := range []string{"trackEverything", "console.log", "font-family", "Home"} {
+		if strings.Contains(content, absent) {
+			t.Errorf("extracted text should not contain %q:\n%s", absent, content)
+		}
+	}
+	for _, present := range []string{"# Deploying Services", "## Scaling", "Services run on Kubernetes.", "Use a horizontal pod autoscaler to scale replicas.", "Set memory limits"} {
+		if !strings.Contains(content, present) {
+			t.Errorf("extracted text missing %q:\n%s", present, content)
+		}
+	}
+	if strings.Contains(content, "  ") || strings.Contains(content, "\n\n") {
+		t.Errorf("extracted text contains duplicate whitespace:\n%q", content)
+	}
+	if docs[0].Metadata["title"] != "Deployment Guide" {
+		t.Errorf("title metadata = %q, want Deployment Guide", docs[0].Metadata["title"])
+	}
+}
+
+func TestHTTPLoader_Load(t *testing.T) {
+	page, err := os.ReadFile("testdata/page.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/guide" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(page)
+	}))
+	defer server.Close()
+
+	loader := NewHTTPLoader()
+	docs, err := loader.Load(context.Background(), server.URL+"/guide")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(docs) != 1 || !strings.Contains(docs[0].Content, "## Scaling") {
+		t.Errorf("Load() = %+v, want extracted page", docs)
+	}
+	if docs[0].Metadata["source"] != server.URL+"/guide" {
+		t.Errorf("source metadata = %q, want URL", docs[0].Metadata["source"])
+	}
+
+	if _, err := loader.Load(context.Background(), server.URL+"/missing"); err == nil {
+		t.Error("Load() expected error for 404 response")
+	}
+}
@@ -0,0 +1,46 @@
+package loaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJSONLoader_Load(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		wantCount int
+		wantFirst string
+	}{
+		{
+			name:      "JSON array",
+			path:      "testdata/services.json",
+			wantCount: 2,
+			wantFirst: "name: payments, replicas: 3, team: fintech",
+		},
+		{
+			name:      "JSON Lines",
+			path:      "testdata/services.jsonl",
+			wantCount: 3,
+			wantFirst: "name: payments, public: false",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := NewJSONLoader().Load(context.Background(), tt.path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if len(docs) != tt.wantCount {
+				t.Fatalf("Load() = %d documents, want %d", len(docs), tt.wantCount)
+			}
+			if docs[0].Content != tt.wantFirst {
+				t.Errorf("first content = %q, want %q", docs[0].Content, tt.wantFirst)
+			}
+			if docs[0].Metadata["name"] != "payments" {
+				t.Errorf("name metadata = %q, want payments", docs[0].Metadata["name"])
+			}
+		})
+	}
+}
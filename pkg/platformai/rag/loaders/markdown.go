@@ -0,0 +1,101 @@
+package loaders
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// MarkdownLoader splits Markdown files into one document per #, ## or ### section.
+// Section content, including tables, is kept verbatim.
+type MarkdownLoader struct{}
+
+// NewMarkdownLoader creates a new Markdown loader
+func NewMarkdownLoader() *MarkdownLoader {
+	return &MarkdownLoader{}
+}
+
+// markdownSection is a heading and the lines below it
+type markdownSection struct {
+	heading string
+	level   int
+	lines   []string
+	hasCode bool
+}
+
+// Load splits the Markdown file at path into sections
+func (l *MarkdownLoader) Load(ctx context.Context, path string) ([]rag.Document, error) {
+	// #nosec G304 - path is provided by the caller to index a document they own
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Markdown file: %w", err)
+	}
+	defer file.Close()
+
+	sections := []*markdownSection{{}}
+	inCodeBlock := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		// Lines starting with # inside fenced code are comments, not headings
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCodeBlock = !inCodeBlock
+			sections[len(sections)-1].hasCode = true
+		} else if !inCodeBlock {
+			if level, heading := parseHeading(trimmed); level > 0 {
+				sections = append(sections, &markdownSection{heading: heading, level: level})
+			}
+		}
+
+		current := sections[len(sections)-1]
+		current.lines = append(current.lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Markdown file: %w", err)
+	}
+
+	var docs []rag.Document
+	for _, section := range sections {
+		content := strings.TrimSpace(strings.Join(section.lines, "\n"))
+		if content == "" {
+			continue
+		}
+
+		metadata := map[string]string{
+			"source":  path,
+			"section": section.heading,
+			"level":   strconv.Itoa(section.level),
+		}
+		if section.hasCode {
+			metadata["type"] = "code"
+		}
+
+		docs = append(docs, rag.Document{
+			ID:       fmt.Sprintf("%s#section-%d", path, len(docs)),
+			Content:  content,
+			Metadata: metadata,
+		})
+	}
+
+	return docs, nil
+}
+
+// parseHeading returns the level and text of a #, ## or ### heading line, or 0 if line is not one
+func parseHeading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 3 || level == len(line) || line[level] != ' ' {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(line[level:])
+}
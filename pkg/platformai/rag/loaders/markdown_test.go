@@ -0,0 +1,49 @@
+package loaders
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownLoader_Load(t *testing.T) {
+	docs, err := NewMarkdownLoader().Load(context.Background(), "testdata/guide.md")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []struct {
+		section string
+		level   string
+		code    bool
+	}{
+		{section: "", level: "0"},
+		{section: "Overview", level: "1"},
+		{section: "Resources", level: "2"},
+		{section: "Deployment", level: "2", code: true},
+		{section: "Rollback", level: "3"},
+	}
+	if len(docs) != len(want) {
+		t.Fatalf("Load() = %d documents, want %d", len(docs), len(want))
+	}
+
+	for i, w := range want {
+		meta := docs[i].Metadata
+		if meta["section"] != w.section || meta["level"] != w.level {
+			t.Errorf("doc %d section/level = %q/%q, want %q/%q", i, meta["section"], meta["level"], w.section, w.level)
+		}
+		if (meta["type"] == "code") != w.code {
+			t.Errorf("doc %d type = %q, want code=%v", i, meta["type"], w.code)
+		}
+	}
+
+	if !strings.Contains(docs[2].Content, "| Go       | 250m | 256Mi  |") {
+		t.Errorf("table was not preserved:\n%s", docs[2].Content)
+	}
+	if !strings.Contains(docs[3].Content, "# this is a shell comment") {
+		t.Errorf("code block comment should stay in its section:\n%s", docs[3].Content)
+	}
+	if !strings.Contains(docs[4].Content, "#### Notes") {
+		t.Errorf("level four heading should stay in the Rollback section:\n%s", docs[4].Content)
+	}
+}
@@ -0,0 +1,108 @@
+// Package loaders provides rag.SourceLoader implementations for common document formats
+package loaders
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dslipak/pdf"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// PDFLoader extracts text from PDF files, one document per page
+type PDFLoader struct{}
+
+// NewPDFLoader creates a new PDF loader
+func NewPDFLoader() *PDFLoader {
+	return &PDFLoader{}
+}
+
+// Load extracts the text of each page in the PDF at path
+func (l *PDFLoader) Load(ctx context.Context, path string) (docs []rag.Document, err error) {
+	// #nosec G304 - path is provided by the caller to index a document they own
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat PDF: %w", err)
+	}
+
+	// The pdf package panics on malformed input
+	defer func() {
+		if r := recover(); r != nil {
+			docs = nil
+			err = fmt.Errorf("failed to parse PDF %s: %v", path, r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+
+	for i := 1; i <= reader.NumPage(); i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		text, err := page.GetPlainText(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract text from page %d: %w", i, err)
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		docs = append(docs, rag.Document{
+			ID:      fmt.Sprintf("%s#page-%d", path, i),
+			Content: text,
+			Metadata: map[string]string{
+				"page":   strconv.Itoa(i),
+				"source": path,
+			},
+		})
+	}
+
+	return docs, nil
+}
+
+// LoadDirectory loads every PDF in dir whose name matches pattern (default "*.pdf")
+func (l *PDFLoader) LoadDirectory(ctx context.Context, dir, pattern string) ([]rag.Document, error) {
+	if pattern == "" {
+		pattern = "*.pdf"
+	}
+	return loadDirectory(ctx, l, dir, pattern)
+}
+
+// loadDirectory runs loader on every file in dir matching pattern
+func loadDirectory(ctx context.Context, loader rag.SourceLoader, dir, pattern string) ([]rag.Document, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	var docs []rag.Document
+	for _, path := range paths {
+		loaded, err := loader.Load(ctx, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		docs = append(docs, loaded...)
+	}
+	return docs, nil
+}
@@ -0,0 +1,45 @@
+package loaders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPDFLoader_Load(t *testing.T) {
+	docs, err := NewPDFLoader().Load(context.Background(), "testdata/guide.pdf")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(docs) != 2 {
+		t.Fatalf("Load() = %d documents, want 2 (one per page)", len(docs))
+	}
+	if docs[1].Metadata["page"] != "2" || docs[1].Metadata["source"] != "testdata/guide.pdf" {
+		t.Errorf("page 2 metadata = %v", docs[1].Metadata)
+	}
+	if docs[0].Content != "Kubernetes deployment guide" {
+		t.Errorf("page 1 content = %q", docs[0].Content)
+	}
+}
+
+func TestPDFLoader_LoadErrors(t *testing.T) {
+	loader := NewPDFLoader()
+	ctx := context.Background()
+
+	if _, err := loader.Load(ctx, "testdata/missing.pdf"); err == nil {
+		t.Error("Load() expected error for missing file")
+	}
+	if _, err := loader.Load(ctx, "pdf_test.go"); err == nil {
+		t.Error("Load() expected error for non-PDF file")
+	}
+}
+
+func TestPDFLoader_LoadDirectory(t *testing.T) {
+	docs, err := NewPDFLoader().LoadDirectory(context.Background(), "testdata", "")
+	if err != nil {
+		t.Fatalf("LoadDirectory() error = %v", err)
+	}
+	if len(docs) != 2 {
+		t.Errorf("LoadDirectory() = %d documents, want 2", len(docs))
+	}
+}
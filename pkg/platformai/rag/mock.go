@@ -0,0 +1,82 @@
+package rag
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// MockEmbeddingProvider generates deterministic embeddings without calling an API.
+// Each word is hashed into one of Dim buckets, so texts sharing words score as similar.
+type MockEmbeddingProvider struct {
+	Dim int
+
+	mu    sync.Mutex
+	calls int
+}
+
+// NewMockEmbeddingProvider creates a mock provider producing dim-sized embeddings
+func NewMockEmbeddingProvider(dim int) *MockEmbeddingProvider {
+	if dim <= 0 {
+		dim = 64
+	}
+	return &MockEmbeddingProvider{Dim: dim}
+}
+
+// GenerateEmbedding generates an embedding for a single text
+func (p *MockEmbeddingProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embeddings, err := p.GenerateEmbeddings(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// GenerateEmbeddings generates embeddings for multiple texts
+func (p *MockEmbeddingProvider) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = p.embed(text)
+	}
+	return embeddings, nil
+}
+
+// GenerateEmbeddingsStream embeds texts as they arrive; every 100 texts count as
+// one call in Calls
+func (p *MockEmbeddingProvider) GenerateEmbeddingsStream(ctx context.Context, texts <-chan string) (<-chan EmbeddingResult, error) {
+	return streamEmbeddings(ctx, texts, embeddingStreamBatchSize, p.GenerateEmbeddings)
+}
+
+// Calls returns the number of GenerateEmbeddings calls made so far
+func (p *MockEmbeddingProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func (p *MockEmbeddingProvider) embed(text string) []float32 {
+	embedding := make([]float32, p.Dim)
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, ".,;:!?\"'()[]")
+		if word == "" {
+			continue
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(word))
+		embedding[h.Sum32()%uint32(p.Dim)]++
+	}
+	// Keep empty texts from producing a zero vector the store would reject
+	if len(strings.TrimSpace(text)) == 0 {
+		embedding[0] = 1
+	}
+	return embedding
+}
@@ -2,7 +2,15 @@ package rag
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
 )
 
 // Module provides RAG functionality
@@ -11,37 +19,194 @@ type Module struct {
 	embedder  EmbeddingProvider
 	store     VectorStore
 	retriever *Retriever
+	chunker   Chunker
+	llm       llm.Client // Optional, needed for LLM-assisted features
+
+	keywords *BM25Index       // Set by WithHybridSearch
+	hybrid   *HybridRetriever // Set by WithHybridSearch
+
+	stats            *RetrievalStats
+	importanceWeight float32 // Set by WithImportanceBoost
+
+	relations RelationGraph
+	graph     DocumentGraph
+	entities  EntityExtractor  // Set by WithEntityExtraction
+	language  LanguageDetector // Set by WithLanguageDetection
+
+	coreference CoreferenceResolver // Set by WithCoreferenceResolution
+
+	customHeaders map[string]string // Set by WithCustomHeaders
+	autoModel     *CostBudget       // Set by WithAutoModelSelection
+}
+
+// Option configures optional Module behavior
+type Option func(*Module)
+
+// WithLLM sets the LLM client used by LLM-assisted features such as grounding verification
+func WithLLM(client llm.Client) Option {
+	return func(m *Module) {
+		m.llm = client
+	}
+}
+
+// WithQueryRewriter rewrites every query with qr before it is embedded
+func WithQueryRewriter(qr QueryRewriter) Option {
+	return func(m *Module) {
+		m.retriever.rewriter = qr
+	}
+}
+
+// WithHybridSearch enables hybrid retrieval, fusing dense vector search with BM25
+// keyword search. alpha weights the dense ranking: 0 is BM25 only, 1 dense only.
+func WithHybridSearch(alpha float32) Option {
+	return func(m *Module) {
+		m.keywords = NewBM25Index()
+		m.hybrid = NewHybridRetriever(m.retriever, m.keywords, alpha)
+	}
+}
+
+// WithImportanceBoost ranks frequently retrieved documents higher by adding
+// weight * (retrieval count / highest retrieval count) to each similarity score
+func WithImportanceBoost(weight float32) Option {
+	return func(m *Module) {
+		m.importanceWeight = weight
+	}
+}
+
+// WithCustomHeaders sends headers with every embedding request, in addition to
+// Config.CustomHeaders. NewModule rejects credential headers such as authorization.
+func WithCustomHeaders(headers map[string]string) Option {
+	return func(m *Module) {
+		m.customHeaders = mergeHeaders(m.customHeaders, headers)
+	}
+}
+
+// WithAutoModelSelection replaces the configured embedding model with the one
+// an EmbeddingModelSelector picks for Config.ExpectedDocuments within budget.
+// Only providers with an API key in the config or its fallbacks are considered;
+// the configured model is kept when none of them is in the selector's table.
+func WithAutoModelSelection(budget CostBudget) Option {
+	return func(m *Module) {
+		m.autoModel = &budget
+	}
 }
 
 // NewModule creates a new RAG module
-func NewModule(config Config) (*Module, error) {
+func NewModule(config Config, opts ...Option) (*Module, error) {
+	metric := config.SimilarityMetric
+	switch metric {
+	case "":
+		metric = CosineSimilarity
+	case CosineSimilarity, DotProduct, EuclideanDistance:
+	default:
+		return nil, fmt.Errorf("unsupported similarity metric: %s", metric)
+	}
+
+	// The embedder and store are created after the options run, since
+	// WithAutoModelSelection may change the configured model
+	m := &Module{
+		config:    config,
+		retriever: &Retriever{normalize: config.NormalizeEmbeddings},
+		chunker:   NewFixedSizeChunker(1000, 100),
+		stats:     NewRetrievalStats(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.autoModel != nil {
+		m.config = selectEmbeddingConfig(m.config, *m.autoModel)
+	}
+
 	// Create embedding provider
-	embedder, err := NewEmbeddingProvider(config)
+	embedder, err := NewEmbeddingProvider(m.config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create embedding provider: %w", err)
 	}
 
 	// Create vector store
-	store := NewInMemoryVectorStore()
+	store := NewInMemoryVectorStoreWithConfig(InMemoryStoreConfig{
+		Dimensions: m.config.EmbeddingDim,
+		Metric:     metric,
+		Freshness:  m.config.Freshness,
+	}).Namespace(m.config.DefaultNamespace)
 
-	// Create retriever
-	retriever := NewRetriever(embedder, store)
+	m.embedder, m.store = embedder, store
+	m.retriever.embedder, m.retriever.store = embedder, store
+	if len(m.customHeaders) > 0 {
+		if err := httpclient.ValidateHeaders(m.customHeaders); err != nil {
+			return nil, err
+		}
+		addCustomHeaders(embedder, m.customHeaders)
+	}
 
-	return &Module{
-		config:    config,
-		embedder:  embedder,
-		store:     store,
-		retriever: retriever,
-	}, nil
+	return m, nil
 }
 
 // AddDocument adds a single document to the knowledge base
 func (m *Module) AddDocument(ctx context.Context, id, content string, metadata map[string]string) error {
-	return m.retriever.AddDocument(ctx, id, content, metadata)
+	content, err := m.resolveCoreferences(ctx, content)
+	if err != nil {
+		return err
+	}
+	metadata, err = m.enrichMetadata(ctx, content, metadata)
+	if err != nil {
+		return err
+	}
+	if err := m.retriever.AddDocument(ctx, id, content, metadata); err != nil {
+		return err
+	}
+	m.indexKeywords(Document{ID: id, Content: content, Metadata: metadata})
+	return nil
+}
+
+// UpdateDocument replaces an existing document, regenerating its embedding
+func (m *Module) UpdateDocument(ctx context.Context, id, content string, metadata map[string]string) error {
+	content, err := m.resolveCoreferences(ctx, content)
+	if err != nil {
+		return err
+	}
+	metadata, err = m.enrichMetadata(ctx, content, metadata)
+	if err != nil {
+		return err
+	}
+	if err := m.retriever.UpdateDocument(ctx, id, content, metadata); err != nil {
+		return err
+	}
+	m.indexKeywords(Document{ID: id, Content: content, Metadata: metadata})
+	return nil
 }
 
 // AddDocuments adds multiple documents to the knowledge base
 func (m *Module) AddDocuments(ctx context.Context, docs []Document) error {
+	docs, err := m.resolveDocuments(ctx, docs)
+	if err != nil {
+		return err
+	}
+	return m.addDocuments(ctx, docs)
+}
+
+// resolveDocuments returns docs with their content rewritten by the configured
+// CoreferenceResolver. It resolves copies, since callers such as loaders may
+// keep using the documents they passed.
+func (m *Module) resolveDocuments(ctx context.Context, docs []Document) ([]Document, error) {
+	if m.coreference == nil {
+		return docs, nil
+	}
+	docs = append([]Document(nil), docs...)
+	for i := range docs {
+		var err error
+		if docs[i].Content, err = m.resolveCoreferences(ctx, docs[i].Content); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// addDocuments adds docs without resolving coreferences
+func (m *Module) addDocuments(ctx context.Context, docs []Document) error {
+	// Metadata is enriched on copies so the caller's documents are unchanged
+	docs = append([]Document(nil), docs...)
+
 	// Convert to internal format for retriever
 	internalDocs := make([]struct {
 		ID       string
@@ -49,6 +214,11 @@ func (m *Module) AddDocuments(ctx context.Context, docs []Document) error {
 		Metadata map[string]string
 	}, len(docs))
 	for i, doc := range docs {
+		metadata, err := m.enrichMetadata(ctx, doc.Content, doc.Metadata)
+		if err != nil {
+			return err
+		}
+		docs[i].Metadata = metadata
 		internalDocs[i] = struct {
 			ID       string
 			Content  string
@@ -56,15 +226,169 @@ func (m *Module) AddDocuments(ctx context.Context, docs []Document) error {
 		}{
 			ID:       doc.ID,
 			Content:  doc.Content,
-			Metadata: doc.Metadata,
+			Metadata: metadata,
 		}
 	}
-	return m.retriever.AddDocuments(ctx, internalDocs)
+	if err := m.retriever.AddDocuments(ctx, internalDocs); err != nil {
+		return err
+	}
+	m.indexKeywords(docs...)
+	for _, doc := range docs {
+		for _, related := range doc.RelatedIDs {
+			m.relations.Add(doc.ID, related, 1)
+		}
+	}
+	return nil
+}
+
+// enrichMetadata returns metadata with extracted entities and the detected
+// language added. The input map is not modified.
+func (m *Module) enrichMetadata(ctx context.Context, content string, metadata map[string]string) (map[string]string, error) {
+	metadata, err := m.withEntities(ctx, content, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return m.withLanguage(content, metadata), nil
+}
+
+// indexKeywords adds documents to the BM25 index when hybrid search is enabled
+func (m *Module) indexKeywords(docs ...Document) {
+	if m.keywords == nil {
+		return
+	}
+	for _, doc := range docs {
+		m.keywords.Add(doc)
+	}
+}
+
+// AddDocumentsFromLoader loads documents with loader, chunks them, and stores the
+// embedded chunks. It returns the number of chunks stored. Use FromSource to
+// load a file or URL with a SourceLoader such as loaders.PDFLoader.
+func (m *Module) AddDocumentsFromLoader(ctx context.Context, loader DocumentLoader) (int, error) {
+	docs, err := loader.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load documents: %w", err)
+	}
+	// Whole documents are resolved before chunking, so chunks keep their referents
+	if docs, err = m.resolveDocuments(ctx, docs); err != nil {
+		return 0, err
+	}
+
+	chunks := chunkDocuments(m.chunker, docs)
+	if len(chunks) == 0 {
+		return 0, nil
+	}
+
+	if err := m.addDocuments(ctx, chunks); err != nil {
+		return 0, err
+	}
+	return len(chunks), nil
 }
 
-// Retrieve retrieves relevant documents for a query
+// Retrieve retrieves relevant documents for a query and records them in the retrieval stats
 func (m *Module) Retrieve(ctx context.Context, req RetrieveRequest) (*RetrieveResponse, error) {
-	return m.retriever.Retrieve(ctx, req)
+	if req.Namespace != "" && req.Namespace != m.config.DefaultNamespace {
+		scoped, err := m.Namespace(req.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		scoped.retriever.rewriter = m.retriever.rewriter
+		req.Namespace = ""
+		return scoped.Retrieve(ctx, req)
+	}
+	if req.TopK <= 0 {
+		req.TopK = 3
+	}
+
+	search := req
+	if req.Language != "" {
+		search.Filters = make(map[string]string, len(req.Filters)+1)
+		for k, v := range req.Filters {
+			search.Filters[k] = v
+		}
+		search.Filters[LanguageMetadataKey] = req.Language
+	}
+	if m.importanceWeight > 0 {
+		// Fetch extra candidates so popular documents just below the cut can be boosted in
+		search.TopK = req.TopK * hybridCandidateFactor
+	}
+
+	var resp *RetrieveResponse
+	var err error
+	if m.hybrid != nil {
+		resp, err = m.hybrid.Retrieve(ctx, search)
+	} else {
+		resp, err = m.retriever.Retrieve(ctx, search)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m.importanceWeight > 0 {
+		resp.Results = m.stats.boost(resp.Results, m.importanceWeight, req.TopK)
+	}
+	if req.ExpandRelations {
+		if resp.Results, err = m.expandResults(ctx, resp.Results); err != nil {
+			return nil, err
+		}
+	}
+	if req.TraverseGraph {
+		if resp.Results, err = m.traverseGraph(ctx, resp.Results); err != nil {
+			return nil, err
+		}
+	}
+	if m.importanceWeight > 0 || req.ExpandRelations || req.TraverseGraph || (req.ContextTemplate != "" && m.llm != nil) {
+		if resp.Context, err = formatContext(resp.Results, req, m.summarizer(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	m.stats.record(resp.Results)
+	return resp, nil
+}
+
+// summarizer returns an LLM-backed summary function for context templates, or nil
+// to fall back to first-sentence summaries when no LLM is configured
+func (m *Module) summarizer(ctx context.Context) summarizeFunc {
+	if m.llm == nil {
+		return nil
+	}
+	return func(content string) (string, error) {
+		resp, err := m.llm.Generate(ctx, llm.GenerateRequest{
+			SystemPrompt: "Summarize the document in one sentence. Respond with the sentence only.",
+			UserPrompt:   content,
+			MaxTokens:    100,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize document: %w", err)
+		}
+		return strings.TrimSpace(resp.Text), nil
+	}
+}
+
+// RetrievalStats returns the module's per-document retrieval counts
+func (m *Module) RetrievalStats() *RetrievalStats {
+	return m.stats
+}
+
+// PopularDocuments returns up to topN documents ordered by how often they have
+// been retrieved, most retrieved first
+func (m *Module) PopularDocuments(ctx context.Context, topN int) ([]Document, error) {
+	ids := m.stats.top(0)
+	docs := make([]Document, 0, len(ids))
+	for _, id := range ids {
+		if topN > 0 && len(docs) == topN {
+			break
+		}
+		doc, err := m.store.Get(ctx, id)
+		if errors.Is(err, ErrDocumentNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document %s: %w", id, err)
+		}
+		docs = append(docs, *doc)
+	}
+	return docs, nil
 }
 
 // Query retrieves documents and returns formatted context
@@ -86,10 +410,126 @@ func (m *Module) GetDocument(ctx context.Context, id string) (*Document, error)
 
 // DeleteDocument removes a document by ID
 func (m *Module) DeleteDocument(ctx context.Context, id string) error {
-	return m.store.Delete(ctx, id)
+	if err := m.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	if m.keywords != nil {
+		m.keywords.Remove(id)
+	}
+	m.stats.forget(id)
+	m.relations.Remove(id)
+	m.graph.RemoveNode(id)
+	return nil
+}
+
+// ListDocuments returns one page of documents ordered by ID. Pages start at 1.
+func (m *Module) ListDocuments(ctx context.Context, page, pageSize int) ([]Document, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("page size must be positive")
+	}
+	return m.store.List(ctx, (page-1)*pageSize, pageSize)
+}
+
+// SearchByMetadata returns all documents whose metadata matches every filter
+func (m *Module) SearchByMetadata(ctx context.Context, filters map[string]string) ([]Document, error) {
+	return m.store.ListByMetadata(ctx, filters, 0, 0)
 }
 
 // Count returns the total number of documents in the knowledge base
 func (m *Module) Count(ctx context.Context) (int, error) {
 	return m.store.Count(ctx)
 }
+
+// PingEmbeddingProvider checks that the embedding provider responds by embedding a single character
+func (m *Module) PingEmbeddingProvider(ctx context.Context) error {
+	if _, err := m.embedder.GenerateEmbedding(ctx, "a"); err != nil {
+		return fmt.Errorf("failed to generate embedding: %w", err)
+	}
+	return nil
+}
+
+// portableStore is implemented by vector stores that can be exported and imported
+type portableStore interface {
+	Export(ctx context.Context, w io.Writer) error
+	Import(ctx context.Context, r io.Reader) error
+}
+
+// ExportKnowledgeBase writes all documents and their embeddings to path as newline-delimited JSON
+func (m *Module) ExportKnowledgeBase(ctx context.Context, path string) error {
+	store, ok := m.store.(portableStore)
+	if !ok {
+		return fmt.Errorf("vector store does not support export")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	if err := store.Export(ctx, file); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to export knowledge base: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close export file: %w", err)
+	}
+	return nil
+}
+
+// ImportKnowledgeBase loads documents written by ExportKnowledgeBase from path.
+// Documents with an existing ID are replaced.
+func (m *Module) ImportKnowledgeBase(ctx context.Context, path string) error {
+	store, ok := m.store.(portableStore)
+	if !ok {
+		return fmt.Errorf("vector store does not support import")
+	}
+
+	// #nosec G304 - path is provided by the caller to restore a knowledge base they exported
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	if err := store.Import(ctx, file); err != nil {
+		return fmt.Errorf("failed to import knowledge base: %w", err)
+	}
+
+	if m.keywords != nil {
+		docs, err := m.store.List(ctx, 0, 0)
+		if err != nil {
+			return fmt.Errorf("failed to index imported documents: %w", err)
+		}
+		m.indexKeywords(docs...)
+	}
+	return nil
+}
+
+// RotateEmbeddingKey replaces the embedding provider's API key without rebuilding the module
+func (m *Module) RotateEmbeddingKey(newKey string) error {
+	rotator, ok := m.embedder.(KeyRotator)
+	if !ok {
+		return fmt.Errorf("%w: embedding provider does not support key rotation", errors.ErrUnsupported)
+	}
+	return rotator.RotateAPIKey(newKey)
+}
+
+// SetHTTPClient replaces the HTTP client used by the embedding provider, if it
+// makes HTTP calls. Embedding calls already under way keep their client.
+func (m *Module) SetHTTPClient(client *http.Client) {
+	if setter, ok := m.embedder.(interface{ SetHTTPClient(*http.Client) }); ok {
+		setter.SetHTTPClient(client)
+	}
+}
+
+// VerifyGrounding checks whether answer is supported by the retrieved context.
+// Requires an LLM client set with WithLLM.
+func (m *Module) VerifyGrounding(ctx context.Context, answer string, context string) (*GroundingResult, error) {
+	if m.llm == nil {
+		return nil, fmt.Errorf("grounding verification requires an LLM client (use WithLLM)")
+	}
+	return NewGroundingVerifier(m.llm).Verify(ctx, &llm.GenerateResponse{Text: answer}, context)
+}
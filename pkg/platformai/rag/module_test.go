@@ -0,0 +1,105 @@
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// newTestModule creates a module backed by the mock embedding provider
+func newTestModule(opts ...Option) *Module {
+	embedder := NewMockEmbeddingProvider(64)
+	store := NewInMemoryVectorStore()
+	m := &Module{
+		embedder:  embedder,
+		store:     store,
+		retriever: NewRetriever(embedder, store),
+		chunker:   NewFixedSizeChunker(1000, 100),
+		stats:     NewRetrievalStats(),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// staticLoader returns its documents
+type staticLoader []Document
+
+func (l staticLoader) Load(ctx context.Context) ([]Document, error) {
+	return l, nil
+}
+
+func TestModule_AddDocumentsFromLoader(t *testing.T) {
+	module := newTestModule()
+	module.chunker = NewFixedSizeChunker(50, 0)
+
+	loader := staticLoader{
+		{ID: "intro", Content: "Kubernetes basics", Metadata: map[string]string{"source": "guide"}},
+		{ID: "scaling", Content: strings.Repeat("autoscaling replicas ", 10), Metadata: map[string]string{"source": "guide"}},
+	}
+
+	ctx := context.Background()
+	n, err := module.AddDocumentsFromLoader(ctx, loader)
+	if err != nil {
+		t.Fatalf("AddDocumentsFromLoader() error = %v", err)
+	}
+
+	count, _ := module.Count(ctx)
+	if n != count || n < 3 {
+		t.Errorf("AddDocumentsFromLoader() = %d, store count = %d, want equal and >= 3", n, count)
+	}
+	if _, err := module.GetDocument(ctx, "scaling#0"); err != nil {
+		t.Errorf("expected chunk scaling#0 to be stored: %v", err)
+	}
+}
+
+// pageLoader returns one document named after each source it loads
+type pageLoader struct{}
+
+func (pageLoader) Load(ctx context.Context, source string) ([]Document, error) {
+	return []Document{{ID: source, Content: "page of " + source}}, nil
+}
+
+func TestModule_AddDocumentsFromSource(t *testing.T) {
+	module := newTestModule()
+	ctx := context.Background()
+
+	n, err := module.AddDocumentsFromLoader(ctx, FromSource(pageLoader{}, "guide.pdf"))
+	if err != nil || n != 1 {
+		t.Fatalf("AddDocumentsFromLoader() = %d, %v; want 1 chunk", n, err)
+	}
+	if _, err := module.GetDocument(ctx, "guide.pdf"); err != nil {
+		t.Errorf("expected document guide.pdf to be stored: %v", err)
+	}
+}
+
+func TestModule_RetrieveNamespace(t *testing.T) {
+	ctx := context.Background()
+	module := newTestModule()
+	tenantA, err := module.Namespace("tenant-a")
+	if err != nil {
+		t.Fatalf("Namespace() error = %v", err)
+	}
+	if err := tenantA.AddDocument(ctx, "runbook", "tenant a database runbook", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		namespace string
+		want      int
+	}{
+		{"", 0},
+		{"tenant-a", 1},
+		{"tenant-b", 0},
+	}
+	for _, tt := range tests {
+		resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "database runbook", TopK: 5, Namespace: tt.namespace})
+		if err != nil {
+			t.Fatalf("Retrieve(namespace %q) error = %v", tt.namespace, err)
+		}
+		if len(resp.Results) != tt.want {
+			t.Errorf("Retrieve(namespace %q) returned %d results, want %d", tt.namespace, len(resp.Results), tt.want)
+		}
+	}
+}
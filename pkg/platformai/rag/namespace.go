@@ -0,0 +1,39 @@
+package rag
+
+import "fmt"
+
+// namespacedStore is implemented by vector stores that partition documents by namespace
+type namespacedStore interface {
+	Namespace(ns string) VectorStore
+}
+
+// Namespace returns a module that shares m's embedding provider and vector
+// store but only sees documents in namespace ns. The new module starts with
+// m's configuration and chunker; options such as hybrid search are not carried
+// over and can be applied with opts. It fails if the store is not partitioned
+// by namespace.
+func (m *Module) Namespace(ns string, opts ...Option) (*Module, error) {
+	store, ok := m.store.(namespacedStore)
+	if !ok {
+		return nil, fmt.Errorf("vector store %T does not support namespaces", m.store)
+	}
+
+	config := m.config
+	config.DefaultNamespace = ns
+	retriever := NewRetriever(m.embedder, store.Namespace(ns))
+	retriever.normalize = m.retriever.normalize
+
+	child := &Module{
+		config:    config,
+		embedder:  m.embedder,
+		store:     retriever.store,
+		retriever: retriever,
+		chunker:   m.chunker,
+		llm:       m.llm,
+		stats:     NewRetrievalStats(),
+	}
+	for _, opt := range opts {
+		opt(child)
+	}
+	return child, nil
+}
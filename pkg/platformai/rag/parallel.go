@@ -0,0 +1,74 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// parallelChunkSize is the number of documents an AddDocumentsParallel worker adds per call
+const parallelChunkSize = 50
+
+// AddDocumentsParallel adds documents like AddDocuments, with workers goroutines
+// each embedding and storing chunks of documents. The first failing chunk cancels
+// the remaining work; chunks already stored are kept. It returns a *MultiError
+// with one error per chunk that was not stored.
+func (m *Module) AddDocumentsParallel(ctx context.Context, docs []struct {
+	ID       string
+	Content  string
+	Metadata map[string]string
+}, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var mu sync.Mutex
+	var failures []error
+	fail := func(chunk []Document, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures = append(failures, fmt.Errorf("failed to add documents %s..%s: %w", chunk[0].ID, chunk[len(chunk)-1].ID, err))
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	chunks := make(chan []Document)
+	g.Go(func() error {
+		defer close(chunks)
+		for start := 0; start < len(docs); start += parallelChunkSize {
+			chunk := make([]Document, 0, parallelChunkSize)
+			for _, doc := range docs[start:min(start+parallelChunkSize, len(docs))] {
+				chunk = append(chunk, Document{ID: doc.ID, Content: doc.Content, Metadata: doc.Metadata})
+			}
+			select {
+			case chunks <- chunk:
+			case <-gctx.Done():
+				fail(chunk, gctx.Err())
+			}
+		}
+		return nil
+	})
+	for range workers {
+		g.Go(func() error {
+			for chunk := range chunks {
+				// Keep draining after cancellation so skipped chunks are reported
+				if err := gctx.Err(); err != nil {
+					fail(chunk, err)
+					continue
+				}
+				if err := m.AddDocuments(gctx, chunk); err != nil {
+					fail(chunk, err)
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	_ = g.Wait() // Every failure is recorded in failures
+
+	if len(failures) > 0 {
+		return &MultiError{Errors: failures}
+	}
+	return nil
+}
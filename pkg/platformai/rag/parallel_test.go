@@ -0,0 +1,86 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// poisonEmbeddingProvider fails every batch containing the word "poison"
+type poisonEmbeddingProvider struct {
+	*MockEmbeddingProvider
+}
+
+func (p poisonEmbeddingProvider) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	for _, text := range texts {
+		if strings.Contains(text, "poison") {
+			return nil, errors.New("embedding rejected")
+		}
+	}
+	return p.MockEmbeddingProvider.GenerateEmbeddings(ctx, texts)
+}
+
+func parallelDocs(n int) []struct {
+	ID       string
+	Content  string
+	Metadata map[string]string
+} {
+	docs := make([]struct {
+		ID       string
+		Content  string
+		Metadata map[string]string
+	}, n)
+	for i := range docs {
+		docs[i].ID = fmt.Sprintf("doc-%03d", i)
+		docs[i].Content = fmt.Sprintf("document number %d about deployments", i)
+	}
+	return docs
+}
+
+func TestModule_AddDocumentsParallel(t *testing.T) {
+	docs := parallelDocs(3*parallelChunkSize + 7)
+
+	for _, workers := range []int{1, 2, 5} {
+		t.Run(fmt.Sprintf("workers %d", workers), func(t *testing.T) {
+			module := newTestModule()
+			if err := module.AddDocumentsParallel(context.Background(), docs, workers); err != nil {
+				t.Fatalf("AddDocumentsParallel() error = %v", err)
+			}
+			count, err := module.Count(context.Background())
+			if err != nil {
+				t.Fatalf("Count() error = %v", err)
+			}
+			if count != len(docs) {
+				t.Errorf("Count() = %d, want %d", count, len(docs))
+			}
+		})
+	}
+}
+
+func TestModule_AddDocumentsParallelFailure(t *testing.T) {
+	docs := parallelDocs(3 * parallelChunkSize)
+	docs[parallelChunkSize].Content = "poison"
+
+	module := newTestModule()
+	module.SetEmbeddingProvider(poisonEmbeddingProvider{NewMockEmbeddingProvider(64)})
+
+	err := module.AddDocumentsParallel(context.Background(), docs, 1)
+	var multi *MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("AddDocumentsParallel() error = %v, want *MultiError", err)
+	}
+	// The poisoned chunk fails and cancels the third, which is reported as skipped
+	if len(multi.Errors) != 2 {
+		t.Errorf("len(MultiError.Errors) = %d, want 2: %v", len(multi.Errors), err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("AddDocumentsParallel() error = %v, want the skipped chunk to report context.Canceled", err)
+	}
+
+	count, _ := module.Count(context.Background())
+	if count != parallelChunkSize {
+		t.Errorf("Count() = %d, want %d documents from the chunk stored before the failure", count, parallelChunkSize)
+	}
+}
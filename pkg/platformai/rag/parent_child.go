@@ -0,0 +1,113 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+)
+
+// ParentDocumentRetriever matches queries against small child chunks but returns
+// the full parent documents, so the LLM sees the surrounding context of a match
+type ParentDocumentRetriever struct {
+	embedder    EmbeddingProvider
+	parentStore VectorStore
+	childStore  VectorStore
+	chunker     Chunker
+}
+
+// NewParentDocumentRetriever creates a retriever storing parents and chunks in separate stores
+func NewParentDocumentRetriever(embedder EmbeddingProvider, parentStore, childStore VectorStore, chunker Chunker) *ParentDocumentRetriever {
+	return &ParentDocumentRetriever{
+		embedder:    embedder,
+		parentStore: parentStore,
+		childStore:  childStore,
+		chunker:     chunker,
+	}
+}
+
+// AddDocument stores the full document in the parent store and its chunks in the child store
+func (r *ParentDocumentRetriever) AddDocument(ctx context.Context, id, content string, metadata map[string]string) error {
+	chunks := r.chunker.Chunk(content)
+	if len(chunks) == 0 {
+		return fmt.Errorf("document content is required")
+	}
+
+	// Embed the parent together with its chunks in one batch call
+	embeddings, err := r.embedder.GenerateEmbeddings(ctx, append([]string{content}, chunks...))
+	if err != nil {
+		return fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+
+	if err := r.parentStore.Add(ctx, Document{
+		ID:        id,
+		Content:   content,
+		Metadata:  metadata,
+		Embedding: embeddings[0],
+	}); err != nil {
+		return fmt.Errorf("failed to add parent document: %w", err)
+	}
+
+	children := make([]Document, len(chunks))
+	for i, chunk := range chunks {
+		children[i] = Document{
+			ID:        fmt.Sprintf("%s#%d", id, i),
+			Content:   chunk,
+			Metadata:  map[string]string{"parent_id": id},
+			Embedding: embeddings[i+1],
+		}
+	}
+	if err := r.childStore.AddBatch(ctx, children); err != nil {
+		return fmt.Errorf("failed to add child chunks: %w", err)
+	}
+
+	return nil
+}
+
+// Retrieve searches the child chunks and returns their parent documents,
+// scored by the best-matching chunk of each parent
+func (r *ParentDocumentRetriever) Retrieve(ctx context.Context, req RetrieveRequest) (*RetrieveResponse, error) {
+	if req.TopK <= 0 {
+		req.TopK = 3
+	}
+
+	queryEmbedding, err := r.embedder.GenerateEmbedding(WithQueryEmbedding(ctx), req.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+	}
+
+	// Unlimited child search: several chunks may belong to the same parent
+	children, err := r.childStore.Search(ctx, queryEmbedding, 0, req.MinScore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search child chunks: %w", err)
+	}
+
+	var results []SearchResult
+	seen := make(map[string]bool)
+	for _, child := range children {
+		parentID := child.Document.Metadata["parent_id"]
+		if seen[parentID] {
+			continue
+		}
+		seen[parentID] = true
+
+		parent, err := r.parentStore.Get(ctx, parentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent document: %w", err)
+		}
+		results = append(results, SearchResult{Document: *parent, Score: child.Score})
+
+		if len(results) == req.TopK {
+			break
+		}
+	}
+
+	context, err := formatContext(results, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RetrieveResponse{
+		Results:        results,
+		Context:        context,
+		QueryEmbedding: queryEmbedding,
+	}, nil
+}
@@ -0,0 +1,64 @@
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParentDocumentRetriever_Retrieve(t *testing.T) {
+	retriever := NewParentDocumentRetriever(
+		NewMockEmbeddingProvider(64),
+		NewInMemoryVectorStore(),
+		NewInMemoryVectorStore(),
+		NewFixedSizeChunker(40, 0),
+	)
+
+	ctx := context.Background()
+	networking := "Services talk over a mesh. " + strings.Repeat("Pods share cluster networking. ", 4) + "Ingress controllers terminate TLS certificates."
+	storage := strings.Repeat("Volumes persist data across restarts. ", 4)
+	if err := retriever.AddDocument(ctx, "networking", networking, map[string]string{"title": "Networking"}); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+	if err := retriever.AddDocument(ctx, "storage", storage, nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+
+	resp, err := retriever.Retrieve(ctx, RetrieveRequest{Query: "ingress TLS certificates", TopK: 1})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Fatalf("Retrieve() = %d results, want 1", len(resp.Results))
+	}
+
+	got := resp.Results[0].Document
+	if got.ID != "networking" || got.Content != networking {
+		t.Errorf("Retrieve() returned %q (%d chars), want full parent (%d chars)", got.ID, len(got.Content), len(networking))
+	}
+	if !strings.Contains(resp.Context, "Title: Networking") {
+		t.Errorf("context should include parent metadata:\n%s", resp.Context)
+	}
+}
+
+func TestParentDocumentRetriever_DeduplicatesParents(t *testing.T) {
+	retriever := NewParentDocumentRetriever(
+		NewMockEmbeddingProvider(64),
+		NewInMemoryVectorStore(),
+		NewInMemoryVectorStore(),
+		NewFixedSizeChunker(30, 0),
+	)
+
+	ctx := context.Background()
+	if err := retriever.AddDocument(ctx, "scaling", strings.Repeat("autoscaler replicas scale ", 6), nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+
+	resp, err := retriever.Retrieve(ctx, RetrieveRequest{Query: "autoscaler replicas", TopK: 5})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Errorf("Retrieve() = %d results, want each parent once", len(resp.Results))
+	}
+}
@@ -0,0 +1,73 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// CoreferenceResolver rewrites text so that pronouns are replaced with what they
+// refer to, which keeps chunks meaningful when they are retrieved in isolation
+type CoreferenceResolver interface {
+	Resolve(ctx context.Context, text string) (string, error)
+}
+
+// maxCoreferenceTokens caps the output tokens requested for one rewritten paragraph
+const maxCoreferenceTokens = 4096
+
+// LLMCoreferenceResolver resolves coreferences by asking the LLM, one paragraph at a time
+type LLMCoreferenceResolver struct {
+	llm llm.Client
+}
+
+// NewLLMCoreferenceResolver creates a new LLM coreference resolver
+func NewLLMCoreferenceResolver(llmClient llm.Client) *LLMCoreferenceResolver {
+	return &LLMCoreferenceResolver{llm: llmClient}
+}
+
+// Resolve rewrites each paragraph of text with its pronouns replaced by their
+// referents. Paragraphs are separated by blank lines, which are preserved.
+func (r *LLMCoreferenceResolver) Resolve(ctx context.Context, text string) (string, error) {
+	systemPrompt := `Rewrite the paragraph, replacing every pronoun with the noun it refers to.
+Change nothing else: keep the wording, order, and formatting.
+Respond with ONLY the rewritten paragraph.`
+
+	paragraphs := strings.Split(text, "\n\n")
+	for i, paragraph := range paragraphs {
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		// The rewrite is about as long as the paragraph; allow twice its tokens
+		resp, err := r.llm.Generate(ctx, llm.GenerateRequest{
+			SystemPrompt: systemPrompt,
+			UserPrompt:   paragraph,
+			MaxTokens:    min(2*llm.HeuristicTokenCounter{}.CountTokens(paragraph)+64, maxCoreferenceTokens),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve coreferences: %w", err)
+		}
+		paragraphs[i] = strings.TrimSpace(resp.Text)
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+// WithCoreferenceResolution rewrites document content with r before it is chunked
+// and embedded, so that chunks like "It handles 100k ops/s" name their subject.
+// It applies to every way of adding documents, including AddDocuments and
+// AddDocumentsStream.
+func WithCoreferenceResolution(r CoreferenceResolver) Option {
+	return func(m *Module) {
+		m.coreference = r
+	}
+}
+
+// resolveCoreferences returns content rewritten by the configured
+// CoreferenceResolver, or content unchanged without one
+func (m *Module) resolveCoreferences(ctx context.Context, content string) (string, error) {
+	if m.coreference == nil {
+		return content, nil
+	}
+	return m.coreference.Resolve(ctx, content)
+}
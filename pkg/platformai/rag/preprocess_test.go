@@ -0,0 +1,141 @@
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// cannedResolutions answers coreference prompts from a fixed table of paragraphs
+func cannedResolutions(resolved map[string]string) *llm.MockClient {
+	mock := llm.NewMockClient("")
+	mock.GenerateFunc = func(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+		text, ok := resolved[req.UserPrompt]
+		if !ok {
+			text = req.UserPrompt
+		}
+		return &llm.GenerateResponse{Text: text + "\n"}, nil
+	}
+	return mock
+}
+
+func TestLLMCoreferenceResolver_Resolve(t *testing.T) {
+	mock := cannedResolutions(map[string]string{
+		"Redis is fast. It handles 100k ops/s": "Redis is fast. Redis handles 100k ops/s",
+		"Postgres is durable. It fsyncs.":      "Postgres is durable. Postgres fsyncs.",
+	})
+	resolver := NewLLMCoreferenceResolver(mock)
+
+	got, err := resolver.Resolve(context.Background(), "Redis is fast. It handles 100k ops/s")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if want := "Redis is fast. Redis handles 100k ops/s"; got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+
+	got, err = resolver.Resolve(context.Background(), "Redis is fast. It handles 100k ops/s\n\n\n\nPostgres is durable. It fsyncs.")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if want := "Redis is fast. Redis handles 100k ops/s\n\n\n\nPostgres is durable. Postgres fsyncs."; got != want {
+		t.Errorf("Resolve() = %q, want %q", got, want)
+	}
+	// One call per non-empty paragraph, plus the first Resolve
+	if n := len(mock.Requests()); n != 3 {
+		t.Errorf("LLM requests = %d, want 3", n)
+	}
+
+	// Output tokens follow the paragraph's token count, up to a fixed cap
+	if _, err := resolver.Resolve(context.Background(), strings.Repeat("word ", 10000)); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	requests := mock.Requests()
+	short := "Redis is fast. It handles 100k ops/s"
+	if got, want := requests[0].MaxTokens, 2*(llm.HeuristicTokenCounter{}).CountTokens(short)+64; got != want {
+		t.Errorf("MaxTokens for a short paragraph = %d, want %d", got, want)
+	}
+	if got := requests[len(requests)-1].MaxTokens; got != maxCoreferenceTokens {
+		t.Errorf("MaxTokens for a long paragraph = %d, want %d", got, maxCoreferenceTokens)
+	}
+}
+
+func TestModule_WithCoreferenceResolution(t *testing.T) {
+	resolver := NewLLMCoreferenceResolver(cannedResolutions(map[string]string{
+		"Redis is fast. It handles 100k ops/s": "Redis is fast. Redis handles 100k ops/s",
+	}))
+	m := newTestModule(WithCoreferenceResolution(resolver))
+	ctx := context.Background()
+
+	if err := m.AddDocument(ctx, "redis", "Redis is fast. It handles 100k ops/s", nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+	doc, err := m.GetDocument(ctx, "redis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Content != "Redis is fast. Redis handles 100k ops/s" {
+		t.Errorf("stored content = %q, want resolved content", doc.Content)
+	}
+
+	loader := staticLoader{{ID: "guide", Content: "Redis is fast. It handles 100k ops/s"}}
+	if _, err := m.AddDocumentsFromLoader(ctx, loader); err != nil {
+		t.Fatalf("AddDocumentsFromLoader() error = %v", err)
+	}
+	docs, err := m.store.List(ctx, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range docs {
+		if strings.Contains(doc.Content, "It handles") {
+			t.Errorf("chunk %s was not resolved: %q", doc.ID, doc.Content)
+		}
+	}
+}
+
+func TestModule_WithCoreferenceResolutionBatch(t *testing.T) {
+	resolver := NewLLMCoreferenceResolver(cannedResolutions(map[string]string{
+		"Redis is fast. It handles 100k ops/s": "Redis is fast. Redis handles 100k ops/s",
+	}))
+	m := newTestModule(WithCoreferenceResolution(resolver))
+	ctx := context.Background()
+
+	input := []Document{{ID: "batch", Content: "Redis is fast. It handles 100k ops/s"}}
+	if err := m.AddDocuments(ctx, input); err != nil {
+		t.Fatalf("AddDocuments() error = %v", err)
+	}
+	if input[0].Content != "Redis is fast. It handles 100k ops/s" {
+		t.Errorf("AddDocuments() modified the caller's document: %q", input[0].Content)
+	}
+
+	stream := make(chan struct {
+		ID       string
+		Content  string
+		Metadata map[string]string
+	}, 1)
+	stream <- struct {
+		ID       string
+		Content  string
+		Metadata map[string]string
+	}{ID: "stream", Content: "Redis is fast. It handles 100k ops/s"}
+	close(stream)
+	errs, err := m.AddDocumentsStream(ctx, stream)
+	if err != nil {
+		t.Fatalf("AddDocumentsStream() error = %v", err)
+	}
+	for err := range errs {
+		t.Errorf("AddDocumentsStream() reported error: %v", err)
+	}
+
+	for _, id := range []string{"batch", "stream"} {
+		doc, err := m.GetDocument(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.Content != "Redis is fast. Redis handles 100k ops/s" {
+			t.Errorf("stored content of %s = %q, want resolved content", id, doc.Content)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Reconfigure returns a module for config that keeps m's documents, relations,
+// and graph edges. The vector store is shared while the embedding and store
+// settings are unchanged. Otherwise the documents are copied to the new module's
+// store, and re-embedded with its provider when the provider, model, dimensions,
+// or normalization changed. m is left as it was, so calls in flight on it are unaffected.
+func (m *Module) Reconfigure(ctx context.Context, config Config, opts ...Option) (*Module, error) {
+	next, err := NewModule(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	reembed := embeddingChanged(m.config, next.config)
+	if !reembed && !storeChanged(m.config, next.config) {
+		next.store, next.retriever.store = m.store, m.store
+	}
+
+	total, err := m.store.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+	for offset := 0; offset < total; offset += reembedBatchSize {
+		docs, err := m.store.List(ctx, offset, reembedBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list documents: %w", err)
+		}
+		if len(docs) == 0 {
+			break
+		}
+		if next.store != m.store {
+			if err := next.copyDocuments(ctx, docs, reembed); err != nil {
+				return nil, err
+			}
+		}
+		next.indexKeywords(docs...)
+	}
+	next.relations.copyFrom(&m.relations)
+	next.graph.copyFrom(&m.graph)
+	return next, nil
+}
+
+// copyDocuments adds docs to m's store, first replacing their embeddings with
+// m's provider when reembed is set
+func (m *Module) copyDocuments(ctx context.Context, docs []Document, reembed bool) error {
+	if reembed {
+		contents := make([]string, len(docs))
+		for i, doc := range docs {
+			contents[i] = doc.Content
+		}
+		embeddings, err := m.embedder.GenerateEmbeddings(ctx, contents)
+		if err != nil {
+			return fmt.Errorf("failed to generate embeddings: %w", err)
+		}
+		if len(embeddings) != len(docs) {
+			return fmt.Errorf("embedding provider returned %d embeddings for %d documents", len(embeddings), len(docs))
+		}
+		for i := range docs {
+			docs[i].Embedding = m.retriever.prepareEmbedding(embeddings[i])
+		}
+	}
+	if err := m.store.AddBatch(ctx, docs); err != nil {
+		return fmt.Errorf("failed to copy documents: %w", err)
+	}
+	return nil
+}
+
+// embeddingChanged reports whether documents embedded under a must be
+// re-embedded to be searched under b
+func embeddingChanged(a, b Config) bool {
+	return a.EmbeddingProvider != b.EmbeddingProvider ||
+		a.Model != b.Model ||
+		a.EmbeddingDim != b.EmbeddingDim ||
+		a.NormalizeEmbeddings != b.NormalizeEmbeddings
+}
+
+// storeChanged reports whether a and b configure the vector store differently
+func storeChanged(a, b Config) bool {
+	return metricOrDefault(a.SimilarityMetric) != metricOrDefault(b.SimilarityMetric) ||
+		a.DefaultNamespace != b.DefaultNamespace ||
+		!reflect.DeepEqual(a.Freshness, b.Freshness)
+}
+
+// metricOrDefault returns metric, or CosineSimilarity when it is unset
+func metricOrDefault(metric SimilarityMetric) SimilarityMetric {
+	if metric == "" {
+		return CosineSimilarity
+	}
+	return metric
+}
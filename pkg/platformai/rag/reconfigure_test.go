@@ -0,0 +1,143 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newReconfigurableModule returns a module created from config whose provider
+// is replaced by a mock, holding n documents
+func newReconfigurableModule(t *testing.T, config Config, n int) *Module {
+	t.Helper()
+	m, err := NewModule(config)
+	if err != nil {
+		t.Fatalf("NewModule() error = %v", err)
+	}
+	m.SetEmbeddingProvider(NewMockEmbeddingProvider(4))
+	for i := 0; i < n; i++ {
+		if err := m.AddDocument(context.Background(), fmt.Sprintf("doc-%d", i), fmt.Sprintf("document number %d", i), nil); err != nil {
+			t.Fatalf("AddDocument() error = %v", err)
+		}
+	}
+	return m
+}
+
+func TestModule_Reconfigure(t *testing.T) {
+	ctx := context.Background()
+	base := Config{EmbeddingProvider: "openai", APIKey: "test", Model: "text-embedding-3-small"}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		shared bool
+	}{
+		{"timeout", func(c *Config) { c.Timeout = time.Minute }, true},
+		{"explicit default metric", func(c *Config) { c.SimilarityMetric = CosineSimilarity }, true},
+		{"metric", func(c *Config) { c.SimilarityMetric = DotProduct }, false},
+		{"freshness", func(c *Config) { c.Freshness = FreshnessConfig{MaxAge: time.Hour, AgeScorePenalty: 0.5} }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newReconfigurableModule(t, base, 3)
+			config := base
+			tt.modify(&config)
+
+			next, err := m.Reconfigure(ctx, config, WithHybridSearch(0.5))
+			if err != nil {
+				t.Fatalf("Reconfigure() error = %v", err)
+			}
+			if shared := next.store == m.store; shared != tt.shared {
+				t.Errorf("store shared = %v, want %v", shared, tt.shared)
+			}
+			if n, _ := next.Count(ctx); n != 3 {
+				t.Errorf("Count() after Reconfigure = %d, want 3", n)
+			}
+			before, _ := m.GetDocument(ctx, "doc-1")
+			after, err := next.GetDocument(ctx, "doc-1")
+			if err != nil || !reflect.DeepEqual(after.Embedding, before.Embedding) {
+				t.Errorf("GetDocument() = %+v, %v; want the original document", after, err)
+			}
+			if len(next.keywords.docs) != 3 {
+				t.Errorf("keyword index has %d documents, want 3", len(next.keywords.docs))
+			}
+		})
+	}
+}
+
+func TestModule_ReconfigureKeepsGraphs(t *testing.T) {
+	ctx := context.Background()
+	base := Config{EmbeddingProvider: "openai", APIKey: "test", Model: "text-embedding-3-small"}
+	m := newReconfigurableModule(t, base, 3)
+	if err := m.AddRelation(ctx, "doc-0", "doc-1", 0.5); err != nil {
+		t.Fatal(err)
+	}
+	m.Graph().AddEdge("doc-1", "doc-2", EdgeCites)
+
+	config := base
+	config.Timeout = time.Minute
+	next, err := m.Reconfigure(ctx, config)
+	if err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if got := next.relations.Relations("doc-0"); len(got) != 1 || got[0] != (Relation{ToID: "doc-1", Weight: 0.5}) {
+		t.Errorf("Relations() after Reconfigure = %+v, want doc-1 at 0.5", got)
+	}
+	if got := next.Graph().Neighbors("doc-1"); len(got) != 1 || got[0].ToID != "doc-2" || got[0].Type != EdgeCites {
+		t.Errorf("Neighbors() after Reconfigure = %+v, want doc-1 cites doc-2", got)
+	}
+
+	// The graphs are copies, so changing the new module leaves the old one alone
+	next.Graph().AddEdge("doc-0", "doc-2", EdgeSeeAlso)
+	if got := m.Graph().Neighbors("doc-0"); len(got) != 0 {
+		t.Errorf("old module Neighbors() = %+v, want none", got)
+	}
+}
+
+func TestModule_ReconfigureReembed(t *testing.T) {
+	ctx := context.Background()
+	var embedded atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		embedded.Add(1)
+	}))
+	defer server.Close()
+
+	base := Config{EmbeddingProvider: "openai", APIKey: "test", Model: "text-embedding-3-small"}
+	m := newReconfigurableModule(t, base, 3)
+
+	// The new model's embeddings cannot be generated, so the old module stays in use
+	config := base
+	config.Model = "text-embedding-3-large"
+	config.ProxyURL = server.URL
+	if _, err := m.Reconfigure(ctx, config); err == nil {
+		t.Fatal("Reconfigure() expected error when re-embedding fails")
+	}
+	if embedded.Load() == 0 {
+		t.Error("Reconfigure() did not re-embed documents after a model change")
+	}
+	if n, _ := m.Count(ctx); n != 3 {
+		t.Errorf("Count() of the old module = %d, want 3", n)
+	}
+}
+
+func TestModule_CopyDocumentsReembed(t *testing.T) {
+	ctx := context.Background()
+	want := []float32{0.5, 0.5, 0.5, 0.5}
+	store := NewInMemoryVectorStore()
+	m := &Module{embedder: constantEmbeddingProvider(want), store: store, retriever: NewRetriever(constantEmbeddingProvider(want), store)}
+
+	docs := []Document{{ID: "a", Content: "alpha", Embedding: []float32{1, 0}}}
+	if err := m.copyDocuments(ctx, docs, true); err != nil {
+		t.Fatalf("copyDocuments() error = %v", err)
+	}
+	doc, err := store.Get(ctx, "a")
+	if err != nil || !reflect.DeepEqual(doc.Embedding, want) {
+		t.Errorf("Get() = %+v, %v; want re-embedded document", doc, err)
+	}
+}
@@ -0,0 +1,87 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// reembedBatchSize is the number of documents ReembedAll embeds per provider call
+const reembedBatchSize = 100
+
+// ReembedOptions configures Module.ReembedAllWithOptions
+type ReembedOptions struct {
+	// OnProgress is called after each batch with the number of documents re-embedded so far
+	OnProgress func(done, total int)
+
+	// Logger receives warnings (default: log.Default())
+	Logger interface{ Printf(format string, v ...any) }
+}
+
+// SetEmbeddingProvider replaces the provider used for new documents and queries.
+// Documents already stored keep their old embeddings until ReembedAll is called.
+func (m *Module) SetEmbeddingProvider(provider EmbeddingProvider) {
+	m.embedder = provider
+	m.retriever.embedder = provider
+}
+
+// ReembedAll regenerates the embedding of every stored document with the current
+// embedding provider, for example after switching providers. It returns the
+// number of documents updated.
+func (m *Module) ReembedAll(ctx context.Context) (int, error) {
+	return m.ReembedAllWithOptions(ctx, ReembedOptions{})
+}
+
+// ReembedAllWithOptions is like ReembedAll with progress reporting
+func (m *Module) ReembedAllWithOptions(ctx context.Context, opts ReembedOptions) (int, error) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	total, err := m.store.Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	done := 0
+	warned := false
+	for done < total {
+		docs, err := m.store.List(ctx, done, reembedBatchSize)
+		if err != nil {
+			return done, fmt.Errorf("failed to list documents: %w", err)
+		}
+		if len(docs) == 0 {
+			break
+		}
+
+		contents := make([]string, len(docs))
+		for i, doc := range docs {
+			contents[i] = doc.Content
+		}
+		embeddings, err := m.embedder.GenerateEmbeddings(ctx, contents)
+		if err != nil {
+			return done, fmt.Errorf("failed to generate embeddings: %w", err)
+		}
+		if len(embeddings) != len(docs) {
+			return done, fmt.Errorf("embedding provider returned %d embeddings for %d documents", len(embeddings), len(docs))
+		}
+
+		for i, doc := range docs {
+			if !warned && len(doc.Embedding) > 0 && len(embeddings[i]) != len(doc.Embedding) {
+				logger.Printf("rag: warning: new embeddings have %d dimensions, stored documents have %d; the store will reject them",
+					len(embeddings[i]), len(doc.Embedding))
+				warned = true
+			}
+			doc.Embedding = m.retriever.prepareEmbedding(embeddings[i])
+			if err := m.store.Update(ctx, doc); err != nil {
+				return done, fmt.Errorf("failed to update document %s: %w", doc.ID, err)
+			}
+			done++
+		}
+		if opts.OnProgress != nil {
+			opts.OnProgress(done, total)
+		}
+	}
+	return done, nil
+}
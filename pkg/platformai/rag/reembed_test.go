@@ -0,0 +1,100 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// constantEmbeddingProvider returns the same embedding for every text
+type constantEmbeddingProvider []float32
+
+func (p constantEmbeddingProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	return p, nil
+}
+
+func (p constantEmbeddingProvider) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i := range texts {
+		embeddings[i] = p
+	}
+	return embeddings, nil
+}
+
+func (p constantEmbeddingProvider) GenerateEmbeddingsStream(ctx context.Context, texts <-chan string) (<-chan EmbeddingResult, error) {
+	return streamEmbeddings(ctx, texts, 0, p.GenerateEmbeddings)
+}
+
+// recordingLogger keeps every formatted message
+type recordingLogger struct{ messages []string }
+
+func (l *recordingLogger) Printf(format string, v ...any) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestModule_ReembedAll(t *testing.T) {
+	ctx := context.Background()
+	embedder := NewMockEmbeddingProvider(4)
+	store := NewInMemoryVectorStore()
+	m := &Module{embedder: embedder, store: store, retriever: NewRetriever(embedder, store), stats: NewRetrievalStats()}
+	for i := 0; i < 10; i++ {
+		if err := m.AddDocument(ctx, fmt.Sprintf("doc-%d", i), fmt.Sprintf("document number %d", i), nil); err != nil {
+			t.Fatalf("AddDocument() error = %v", err)
+		}
+	}
+
+	want := []float32{0.5, 0.5, 0.5, 0.5}
+	m.SetEmbeddingProvider(constantEmbeddingProvider(want))
+
+	var progress [][2]int
+	logger := &recordingLogger{}
+	n, err := m.ReembedAllWithOptions(ctx, ReembedOptions{
+		OnProgress: func(done, total int) { progress = append(progress, [2]int{done, total}) },
+		Logger:     logger,
+	})
+	if err != nil {
+		t.Fatalf("ReembedAll() error = %v", err)
+	}
+	if n != 10 {
+		t.Errorf("ReembedAll() = %d, want 10", n)
+	}
+	if !reflect.DeepEqual(progress, [][2]int{{10, 10}}) {
+		t.Errorf("progress = %v, want [[10 10]]", progress)
+	}
+	if len(logger.messages) != 0 {
+		t.Errorf("unexpected warnings: %v", logger.messages)
+	}
+
+	docs, err := store.List(ctx, 0, 0)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	for _, doc := range docs {
+		if !reflect.DeepEqual(doc.Embedding, want) {
+			t.Errorf("document %s embedding = %v, want %v", doc.ID, doc.Embedding, want)
+		}
+	}
+}
+
+func TestModule_ReembedAllDimensionMismatch(t *testing.T) {
+	ctx := context.Background()
+	embedder := NewMockEmbeddingProvider(4)
+	store := NewInMemoryVectorStore()
+	m := &Module{embedder: embedder, store: store, retriever: NewRetriever(embedder, store), stats: NewRetrievalStats()}
+	if err := m.AddDocument(ctx, "doc", "some content", nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+
+	m.SetEmbeddingProvider(NewMockEmbeddingProvider(8))
+	logger := &recordingLogger{}
+	_, err := m.ReembedAllWithOptions(ctx, ReembedOptions{Logger: logger})
+	if !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ReembedAll() error = %v, want ErrDimensionMismatch", err)
+	}
+	if len(logger.messages) != 1 || !strings.Contains(logger.messages[0], "8 dimensions") {
+		t.Errorf("warnings = %v, want one dimension warning", logger.messages)
+	}
+}
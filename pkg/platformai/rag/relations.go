@@ -0,0 +1,182 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RelationGraph is a weighted, directed adjacency list of document relations.
+// The zero value is an empty graph ready to use.
+type RelationGraph struct {
+	mu    sync.RWMutex
+	edges map[string]map[string]float32
+}
+
+// Relation is an edge of the relation graph
+type Relation struct {
+	ToID   string
+	Weight float32
+}
+
+// Add records a relation from fromID to toID, replacing the weight of an existing one
+func (g *RelationGraph) Add(fromID, toID string, weight float32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.edges == nil {
+		g.edges = make(map[string]map[string]float32)
+	}
+	if g.edges[fromID] == nil {
+		g.edges[fromID] = make(map[string]float32)
+	}
+	g.edges[fromID][toID] = weight
+}
+
+// Remove drops a document and every relation to or from it
+func (g *RelationGraph) Remove(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.edges, id)
+	for _, to := range g.edges {
+		delete(to, id)
+	}
+}
+
+// Relations returns the relations from id, highest weight first
+func (g *RelationGraph) Relations(id string) []Relation {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	relations := make([]Relation, 0, len(g.edges[id]))
+	for to, weight := range g.edges[id] {
+		relations = append(relations, Relation{ToID: to, Weight: weight})
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		if relations[i].Weight != relations[j].Weight {
+			return relations[i].Weight > relations[j].Weight
+		}
+		return relations[i].ToID < relations[j].ToID
+	})
+	return relations
+}
+
+// copyFrom adds every relation of other to g
+func (g *RelationGraph) copyFrom(other *RelationGraph) {
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	for fromID, to := range other.edges {
+		for toID, weight := range to {
+			g.Add(fromID, toID, weight)
+		}
+	}
+}
+
+// AddRelation records that the document fromID references toID. Both documents
+// must exist; weight must be positive.
+func (m *Module) AddRelation(ctx context.Context, fromID, toID string, weight float32) error {
+	if weight <= 0 {
+		return fmt.Errorf("relation weight must be positive, got %v", weight)
+	}
+	if fromID == toID {
+		return fmt.Errorf("document %s cannot relate to itself", fromID)
+	}
+	for _, id := range []string{fromID, toID} {
+		if _, err := m.store.Get(ctx, id); err != nil {
+			return err
+		}
+	}
+	m.relations.Add(fromID, toID, weight)
+	return nil
+}
+
+// GraphExpand walks the relation graph breadth-first from seedIDs up to depth
+// hops and returns the documents reached, nearest first, excluding the seeds.
+// Related documents that no longer exist are skipped.
+func (m *Module) GraphExpand(ctx context.Context, seedIDs []string, depth int) ([]Document, error) {
+	visited := make(map[string]bool, len(seedIDs))
+	for _, id := range seedIDs {
+		visited[id] = true
+	}
+
+	var docs []Document
+	frontier := seedIDs
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, id := range frontier {
+			for _, rel := range m.relations.Relations(id) {
+				if visited[rel.ToID] {
+					continue
+				}
+				visited[rel.ToID] = true
+
+				doc, err := m.relatedDocument(ctx, rel.ToID)
+				if err != nil {
+					return nil, err
+				}
+				if doc == nil {
+					continue
+				}
+				docs = append(docs, *doc)
+				next = append(next, rel.ToID)
+			}
+		}
+		frontier = next
+	}
+	return docs, nil
+}
+
+// expandResults appends the documents one relation hop from results that are not already in them
+func (m *Module) expandResults(ctx context.Context, results []SearchResult) ([]SearchResult, error) {
+	return m.expandOneHop(ctx, results, m.relations.Relations)
+}
+
+// expandOneHop appends the documents one hop along neighbors from results that are
+// not already in them, scored by the linking result's score times the relation weight
+func (m *Module) expandOneHop(ctx context.Context, results []SearchResult, neighbors func(id string) []Relation) ([]SearchResult, error) {
+	seen := make(map[string]bool, len(results))
+	for _, result := range results {
+		seen[result.Document.ID] = true
+	}
+
+	expanded := results
+	for _, result := range results {
+		for _, rel := range neighbors(result.Document.ID) {
+			if seen[rel.ToID] {
+				continue
+			}
+			seen[rel.ToID] = true
+
+			doc, err := m.relatedDocument(ctx, rel.ToID)
+			if err != nil {
+				return nil, err
+			}
+			if doc == nil {
+				continue
+			}
+			expanded = append(expanded, SearchResult{Document: *doc, Score: result.Score * rel.Weight})
+		}
+	}
+	return expanded, nil
+}
+
+// relatedDocument loads a document with RelatedIDs filled in from the graph, or
+// returns nil if it no longer exists
+func (m *Module) relatedDocument(ctx context.Context, id string) (*Document, error) {
+	doc, err := m.store.Get(ctx, id)
+	if errors.Is(err, ErrDocumentNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load related document %s: %w", id, err)
+	}
+	doc.RelatedIDs = nil
+	for _, rel := range m.relations.Relations(id) {
+		doc.RelatedIDs = append(doc.RelatedIDs, rel.ToID)
+	}
+	return doc, nil
+}
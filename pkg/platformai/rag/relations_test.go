@@ -0,0 +1,103 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestModule_GraphExpand(t *testing.T) {
+	ctx := context.Background()
+	m := newTestModule()
+	docs := []Document{
+		{ID: "k8s", Content: "kubernetes deployment guide", RelatedIDs: []string{"networking"}},
+		{ID: "networking", Content: "cluster networking and ingress"},
+		{ID: "dns", Content: "service discovery with dns"},
+		{ID: "tls", Content: "certificates for ingress"},
+		{ID: "storage", Content: "persistent volumes"},
+	}
+	if err := m.AddDocuments(ctx, docs); err != nil {
+		t.Fatalf("AddDocuments() error = %v", err)
+	}
+	for _, rel := range []struct {
+		from, to string
+		weight   float32
+	}{
+		{"k8s", "storage", 0.5},
+		{"networking", "dns", 0.8},
+		{"dns", "tls", 1},
+	} {
+		if err := m.AddRelation(ctx, rel.from, rel.to, rel.weight); err != nil {
+			t.Fatalf("AddRelation(%s, %s) error = %v", rel.from, rel.to, err)
+		}
+	}
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{0, nil},
+		{1, []string{"networking", "storage"}},
+		{2, []string{"networking", "storage", "dns"}},
+		{3, []string{"networking", "storage", "dns", "tls"}},
+	}
+	for _, tt := range tests {
+		got, err := m.GraphExpand(ctx, []string{"k8s"}, tt.depth)
+		if err != nil {
+			t.Fatalf("GraphExpand(depth %d) error = %v", tt.depth, err)
+		}
+		var ids []string
+		for _, doc := range got {
+			ids = append(ids, doc.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("GraphExpand(depth %d) = %v, want %v", tt.depth, ids, tt.want)
+		}
+	}
+
+	if err := m.AddRelation(ctx, "k8s", "missing", 1); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("AddRelation() error = %v, want ErrDocumentNotFound", err)
+	}
+
+	if err := m.DeleteDocument(ctx, "dns"); err != nil {
+		t.Fatalf("DeleteDocument() error = %v", err)
+	}
+	got, err := m.GraphExpand(ctx, []string{"networking"}, 2)
+	if err != nil {
+		t.Fatalf("GraphExpand() error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("GraphExpand() after delete = %v, want none", got)
+	}
+}
+
+func TestModule_RetrieveExpandRelations(t *testing.T) {
+	ctx := context.Background()
+	m := newTestModule()
+	if err := m.AddDocuments(ctx, []Document{
+		{ID: "k8s", Content: "kubernetes deployment guide", RelatedIDs: []string{"networking"}},
+		{ID: "networking", Content: "cluster networking and ingress"},
+	}); err != nil {
+		t.Fatalf("AddDocuments() error = %v", err)
+	}
+
+	resp, err := m.Retrieve(ctx, RetrieveRequest{Query: "kubernetes deployment", TopK: 1, ExpandRelations: true})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Document.ID != "k8s" || resp.Results[1].Document.ID != "networking" {
+		t.Fatalf("Retrieve() results = %+v, want k8s expanded to networking", resp.Results)
+	}
+	if resp.Results[1].Score != resp.Results[0].Score {
+		t.Errorf("expanded score = %v, want %v (weight 1)", resp.Results[1].Score, resp.Results[0].Score)
+	}
+
+	resp, err = m.Retrieve(ctx, RetrieveRequest{Query: "kubernetes deployment", TopK: 1})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Errorf("Retrieve() without expansion returned %d results, want 1", len(resp.Results))
+	}
+}
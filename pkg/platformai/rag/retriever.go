@@ -3,13 +3,20 @@ package rag
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
 )
 
 // Retriever handles document retrieval and context formatting
 type Retriever struct {
 	embedder EmbeddingProvider
 	store    VectorStore
+	rewriter QueryRewriter // Optional, applied to queries before embedding
+
+	normalize bool // L2-normalize document and query embeddings
 }
 
 // NewRetriever creates a new retriever
@@ -27,56 +34,145 @@ func (r *Retriever) Retrieve(ctx context.Context, req RetrieveRequest) (*Retriev
 		req.TopK = 3
 	}
 
+	query, err := r.rewriteQuery(ctx, req.Query)
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate query embedding
-	queryEmbedding, err := r.embedder.GenerateEmbedding(ctx, req.Query)
+	queryEmbedding, err := r.embedder.GenerateEmbedding(WithQueryEmbedding(ctx), query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate query embedding: %w", err)
 	}
+	queryEmbedding = r.prepareEmbedding(queryEmbedding)
 
 	// Search for similar documents
-	results, err := r.store.Search(ctx, queryEmbedding, req.TopK, req.MinScore)
+	results, err := r.store.SearchWithFilter(ctx, queryEmbedding, req.TopK, req.MinScore, req.Filters)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search documents: %w", err)
 	}
 
 	// Format context for LLM
-	context := r.formatContext(results)
+	context, err := formatContext(results, req, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	return &RetrieveResponse{
+	resp := &RetrieveResponse{
 		Results:        results,
 		Context:        context,
 		QueryEmbedding: queryEmbedding,
-	}, nil
+	}
+	if r.rewriter != nil {
+		resp.RewrittenQuery = query
+	}
+	return resp, nil
+}
+
+// prepareEmbedding L2-normalizes v when normalization is enabled
+func (r *Retriever) prepareEmbedding(v []float32) []float32 {
+	if !r.normalize {
+		return v
+	}
+	return normalize(v)
 }
 
-// formatContext formats search results into a context string for the LLM
-func (r *Retriever) formatContext(results []SearchResult) string {
+// rewriteQuery applies the configured QueryRewriter, returning query unchanged without one
+func (r *Retriever) rewriteQuery(ctx context.Context, query string) (string, error) {
+	if r.rewriter == nil {
+		return query, nil
+	}
+	rewritten, err := r.rewriter.Rewrite(ctx, query)
+	if err != nil {
+		return "", fmt.Errorf("failed to rewrite query: %w", err)
+	}
+	return rewritten, nil
+}
+
+// formatContext formats search results into a context string for the LLM.
+// A ContextTemplate in req replaces the built-in per-document format. With citations,
+// documents are numbered [1], [2], ... and listed in a trailing references section
+// so answers can cite them.
+func formatContext(results []SearchResult, req RetrieveRequest, summarize summarizeFunc) (string, error) {
 	if len(results) == 0 {
-		return ""
+		return "", nil
 	}
 
 	var builder strings.Builder
-	builder.WriteString("Relevant context from knowledge base:\n\n")
-
-	for i, result := range results {
-		builder.WriteString(fmt.Sprintf("--- Document %d (Relevance: %.2f) ---\n", i+1, result.Score))
+	if req.ContextTemplate != "" {
+		body, err := executeContextTemplate(req.ContextTemplate, results, summarize)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(body)
+	} else {
+		builder.WriteString("Relevant context from knowledge base:\n\n")
 
-		// Add metadata if available
-		if len(result.Document.Metadata) > 0 {
-			if title, ok := result.Document.Metadata["title"]; ok {
-				builder.WriteString(fmt.Sprintf("Title: %s\n", title))
+		for i, result := range results {
+			if req.WithCitations {
+				builder.WriteString(fmt.Sprintf("--- [%d] (Relevance: %.2f) ---\n", i+1, result.Score))
+			} else {
+				builder.WriteString(fmt.Sprintf("--- Document %d (Relevance: %.2f) ---\n", i+1, result.Score))
 			}
-			if source, ok := result.Document.Metadata["source"]; ok {
-				builder.WriteString(fmt.Sprintf("Source: %s\n", source))
+
+			// Add metadata if available
+			if len(result.Document.Metadata) > 0 {
+				if title, ok := result.Document.Metadata["title"]; ok {
+					builder.WriteString(fmt.Sprintf("Title: %s\n", title))
+				}
+				if source, ok := result.Document.Metadata["source"]; ok {
+					builder.WriteString(fmt.Sprintf("Source: %s\n", source))
+				}
 			}
+
+			builder.WriteString("\n")
+			builder.WriteString(result.Document.Content)
+			builder.WriteString("\n\n")
 		}
+	}
 
-		builder.WriteString("\n")
-		builder.WriteString(result.Document.Content)
-		builder.WriteString("\n\n")
+	if req.WithCitations {
+		builder.WriteString(llm.CitationReferencesHeader + "\n")
+		for i, result := range results {
+			builder.WriteString(fmt.Sprintf("[%d] %s\n", i+1, citationReference(result.Document)))
+		}
 	}
 
-	return builder.String()
+	return builder.String(), nil
+}
+
+// citationReference identifies a document in the references section as "id" or "id: title"
+func citationReference(doc Document) string {
+	if title := doc.Metadata["title"]; title != "" {
+		return doc.ID + ": " + title
+	}
+	return doc.ID
+}
+
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// ParseCitations extracts the [N] markers from answer and maps each number to its
+// entry in the references section of the citation-formatted context the answer was
+// generated from. Numbers without a reference map to an empty string.
+func ParseCitations(answer, context string) map[int]string {
+	references := make(map[int]string)
+	if i := strings.LastIndex(context, llm.CitationReferencesHeader+"\n"); i >= 0 {
+		for _, line := range strings.Split(context[i+len(llm.CitationReferencesHeader)+1:], "\n") {
+			match := citationPattern.FindStringSubmatchIndex(line)
+			if match == nil || match[0] != 0 {
+				continue
+			}
+			n, _ := strconv.Atoi(line[match[2]:match[3]])
+			references[n] = strings.TrimSpace(line[match[1]:])
+		}
+	}
+
+	citations := make(map[int]string)
+	for _, match := range citationPattern.FindAllStringSubmatch(answer, -1) {
+		n, _ := strconv.Atoi(match[1])
+		citations[n] = references[n]
+	}
+	return citations
 }
 
 // AddDocument adds a document to the retriever's store with automatic embedding
@@ -92,7 +188,8 @@ func (r *Retriever) AddDocument(ctx context.Context, id, content string, metadat
 		ID:        id,
 		Content:   content,
 		Metadata:  metadata,
-		Embedding: embedding,
+		Embedding: r.prepareEmbedding(embedding),
+		Language:  metadata[LanguageMetadataKey],
 	}
 
 	// Add to store
@@ -103,6 +200,26 @@ func (r *Retriever) AddDocument(ctx context.Context, id, content string, metadat
 	return nil
 }
 
+// UpdateDocument re-embeds the content and replaces the existing document
+func (r *Retriever) UpdateDocument(ctx context.Context, id, content string, metadata map[string]string) error {
+	embedding, err := r.embedder.GenerateEmbedding(ctx, content)
+	if err != nil {
+		return fmt.Errorf("failed to generate embedding: %w", err)
+	}
+
+	if err := r.store.Update(ctx, Document{
+		ID:        id,
+		Content:   content,
+		Metadata:  metadata,
+		Embedding: r.prepareEmbedding(embedding),
+		Language:  metadata[LanguageMetadataKey],
+	}); err != nil {
+		return fmt.Errorf("failed to update document: %w", err)
+	}
+
+	return nil
+}
+
 // AddDocuments adds multiple documents with automatic embedding
 func (r *Retriever) AddDocuments(ctx context.Context, docs []struct {
 	ID       string
@@ -128,7 +245,8 @@ func (r *Retriever) AddDocuments(ctx context.Context, docs []struct {
 			ID:        doc.ID,
 			Content:   doc.Content,
 			Metadata:  doc.Metadata,
-			Embedding: embeddings[i],
+			Embedding: r.prepareEmbedding(embeddings[i]),
+			Language:  doc.Metadata[LanguageMetadataKey],
 		}
 	}
 
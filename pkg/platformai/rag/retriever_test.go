@@ -0,0 +1,198 @@
+package rag
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+func TestRetriever_RetrieveWithCitations(t *testing.T) {
+	module := newTestModule()
+	ctx := context.Background()
+	if err := module.AddDocuments(ctx, []Document{
+		{ID: "k8s-scaling", Content: "Horizontal pod autoscaler scales replicas", Metadata: map[string]string{"title": "Scaling"}},
+		{ID: "k8s-net", Content: "Network policies restrict pod traffic"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "autoscaler replicas pod", TopK: 2, WithCitations: true})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+
+	for _, want := range []string{"--- [1]", "--- [2]", "References:\n[1] k8s-scaling: Scaling\n[2] k8s-net\n"} {
+		if !strings.Contains(resp.Context, want) {
+			t.Errorf("context missing %q:\n%s", want, resp.Context)
+		}
+	}
+
+	answer := "Use the autoscaler [1]."
+	got := ParseCitations(answer, resp.Context)
+	want := map[int]string{1: "k8s-scaling: Scaling"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseCitations() = %v, want %v", got, want)
+	}
+
+	plain, err := module.Retrieve(ctx, RetrieveRequest{Query: "autoscaler", TopK: 2})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if strings.Contains(plain.Context, "References:") || !strings.Contains(plain.Context, "--- Document 1") {
+		t.Errorf("context without citations changed format:\n%s", plain.Context)
+	}
+}
+
+func TestParseCitations(t *testing.T) {
+	references := "--- [1] ---\nfirst\n\n--- [2] ---\nsecond\n\nReferences:\n[1] doc-a\n[2] doc-b: Title\n"
+	tests := []struct {
+		name    string
+		answer  string
+		context string
+		want    map[int]string
+	}{
+		{name: "no citations", answer: "plain answer", context: references, want: map[int]string{}},
+		{name: "markers without references", answer: "see [3] and [1]", want: map[int]string{1: "", 3: ""}},
+		{name: "cited subset", answer: "as [2] says", context: references, want: map[int]string{2: "doc-b: Title"}},
+		{name: "unknown number", answer: "see [1] and [5]", context: references, want: map[int]string{1: "doc-a", 5: ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCitations(tt.answer, tt.context); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCitations() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetriever_RetrieveWithContextTemplate(t *testing.T) {
+	module := newTestModule()
+	ctx := context.Background()
+	if err := module.AddDocuments(ctx, []Document{
+		{ID: "scaling", Content: "Autoscaler scales replicas. It reads CPU metrics.", Metadata: map[string]string{"title": "Scaling", "source": "docs/scaling.md"}},
+		{ID: "network", Content: "Network policies restrict pod traffic."},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     []string
+	}{
+		{
+			name:     "custom separator",
+			template: "=== {{.Index}} {{.Title}} <{{.Source}}> ===\n{{.Content}}\n",
+			want:     []string{"=== 1 Scaling <docs/scaling.md> ===\n", "=== 2  <> ===\n"},
+		},
+		{
+			name:     "default",
+			template: DefaultContextTemplate,
+			want:     []string{"--- Document 1 (Relevance: ", "Title: Scaling\nSource: docs/scaling.md\n"},
+		},
+		{
+			name:     "compact without LLM",
+			template: CompactContextTemplate,
+			want:     []string{"[1] Scaling: Autoscaler scales replicas.\n", "[2] Network policies restrict pod traffic.\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "autoscaler replicas", TopK: 2, ContextTemplate: tt.template})
+			if err != nil {
+				t.Fatalf("Retrieve() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(resp.Context, want) {
+					t.Errorf("context missing %q:\n%s", want, resp.Context)
+				}
+			}
+			if strings.Contains(resp.Context, "Relevant context from knowledge base") {
+				t.Errorf("context template output should replace the built-in preamble:\n%s", resp.Context)
+			}
+		})
+	}
+
+	for _, bad := range []string{"{{.Missing", "{{.Missing}}"} {
+		if _, err := module.Retrieve(ctx, RetrieveRequest{Query: "autoscaler", ContextTemplate: bad}); err == nil || !strings.Contains(err.Error(), "context template") {
+			t.Errorf("Retrieve() template %q error = %v, want context template error", bad, err)
+		}
+	}
+}
+
+func TestModule_CompactContextTemplateWithLLM(t *testing.T) {
+	mock := llm.NewMockClient("Scales pods automatically.")
+	module := newTestModule(WithLLM(mock))
+	ctx := context.Background()
+	if err := module.AddDocument(ctx, "scaling", "The autoscaler adds replicas when CPU is high. It also removes them.", map[string]string{"title": "Scaling"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "autoscaler", ContextTemplate: CompactContextTemplate})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if want := "[1] Scaling: Scales pods automatically.\n"; resp.Context != want {
+		t.Errorf("Retrieve() context = %q, want %q", resp.Context, want)
+	}
+	if len(mock.Requests()) != 1 {
+		t.Errorf("LLM requests = %d, want 1", len(mock.Requests()))
+	}
+}
+
+func TestNormalize_DotProductEqualsCosine(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a := make([]float32, 32)
+	b := make([]float32, 32)
+	for i := range a {
+		a[i] = rng.Float32()*10 - 5
+		b[i] = rng.Float32()*10 - 5
+	}
+
+	dot := dotProduct(normalize(a), normalize(b))
+	cosine := cosineSimilarity(a, b)
+	if math.Abs(float64(dot-cosine)) > 1e-5 {
+		t.Errorf("dot product of normalized vectors = %v, want cosine similarity %v", dot, cosine)
+	}
+}
+
+func TestRetriever_NormalizeEmbeddings(t *testing.T) {
+	ctx := context.Background()
+	embedder := NewMockEmbeddingProvider(16)
+	store := NewInMemoryVectorStoreWithConfig(InMemoryStoreConfig{Metric: DotProduct})
+	retriever := NewRetriever(embedder, store)
+	retriever.normalize = true
+
+	content := "deploy deploy deploy the service to staging"
+	if err := retriever.AddDocument(ctx, "doc", content, nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+	doc, err := store.Get(ctx, "doc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var norm float64
+	for _, x := range doc.Embedding {
+		norm += float64(x) * float64(x)
+	}
+	if math.Abs(norm-1) > 1e-5 {
+		t.Errorf("stored embedding norm = %v, want 1", math.Sqrt(norm))
+	}
+
+	resp, err := retriever.Retrieve(ctx, RetrieveRequest{Query: "deploy service", TopK: 1})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	docEmbedding, _ := embedder.GenerateEmbedding(ctx, content)
+	queryEmbedding, _ := embedder.GenerateEmbedding(ctx, "deploy service")
+	if want := cosineSimilarity(docEmbedding, queryEmbedding); math.Abs(float64(resp.Results[0].Score-want)) > 1e-5 {
+		t.Errorf("score = %v, want cosine similarity %v", resp.Results[0].Score, want)
+	}
+}
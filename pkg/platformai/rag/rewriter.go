@@ -0,0 +1,49 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// QueryRewriter rewrites a search query before it is embedded
+type QueryRewriter interface {
+	Rewrite(ctx context.Context, query string) (string, error)
+}
+
+const queryRewriteSystemPrompt = `You rewrite search queries for a platform engineering knowledge base.
+Expand abbreviations (for example "k8s" to "Kubernetes"), fix typos, and keep the original meaning.
+Respond with the rewritten query only, on a single line.`
+
+// LLMQueryRewriter expands abbreviations and fixes typos with a short LLM call
+type LLMQueryRewriter struct {
+	llm llm.Client
+}
+
+// NewLLMQueryRewriter creates a query rewriter backed by llmClient
+func NewLLMQueryRewriter(llmClient llm.Client) *LLMQueryRewriter {
+	return &LLMQueryRewriter{llm: llmClient}
+}
+
+// Rewrite returns the LLM's rewrite of query, or query unchanged if the LLM returns nothing
+func (r *LLMQueryRewriter) Rewrite(ctx context.Context, query string) (string, error) {
+	resp, err := r.llm.Generate(ctx, llm.GenerateRequest{
+		SystemPrompt: queryRewriteSystemPrompt,
+		UserPrompt:   query,
+		MaxTokens:    100,
+	}.Deterministic())
+	if err != nil {
+		return "", fmt.Errorf("failed to generate query rewrite: %w", err)
+	}
+
+	rewritten := strings.TrimSpace(resp.Text)
+	if i := strings.IndexByte(rewritten, '\n'); i >= 0 {
+		rewritten = strings.TrimSpace(rewritten[:i])
+	}
+	if rewritten == "" {
+		return query, nil
+	}
+	return rewritten, nil
+}
@@ -0,0 +1,71 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+func TestLLMQueryRewriter_Rewrite(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+	}{
+		{"expanded", "Kubernetes CPU resource limits", "Kubernetes CPU resource limits"},
+		{"trims extra lines", "  Kubernetes CPU limits\nExplanation: expanded k8s", "Kubernetes CPU limits"},
+		{"empty keeps original", "   ", "k8s cpu limits"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := llm.NewMockClient(tt.response)
+			got, err := NewLLMQueryRewriter(mock).Rewrite(context.Background(), "k8s cpu limits")
+			if err != nil {
+				t.Fatalf("Rewrite() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Rewrite() = %q, want %q", got, tt.want)
+			}
+			if req := mock.Requests()[0]; req.UserPrompt != "k8s cpu limits" || req.Temperature != 0 {
+				t.Errorf("Rewrite() request = %+v, want deterministic request with the query", req)
+			}
+		})
+	}
+}
+
+func TestModule_RetrieveWithQueryRewriter(t *testing.T) {
+	ctx := context.Background()
+	const rewritten = "Kubernetes CPU resource limits"
+	module := newTestModule(WithQueryRewriter(NewLLMQueryRewriter(llm.NewMockClient(rewritten))))
+	if err := module.AddDocument(ctx, "limits", "Kubernetes CPU resource limits cap container usage", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "k8s cpu limits"})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if resp.RewrittenQuery != rewritten {
+		t.Errorf("Retrieve() RewrittenQuery = %q, want %q", resp.RewrittenQuery, rewritten)
+	}
+
+	want, _ := module.embedder.GenerateEmbedding(ctx, rewritten)
+	if !reflect.DeepEqual(resp.QueryEmbedding, want) {
+		t.Error("Retrieve() should embed the rewritten query, not the original")
+	}
+}
+
+func TestModule_RetrieveQueryRewriterError(t *testing.T) {
+	mock := &llm.MockClient{GenerateFunc: func(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+		return nil, errors.New("rate limited")
+	}}
+	module := newTestModule(WithQueryRewriter(NewLLMQueryRewriter(mock)))
+
+	if _, err := module.Retrieve(context.Background(), RetrieveRequest{Query: "k8s"}); err == nil {
+		t.Error("Retrieve() expected error when the query rewriter fails")
+	}
+}
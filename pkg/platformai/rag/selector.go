@@ -0,0 +1,134 @@
+package rag
+
+// smallKnowledgeBaseDocs is the document count below which SelectModel halves
+// PerformancePriority: retrieval over few documents gains little from a better model
+const smallKnowledgeBaseDocs = 1000
+
+// CostBudget constrains automatic embedding model selection
+type CostBudget struct {
+	MaxCostPerMillionTokens float64 // USD; 0 means no limit
+	PerformancePriority     float32 // 0 picks the cheapest model, 1 the best
+}
+
+// EmbeddingModelInfo is an embedding model's approximate price and retrieval quality
+type EmbeddingModelInfo struct {
+	Provider             string
+	Model                string
+	Dimensions           int
+	CostPerMillionTokens float64 // USD, list price
+	Quality              float32 // Relative retrieval quality from 0 to 1
+}
+
+// defaultEmbeddingModels are the models NewEmbeddingModelSelector chooses from.
+// Quality scores are approximate, relative to each other on retrieval benchmarks.
+var defaultEmbeddingModels = []EmbeddingModelInfo{
+	{Provider: "openai", Model: "text-embedding-3-small", Dimensions: 1536, CostPerMillionTokens: 0.02, Quality: 0.62},
+	{Provider: "openai", Model: "text-embedding-3-large", Dimensions: 3072, CostPerMillionTokens: 0.13, Quality: 0.75},
+	{Provider: "voyageai", Model: "voyage-3", Dimensions: 1024, CostPerMillionTokens: 0.06, Quality: 0.80},
+	{Provider: "voyageai", Model: "voyage-3-lite", Dimensions: 512, CostPerMillionTokens: 0.02, Quality: 0.68},
+}
+
+// EmbeddingModelSelector picks an embedding model for a knowledge base
+type EmbeddingModelSelector struct {
+	Models []EmbeddingModelInfo
+}
+
+// NewEmbeddingModelSelector creates a selector over the OpenAI and Voyage AI models
+func NewEmbeddingModelSelector() *EmbeddingModelSelector {
+	return &EmbeddingModelSelector{Models: append([]EmbeddingModelInfo(nil), defaultEmbeddingModels...)}
+}
+
+// SelectModel returns the model scoring best for docCount documents within
+// budget. Each model scores PerformancePriority * quality minus the remaining
+// weight times its cost relative to the most expensive model; ties go to the
+// cheaper, then the better model. If no model fits the budget the cheapest is returned.
+func (s *EmbeddingModelSelector) SelectModel(docCount int, budget CostBudget) (provider, model string) {
+	info, ok := s.selectModel(docCount, budget)
+	if !ok {
+		return "", ""
+	}
+	return info.Provider, info.Model
+}
+
+func (s *EmbeddingModelSelector) selectModel(docCount int, budget CostBudget) (EmbeddingModelInfo, bool) {
+	if len(s.Models) == 0 {
+		return EmbeddingModelInfo{}, false
+	}
+
+	priority := float64(min(max(budget.PerformancePriority, 0), 1))
+	if docCount < smallKnowledgeBaseDocs {
+		priority /= 2
+	}
+	maxCost := 0.0
+	for _, m := range s.Models {
+		maxCost = max(maxCost, m.CostPerMillionTokens)
+	}
+
+	best, cheapest := -1, 0
+	var bestScore float64
+	for i, m := range s.Models {
+		if s.breaksTie(m, s.Models[cheapest]) {
+			cheapest = i
+		}
+		if budget.MaxCostPerMillionTokens > 0 && m.CostPerMillionTokens > budget.MaxCostPerMillionTokens {
+			continue
+		}
+		score := priority * float64(m.Quality)
+		if maxCost > 0 {
+			score -= (1 - priority) * m.CostPerMillionTokens / maxCost
+		}
+		if best < 0 || score > bestScore || (score == bestScore && s.breaksTie(m, s.Models[best])) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return s.Models[cheapest], true
+	}
+	return s.Models[best], true
+}
+
+// breaksTie reports whether a beats b among equally scored models
+func (s *EmbeddingModelSelector) breaksTie(a, b EmbeddingModelInfo) bool {
+	if a.CostPerMillionTokens != b.CostPerMillionTokens {
+		return a.CostPerMillionTokens < b.CostPerMillionTokens
+	}
+	return a.Quality > b.Quality
+}
+
+// selectEmbeddingConfig returns config switched to the model selected for
+// budget, among providers config has an API key for
+func selectEmbeddingConfig(config Config, budget CostBudget) Config {
+	keys := map[string]string{normalizeProvider(config.EmbeddingProvider): config.APIKey}
+	for _, fallback := range config.FallbackEmbeddingProviders {
+		if provider := normalizeProvider(fallback.EmbeddingProvider); keys[provider] == "" {
+			keys[provider] = fallback.APIKey
+		}
+	}
+
+	selector := NewEmbeddingModelSelector()
+	models := selector.Models[:0]
+	for _, m := range selector.Models {
+		if keys[m.Provider] != "" {
+			models = append(models, m)
+		}
+	}
+	selector.Models = models
+
+	info, ok := selector.selectModel(config.ExpectedDocuments, budget)
+	if !ok {
+		return config
+	}
+	config.EmbeddingProvider = info.Provider
+	config.Model = info.Model
+	config.APIKey = keys[info.Provider]
+	config.EmbeddingDim = info.Dimensions
+	return config
+}
+
+// normalizeProvider maps provider aliases to the names used in the selector's table
+func normalizeProvider(provider string) string {
+	if provider == "voyage" {
+		return "voyageai"
+	}
+	return provider
+}
@@ -0,0 +1,66 @@
+package rag
+
+import "testing"
+
+func TestEmbeddingModelSelector_SelectModel(t *testing.T) {
+	tests := []struct {
+		name      string
+		docCount  int
+		budget    CostBudget
+		wantModel string
+	}{
+		{"tight budget", 500, CostBudget{MaxCostPerMillionTokens: 0.02, PerformancePriority: 0.5}, "voyage-3-lite"},
+		{"tight budget large corpus", 100000, CostBudget{MaxCostPerMillionTokens: 0.02, PerformancePriority: 1}, "voyage-3-lite"},
+		{"cheapest", 100000, CostBudget{PerformancePriority: 0}, "voyage-3-lite"},
+		{"best large corpus", 100000, CostBudget{PerformancePriority: 1}, "voyage-3"},
+		{"best excluded by budget", 100000, CostBudget{MaxCostPerMillionTokens: 0.05, PerformancePriority: 1}, "voyage-3-lite"},
+		{"small corpus needs no best model", 100, CostBudget{PerformancePriority: 1}, "voyage-3-lite"},
+		{"nothing within budget", 100, CostBudget{MaxCostPerMillionTokens: 0.001}, "voyage-3-lite"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, model := NewEmbeddingModelSelector().SelectModel(tt.docCount, tt.budget)
+			if model != tt.wantModel {
+				t.Errorf("SelectModel() model = %s, want %s", model, tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestEmbeddingModelSelector_OpenAIOnly(t *testing.T) {
+	selector := &EmbeddingModelSelector{Models: defaultEmbeddingModels[:2]}
+	provider, model := selector.SelectModel(100000, CostBudget{PerformancePriority: 1})
+	if provider != "openai" || model != "text-embedding-3-large" {
+		t.Errorf("SelectModel() = %s/%s, want openai/text-embedding-3-large", provider, model)
+	}
+}
+
+func TestNewModule_AutoModelSelection(t *testing.T) {
+	config := Config{
+		EmbeddingProvider:          "openai",
+		APIKey:                     "openai-key",
+		Model:                      "text-embedding-3-large",
+		EmbeddingDim:               3072,
+		FallbackEmbeddingProviders: []Config{{EmbeddingProvider: "voyageai", APIKey: "voyage-key", Model: "voyage-3-lite"}},
+	}
+
+	m, err := NewModule(config, WithAutoModelSelection(CostBudget{MaxCostPerMillionTokens: 0.02, PerformancePriority: 0.5}))
+	if err != nil {
+		t.Fatalf("NewModule() error = %v", err)
+	}
+	if m.config.EmbeddingProvider != "voyageai" || m.config.Model != "voyage-3-lite" ||
+		m.config.APIKey != "voyage-key" || m.config.EmbeddingDim != 512 {
+		t.Errorf("config = %s/%s key %s dim %d, want voyageai/voyage-3-lite key voyage-key dim 512",
+			m.config.EmbeddingProvider, m.config.Model, m.config.APIKey, m.config.EmbeddingDim)
+	}
+
+	// Without a Voyage AI key only OpenAI models are considered
+	config.FallbackEmbeddingProviders = nil
+	m, err = NewModule(config, WithAutoModelSelection(CostBudget{MaxCostPerMillionTokens: 0.02}))
+	if err != nil {
+		t.Fatalf("NewModule() error = %v", err)
+	}
+	if m.config.Model != "text-embedding-3-small" {
+		t.Errorf("config.Model = %s, want text-embedding-3-small", m.config.Model)
+	}
+}
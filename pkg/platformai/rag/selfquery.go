@@ -0,0 +1,91 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// SelfQueryRetriever uses the LLM to split a natural language query into a
+// search query and metadata filters, e.g. "database docs written in 2024" becomes
+// query "database docs" with filter year=2024
+type SelfQueryRetriever struct {
+	llm       llm.Client
+	retriever *Retriever
+	fields    []string
+}
+
+// NewSelfQueryRetriever creates a self-querying retriever. fields lists the metadata
+// keys the LLM may filter on; filters on other keys are discarded.
+func NewSelfQueryRetriever(llmClient llm.Client, embedder EmbeddingProvider, store VectorStore, fields []string) *SelfQueryRetriever {
+	return &SelfQueryRetriever{
+		llm:       llmClient,
+		retriever: NewRetriever(embedder, store),
+		fields:    fields,
+	}
+}
+
+// selfQuery is the structured query the LLM is asked to return
+type selfQuery struct {
+	Query   string            `json:"query"`
+	Filters map[string]string `json:"filters"`
+}
+
+// Retrieve extracts filters from req.Query and runs a filtered search.
+// Without extracted filters it falls back to a plain vector search of the original query.
+func (r *SelfQueryRetriever) Retrieve(ctx context.Context, req RetrieveRequest) (*RetrieveResponse, error) {
+	parsed, err := r.extractQuery(ctx, req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make(map[string]string)
+	for _, field := range r.fields {
+		if value, ok := parsed.Filters[field]; ok && value != "" {
+			filters[field] = value
+		}
+	}
+	if len(filters) == 0 {
+		return r.retriever.Retrieve(ctx, req)
+	}
+
+	for k, v := range req.Filters {
+		filters[k] = v
+	}
+	if parsed.Query != "" {
+		req.Query = parsed.Query
+	}
+	req.Filters = filters
+
+	return r.retriever.Retrieve(ctx, req)
+}
+
+// extractQuery asks the LLM to separate the semantic query from metadata filters
+func (r *SelfQueryRetriever) extractQuery(ctx context.Context, query string) (*selfQuery, error) {
+	systemPrompt := `You convert search requests into structured queries.
+
+Respond with ONLY valid JSON of the form {"query": "...", "filters": {"field": "value"}}.
+- "query" is the request with the filter conditions removed
+- "filters" uses only the allowed metadata fields, with exact string values
+- Use an empty "filters" object when the request has no filter conditions`
+
+	userPrompt := fmt.Sprintf("Allowed metadata fields: %s\n\nSearch request: %s", strings.Join(r.fields, ", "), query)
+
+	resp, err := r.llm.Generate(ctx, llm.GenerateRequest{
+		SystemPrompt: systemPrompt,
+		UserPrompt:   userPrompt,
+		MaxTokens:    512,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract query filters: %w", err)
+	}
+
+	var parsed selfQuery
+	if err := json.Unmarshal([]byte(resp.Text), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse query filters: %w (response: %s)", err, resp.Text)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,82 @@
+package rag
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// filterRecordingStore records the filters passed to SearchWithFilter
+type filterRecordingStore struct {
+	*InMemoryVectorStore
+	filters []map[string]string
+}
+
+func (s *filterRecordingStore) SearchWithFilter(ctx context.Context, queryEmbedding []float32, topK int, minScore float32, filters map[string]string) ([]SearchResult, error) {
+	s.filters = append(s.filters, filters)
+	return s.InMemoryVectorStore.SearchWithFilter(ctx, queryEmbedding, topK, minScore, filters)
+}
+
+func TestSelfQueryRetriever_Retrieve(t *testing.T) {
+	embedder := NewMockEmbeddingProvider(64)
+	store := &filterRecordingStore{InMemoryVectorStore: NewInMemoryVectorStore()}
+	retriever := NewRetriever(embedder, store)
+
+	ctx := context.Background()
+	for _, doc := range []struct{ id, content, year string }{
+		{"pg-2023", "PostgreSQL databases tuning", "2023"},
+		{"pg-2024", "PostgreSQL databases replication", "2024"},
+	} {
+		if err := retriever.AddDocument(ctx, doc.id, doc.content, map[string]string{"year": doc.year}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mock := llm.NewMockClient(`{"query": "databases", "filters": {"year": "2024", "author": "ignored"}}`)
+	selfQuery := NewSelfQueryRetriever(mock, embedder, store, []string{"year"})
+
+	resp, err := selfQuery.Retrieve(ctx, RetrieveRequest{Query: "find documents about databases written in 2024", TopK: 5})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+
+	want := map[string]string{"year": "2024"}
+	if len(store.filters) != 1 || !reflect.DeepEqual(store.filters[0], want) {
+		t.Errorf("store filters = %v, want [%v]", store.filters, want)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Document.ID != "pg-2024" {
+		t.Errorf("Retrieve() results = %+v, want only pg-2024", resp.Results)
+	}
+}
+
+func TestSelfQueryRetriever_NoFilters(t *testing.T) {
+	embedder := NewMockEmbeddingProvider(64)
+	store := &filterRecordingStore{InMemoryVectorStore: NewInMemoryVectorStore()}
+	if err := NewRetriever(embedder, store).AddDocument(context.Background(), "doc", "kubernetes networking", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := llm.NewMockClient(`{"query": "kubernetes networking", "filters": {}}`)
+	selfQuery := NewSelfQueryRetriever(mock, embedder, store, []string{"year"})
+
+	resp, err := selfQuery.Retrieve(context.Background(), RetrieveRequest{Query: "kubernetes networking"})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(store.filters) != 1 || len(store.filters[0]) != 0 {
+		t.Errorf("store filters = %v, want plain search without filters", store.filters)
+	}
+	if len(resp.Results) != 1 {
+		t.Errorf("Retrieve() = %d results, want 1", len(resp.Results))
+	}
+}
+
+func TestSelfQueryRetriever_InvalidResponse(t *testing.T) {
+	selfQuery := NewSelfQueryRetriever(llm.NewMockClient("not json"), NewMockEmbeddingProvider(8), NewInMemoryVectorStore(), nil)
+
+	if _, err := selfQuery.Retrieve(context.Background(), RetrieveRequest{Query: "anything"}); err == nil {
+		t.Error("Retrieve() expected error for invalid LLM response")
+	}
+}
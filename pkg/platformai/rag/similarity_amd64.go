@@ -0,0 +1,48 @@
+//go:build amd64
+
+package rag
+
+import (
+	"math"
+
+	"golang.org/x/sys/cpu"
+)
+
+// useAVX2 selects the vectorized cosine similarity, which needs AVX2 and FMA
+var useAVX2 = cpu.X86.HasAVX2 && cpu.X86.HasFMA
+
+// minVectorizedNorm is the smallest squared norm the float32 accumulators of
+// dotNormsAVX2 compute reliably; smaller vectors lose precision to underflow
+const minVectorizedNorm = 1e-30
+
+// dotNormsAVX2 returns a·b, a·a, and b·b for len(a) a multiple of 8 and
+// len(b) >= len(a), accumulating 16 float32 lanes at a time. Implemented in similarity_amd64.s.
+//
+//go:noescape
+func dotNormsAVX2(a, b []float32) (dot, normA, normB float32)
+
+// cosineSimilarity calculates the cosine similarity between two vectors
+// Returns a value between -1 and 1, where 1 means identical, 0 means orthogonal, -1 means opposite
+func cosineSimilarity(a, b []float32) float32 {
+	if !useAVX2 || len(a) != len(b) || len(a) < 8 {
+		return cosineSimilarityGeneric(a, b)
+	}
+
+	n := len(a) &^ 7
+	dot32, normA32, normB32 := dotNormsAVX2(a[:n], b[:n])
+	dot, normA, normB := float64(dot32), float64(normA32), float64(normB32)
+	for i := n; i < len(a); i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	// float32 sums overflow or underflow for extreme magnitudes, which float64 handles
+	if math.IsInf(dot, 0) || math.IsInf(normA, 0) || math.IsInf(normB, 0) ||
+		normA < minVectorizedNorm || normB < minVectorizedNorm {
+		return cosineSimilarityGeneric(a, b)
+	}
+
+	// Rounding in the float32 lanes can push identical vectors just past 1
+	return float32(max(-1, min(1, dot/(math.Sqrt(normA)*math.Sqrt(normB)))))
+}
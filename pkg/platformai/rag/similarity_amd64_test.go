@@ -0,0 +1,46 @@
+package rag
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCosineSimilarity_MatchesGeneric(t *testing.T) {
+	if !useAVX2 {
+		t.Skip("CPU lacks AVX2 and FMA; cosineSimilarity is the generic implementation")
+	}
+
+	rng := rand.New(rand.NewSource(7))
+	for i := 0; i < 100; i++ {
+		// Cover lengths that leave a remainder after the 16- and 8-wide loops
+		dim := 1 + rng.Intn(3072)
+		a, b := randomVector(rng, dim), randomVector(rng, dim)
+		if i%10 == 0 {
+			b = a // Identical vectors must score 1, not slightly more
+		}
+
+		got, want := cosineSimilarity(a, b), cosineSimilarityGeneric(a, b)
+		if math.Abs(float64(got-want)) > 1e-5 {
+			t.Errorf("dim %d: cosineSimilarity() = %v, generic = %v", dim, got, want)
+		}
+		if got > 1 || got < -1 {
+			t.Errorf("dim %d: cosineSimilarity() = %v, want a value in [-1, 1]", dim, got)
+		}
+	}
+}
+
+func TestCosineSimilarity_ExtremeMagnitudes(t *testing.T) {
+	huge := make([]float32, 16)
+	tiny := make([]float32, 16)
+	for i := range huge {
+		huge[i] = math.MaxFloat32 / 2
+		tiny[i] = 1e-30
+	}
+
+	for name, v := range map[string][]float32{"huge": huge, "tiny": tiny} {
+		if got := cosineSimilarity(v, v); math.Abs(float64(got)-1) > 1e-6 {
+			t.Errorf("%s: cosineSimilarity(v, v) = %v, want 1", name, got)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !amd64
+
+package rag
+
+// cosineSimilarity calculates the cosine similarity between two vectors
+// Returns a value between -1 and 1, where 1 means identical, 0 means orthogonal, -1 means opposite
+func cosineSimilarity(a, b []float32) float32 {
+	return cosineSimilarityGeneric(a, b)
+}
@@ -0,0 +1,200 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotMetadata describes a saved copy of the knowledge base
+type SnapshotMetadata struct {
+	ID            string    `json:"id"`
+	Label         string    `json:"label"`
+	Timestamp     time.Time `json:"timestamp"`
+	DocumentCount int       `json:"document_count"`
+}
+
+// snapshotFile is the on-disk format of {SnapshotDir}/{id}.json
+type snapshotFile struct {
+	Metadata  SnapshotMetadata `json:"metadata"`
+	Documents []Document       `json:"documents"`
+}
+
+// Snapshot saves every document in the knowledge base to Config.SnapshotDir under a new snapshot ID
+func (m *Module) Snapshot(ctx context.Context, label string) (*SnapshotMetadata, error) {
+	dir, err := m.snapshotDir()
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := m.store.List(ctx, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+
+	now := time.Now().UTC()
+	snapshot := snapshotFile{
+		Metadata: SnapshotMetadata{
+			ID:            strconv.FormatInt(now.UnixNano(), 10),
+			Label:         label,
+			Timestamp:     now,
+			DocumentCount: len(docs),
+		},
+		Documents: docs,
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshot.Metadata.ID+".json"), data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return &snapshot.Metadata, nil
+}
+
+// RestoreSnapshot replaces the knowledge base contents with the documents in a snapshot.
+// The snapshot is validated before any document is removed, and the previous
+// documents are put back if the snapshot's cannot be added.
+func (m *Module) RestoreSnapshot(ctx context.Context, snapshotID string) error {
+	snapshot, err := m.readSnapshot(snapshotID)
+	if err != nil {
+		return err
+	}
+	if err := m.validateSnapshot(snapshot); err != nil {
+		return err
+	}
+
+	current, err := m.store.List(ctx, 0, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list documents: %w", err)
+	}
+	if err := m.removeDocuments(ctx, current); err != nil {
+		return err
+	}
+
+	if err := m.store.AddBatch(ctx, snapshot.Documents); err != nil {
+		// Drop whatever was added before the failure, then put the previous documents back
+		for _, doc := range snapshot.Documents {
+			_ = m.store.Delete(ctx, doc.ID)
+		}
+		if rollbackErr := m.store.AddBatch(ctx, current); rollbackErr != nil {
+			return fmt.Errorf("failed to restore documents: %w (previous documents could not be put back: %v)", err, rollbackErr)
+		}
+		m.indexKeywords(current...)
+		return fmt.Errorf("failed to restore documents: %w", err)
+	}
+	m.indexKeywords(snapshot.Documents...)
+	return nil
+}
+
+// validateSnapshot checks that every document in snapshot can be added to the store
+func (m *Module) validateSnapshot(snapshot *snapshotFile) error {
+	dims := 0
+	if sized, ok := m.store.(interface{ Dimensions() int }); ok {
+		dims = sized.Dimensions()
+	}
+	for _, doc := range snapshot.Documents {
+		if doc.ID == "" {
+			return fmt.Errorf("invalid snapshot %s: document ID is required", snapshot.Metadata.ID)
+		}
+		if len(doc.Embedding) == 0 {
+			return fmt.Errorf("invalid snapshot %s: document %s has no embedding", snapshot.Metadata.ID, doc.ID)
+		}
+		if dims == 0 {
+			dims = len(doc.Embedding)
+		}
+		if len(doc.Embedding) != dims {
+			return fmt.Errorf("invalid snapshot %s: %w: document %s has %d dimensions, expected %d",
+				snapshot.Metadata.ID, ErrDimensionMismatch, doc.ID, len(doc.Embedding), dims)
+		}
+	}
+	return nil
+}
+
+// removeDocuments deletes docs from the store and the keyword index
+func (m *Module) removeDocuments(ctx context.Context, docs []Document) error {
+	for _, doc := range docs {
+		if err := m.store.Delete(ctx, doc.ID); err != nil {
+			return fmt.Errorf("failed to delete document %s: %w", doc.ID, err)
+		}
+		if m.keywords != nil {
+			m.keywords.Remove(doc.ID)
+		}
+	}
+	return nil
+}
+
+// ListSnapshots returns the snapshots in Config.SnapshotDir, oldest first
+func (m *Module) ListSnapshots(ctx context.Context) ([]SnapshotMetadata, error) {
+	dir, err := m.snapshotDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []SnapshotMetadata{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	snapshots := make([]SnapshotMetadata, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		snapshot, err := m.readSnapshot(strings.TrimSuffix(entry.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot.Metadata)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+	return snapshots, nil
+}
+
+// snapshotDir returns the configured snapshot directory
+func (m *Module) snapshotDir() (string, error) {
+	if m.config.SnapshotDir == "" {
+		return "", fmt.Errorf("snapshot directory is not configured (set Config.SnapshotDir)")
+	}
+	return m.config.SnapshotDir, nil
+}
+
+// readSnapshot loads a snapshot file by ID
+func (m *Module) readSnapshot(id string) (*snapshotFile, error) {
+	dir, err := m.snapshotDir()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" || filepath.Base(id) != id {
+		return nil, fmt.Errorf("invalid snapshot ID: %q", id)
+	}
+
+	// #nosec G304 - path is built from the configured snapshot directory and a validated ID
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", id, err)
+	}
+
+	var snapshot snapshotFile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", id, err)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,148 @@
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// failingBatchStore fails the next AddBatch call after adding its first document
+type failingBatchStore struct {
+	*InMemoryVectorStore
+	fail bool
+}
+
+func (s *failingBatchStore) AddBatch(ctx context.Context, docs []Document) error {
+	if !s.fail || len(docs) == 0 {
+		return s.InMemoryVectorStore.AddBatch(ctx, docs)
+	}
+	s.fail = false
+	if err := s.InMemoryVectorStore.Add(ctx, docs[0]); err != nil {
+		return err
+	}
+	return errors.New("store unavailable")
+}
+
+func TestModule_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	module := newTestModule()
+	module.config.SnapshotDir = t.TempDir()
+
+	if err := module.AddDocuments(ctx, []Document{
+		{ID: "a", Content: "first document"},
+		{ID: "b", Content: "second document"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := module.Snapshot(ctx, "before import")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if snapshot.DocumentCount != 2 || snapshot.Label != "before import" || snapshot.ID == "" {
+		t.Errorf("Snapshot() = %+v, want 2 documents with label", snapshot)
+	}
+
+	if err := module.AddDocuments(ctx, []Document{
+		{ID: "c", Content: "third document"},
+		{ID: "d", Content: "fourth document"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := module.DeleteDocument(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := module.RestoreSnapshot(ctx, snapshot.ID); err != nil {
+		t.Fatalf("RestoreSnapshot() error = %v", err)
+	}
+	if n, _ := module.Count(ctx); n != snapshot.DocumentCount {
+		t.Errorf("Count() after restore = %d, want %d", n, snapshot.DocumentCount)
+	}
+	if _, err := module.GetDocument(ctx, "a"); err != nil {
+		t.Errorf("GetDocument(a) after restore error = %v", err)
+	}
+
+	snapshots, err := module.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatalf("ListSnapshots() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].ID != snapshot.ID || !snapshots[0].Timestamp.Equal(snapshot.Timestamp) {
+		t.Errorf("ListSnapshots() = %+v, want [%+v]", snapshots, *snapshot)
+	}
+}
+
+func TestModule_SnapshotErrors(t *testing.T) {
+	ctx := context.Background()
+	module := newTestModule()
+
+	if _, err := module.Snapshot(ctx, "x"); err == nil {
+		t.Error("Snapshot() expected error without SnapshotDir")
+	}
+
+	module.config.SnapshotDir = t.TempDir()
+	for _, id := range []string{"missing", "../escape", ""} {
+		if err := module.RestoreSnapshot(ctx, id); err == nil {
+			t.Errorf("RestoreSnapshot(%q) expected error", id)
+		}
+	}
+}
+
+func TestModule_RestoreSnapshotFailure(t *testing.T) {
+	ctx := context.Background()
+	store := &failingBatchStore{InMemoryVectorStore: NewInMemoryVectorStore()}
+	module := newTestModule()
+	module.store, module.retriever.store = store, store
+	module.config.SnapshotDir = t.TempDir()
+
+	if err := module.AddDocuments(ctx, []Document{
+		{ID: "a", Content: "first document"},
+		{ID: "b", Content: "second document"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := module.Snapshot(ctx, "two documents")
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if err := module.AddDocuments(ctx, []Document{{ID: "c", Content: "third document"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A snapshot whose embeddings the store would reject is refused before anything is deleted
+	invalid := snapshotFile{
+		Metadata:  SnapshotMetadata{ID: "invalid"},
+		Documents: []Document{{ID: "x", Content: "short embedding", Embedding: []float32{1, 0}}},
+	}
+	data, err := json.Marshal(invalid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(module.config.SnapshotDir, "invalid.json"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"invalid", snapshot.ID} {
+		store.fail = true
+		if err := module.RestoreSnapshot(ctx, id); err == nil {
+			t.Errorf("RestoreSnapshot(%s) expected error", id)
+		}
+
+		docs, err := module.store.List(ctx, 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.ID
+		}
+		sort.Strings(ids)
+		if len(ids) != 3 || ids[0] != "a" || ids[1] != "b" || ids[2] != "c" {
+			t.Errorf("documents after failed RestoreSnapshot(%s) = %v, want [a b c]", id, ids)
+		}
+	}
+}
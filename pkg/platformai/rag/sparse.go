@@ -0,0 +1,48 @@
+package rag
+
+import "context"
+
+// SparseVector is a sparse embedding such as SPLADE produces, mapping vocabulary
+// term IDs to weights. Terms missing from the map have weight 0.
+type SparseVector map[int]float32
+
+// SparseEmbeddingProvider generates sparse embeddings, which match exact
+// keywords that dense embeddings tend to blur
+type SparseEmbeddingProvider interface {
+	// GenerateSparseEmbedding generates a sparse embedding for a single text
+	GenerateSparseEmbedding(ctx context.Context, text string) (SparseVector, error)
+}
+
+// sparseDotProduct calculates the inner product of two sparse vectors
+func sparseDotProduct(a, b SparseVector) float32 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	var sum float64
+	for term, weight := range a {
+		sum += float64(weight) * float64(b[term])
+	}
+	return float32(sum)
+}
+
+// rankSparse scores docs against query by sparse dot product and returns the
+// topK documents sharing at least one weighted term with it, highest first with
+// ties in document ID order
+func rankSparse(docs []Document, query SparseVector, topK int) []SearchResult {
+	results := make([]SearchResult, 0)
+	for _, doc := range docs {
+		if len(doc.SparseVector) == 0 {
+			continue
+		}
+		if score := sparseDotProduct(query, doc.SparseVector); score > 0 {
+			results = append(results, SearchResult{Document: doc, Score: score})
+		}
+	}
+
+	sortResults(results)
+	if topK > 0 && topK < len(results) {
+		results = results[:topK]
+	}
+	return results
+}
@@ -0,0 +1,72 @@
+package rag
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVectorStore_SparseSearch(t *testing.T) {
+	docs := []Document{
+		{ID: "exact", Embedding: []float32{1, 0}, SparseVector: SparseVector{10: 2.0, 42: 1.5, 7: 0.5}},
+		{ID: "partial", Embedding: []float32{0, 1}, SparseVector: SparseVector{42: 0.5, 99: 3.0}},
+		{ID: "unrelated", Embedding: []float32{1, 1}, SparseVector: SparseVector{5: 4.0}},
+		{ID: "dense-only", Embedding: []float32{1, 0}},
+	}
+	query := SparseVector{10: 1.0, 42: 1.0}
+
+	stores := map[string]VectorStore{
+		"memory": NewInMemoryVectorStore(),
+		"hnsw":   NewHNSWVectorStore(2, 0, 0),
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := store.AddBatch(ctx, docs); err != nil {
+				t.Fatalf("AddBatch() error = %v", err)
+			}
+
+			results, err := store.SparseSearch(ctx, query, 10)
+			if err != nil {
+				t.Fatalf("SparseSearch() error = %v", err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("SparseSearch() returned %d results, want 2: %+v", len(results), results)
+			}
+			if results[0].Document.ID != "exact" || results[0].Score != 3.5 {
+				t.Errorf("top result = %s (%v), want exact (3.5)", results[0].Document.ID, results[0].Score)
+			}
+			if results[1].Document.ID != "partial" || results[1].Score != 0.5 {
+				t.Errorf("second result = %s (%v), want partial (0.5)", results[1].Document.ID, results[1].Score)
+			}
+
+			top, err := store.SparseSearch(ctx, query, 1)
+			if err != nil || len(top) != 1 {
+				t.Errorf("SparseSearch(topK=1) = %d results, %v; want 1", len(top), err)
+			}
+			if _, err := store.SparseSearch(ctx, nil, 10); err == nil {
+				t.Error("SparseSearch() expected error for empty query")
+			}
+
+			doc, err := store.Get(ctx, "exact")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(doc.SparseVector) != 3 {
+				t.Errorf("stored sparse vector = %v, want 3 terms", doc.SparseVector)
+			}
+		})
+	}
+}
+
+func TestRankSparse_Ties(t *testing.T) {
+	docs := []Document{
+		{ID: "c", SparseVector: SparseVector{1: 1.0}},
+		{ID: "a", SparseVector: SparseVector{1: 1.0}},
+		{ID: "b", SparseVector: SparseVector{1: 1.0}},
+	}
+
+	results := rankSparse(docs, SparseVector{1: 1.0}, 2)
+	if len(results) != 2 || results[0].Document.ID != "a" || results[1].Document.ID != "b" {
+		t.Errorf("rankSparse() = %+v, want a, b with ties broken by ID", results)
+	}
+}
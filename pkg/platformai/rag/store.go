@@ -1,27 +1,109 @@
 package rag
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"sort"
 	"sync"
+	"time"
 )
 
-// InMemoryVectorStore is an in-memory implementation of VectorStore
+// InMemoryVectorStore is an in-memory implementation of VectorStore.
+// Documents are partitioned by namespace; each store value operates on one
+// namespace, and Namespace returns views onto the others.
 type InMemoryVectorStore struct {
-	mu        sync.RWMutex
-	documents map[string]Document
+	mu         *sync.RWMutex // Shared by all namespace views
+	namespaces map[string]map[string]Document
+	namespace  string
+	metric     SimilarityMetric
+	freshness  FreshnessConfig
+
+	// expectedDim is shared by all namespace views and is 0 until the first document is added
+	expectedDim *int
+}
+
+// InMemoryStoreConfig configures an InMemoryVectorStore
+type InMemoryStoreConfig struct {
+	Dimensions int              // Expected embedding length; 0 infers it from the first document
+	Metric     SimilarityMetric // Scoring function (default: CosineSimilarity)
+	Freshness  FreshnessConfig  // Optional age penalty applied to scores
 }
 
-// NewInMemoryVectorStore creates a new in-memory vector store
+// NewInMemoryVectorStore creates a new in-memory vector store using the default ("") namespace
+// and cosine similarity
 func NewInMemoryVectorStore() *InMemoryVectorStore {
+	return NewInMemoryVectorStoreWithConfig(InMemoryStoreConfig{})
+}
+
+// NewInMemoryVectorStoreWithConfig creates a new in-memory vector store from config
+func NewInMemoryVectorStoreWithConfig(config InMemoryStoreConfig) *InMemoryVectorStore {
+	if config.Metric == "" {
+		config.Metric = CosineSimilarity
+	}
+	return &InMemoryVectorStore{
+		mu:          &sync.RWMutex{},
+		namespaces:  make(map[string]map[string]Document),
+		metric:      config.Metric,
+		freshness:   config.Freshness,
+		expectedDim: &config.Dimensions,
+	}
+}
+
+// Namespace returns a view of the store isolated to namespace ns.
+// Documents in one namespace are invisible to every other namespace.
+func (s *InMemoryVectorStore) Namespace(ns string) VectorStore {
 	return &InMemoryVectorStore{
-		documents: make(map[string]Document),
+		mu:          s.mu,
+		namespaces:  s.namespaces,
+		namespace:   ns,
+		metric:      s.metric,
+		freshness:   s.freshness,
+		expectedDim: s.expectedDim,
+	}
+}
+
+// docs returns the documents of this view's namespace. Callers must hold the lock.
+func (s *InMemoryVectorStore) docs() map[string]Document {
+	return s.namespaces[s.namespace]
+}
+
+// checkDimensions rejects embeddings whose length differs from the store's
+// dimension, adopting the first length it sees. Callers must hold the write lock.
+func (s *InMemoryVectorStore) checkDimensions(doc Document) error {
+	if *s.expectedDim == 0 {
+		*s.expectedDim = len(doc.Embedding)
+		return nil
+	}
+	if len(doc.Embedding) != *s.expectedDim {
+		return fmt.Errorf("%w: document %s has %d dimensions, store expects %d", ErrDimensionMismatch, doc.ID, len(doc.Embedding), *s.expectedDim)
+	}
+	return nil
+}
+
+// Dimensions returns the embedding length the store accepts, or 0 if it has not been set yet
+func (s *InMemoryVectorStore) Dimensions() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return *s.expectedDim
+}
+
+// put stores doc in this view's namespace. Callers must hold the write lock.
+func (s *InMemoryVectorStore) put(doc Document) {
+	docs, ok := s.namespaces[s.namespace]
+	if !ok {
+		docs = make(map[string]Document)
+		s.namespaces[s.namespace] = docs
 	}
+	doc.Namespace = s.namespace
+	docs[doc.ID] = doc
 }
 
-// Add adds a document to the store
+// Add adds a document to the store. Adding an existing ID replaces the document.
 func (s *InMemoryVectorStore) Add(ctx context.Context, doc Document) error {
 	if doc.ID == "" {
 		return fmt.Errorf("document ID is required")
@@ -33,7 +115,30 @@ func (s *InMemoryVectorStore) Add(ctx context.Context, doc Document) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.documents[doc.ID] = doc
+	if err := s.checkDimensions(doc); err != nil {
+		return err
+	}
+	s.put(doc)
+	return nil
+}
+
+// Update replaces an existing document
+func (s *InMemoryVectorStore) Update(ctx context.Context, doc Document) error {
+	if len(doc.Embedding) == 0 {
+		return fmt.Errorf("document embedding is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.docs()[doc.ID]; !exists {
+		return fmt.Errorf("%w: %s", ErrDocumentNotFound, doc.ID)
+	}
+	if err := s.checkDimensions(doc); err != nil {
+		return err
+	}
+
+	s.put(doc)
 	return nil
 }
 
@@ -49,13 +154,21 @@ func (s *InMemoryVectorStore) AddBatch(ctx context.Context, docs []Document) err
 		if len(doc.Embedding) == 0 {
 			return fmt.Errorf("document embedding is required")
 		}
-		s.documents[doc.ID] = doc
+		if err := s.checkDimensions(doc); err != nil {
+			return err
+		}
+		s.put(doc)
 	}
 	return nil
 }
 
 // Search finds similar documents based on query embedding
 func (s *InMemoryVectorStore) Search(ctx context.Context, queryEmbedding []float32, topK int, minScore float32) ([]SearchResult, error) {
+	return s.SearchWithFilter(ctx, queryEmbedding, topK, minScore, nil)
+}
+
+// SearchWithFilter finds similar documents among those matching the metadata filters
+func (s *InMemoryVectorStore) SearchWithFilter(ctx context.Context, queryEmbedding []float32, topK int, minScore float32, filters map[string]string) ([]SearchResult, error) {
 	if len(queryEmbedding) == 0 {
 		return nil, fmt.Errorf("query embedding is required")
 	}
@@ -64,9 +177,13 @@ func (s *InMemoryVectorStore) Search(ctx context.Context, queryEmbedding []float
 	defer s.mu.RUnlock()
 
 	// Calculate similarity scores for all documents
-	results := make([]SearchResult, 0, len(s.documents))
-	for _, doc := range s.documents {
-		similarity := cosineSimilarity(queryEmbedding, doc.Embedding)
+	now := time.Now()
+	results := make([]SearchResult, 0, len(s.docs()))
+	for _, doc := range s.docs() {
+		if !matchesFilters(doc, filters) {
+			continue
+		}
+		similarity := s.freshness.adjust(similarity(s.metric, queryEmbedding, doc.Embedding), doc, now)
 		if similarity >= minScore {
 			results = append(results, SearchResult{
 				Document: doc,
@@ -75,10 +192,7 @@ func (s *InMemoryVectorStore) Search(ctx context.Context, queryEmbedding []float
 		}
 	}
 
-	// Sort by similarity (highest first)
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
+	sortResults(results)
 
 	// Return top K results
 	if topK > 0 && topK < len(results) {
@@ -88,14 +202,30 @@ func (s *InMemoryVectorStore) Search(ctx context.Context, queryEmbedding []float
 	return results, nil
 }
 
+// SparseSearch finds documents by sparse dot product with query
+func (s *InMemoryVectorStore) SparseSearch(ctx context.Context, query SparseVector, topK int) ([]SearchResult, error) {
+	if len(query) == 0 {
+		return nil, fmt.Errorf("sparse query vector is required")
+	}
+
+	s.mu.RLock()
+	docs := make([]Document, 0, len(s.docs()))
+	for _, doc := range s.docs() {
+		docs = append(docs, doc)
+	}
+	s.mu.RUnlock()
+
+	return rankSparse(docs, query, topK), nil
+}
+
 // Get retrieves a document by ID
 func (s *InMemoryVectorStore) Get(ctx context.Context, id string) (*Document, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	doc, exists := s.documents[id]
+	doc, exists := s.docs()[id]
 	if !exists {
-		return nil, fmt.Errorf("document not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrDocumentNotFound, id)
 	}
 
 	return &doc, nil
@@ -106,11 +236,11 @@ func (s *InMemoryVectorStore) Delete(ctx context.Context, id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.documents[id]; !exists {
-		return fmt.Errorf("document not found: %s", id)
+	if _, exists := s.docs()[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrDocumentNotFound, id)
 	}
 
-	delete(s.documents, id)
+	delete(s.docs(), id)
 	return nil
 }
 
@@ -119,12 +249,153 @@ func (s *InMemoryVectorStore) Count(ctx context.Context) (int, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return len(s.documents), nil
+	return len(s.docs()), nil
+}
+
+// List returns documents ordered by ID for stable pagination
+func (s *InMemoryVectorStore) List(ctx context.Context, offset, limit int) ([]Document, error) {
+	return s.ListByMetadata(ctx, nil, offset, limit)
+}
+
+// ListByMetadata returns documents matching the filters, ordered by ID
+func (s *InMemoryVectorStore) ListByMetadata(ctx context.Context, filters map[string]string, offset, limit int) ([]Document, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	s.mu.RLock()
+	docs := make([]Document, 0, len(s.docs()))
+	for _, doc := range s.docs() {
+		if matchesFilters(doc, filters) {
+			docs = append(docs, doc)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].ID < docs[j].ID
+	})
+
+	return paginate(docs, offset, limit), nil
+}
+
+// Export writes every document in the namespace to w as newline-delimited JSON,
+// one document per line including its embedding
+func (s *InMemoryVectorStore) Export(ctx context.Context, w io.Writer) error {
+	docs, err := s.List(ctx, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	for _, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := encoder.Encode(doc); err != nil {
+			return fmt.Errorf("failed to encode document %s: %w", doc.ID, err)
+		}
+	}
+	return nil
+}
+
+// Import reads newline-delimited JSON documents written by Export into the namespace,
+// replacing stored documents that share an ID
+func (s *InMemoryVectorStore) Import(ctx context.Context, r io.Reader) error {
+	var docs []Document
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var doc Document
+		if err := decoder.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode document %d: %w", len(docs)+1, err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return s.AddBatch(ctx, docs)
+}
+
+// paginate returns the documents in [offset, offset+limit), or everything from offset if limit <= 0
+func paginate(docs []Document, offset, limit int) []Document {
+	if offset >= len(docs) {
+		return []Document{}
+	}
+	docs = docs[offset:]
+	if limit > 0 && limit < len(docs) {
+		docs = docs[:limit]
+	}
+	return docs
+}
+
+// matchesFilters reports whether the document metadata contains every filter key with the same value
+func matchesFilters(doc Document, filters map[string]string) bool {
+	for key, value := range filters {
+		if doc.Metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// sortResults orders results by score, highest first. Ties are broken by
+// document ID, since map iteration would otherwise make equal scores come back
+// in a different order on every search.
+func sortResults(results []SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Document.ID < results[j].Document.ID
+	})
+}
+
+// similarity scores a against b using metric, defaulting to cosine similarity
+func similarity(metric SimilarityMetric, a, b []float32) float32 {
+	switch metric {
+	case DotProduct:
+		return dotProduct(a, b)
+	case EuclideanDistance:
+		return euclideanSimilarity(a, b)
+	default:
+		return cosineSimilarity(a, b)
+	}
+}
+
+// dotProduct calculates the inner product of two vectors without normalization
+func dotProduct(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return float32(sum)
+}
+
+// euclideanSimilarity converts the L2 distance between two vectors into a score
+// between 0 and 1 as 1 / (1 + distance), where 1 means identical
+func euclideanSimilarity(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var sum float64
+	for i := range a {
+		d := float64(a[i]) - float64(b[i])
+		sum += d * d
+	}
+	return float32(1 / (1 + math.Sqrt(sum)))
 }
 
-// cosineSimilarity calculates the cosine similarity between two vectors
-// Returns a value between -1 and 1, where 1 means identical, 0 means orthogonal, -1 means opposite
-func cosineSimilarity(a, b []float32) float32 {
+// cosineSimilarityGeneric calculates the cosine similarity between two vectors.
+// It is the portable implementation behind cosineSimilarity, which returns a
+// value between -1 and 1, where 1 means identical, 0 means orthogonal, -1 means opposite.
+func cosineSimilarityGeneric(a, b []float32) float32 {
 	if len(a) != len(b) {
 		return 0
 	}
@@ -0,0 +1,461 @@
+package rag
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+const (
+	defaultHNSWM              = 16
+	defaultHNSWEfConstruction = 200
+	defaultHNSWEfSearch       = 64
+)
+
+// HNSWVectorStore is an approximate nearest neighbor VectorStore based on a
+// Hierarchical Navigable Small World graph. Search visits a small fraction of
+// the documents, trading exact recall for speed on large knowledge bases.
+//
+// Deleted and replaced documents stay in the graph as routing nodes and are
+// skipped in results, so memory is only reclaimed by building a new store.
+type HNSWVectorStore struct {
+	mu             sync.RWMutex
+	dim            int
+	m              int // Links per node on upper layers (layer 0 allows 2*m)
+	efConstruction int
+	efSearch       int
+	levelMult      float64
+	rng            *rand.Rand
+
+	nodes    []*hnswNode
+	ids      map[string]int // Document ID -> index of its live node
+	entry    int            // Entry point node, -1 when empty
+	maxLevel int
+}
+
+// hnswNode is a graph vertex holding one document version
+type hnswNode struct {
+	doc     Document
+	vector  []float32 // Normalized embedding
+	links   [][]int   // Neighbor node indices per layer
+	deleted bool
+}
+
+// NewHNSWVectorStore creates an HNSW vector store for embeddings of size dim.
+// M is the number of bi-directional links per node and efConstruction the
+// candidate list size used while building the graph; values <= 0 use defaults (16, 200).
+func NewHNSWVectorStore(dim, M, efConstruction int) *HNSWVectorStore {
+	if M <= 0 {
+		M = defaultHNSWM
+	}
+	if M < 2 {
+		M = 2
+	}
+	if efConstruction <= 0 {
+		efConstruction = defaultHNSWEfConstruction
+	}
+
+	return &HNSWVectorStore{
+		dim:            dim,
+		m:              M,
+		efConstruction: efConstruction,
+		efSearch:       defaultHNSWEfSearch,
+		levelMult:      1 / math.Log(float64(M)),
+		rng:            rand.New(rand.NewSource(1)), // #nosec G404 - level sampling does not need crypto randomness
+		ids:            make(map[string]int),
+		entry:          -1,
+	}
+}
+
+// Dimensions returns the embedding length the store accepts
+func (s *HNSWVectorStore) Dimensions() int {
+	return s.dim
+}
+
+// validate checks that doc can be indexed by this store
+func (s *HNSWVectorStore) validate(doc Document) error {
+	if doc.ID == "" {
+		return fmt.Errorf("document ID is required")
+	}
+	if len(doc.Embedding) == 0 {
+		return fmt.Errorf("document embedding is required")
+	}
+	if len(doc.Embedding) != s.dim {
+		return fmt.Errorf("%w: document %s has %d dimensions, store expects %d", ErrDimensionMismatch, doc.ID, len(doc.Embedding), s.dim)
+	}
+	return nil
+}
+
+// Add adds a document to the store. Adding an existing ID replaces the document.
+func (s *HNSWVectorStore) Add(ctx context.Context, doc Document) error {
+	if err := s.validate(doc); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.insert(doc)
+	return nil
+}
+
+// Update replaces an existing document
+func (s *HNSWVectorStore) Update(ctx context.Context, doc Document) error {
+	if err := s.validate(doc); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.ids[doc.ID]; !exists {
+		return fmt.Errorf("%w: %s", ErrDocumentNotFound, doc.ID)
+	}
+
+	s.insert(doc)
+	return nil
+}
+
+// AddBatch adds multiple documents to the store
+func (s *HNSWVectorStore) AddBatch(ctx context.Context, docs []Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, doc := range docs {
+		if err := s.validate(doc); err != nil {
+			return err
+		}
+		s.insert(doc)
+	}
+	return nil
+}
+
+// Search finds similar documents based on query embedding
+func (s *HNSWVectorStore) Search(ctx context.Context, queryEmbedding []float32, topK int, minScore float32) ([]SearchResult, error) {
+	return s.SearchWithFilter(ctx, queryEmbedding, topK, minScore, nil)
+}
+
+// SearchWithFilter finds similar documents among those matching the metadata filters.
+// Filters and minScore are applied to the graph candidates; the candidate list
+// is widened until topK matches are found or the graph is exhausted.
+func (s *HNSWVectorStore) SearchWithFilter(ctx context.Context, queryEmbedding []float32, topK int, minScore float32, filters map[string]string) ([]SearchResult, error) {
+	if len(queryEmbedding) == 0 {
+		return nil, fmt.Errorf("query embedding is required")
+	}
+	if len(queryEmbedding) != s.dim {
+		return nil, fmt.Errorf("%w: query has %d dimensions, store expects %d", ErrDimensionMismatch, len(queryEmbedding), s.dim)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.entry < 0 {
+		return []SearchResult{}, nil
+	}
+
+	query := normalize(queryEmbedding)
+	entry := []hnswCandidate{{node: s.entry, dist: s.distance(query, s.entry)}}
+	for layer := s.maxLevel; layer > 0; layer-- {
+		entry = s.searchLayer(query, entry, 1, layer)
+	}
+
+	want := topK
+	if want <= 0 {
+		want = len(s.ids)
+	}
+	ef := max(want, s.efSearch)
+
+	for {
+		candidates := s.searchLayer(query, entry, ef, 0)
+
+		results := make([]SearchResult, 0, want)
+		for _, c := range candidates {
+			node := s.nodes[c.node]
+			score := 1 - c.dist
+			if node.deleted || score < minScore || !matchesFilters(node.doc, filters) {
+				continue
+			}
+			results = append(results, SearchResult{Document: node.doc, Score: score})
+		}
+
+		// Candidates are sorted nearest first, so once the farthest one falls
+		// below minScore a wider search cannot add qualifying documents
+		exhausted := len(candidates) < ef || ef >= len(s.nodes) ||
+			1-candidates[len(candidates)-1].dist < minScore
+		if len(results) >= want || exhausted {
+			if len(results) > want {
+				results = results[:want]
+			}
+			return results, nil
+		}
+		ef *= 2
+	}
+}
+
+// SparseSearch finds documents by sparse dot product with query. The graph only
+// indexes dense embeddings, so this scans every document.
+func (s *HNSWVectorStore) SparseSearch(ctx context.Context, query SparseVector, topK int) ([]SearchResult, error) {
+	if len(query) == 0 {
+		return nil, fmt.Errorf("sparse query vector is required")
+	}
+
+	s.mu.RLock()
+	docs := make([]Document, 0, len(s.ids))
+	for _, idx := range s.ids {
+		docs = append(docs, s.nodes[idx].doc)
+	}
+	s.mu.RUnlock()
+
+	return rankSparse(docs, query, topK), nil
+}
+
+// Get retrieves a document by ID
+func (s *HNSWVectorStore) Get(ctx context.Context, id string) (*Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	idx, exists := s.ids[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDocumentNotFound, id)
+	}
+
+	doc := s.nodes[idx].doc
+	return &doc, nil
+}
+
+// Delete removes a document by ID
+func (s *HNSWVectorStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx, exists := s.ids[id]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrDocumentNotFound, id)
+	}
+
+	s.nodes[idx].deleted = true
+	delete(s.ids, id)
+	return nil
+}
+
+// Count returns the total number of documents
+func (s *HNSWVectorStore) Count(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.ids), nil
+}
+
+// List returns documents ordered by ID for stable pagination
+func (s *HNSWVectorStore) List(ctx context.Context, offset, limit int) ([]Document, error) {
+	return s.ListByMetadata(ctx, nil, offset, limit)
+}
+
+// ListByMetadata returns documents matching the filters, ordered by ID
+func (s *HNSWVectorStore) ListByMetadata(ctx context.Context, filters map[string]string, offset, limit int) ([]Document, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	s.mu.RLock()
+	docs := make([]Document, 0, len(s.ids))
+	for _, idx := range s.ids {
+		if doc := s.nodes[idx].doc; matchesFilters(doc, filters) {
+			docs = append(docs, doc)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].ID < docs[j].ID
+	})
+
+	return paginate(docs, offset, limit), nil
+}
+
+// insert links a new node for doc into the graph. Callers must hold the write lock.
+func (s *HNSWVectorStore) insert(doc Document) {
+	if old, exists := s.ids[doc.ID]; exists {
+		s.nodes[old].deleted = true
+	}
+
+	level := int(-math.Log(1-s.rng.Float64()) * s.levelMult)
+	idx := len(s.nodes)
+	node := &hnswNode{
+		doc:    doc,
+		vector: normalize(doc.Embedding),
+		links:  make([][]int, level+1),
+	}
+	s.nodes = append(s.nodes, node)
+	s.ids[doc.ID] = idx
+
+	if s.entry < 0 {
+		s.entry = idx
+		s.maxLevel = level
+		return
+	}
+
+	entry := []hnswCandidate{{node: s.entry, dist: s.distance(node.vector, s.entry)}}
+	for layer := s.maxLevel; layer > level; layer-- {
+		entry = s.searchLayer(node.vector, entry, 1, layer)
+	}
+
+	for layer := min(level, s.maxLevel); layer >= 0; layer-- {
+		candidates := s.searchLayer(node.vector, entry, s.efConstruction, layer)
+		neighbors := candidates[:min(s.m, len(candidates))]
+
+		maxLinks := s.maxLinks(layer)
+		node.links[layer] = make([]int, 0, len(neighbors))
+		for _, neighbor := range neighbors {
+			node.links[layer] = append(node.links[layer], neighbor.node)
+
+			links := append(s.nodes[neighbor.node].links[layer], idx)
+			if len(links) > maxLinks {
+				links = s.prune(neighbor.node, links, maxLinks)
+			}
+			s.nodes[neighbor.node].links[layer] = links
+		}
+		entry = candidates
+	}
+
+	if level > s.maxLevel {
+		s.maxLevel = level
+		s.entry = idx
+	}
+}
+
+// maxLinks returns the link limit for a layer
+func (s *HNSWVectorStore) maxLinks(layer int) int {
+	if layer == 0 {
+		return 2 * s.m
+	}
+	return s.m
+}
+
+// prune keeps the n links nearest to node
+func (s *HNSWVectorStore) prune(node int, links []int, n int) []int {
+	candidates := make([]hnswCandidate, len(links))
+	for i, link := range links {
+		candidates[i] = hnswCandidate{node: link, dist: s.distance(s.nodes[node].vector, link)}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].dist < candidates[j].dist
+	})
+
+	pruned := make([]int, n)
+	for i := range pruned {
+		pruned[i] = candidates[i].node
+	}
+	return pruned
+}
+
+// searchLayer performs a greedy best-first search of one layer starting from
+// entry and returns up to ef nearest nodes, nearest first
+func (s *HNSWVectorStore) searchLayer(query []float32, entry []hnswCandidate, ef, layer int) []hnswCandidate {
+	visited := make([]bool, len(s.nodes))
+	candidates := &candidateHeap{}
+	results := &candidateHeap{farthestFirst: true}
+	for _, c := range entry {
+		visited[c.node] = true
+		heap.Push(candidates, c)
+		heap.Push(results, c)
+	}
+	for results.Len() > ef {
+		heap.Pop(results)
+	}
+
+	for candidates.Len() > 0 {
+		current := heap.Pop(candidates).(hnswCandidate)
+		if results.Len() >= ef && current.dist > results.items[0].dist {
+			break
+		}
+
+		for _, neighbor := range s.nodes[current.node].links[layer] {
+			if visited[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+
+			dist := s.distance(query, neighbor)
+			if results.Len() < ef || dist < results.items[0].dist {
+				c := hnswCandidate{node: neighbor, dist: dist}
+				heap.Push(candidates, c)
+				heap.Push(results, c)
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	nearest := make([]hnswCandidate, results.Len())
+	for i := len(nearest) - 1; i >= 0; i-- {
+		nearest[i] = heap.Pop(results).(hnswCandidate)
+	}
+	return nearest
+}
+
+// distance returns the cosine distance between a normalized query and a node
+func (s *HNSWVectorStore) distance(query []float32, node int) float32 {
+	var dot float32
+	for i, v := range s.nodes[node].vector {
+		dot += query[i] * v
+	}
+	return 1 - dot
+}
+
+// normalize returns v scaled to unit length, or a copy of v if it is all zeros
+func normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+
+	out := make([]float32, len(v))
+	if norm == 0 {
+		copy(out, v)
+		return out
+	}
+
+	scale := float32(1 / math.Sqrt(norm))
+	for i, x := range v {
+		out[i] = x * scale
+	}
+	return out
+}
+
+// hnswCandidate is a node and its distance from the current query
+type hnswCandidate struct {
+	node int
+	dist float32
+}
+
+// candidateHeap is a heap of candidates ordered nearest first, or farthest
+// first when farthestFirst is set
+type candidateHeap struct {
+	items         []hnswCandidate
+	farthestFirst bool
+}
+
+func (h *candidateHeap) Len() int { return len(h.items) }
+
+func (h *candidateHeap) Less(i, j int) bool {
+	if h.farthestFirst {
+		return h.items[i].dist > h.items[j].dist
+	}
+	return h.items[i].dist < h.items[j].dist
+}
+
+func (h *candidateHeap) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *candidateHeap) Push(x any) { h.items = append(h.items, x.(hnswCandidate)) }
+
+func (h *candidateHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,146 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// randomDocuments generates documents whose embeddings are spread around a fixed
+// set of topic centers, which resembles real embeddings more closely than uniform noise
+func randomDocuments(n, dim int, seed int64) []Document {
+	centers := rand.New(rand.NewSource(0))
+	topics := make([][]float32, 100)
+	for i := range topics {
+		topics[i] = make([]float32, dim)
+		for j := range topics[i] {
+			topics[i][j] = float32(centers.NormFloat64())
+		}
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	docs := make([]Document, n)
+	for i := range docs {
+		topic := topics[rng.Intn(len(topics))]
+		embedding := make([]float32, dim)
+		for j := range embedding {
+			embedding[j] = topic[j] + 0.3*float32(rng.NormFloat64())
+		}
+		docs[i] = Document{
+			ID:        fmt.Sprintf("doc-%05d", i),
+			Metadata:  map[string]string{"shard": fmt.Sprint(i % 4)},
+			Embedding: embedding,
+		}
+	}
+	return docs
+}
+
+func TestHNSWVectorStore_CRUD(t *testing.T) {
+	store := NewHNSWVectorStore(2, 0, 0)
+	ctx := context.Background()
+
+	docs := []Document{
+		{ID: "east", Embedding: []float32{1, 0}, Metadata: map[string]string{"axis": "x"}},
+		{ID: "north", Embedding: []float32{0, 1}, Metadata: map[string]string{"axis": "y"}},
+		{ID: "northeast", Embedding: []float32{1, 1}, Metadata: map[string]string{"axis": "x"}},
+	}
+	if err := store.AddBatch(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := store.Search(ctx, []float32{1, 0.1}, 2, 0.5)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Document.ID != "east" || results[1].Document.ID != "northeast" {
+		t.Errorf("Search() = %+v, want east then northeast", results)
+	}
+
+	results, _ = store.Search(ctx, []float32{1, 0}, 10, 0.9)
+	if len(results) != 1 {
+		t.Errorf("Search() with minScore 0.9 = %d results, want 1", len(results))
+	}
+
+	results, _ = store.SearchWithFilter(ctx, []float32{0, 1}, 10, -1, map[string]string{"axis": "x"})
+	if len(results) != 2 || results[0].Document.ID != "northeast" {
+		t.Errorf("SearchWithFilter() = %+v, want axis=x documents", results)
+	}
+
+	if err := store.Update(ctx, Document{ID: "east", Embedding: []float32{0, -1}}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	results, _ = store.Search(ctx, []float32{0, -1}, 1, 0)
+	if len(results) != 1 || results[0].Document.ID != "east" {
+		t.Errorf("Search() after Update = %+v, want east", results)
+	}
+
+	if err := store.Delete(ctx, "east"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Get(ctx, "east"); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrDocumentNotFound", err)
+	}
+	if n, _ := store.Count(ctx); n != 2 {
+		t.Errorf("Count() = %d, want 2", n)
+	}
+	for _, r := range mustSearch(t, store, []float32{0, -1}, 10) {
+		if r.Document.ID == "east" {
+			t.Error("Search() returned deleted document")
+		}
+	}
+
+	if err := store.Add(ctx, Document{ID: "bad", Embedding: []float32{1, 2, 3}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Add() wrong dimension error = %v, want ErrDimensionMismatch", err)
+	}
+	if err := store.Update(ctx, Document{ID: "missing", Embedding: []float32{1, 0}}); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("Update() missing document error = %v, want ErrDocumentNotFound", err)
+	}
+}
+
+func mustSearch(t *testing.T, store VectorStore, query []float32, topK int) []SearchResult {
+	t.Helper()
+	results, err := store.Search(context.Background(), query, topK, -1)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	return results
+}
+
+func TestHNSWVectorStore_Recall(t *testing.T) {
+	const dim, topK = 32, 10
+	ctx := context.Background()
+	docs := randomDocuments(2000, dim, 1)
+
+	exact := NewInMemoryVectorStore()
+	approx := NewHNSWVectorStore(dim, 16, 100)
+	if err := exact.AddBatch(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+	if err := approx.AddBatch(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := randomDocuments(50, dim, 2)
+	var hits int
+	for _, q := range queries {
+		want := map[string]bool{}
+		for _, r := range mustSearch(t, exact, q.Embedding, topK) {
+			want[r.Document.ID] = true
+		}
+		for _, r := range mustSearch(t, approx, q.Embedding, topK) {
+			if want[r.Document.ID] {
+				hits++
+			}
+		}
+	}
+
+	if recall := float64(hits) / float64(len(queries)*topK); recall < 0.9 {
+		t.Errorf("HNSW recall@%d = %.2f, want >= 0.90", topK, recall)
+	}
+}
+
+func TestHNSWVectorStore_Contract(t *testing.T) {
+	testVectorStoreContract(t, NewHNSWVectorStore(3, 0, 0))
+}
@@ -0,0 +1,474 @@
+package rag
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInMemoryVectorStore_Update(t *testing.T) {
+	store := NewInMemoryVectorStore()
+	ctx := context.Background()
+
+	err := store.Update(ctx, Document{ID: "missing", Embedding: []float32{1}})
+	if !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("Update() missing document error = %v, want ErrDocumentNotFound", err)
+	}
+
+	if err := store.Add(ctx, Document{ID: "doc", Content: "old", Embedding: []float32{1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Update(ctx, Document{ID: "doc", Content: "new", Embedding: []float32{0, 1}}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	doc, err := store.Get(ctx, "doc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Content != "new" || doc.Embedding[1] != 1 {
+		t.Errorf("Get() after Update = %+v, want new content and embedding", doc)
+	}
+}
+
+func TestModule_UpdateDocument(t *testing.T) {
+	module := newTestModule()
+	ctx := context.Background()
+
+	if err := module.AddDocument(ctx, "db", "postgresql connection pooling", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := module.UpdateDocument(ctx, "db", "redis cache eviction", map[string]string{"rev": "2"}); err != nil {
+		t.Fatalf("UpdateDocument() error = %v", err)
+	}
+
+	resp, err := module.Retrieve(ctx, RetrieveRequest{Query: "redis cache eviction", TopK: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Document.Content != "redis cache eviction" || resp.Results[0].Score < 0.99 {
+		t.Errorf("Retrieve() after update = %+v, want the new content", resp.Results)
+	}
+
+	// The old embedding must be gone: the old content should no longer match
+	resp, err = module.Retrieve(ctx, RetrieveRequest{Query: "postgresql connection pooling", TopK: 1, MinScore: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 0 {
+		t.Errorf("old content still matches: %+v", resp.Results)
+	}
+
+	if err := module.UpdateDocument(ctx, "missing", "content", nil); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("UpdateDocument() missing error = %v, want ErrDocumentNotFound", err)
+	}
+}
+
+func TestInMemoryVectorStore_List(t *testing.T) {
+	store := NewInMemoryVectorStore()
+	ctx := context.Background()
+	for _, id := range []string{"c", "a", "e", "b", "d"} {
+		team := "platform"
+		if id == "b" || id == "d" {
+			team = "data"
+		}
+		if err := store.Add(ctx, Document{ID: id, Embedding: []float32{1}, Metadata: map[string]string{"team": team}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		offset  int
+		limit   int
+		want    []string
+	}{
+		{name: "first page", offset: 0, limit: 2, want: []string{"a", "b"}},
+		{name: "last partial page", offset: 4, limit: 2, want: []string{"e"}},
+		{name: "offset past end", offset: 5, limit: 2, want: []string{}},
+		{name: "no limit", offset: 1, limit: 0, want: []string{"b", "c", "d", "e"}},
+		{name: "filtered", filters: map[string]string{"team": "data"}, want: []string{"b", "d"}},
+		{name: "filtered page", filters: map[string]string{"team": "platform"}, offset: 1, limit: 1, want: []string{"c"}},
+		{name: "filter without match", filters: map[string]string{"team": "none"}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := store.ListByMetadata(ctx, tt.filters, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatalf("ListByMetadata() error = %v", err)
+			}
+			got := make([]string, len(docs))
+			for i, doc := range docs {
+				got[i] = doc.ID
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListByMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := store.List(ctx, -1, 2); err == nil {
+		t.Error("List() expected error for negative offset")
+	}
+}
+
+func TestModule_ListDocuments(t *testing.T) {
+	module := newTestModule()
+	ctx := context.Background()
+	for _, id := range []string{"doc-1", "doc-2", "doc-3"} {
+		if err := module.AddDocument(ctx, id, "content of "+id, map[string]string{"kind": "guide"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page2, err := module.ListDocuments(ctx, 2, 2)
+	if err != nil {
+		t.Fatalf("ListDocuments() error = %v", err)
+	}
+	if len(page2) != 1 || page2[0].ID != "doc-3" {
+		t.Errorf("ListDocuments(2, 2) = %+v, want [doc-3]", page2)
+	}
+
+	guides, err := module.SearchByMetadata(ctx, map[string]string{"kind": "guide"})
+	if err != nil {
+		t.Fatalf("SearchByMetadata() error = %v", err)
+	}
+	if len(guides) != 3 {
+		t.Errorf("SearchByMetadata() = %d documents, want 3", len(guides))
+	}
+}
+
+func TestInMemoryVectorStore_Namespace(t *testing.T) {
+	root := NewInMemoryVectorStore()
+	tenantA := root.Namespace("tenant-a")
+	tenantB := root.Namespace("tenant-b")
+	ctx := context.Background()
+
+	if err := tenantA.Add(ctx, Document{ID: "secret", Content: "tenant a data", Embedding: []float32{1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := tenantB.Search(ctx, []float32{1, 0}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("tenant-b Search() = %+v, want no tenant-a documents", results)
+	}
+	if _, err := tenantB.Get(ctx, "secret"); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("tenant-b Get() error = %v, want ErrDocumentNotFound", err)
+	}
+	if err := tenantB.Delete(ctx, "secret"); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("tenant-b Delete() error = %v, want ErrDocumentNotFound", err)
+	}
+	if docs, _ := tenantB.List(ctx, 0, 0); len(docs) != 0 {
+		t.Errorf("tenant-b List() = %+v, want empty", docs)
+	}
+	if n, _ := root.Count(ctx); n != 0 {
+		t.Errorf("default namespace Count() = %d, want 0", n)
+	}
+
+	results, err = tenantA.Search(ctx, []float32{1, 0}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Document.Namespace != "tenant-a" {
+		t.Errorf("tenant-a Search() = %+v, want its document with namespace set", results)
+	}
+}
+
+func testExportDocuments(n int) []Document {
+	docs := make([]Document, n)
+	for i := range docs {
+		docs[i] = Document{
+			ID:       fmt.Sprintf("doc-%02d", i),
+			Content:  fmt.Sprintf("content %d", i),
+			Metadata: map[string]string{"index": fmt.Sprint(i)},
+			Embedding: []float32{
+				float32(math.Pi) / float32(i+1),
+				float32(math.Sqrt2) * float32(i),
+				-1e-7 * float32(i+1),
+			},
+		}
+	}
+	return docs
+}
+
+func TestInMemoryVectorStore_ExportImport(t *testing.T) {
+	ctx := context.Background()
+	source := NewInMemoryVectorStore()
+	docs := testExportDocuments(10)
+	if err := source.AddBatch(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := source.Export(ctx, &buf); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 10 {
+		t.Errorf("Export() wrote %d lines, want 10", lines)
+	}
+
+	target := NewInMemoryVectorStore()
+	if err := target.Import(ctx, &buf); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	for _, want := range docs {
+		got, err := target.Get(ctx, want.ID)
+		if err != nil {
+			t.Fatalf("Get(%q) after Import error = %v", want.ID, err)
+		}
+		if got.Content != want.Content || !reflect.DeepEqual(got.Metadata, want.Metadata) {
+			t.Errorf("Import() document = %+v, want %+v", got, want)
+		}
+		if len(got.Embedding) != len(want.Embedding) {
+			t.Fatalf("Import() embedding length = %d, want %d", len(got.Embedding), len(want.Embedding))
+		}
+		for i := range want.Embedding {
+			if math.Float32bits(got.Embedding[i]) != math.Float32bits(want.Embedding[i]) {
+				t.Errorf("Import() %s embedding[%d] = %v, want %v", want.ID, i, got.Embedding[i], want.Embedding[i])
+			}
+		}
+	}
+}
+
+func TestInMemoryVectorStore_ImportDeduplicates(t *testing.T) {
+	ctx := context.Background()
+	source := NewInMemoryVectorStore()
+	if err := source.AddBatch(ctx, testExportDocuments(10)); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := source.Export(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	target := NewInMemoryVectorStore()
+	existing := []Document{
+		{ID: "doc-00", Content: "stale", Embedding: []float32{1, 0, 0}},
+		{ID: "local", Content: "kept", Embedding: []float32{0, 1, 0}},
+	}
+	if err := target.AddBatch(ctx, existing); err != nil {
+		t.Fatal(err)
+	}
+	if err := target.Import(ctx, &buf); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if n, _ := target.Count(ctx); n != 11 {
+		t.Errorf("Count() after Import = %d, want 11", n)
+	}
+	if doc, _ := target.Get(ctx, "doc-00"); doc == nil || doc.Content != "content 0" {
+		t.Errorf("Get(doc-00) after Import = %+v, want imported document", doc)
+	}
+}
+
+func TestInMemoryVectorStore_ImportInvalid(t *testing.T) {
+	store := NewInMemoryVectorStore()
+	if err := store.Import(context.Background(), strings.NewReader("{not json}\n")); err == nil {
+		t.Error("Import() expected error for invalid JSON")
+	}
+}
+
+func TestModule_ExportImportKnowledgeBase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "kb.ndjson")
+
+	source := newTestModule()
+	if err := source.AddDocument(ctx, "guide", "deploy with kubernetes", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.ExportKnowledgeBase(ctx, path); err != nil {
+		t.Fatalf("ExportKnowledgeBase() error = %v", err)
+	}
+
+	target := newTestModule()
+	if err := target.ImportKnowledgeBase(ctx, path); err != nil {
+		t.Fatalf("ImportKnowledgeBase() error = %v", err)
+	}
+	doc, err := target.GetDocument(ctx, "guide")
+	if err != nil {
+		t.Fatalf("GetDocument() after import error = %v", err)
+	}
+	if doc.Content != "deploy with kubernetes" {
+		t.Errorf("GetDocument() content = %q, want %q", doc.Content, "deploy with kubernetes")
+	}
+}
+
+func TestInMemoryVectorStore_SearchTies(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryVectorStore()
+	for _, id := range []string{"d", "b", "e", "a", "c"} {
+		if err := store.Add(ctx, Document{ID: id, Embedding: []float32{1, 0}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Equal scores come back in ID order on every search
+	for i := 0; i < 5; i++ {
+		results, err := store.Search(ctx, []float32{1, 0}, 3, 0)
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		var ids []string
+		for _, result := range results {
+			ids = append(ids, result.Document.ID)
+		}
+		if want := []string{"a", "b", "c"}; !reflect.DeepEqual(ids, want) {
+			t.Fatalf("Search() = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestInMemoryVectorStore_SimilarityMetric(t *testing.T) {
+	docs := []Document{
+		{ID: "same", Embedding: []float32{1, 0}},
+		{ID: "long", Embedding: []float32{1.2, 0.4}},
+		{ID: "short", Embedding: []float32{0.5, 0.1}},
+	}
+
+	tests := []struct {
+		metric SimilarityMetric
+		want   []string
+	}{
+		{CosineSimilarity, []string{"same", "short", "long"}},
+		{DotProduct, []string{"long", "same", "short"}},
+		{EuclideanDistance, []string{"same", "long", "short"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.metric), func(t *testing.T) {
+			ctx := context.Background()
+			store := NewInMemoryVectorStoreWithConfig(InMemoryStoreConfig{Metric: tt.metric})
+			if err := store.AddBatch(ctx, docs); err != nil {
+				t.Fatal(err)
+			}
+
+			results, err := store.Search(ctx, []float32{1, 0}, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range results {
+				got = append(got, r.Document.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() ranking = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEuclideanSimilarity(t *testing.T) {
+	if got := euclideanSimilarity([]float32{1, 2}, []float32{1, 2}); got != 1 {
+		t.Errorf("euclideanSimilarity() identical = %v, want 1", got)
+	}
+	if got := euclideanSimilarity([]float32{0, 0}, []float32{3, 4}); math.Abs(float64(got)-1.0/6) > 1e-6 {
+		t.Errorf("euclideanSimilarity() distance 5 = %v, want %v", got, 1.0/6)
+	}
+}
+
+func TestDotProduct_NormalizedEqualsCosine(t *testing.T) {
+	docs := randomDocuments(20, 16, 1)
+	query := normalize(docs[0].Embedding)
+	for _, doc := range docs[1:] {
+		v := normalize(doc.Embedding)
+		dot, cos := dotProduct(query, v), cosineSimilarity(query, v)
+		if math.Abs(float64(dot-cos)) > 1e-6 {
+			t.Errorf("dotProduct() = %v, cosineSimilarity() = %v, want equal for normalized vectors", dot, cos)
+		}
+	}
+}
+
+func TestNewModule_SimilarityMetric(t *testing.T) {
+	if _, err := NewModule(Config{EmbeddingProvider: "openai", APIKey: "test", SimilarityMetric: "manhattan"}); err == nil {
+		t.Error("NewModule() expected error for unsupported similarity metric")
+	}
+}
+
+func TestInMemoryVectorStore_Dimensions(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		store   *InMemoryVectorStore
+		docs    []Document
+		wantErr bool
+		wantDim int
+	}{
+		{
+			name:    "inferred from first document",
+			store:   NewInMemoryVectorStore(),
+			docs:    []Document{{ID: "a", Embedding: []float32{1, 0}}, {ID: "b", Embedding: []float32{0, 1}}},
+			wantDim: 2,
+		},
+		{
+			name:    "mismatch after inference",
+			store:   NewInMemoryVectorStore(),
+			docs:    []Document{{ID: "a", Embedding: []float32{1, 0}}, {ID: "b", Embedding: []float32{0, 1, 0}}},
+			wantErr: true,
+			wantDim: 2,
+		},
+		{
+			name:    "configured dimension",
+			store:   NewInMemoryVectorStoreWithConfig(InMemoryStoreConfig{Dimensions: 3}),
+			docs:    []Document{{ID: "a", Embedding: []float32{1, 0}}},
+			wantErr: true,
+			wantDim: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			for _, doc := range tt.docs {
+				if err = tt.store.Add(ctx, doc); err != nil {
+					break
+				}
+			}
+			if tt.wantErr != errors.Is(err, ErrDimensionMismatch) {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := tt.store.Dimensions(); got != tt.wantDim {
+				t.Errorf("Dimensions() = %d, want %d", got, tt.wantDim)
+			}
+		})
+	}
+}
+
+func TestInMemoryVectorStore_DimensionsSharedAcrossNamespaces(t *testing.T) {
+	ctx := context.Background()
+	root := NewInMemoryVectorStore()
+	if err := root.Add(ctx, Document{ID: "a", Embedding: []float32{1, 0}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tenant := root.Namespace("tenant")
+	if err := tenant.Add(ctx, Document{ID: "b", Embedding: []float32{1, 0, 0}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Add() in namespace error = %v, want ErrDimensionMismatch", err)
+	}
+	if err := tenant.Update(ctx, Document{ID: "a", Embedding: []float32{1, 0, 0}}); !errors.Is(err, ErrDocumentNotFound) {
+		t.Errorf("Update() missing document error = %v, want ErrDocumentNotFound", err)
+	}
+	if err := root.Update(ctx, Document{ID: "a", Embedding: []float32{1, 0, 0}}); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("Update() error = %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestInMemoryVectorStore_Contract(t *testing.T) {
+	testVectorStoreContract(t, NewInMemoryVectorStore())
+}
+
+func TestInMemoryVectorStore_NamespaceContract(t *testing.T) {
+	testVectorStoreContract(t, NewInMemoryVectorStore().Namespace("tenant-a"))
+}
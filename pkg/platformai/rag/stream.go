@@ -0,0 +1,206 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// embeddingStreamBatchSize is the number of texts GenerateEmbeddingsStream embeds
+// per provider call when the provider has no batch size of its own
+const embeddingStreamBatchSize = 100
+
+// streamBatcher is implemented by providers whose GenerateEmbeddingsStream reads
+// batches of other than embeddingStreamBatchSize texts
+type streamBatcher interface {
+	streamBatchSize() int
+}
+
+// streamBatchSize returns how many texts embedder reads before returning results
+func streamBatchSize(embedder EmbeddingProvider) int {
+	if batcher, ok := embedder.(streamBatcher); ok && batcher.streamBatchSize() > 0 {
+		return batcher.streamBatchSize()
+	}
+	return embeddingStreamBatchSize
+}
+
+// streamEmbeddings implements GenerateEmbeddingsStream on top of a batch embedding
+// function. It reads up to size texts (embeddingStreamBatchSize when size is not
+// positive), embeds them, and only reads more once every result of the batch has
+// been received, so a slow consumer slows down the producer. A failed batch
+// yields one error result per text.
+func streamEmbeddings(ctx context.Context, texts <-chan string, size int, generate func(context.Context, []string) ([][]float32, error)) (<-chan EmbeddingResult, error) {
+	if texts == nil {
+		return nil, fmt.Errorf("texts channel is nil")
+	}
+	if size <= 0 {
+		size = embeddingStreamBatchSize
+	}
+
+	results := make(chan EmbeddingResult)
+	go func() {
+		defer close(results)
+
+		batch := make([]string, 0, size)
+		for open := true; open; {
+			batch = batch[:0]
+			for open && len(batch) < size {
+				select {
+				case text, ok := <-texts:
+					if !ok {
+						open = false
+						continue
+					}
+					batch = append(batch, text)
+				case <-ctx.Done():
+					return
+				}
+			}
+			if len(batch) == 0 {
+				continue
+			}
+
+			embeddings, err := generate(ctx, batch)
+			if err == nil && len(embeddings) != len(batch) {
+				err = fmt.Errorf("embedding provider returned %d embeddings for %d texts", len(embeddings), len(batch))
+			}
+			for i := range batch {
+				result := EmbeddingResult{Err: err}
+				if err == nil {
+					result.Embedding = embeddings[i]
+				}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return results, nil
+}
+
+// AddDocumentsStream embeds and stores documents as they arrive, for document
+// sets too large to hold in memory. Embedding happens in batches while later
+// documents are still being produced; a slow store slows down reading from docs.
+//
+// The returned channel reports one error per document that could not be stored
+// and is closed once docs is closed and every document has been handled, or ctx
+// is done. The caller must drain it.
+func (m *Module) AddDocumentsStream(ctx context.Context, docs <-chan struct {
+	ID       string
+	Content  string
+	Metadata map[string]string
+}) (<-chan error, error) {
+	if docs == nil {
+		return nil, fmt.Errorf("documents channel is nil")
+	}
+
+	texts := make(chan string)
+	results, err := m.embedder.GenerateEmbeddingsStream(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start embedding stream: %w", err)
+	}
+
+	errs := make(chan error)
+	report := func(err error) bool {
+		select {
+		case errs <- err:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	// Documents waiting for their embedding, in the order their texts were sent.
+	// The buffer holds a full embedding batch so the embedder never waits on it.
+	pending := make(chan Document, 2*streamBatchSize(m.embedder))
+
+	// errs is closed once both the producer and the consumer below have stopped
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		wg.Wait()
+		close(errs)
+	}()
+
+	go func() {
+		defer wg.Done()
+		defer close(texts)
+		defer close(pending)
+		for {
+			var in struct {
+				ID       string
+				Content  string
+				Metadata map[string]string
+			}
+			var ok bool
+			select {
+			case in, ok = <-docs:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+
+			content, err := m.resolveCoreferences(ctx, in.Content)
+			if err != nil {
+				if !report(fmt.Errorf("document %s: %w", in.ID, err)) {
+					return
+				}
+				continue
+			}
+			metadata, err := m.enrichMetadata(ctx, content, in.Metadata)
+			if err != nil {
+				if !report(fmt.Errorf("document %s: %w", in.ID, err)) {
+					return
+				}
+				continue
+			}
+			doc := Document{ID: in.ID, Content: content, Metadata: metadata, Language: metadata[LanguageMetadataKey]}
+			select {
+			case pending <- doc:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case texts <- doc.Content:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for doc := range pending {
+			var result EmbeddingResult
+			select {
+			case r, ok := <-results:
+				if !ok {
+					return
+				}
+				result = r
+			case <-ctx.Done():
+				return
+			}
+
+			if result.Err != nil {
+				if !report(fmt.Errorf("document %s: failed to generate embedding: %w", doc.ID, result.Err)) {
+					return
+				}
+				continue
+			}
+			doc.Embedding = m.retriever.prepareEmbedding(result.Embedding)
+			if err := m.store.Add(ctx, doc); err != nil {
+				if !report(fmt.Errorf("document %s: failed to add document: %w", doc.ID, err)) {
+					return
+				}
+				continue
+			}
+			m.indexKeywords(doc)
+		}
+	}()
+	return errs, nil
+}
@@ -0,0 +1,157 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamEmbeddings(t *testing.T) {
+	provider := NewMockEmbeddingProvider(8)
+	texts := make(chan string)
+	results, err := provider.GenerateEmbeddingsStream(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("GenerateEmbeddingsStream() error = %v", err)
+	}
+
+	go func() {
+		defer close(texts)
+		for i := 0; i < 250; i++ {
+			texts <- fmt.Sprintf("text %d", i)
+		}
+	}()
+
+	n := 0
+	for result := range results {
+		if result.Err != nil {
+			t.Fatalf("result %d error = %v", n, result.Err)
+		}
+		if len(result.Embedding) != 8 {
+			t.Errorf("result %d has %d dimensions, want 8", n, len(result.Embedding))
+		}
+		n++
+	}
+	if n != 250 {
+		t.Errorf("received %d results, want 250", n)
+	}
+	// 250 texts in batches of 100
+	if got := provider.Calls(); got != 3 {
+		t.Errorf("provider calls = %d, want 3", got)
+	}
+}
+
+func TestStreamEmbeddings_BatchError(t *testing.T) {
+	texts := make(chan string, 3)
+	texts <- "a"
+	texts <- "b"
+	close(texts)
+
+	results, err := streamEmbeddings(context.Background(), texts, 0, func(ctx context.Context, texts []string) ([][]float32, error) {
+		return nil, errors.New("service unavailable")
+	})
+	if err != nil {
+		t.Fatalf("streamEmbeddings() error = %v", err)
+	}
+	n := 0
+	for result := range results {
+		if result.Err == nil {
+			t.Errorf("result %d has no error", n)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("received %d results, want one per text", n)
+	}
+}
+
+func TestModule_AddDocumentsStream(t *testing.T) {
+	ctx := context.Background()
+	m := newTestModule()
+
+	docs := make(chan struct {
+		ID       string
+		Content  string
+		Metadata map[string]string
+	})
+	errs, err := m.AddDocumentsStream(ctx, docs)
+	if err != nil {
+		t.Fatalf("AddDocumentsStream() error = %v", err)
+	}
+
+	go func() {
+		defer close(docs)
+		for i := 0; i < 500; i++ {
+			docs <- struct {
+				ID       string
+				Content  string
+				Metadata map[string]string
+			}{ID: fmt.Sprintf("doc-%03d", i), Content: fmt.Sprintf("document number %d", i)}
+		}
+	}()
+
+	for err := range errs {
+		t.Errorf("AddDocumentsStream() reported error: %v", err)
+	}
+	if n, _ := m.Count(ctx); n != 500 {
+		t.Errorf("Count() = %d, want 500", n)
+	}
+	doc, err := m.GetDocument(ctx, "doc-499")
+	if err != nil {
+		t.Fatalf("GetDocument() error = %v", err)
+	}
+	if doc.Content != "document number 499" || len(doc.Embedding) == 0 {
+		t.Errorf("GetDocument() = %+v, want stored embedded document", doc)
+	}
+}
+
+func TestModule_AddDocumentsStreamLargeBatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var calls atomic.Int32
+	client := NewVoyageEmbeddingClient("test", "voyage-3")
+	client.MaxBatchSize = 300
+	client.SetHTTPClient(newEmbeddingServer(t, &calls))
+	m := newTestModule()
+	m.SetEmbeddingProvider(client)
+
+	docs := make(chan struct {
+		ID       string
+		Content  string
+		Metadata map[string]string
+	})
+	errs, err := m.AddDocumentsStream(ctx, docs)
+	if err != nil {
+		t.Fatalf("AddDocumentsStream() error = %v", err)
+	}
+	go func() {
+		defer close(docs)
+		for i := 0; i < 500; i++ {
+			select {
+			case docs <- struct {
+				ID       string
+				Content  string
+				Metadata map[string]string
+			}{ID: fmt.Sprintf("doc-%03d", i), Content: fmt.Sprintf("document number %d", i)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for err := range errs {
+		t.Errorf("AddDocumentsStream() reported error: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("AddDocumentsStream() did not finish with a batch size above the pending buffer")
+	}
+	if n, _ := m.Count(ctx); n != 500 {
+		t.Errorf("Count() = %d, want 500", n)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("API calls = %d, want 2 batches of up to 300", got)
+	}
+}
@@ -0,0 +1,83 @@
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// defaultSyncBatchSize is the number of documents SyncStores lists at a time when BatchSize is unset
+const defaultSyncBatchSize = 100
+
+// SyncOptions configures SyncStores
+type SyncOptions struct {
+	BatchSize int  // Documents listed from the source at a time (default: 100)
+	Overwrite bool // Replace documents that already exist in the destination
+	DryRun    bool // Report what would be copied without writing anything
+
+	// Logger receives per-document errors (default: log.Default())
+	Logger interface{ Printf(format string, v ...any) }
+}
+
+// SyncReport counts the outcome of a SyncStores call. In a dry run, Copied is
+// the number of documents that would have been copied.
+type SyncReport struct {
+	Copied  int
+	Skipped int // Already present in the destination
+	Failed  int
+}
+
+// SyncStores copies every document in src to dst, for example to warm an
+// in-memory store from a persistent one. Documents that fail to copy are logged
+// and counted in the report without stopping the sync; an error is only returned
+// when src cannot be listed.
+func SyncStores(ctx context.Context, src, dst VectorStore, opts SyncOptions) (SyncReport, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSyncBatchSize
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	var report SyncReport
+	for offset := 0; ; offset += batchSize {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		docs, err := src.List(ctx, offset, batchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to list source documents: %w", err)
+		}
+
+		for _, doc := range docs {
+			if !opts.Overwrite {
+				_, err := dst.Get(ctx, doc.ID)
+				if err == nil {
+					report.Skipped++
+					continue
+				}
+				if !errors.Is(err, ErrDocumentNotFound) {
+					logger.Printf("rag: sync: failed to check document %s: %v", doc.ID, err)
+					report.Failed++
+					continue
+				}
+			}
+
+			if !opts.DryRun {
+				if err := dst.Add(ctx, doc); err != nil {
+					logger.Printf("rag: sync: failed to copy document %s: %v", doc.ID, err)
+					report.Failed++
+					continue
+				}
+			}
+			report.Copied++
+		}
+
+		if len(docs) < batchSize {
+			return report, nil
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package rag
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestSyncStores(t *testing.T) {
+	ctx := context.Background()
+	src := NewInMemoryVectorStore()
+	for i := 0; i < 5; i++ {
+		doc := Document{ID: fmt.Sprintf("doc-%d", i), Content: "content", Embedding: []float32{1, 0, 0}}
+		if err := src.Add(ctx, doc); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	dst := NewInMemoryVectorStore()
+	if err := dst.Add(ctx, Document{ID: "doc-0", Content: "stale", Embedding: []float32{0, 1, 0}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	report, err := SyncStores(ctx, src, dst, SyncOptions{BatchSize: 2, DryRun: true})
+	if err != nil {
+		t.Fatalf("SyncStores() dry run error = %v", err)
+	}
+	if want := (SyncReport{Copied: 4, Skipped: 1}); report != want {
+		t.Errorf("SyncStores() dry run report = %+v, want %+v", report, want)
+	}
+	if n, _ := dst.Count(ctx); n != 1 {
+		t.Errorf("dry run wrote documents: destination has %d, want 1", n)
+	}
+
+	report, err = SyncStores(ctx, src, dst, SyncOptions{BatchSize: 2})
+	if err != nil {
+		t.Fatalf("SyncStores() error = %v", err)
+	}
+	if want := (SyncReport{Copied: 4, Skipped: 1}); report != want {
+		t.Errorf("SyncStores() report = %+v, want %+v", report, want)
+	}
+	if n, _ := dst.Count(ctx); n != 5 {
+		t.Errorf("destination has %d documents, want 5", n)
+	}
+	if doc, _ := dst.Get(ctx, "doc-0"); doc.Content != "stale" {
+		t.Errorf("existing document content = %q, want it left alone", doc.Content)
+	}
+
+	report, err = SyncStores(ctx, src, dst, SyncOptions{Overwrite: true})
+	if err != nil {
+		t.Fatalf("SyncStores() overwrite error = %v", err)
+	}
+	if want := (SyncReport{Copied: 5}); report != want {
+		t.Errorf("SyncStores() overwrite report = %+v, want %+v", report, want)
+	}
+	if doc, _ := dst.Get(ctx, "doc-0"); doc.Content != "content" {
+		t.Errorf("overwritten document content = %q, want content", doc.Content)
+	}
+}
+
+func TestSyncStores_Failures(t *testing.T) {
+	ctx := context.Background()
+	src := NewInMemoryVectorStore()
+	_ = src.Add(ctx, Document{ID: "a", Embedding: []float32{1, 0}})
+	_ = src.Add(ctx, Document{ID: "b", Embedding: []float32{1, 0}})
+
+	// The destination expects 3 dimensions, so every copy fails
+	dst := NewInMemoryVectorStoreWithConfig(InMemoryStoreConfig{Dimensions: 3})
+	logger := &recordingLogger{}
+	report, err := SyncStores(ctx, src, dst, SyncOptions{Logger: logger})
+	if err != nil {
+		t.Fatalf("SyncStores() error = %v", err)
+	}
+	if want := (SyncReport{Failed: 2}); report != want {
+		t.Errorf("SyncStores() report = %+v, want %+v", report, want)
+	}
+	if len(logger.messages) != 2 {
+		t.Errorf("logged %d errors, want 2", len(logger.messages))
+	}
+}
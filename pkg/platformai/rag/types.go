@@ -1,13 +1,30 @@
 package rag
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
 
 // Document represents a document stored in the RAG system
 type Document struct {
-	ID        string            // Unique identifier
-	Content   string            // Document content
-	Metadata  map[string]string // Optional metadata (e.g., source, title, category)
-	Embedding []float32         // Vector embedding of the document
+	ID        string            `json:"id"`                  // Unique identifier
+	Content   string            `json:"content"`             // Document content
+	Metadata  map[string]string `json:"metadata,omitempty"`  // Optional metadata (e.g., source, title, category)
+	Embedding []float32         `json:"embedding"`           // Vector embedding of the document
+	Namespace string            `json:"namespace,omitempty"` // Tenant namespace, set by the store the document lives in
+
+	// SparseVector is an optional sparse embedding searched by VectorStore.SparseSearch
+	SparseVector SparseVector `json:"sparse_vector,omitempty"`
+
+	// RelatedIDs lists documents this one references. Module.AddDocuments records
+	// them as relations of weight 1; GraphExpand fills them in from the relation graph.
+	RelatedIDs []string `json:"related_ids,omitempty"`
+
+	// Language is the ISO 639-1 code detected on ingest when the module has a
+	// LanguageDetector; it mirrors Metadata[LanguageMetadataKey]
+	Language string `json:"language,omitempty"`
 }
 
 // Query represents a search query
@@ -20,7 +37,7 @@ type Query struct {
 // SearchResult represents a document with similarity score
 type SearchResult struct {
 	Document Document
-	Score    float32 // Cosine similarity score (0-1)
+	Score    float32 // Similarity score under the store's SimilarityMetric; higher is more similar
 }
 
 // EmbeddingProvider defines the interface for generating embeddings
@@ -30,19 +47,66 @@ type EmbeddingProvider interface {
 
 	// GenerateEmbeddings generates embedding vectors for multiple texts
 	GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error)
+
+	// GenerateEmbeddingsStream embeds texts as they arrive, sending one result per
+	// text in input order. The result channel is closed once texts is closed and
+	// drained, or ctx is done.
+	GenerateEmbeddingsStream(ctx context.Context, texts <-chan string) (<-chan EmbeddingResult, error)
+}
+
+// EmbeddingResult is one result of GenerateEmbeddingsStream
+type EmbeddingResult struct {
+	Embedding []float32
+	Err       error
+}
+
+// DocumentLoader loads the documents of one source
+type DocumentLoader interface {
+	Load(ctx context.Context) ([]Document, error)
+}
+
+// SourceLoader loads documents from a source such as a file path or URL
+type SourceLoader interface {
+	Load(ctx context.Context, source string) ([]Document, error)
+}
+
+// FromSource returns a DocumentLoader that loads source with loader
+func FromSource(loader SourceLoader, source string) DocumentLoader {
+	return boundLoader{loader: loader, source: source}
+}
+
+// boundLoader is a SourceLoader bound to one source
+type boundLoader struct {
+	loader SourceLoader
+	source string
+}
+
+func (l boundLoader) Load(ctx context.Context) ([]Document, error) {
+	return l.loader.Load(ctx, l.source)
 }
 
 // VectorStore defines the interface for storing and searching documents
 type VectorStore interface {
-	// Add adds a document to the store
+	// Add adds a document to the store, replacing any document with the same ID
 	Add(ctx context.Context, doc Document) error
 
+	// Update replaces an existing document, returning ErrDocumentNotFound if it is absent
+	Update(ctx context.Context, doc Document) error
+
 	// AddBatch adds multiple documents to the store
 	AddBatch(ctx context.Context, docs []Document) error
 
 	// Search finds similar documents based on query embedding
 	Search(ctx context.Context, queryEmbedding []float32, topK int, minScore float32) ([]SearchResult, error)
 
+	// SearchWithFilter is like Search but only considers documents whose metadata
+	// matches every filter key/value exactly
+	SearchWithFilter(ctx context.Context, queryEmbedding []float32, topK int, minScore float32, filters map[string]string) ([]SearchResult, error)
+
+	// SparseSearch finds documents by the dot product of their SparseVector with
+	// query. Documents without a sparse vector or without shared terms are skipped.
+	SparseSearch(ctx context.Context, query SparseVector, topK int) ([]SearchResult, error)
+
 	// Get retrieves a document by ID
 	Get(ctx context.Context, id string) (*Document, error)
 
@@ -51,21 +115,105 @@ type VectorStore interface {
 
 	// Count returns the total number of documents
 	Count(ctx context.Context) (int, error)
+
+	// List returns documents ordered by ID, skipping offset and returning at most
+	// limit documents (limit <= 0 means no limit)
+	List(ctx context.Context, offset, limit int) ([]Document, error)
+
+	// ListByMetadata is like List but only includes documents matching every filter
+	ListByMetadata(ctx context.Context, filters map[string]string, offset, limit int) ([]Document, error)
+}
+
+// KeyRotator is implemented by embedding providers whose API key can be replaced at runtime
+type KeyRotator interface {
+	RotateAPIKey(newKey string) error
 }
 
 // Config holds RAG module configuration
 type Config struct {
-	EmbeddingProvider string // Provider for embeddings ("anthropic", "voyageai", "openai")
-	APIKey            string // API key for embedding provider
-	Model             string // Model name for embeddings
-	EmbeddingDim      int    // Embedding dimension
+	EmbeddingProvider string           `yaml:"embedding_provider"` // Provider for embeddings ("voyageai", "openai", "cohere")
+	APIKey            string           `yaml:"api_key"`            // API key for embedding provider
+	Model             string           `yaml:"model"`              // Model name for embeddings
+	EmbeddingDim      int              `yaml:"embedding_dim"`      // Embedding dimension
+	DefaultNamespace  string           `yaml:"default_namespace"`  // Namespace the module stores documents in (default: "")
+	SimilarityMetric  SimilarityMetric `yaml:"similarity_metric"`  // Scoring function for search (default: CosineSimilarity)
+	SnapshotDir       string           `yaml:"snapshot_dir"`       // Directory Module.Snapshot writes to (required for snapshots)
+	Freshness         FreshnessConfig  `yaml:"freshness"`          // Optional age penalty applied to search scores
+	ExpectedDocuments int              `yaml:"expected_documents"` // Approximate knowledge base size, used by WithAutoModelSelection
+
+	// NormalizeEmbeddings L2-normalizes document and query embeddings before they
+	// reach the store. Combined with DotProduct this scores like CosineSimilarity.
+	NormalizeEmbeddings bool `yaml:"normalize_embeddings"`
+
+	// FallbackEmbeddingProviders are tried in order when the primary provider fails.
+	// Only their EmbeddingProvider, APIKey, Model, and EmbeddingDim fields are used; proxy settings
+	// are inherited from the primary. Each must produce embeddings of the primary's
+	// dimension, see NewEmbeddingProvider.
+	FallbackEmbeddingProviders []Config `yaml:"fallback_embedding_providers"`
+
+	// ProxyURL routes embedding requests through an HTTP(S) proxy. Empty uses the
+	// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+	ProxyURL string `yaml:"proxy_url"`
+
+	// Timeout bounds each embedding API call (default: 30s)
+	Timeout time.Duration `yaml:"timeout"`
+
+	// ConnectionPool sizes the HTTP connection pool to the embedding API
+	ConnectionPool llm.ConnectionPoolConfig `yaml:"connection_pool"`
+
+	// CustomHeaders are sent with every embedding request, including to fallback
+	// providers. Credential headers such as authorization are rejected.
+	CustomHeaders map[string]string `yaml:"custom_headers"`
+
+	// TLSInsecureSkipVerify disables TLS certificate verification.
+	//
+	// Deprecated: install the proxy's CA certificate instead; this option will be removed.
+	TLSInsecureSkipVerify bool `yaml:"tls_insecure_skip_verify"`
 }
 
+// SimilarityMetric selects how the vector store scores a document against a query.
+// Higher scores always mean more similar.
+type SimilarityMetric string
+
+const (
+	// CosineSimilarity scores by the angle between vectors, ignoring magnitude (-1 to 1)
+	CosineSimilarity SimilarityMetric = "cosine"
+
+	// DotProduct scores by the raw inner product, for embeddings whose magnitude carries meaning
+	DotProduct SimilarityMetric = "dot"
+
+	// EuclideanDistance scores by 1 / (1 + L2 distance), ranging from 0 to 1
+	EuclideanDistance SimilarityMetric = "euclidean"
+)
+
 // RetrieveRequest represents a request to retrieve relevant documents
 type RetrieveRequest struct {
 	Query    string  // Query text
 	TopK     int     // Number of documents to retrieve (default: 3)
 	MinScore float32 // Minimum similarity score (default: 0.0)
+
+	Filters       map[string]string // Optional exact-match metadata filters
+	WithCitations bool              // Number documents [1], [2], ... and append a references section
+
+	// ContextTemplate is a text/template executed once per document to build the context,
+	// with the fields of ContextDocument (see DefaultContextTemplate). Empty uses the built-in format.
+	ContextTemplate string
+
+	// ExpandRelations appends documents one relation hop away from the results,
+	// scored by the linking result's score times the relation weight
+	ExpandRelations bool
+
+	// TraverseGraph appends the documents one DocumentGraph edge away from the
+	// results, such as the documents they cite, with the citing result's score
+	TraverseGraph bool
+
+	// Language restricts retrieval to documents detected as this ISO 639-1 code
+	Language string
+
+	// Namespace retrieves from this namespace instead of the module's
+	// (Config.DefaultNamespace). Hybrid search and importance boosting only
+	// apply to the module's own namespace.
+	Namespace string
 }
 
 // RetrieveResponse represents retrieved documents with context
@@ -73,4 +221,5 @@ type RetrieveResponse struct {
 	Results        []SearchResult // Retrieved documents with scores
 	Context        string         // Formatted context for LLM
 	QueryEmbedding []float32      // Embedding of the query
+	RewrittenQuery string         // Query that was embedded, set when a QueryRewriter is configured
 }
@@ -2,61 +2,267 @@ package platformai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
 
 	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/codemapping"
 	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
 	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SDK is the main entry point for the Platform AI SDK
 type SDK struct {
-	config    *Config
-	llmClient llm.Client
-	ragModule *rag.Module
+	cfgMu       sync.RWMutex // Guards config, baseLLM, ragModule, and codeMapping against ApplyConfig
+	config      *Config
+	baseLLM     llm.Client // Provider client
+	llmClient   llm.Client // baseLLM wrapped to track in-flight calls
+	ragModule   *rag.Module
+	codeMapping *codemapping.Module // Created on first use; reset when ApplyConfig changes the analyze timeout
+	httpClient  *http.Client        // Set by WithHTTPClient; reused when ApplyConfig creates clients
+	wrappers    []llmWrapper        // Set by WithRouter and WithFallback; reapplied when ApplyConfig creates clients
+
+	logger      Logger
+	tracer      trace.Tracer
+	auditLogger AuditLogger
+	rateLimiter RateLimiter
+	analytics   *Analytics
+
+	mu       sync.Mutex // Guards draining and inFlight.Add
+	draining bool
+	inFlight sync.WaitGroup
+
+	wsMu       sync.Mutex // Guards workspaces
+	workspaces map[string]*Workspace
 }
 
-// New creates a new SDK instance
-func New(ctx context.Context, config *Config) (*SDK, error) {
+// New creates a new SDK instance. Options are applied after the LLM client and
+// RAG module are created.
+func New(ctx context.Context, config *Config, opts ...Option) (*SDK, error) {
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
 	// Initialize LLM client
-	llmClient, err := llm.NewClient(llm.Config{
-		Provider:    config.LLM.Provider,
-		APIKey:      config.LLM.APIKey,
-		Model:       config.LLM.Model,
-		Temperature: config.LLM.Temperature,
-		MaxTokens:   config.LLM.MaxTokens,
-	})
+	llmClient, err := newLLMClient(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create LLM client: %w", err)
+		return nil, err
 	}
 
+	sdk := &SDK{config: config, analytics: NewAnalytics(nil)}
+	sdk.setLLMClient(llmClient)
+
 	// Initialize RAG module if configured
-	var ragModule *rag.Module
 	if config.RAG != nil {
-		ragModule, err = rag.NewModule(*config.RAG)
+		sdk.ragModule, err = rag.NewModule(ragConfig(config), rag.WithLLM(sdk.llmClient))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create RAG module: %w", err)
 		}
 	}
 
-	return &SDK{
-		config:    config,
-		llmClient: llmClient,
-		ragModule: ragModule,
-	}, nil
+	for _, opt := range opts {
+		if err := opt(sdk); err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+
+	return sdk, nil
+}
+
+// llmConfig converts the SDK's LLM configuration to the llm package's
+func llmConfig(cfg *Config) llm.Config {
+	config := cfg.LLM
+	return llm.Config{
+		Provider:    config.Provider,
+		APIKey:      config.APIKey,
+		Model:       config.Model,
+		Temperature: config.Temperature,
+		MaxTokens:   config.MaxTokens,
+
+		SystemPromptPrefix: config.SystemPromptPrefix,
+
+		ProxyURL:              config.ProxyURL,
+		TLSInsecureSkipVerify: config.TLSInsecureSkipVerify,
+		TLS:                   config.TLS,
+		ConnectionPool:        config.ConnectionPool,
+
+		Timeout: cfg.Timeouts.GenerateTimeout,
+	}
+}
+
+// ragConfig returns the RAG configuration with the SDK's embedding timeout
+// applied unless it sets its own. config.RAG must not be nil.
+func ragConfig(config *Config) rag.Config {
+	ragCfg := *config.RAG
+	if ragCfg.Timeout <= 0 {
+		ragCfg.Timeout = config.Timeouts.EmbeddingTimeout
+	}
+	return ragCfg
+}
+
+// newLLMClient creates the provider client described by config
+func newLLMClient(config *Config) (llm.Client, error) {
+	client, err := llm.NewClient(llmConfig(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LLM client: %w", err)
+	}
+	return client, nil
+}
+
+// llmWrapper wraps the provider client created from config
+type llmWrapper func(client llm.Client, config *Config) llm.Client
+
+// keyRotator is implemented by LLM clients whose API key can be replaced in place
+type keyRotator interface {
+	RotateAPIKey(newKey string) error
+}
+
+// RotateLLMKey replaces the LLM client's API key for new requests
+func (s *SDK) RotateLLMKey(newKey string) error {
+	rotator, ok := s.provider().(keyRotator)
+	if !ok {
+		return fmt.Errorf("%w: LLM client does not support key rotation", errors.ErrUnsupported)
+	}
+	return rotator.RotateAPIKey(newKey)
+}
+
+// RotateEmbeddingKey replaces the RAG embedding provider's API key for new requests
+func (s *SDK) RotateEmbeddingKey(newKey string) error {
+	ragModule := s.RAG()
+	if ragModule == nil {
+		return fmt.Errorf("%w: RAG is not configured", ErrInvalidConfig)
+	}
+	return ragModule.RotateEmbeddingKey(newKey)
+}
+
+// ApplyConfig validates cfg and switches the SDK over to it. Calls already in
+// flight finish on the previous clients. When only an API key changed the
+// existing client is rotated in place. Any other RAG change creates a new RAG
+// module that keeps the indexed documents, re-embedding them when the embedding
+// model or dimensions changed (see rag.Module.Reconfigure).
+func (s *SDK) ApplyConfig(cfg *Config) error {
+	if cfg == nil {
+		return fmt.Errorf("%w: config is nil", ErrInvalidConfig)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+
+	old := s.config
+	if old == nil {
+		old = &Config{}
+	}
+
+	// Create replacements before changing anything so a failure leaves the SDK as it was
+	baseLLM := s.baseLLM
+	rotateLLM := false
+	timeoutChanged := cfg.Timeouts.GenerateTimeout != old.Timeouts.GenerateTimeout
+	if cfg.LLM != old.LLM || timeoutChanged {
+		_, canRotate := s.baseLLM.(keyRotator)
+		if canRotate && !timeoutChanged && onlyLLMKeyChanged(old.LLM, cfg.LLM) {
+			rotateLLM = true
+		} else {
+			client, err := newLLMClient(cfg)
+			if err != nil {
+				return err
+			}
+			for _, wrap := range s.wrappers {
+				client = wrap(client, cfg)
+			}
+			if s.httpClient != nil {
+				if setter, ok := client.(interface{ SetHTTPClient(*http.Client) }); ok {
+					setter.SetHTTPClient(s.httpClient)
+				}
+			}
+			baseLLM = client
+		}
+	}
+
+	ragModule := s.ragModule
+	if !reflect.DeepEqual(cfg.RAG, old.RAG) || cfg.Timeouts.EmbeddingTimeout != old.Timeouts.EmbeddingTimeout {
+		rotated := false
+		if s.ragModule != nil && old.RAG != nil && cfg.RAG != nil && onlyRAGKeyChanged(ragConfig(old), ragConfig(cfg)) {
+			rotated = s.ragModule.RotateEmbeddingKey(cfg.RAG.APIKey) == nil
+		}
+		switch {
+		case rotated:
+		case cfg.RAG == nil:
+			ragModule = nil
+		default:
+			var module *rag.Module
+			var err error
+			if s.ragModule != nil {
+				module, err = s.ragModule.Reconfigure(context.Background(), ragConfig(cfg), rag.WithLLM(s.llmClient))
+			} else {
+				module, err = rag.NewModule(ragConfig(cfg), rag.WithLLM(s.llmClient))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to create RAG module: %w", err)
+			}
+			if s.httpClient != nil {
+				module.SetHTTPClient(s.httpClient)
+			}
+			ragModule = module
+		}
+	}
+
+	if rotateLLM {
+		if err := s.baseLLM.(keyRotator).RotateAPIKey(cfg.LLM.APIKey); err != nil {
+			return fmt.Errorf("failed to rotate LLM API key: %w", err)
+		}
+	}
+
+	if cfg.Timeouts.AnalyzeTimeout != old.Timeouts.AnalyzeTimeout {
+		s.codeMapping = nil
+	}
+	s.config = cfg
+	s.baseLLM = baseLLM
+	s.ragModule = ragModule
+	return nil
+}
+
+// onlyLLMKeyChanged reports whether a and b differ in nothing but the API key
+func onlyLLMKeyChanged(a, b LLMConfig) bool {
+	a.APIKey = b.APIKey
+	return a == b
+}
+
+// onlyRAGKeyChanged reports whether a and b differ in nothing but the API key
+func onlyRAGKeyChanged(a, b rag.Config) bool {
+	a.APIKey = b.APIKey
+	return reflect.DeepEqual(a, b)
 }
 
-// CodeMapping returns the code mapping module
+// provider returns the current provider client
+func (s *SDK) provider() llm.Client {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	return s.baseLLM
+}
+
+// CodeMapping returns the code mapping module. Every call returns the same module,
+// so rules and detectors registered on it apply to later calls, until ApplyConfig
+// changes Timeouts.AnalyzeTimeout and replaces it.
 func (s *SDK) CodeMapping() *codemapping.Module {
-	return codemapping.NewModule(s.llmClient)
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+
+	if s.codeMapping == nil {
+		s.codeMapping = codemapping.NewModule(s.llmClient, codemapping.WithAnalyzeTimeout(s.config.Timeouts.AnalyzeTimeout))
+	}
+	return s.codeMapping
 }
 
 // RAG returns the RAG module
 func (s *SDK) RAG() *rag.Module {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
 	return s.ragModule
 }
 
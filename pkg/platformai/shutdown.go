@@ -0,0 +1,140 @@
+package platformai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/codemapping"
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// Shutdown stops the SDK from accepting new calls and waits for in-flight LLM
+// and retrieval calls to finish. It returns ctx.Err() if ctx ends first; calls
+// still running at that point are left to complete on their own.
+func (s *SDK) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Retrieve retrieves relevant documents from the RAG module. Unlike calling
+// RAG().Retrieve directly, the call is waited for by Shutdown.
+func (s *SDK) Retrieve(ctx context.Context, req rag.RetrieveRequest) (*rag.RetrieveResponse, error) {
+	ragModule := s.RAG()
+	if ragModule == nil {
+		return nil, fmt.Errorf("%w: RAG is not configured", ErrInvalidConfig)
+	}
+	var resp *rag.RetrieveResponse
+	err := s.track(ctx, "rag.Retrieve", func(ctx context.Context) (llm.Usage, error) {
+		var err error
+		resp, err = ragModule.Retrieve(ctx, req)
+		return llm.Usage{}, err
+	})
+	return resp, err
+}
+
+// Analyze analyzes a repository with the code mapping module. Unlike calling
+// CodeMapping().Analyze directly, the call is waited for by Shutdown and
+// recorded in analytics.
+func (s *SDK) Analyze(ctx context.Context, req codemapping.AnalyzeRequest) (*codemapping.AnalyzeResult, error) {
+	var result *codemapping.AnalyzeResult
+	err := s.track(ctx, "codemapping.Analyze", func(ctx context.Context) (llm.Usage, error) {
+		var err error
+		result, err = s.CodeMapping().Analyze(ctx, req)
+		return llm.Usage{}, err
+	})
+	return result, err
+}
+
+// begin registers an in-flight call, failing once Shutdown has been called
+func (s *SDK) begin() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.draining {
+		return ErrSDKShutdown
+	}
+	s.inFlight.Add(1)
+	return nil
+}
+
+// setLLMClient installs the provider client behind a wrapper that tracks in-flight calls
+func (s *SDK) setLLMClient(client llm.Client) {
+	s.cfgMu.Lock()
+	s.baseLLM = client
+	s.cfgMu.Unlock()
+	s.llmClient = &trackedClient{sdk: s}
+}
+
+// trackedClient routes every call through SDK.track so Shutdown can wait for it
+// and options can observe it. It always forwards to the SDK's current provider
+// client, so callers holding it pick up clients installed by ApplyConfig.
+type trackedClient struct {
+	sdk *SDK
+}
+
+// Generate forwards to the wrapped client
+func (c *trackedClient) Generate(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+	var resp *llm.GenerateResponse
+	err := c.sdk.track(ctx, "llm.Generate", func(ctx context.Context) (llm.Usage, error) {
+		var err error
+		resp, err = c.sdk.provider().Generate(ctx, req)
+		if err != nil {
+			return llm.Usage{}, err
+		}
+		return resp.Usage, nil
+	})
+	return resp, err
+}
+
+// GenerateWithContext forwards to the wrapped client
+func (c *trackedClient) GenerateWithContext(ctx context.Context, req llm.GenerateRequest, additionalContext string) (*llm.GenerateResponse, error) {
+	var resp *llm.GenerateResponse
+	err := c.sdk.track(ctx, "llm.GenerateWithContext", func(ctx context.Context) (llm.Usage, error) {
+		var err error
+		resp, err = c.sdk.provider().GenerateWithContext(ctx, req, additionalContext)
+		if err != nil {
+			return llm.Usage{}, err
+		}
+		return resp.Usage, nil
+	})
+	return resp, err
+}
+
+// EstimateCost forwards to the wrapped client
+func (c *trackedClient) EstimateCost(req llm.GenerateRequest) (llm.EstimatedCost, error) {
+	return c.sdk.provider().EstimateCost(req)
+}
+
+// Capabilities forwards to the wrapped client
+func (c *trackedClient) Capabilities() llm.ModelCapabilities {
+	return c.sdk.provider().Capabilities()
+}
+
+// GenerateWithTools forwards to the wrapped client
+func (c *trackedClient) GenerateWithTools(ctx context.Context, req llm.GenerateWithToolsRequest) (*llm.GenerateResponse, error) {
+	var resp *llm.GenerateResponse
+	err := c.sdk.track(ctx, "llm.GenerateWithTools", func(ctx context.Context) (llm.Usage, error) {
+		var err error
+		resp, err = c.sdk.provider().GenerateWithTools(ctx, req)
+		if err != nil {
+			return llm.Usage{}, err
+		}
+		return resp.Usage, nil
+	})
+	return resp, err
+}
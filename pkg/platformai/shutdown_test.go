@@ -0,0 +1,89 @@
+package platformai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+)
+
+// slowClient is an LLM client whose calls take a fixed time
+type slowClient struct {
+	delay time.Duration
+}
+
+func (c *slowClient) Generate(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+	time.Sleep(c.delay)
+	return &llm.GenerateResponse{Text: "ok"}, nil
+}
+
+func (c *slowClient) GenerateWithContext(ctx context.Context, req llm.GenerateRequest, additionalContext string) (*llm.GenerateResponse, error) {
+	return c.Generate(ctx, req)
+}
+
+func (c *slowClient) EstimateCost(req llm.GenerateRequest) (llm.EstimatedCost, error) {
+	return llm.EstimatedCost{}, nil
+}
+
+func (c *slowClient) Capabilities() llm.ModelCapabilities {
+	return llm.ModelCapabilities{}
+}
+
+func (c *slowClient) GenerateWithTools(ctx context.Context, req llm.GenerateWithToolsRequest) (*llm.GenerateResponse, error) {
+	return c.Generate(ctx, llm.GenerateRequest{})
+}
+
+func TestSDK_Shutdown(t *testing.T) {
+	sdk := &SDK{analytics: NewAnalytics(nil)}
+	sdk.setLLMClient(&slowClient{delay: 100 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := sdk.LLM().Generate(context.Background(), llm.GenerateRequest{UserPrompt: "test"})
+			errs <- err
+		}()
+	}
+	// Let every call register before shutting down
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := sdk.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("in-flight Generate() error = %v, want nil", err)
+		}
+	}
+
+	if _, err := sdk.LLM().Generate(context.Background(), llm.GenerateRequest{}); !errors.Is(err, ErrSDKShutdown) {
+		t.Errorf("Generate() after Shutdown error = %v, want ErrSDKShutdown", err)
+	}
+}
+
+func TestSDK_ShutdownTimeout(t *testing.T) {
+	sdk := &SDK{analytics: NewAnalytics(nil)}
+	sdk.setLLMClient(&slowClient{delay: 200 * time.Millisecond})
+
+	go func() {
+		_, _ = sdk.LLM().Generate(context.Background(), llm.GenerateRequest{})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := sdk.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,109 @@
+//go:build integration
+
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// Container images, pinned so integration runs are reproducible
+const (
+	RedisImage    = "redis:7-alpine"
+	PostgresImage = "postgres:16-alpine"
+	QdrantImage   = "qdrant/qdrant:v1.12.4"
+)
+
+// startupTimeout bounds how long each container may take to become healthy
+const startupTimeout = 2 * time.Minute
+
+// Postgres credentials of the container started by StartPostgres
+const (
+	postgresUser     = "platformai"
+	postgresPassword = "platformai"
+	postgresDB       = "platformai"
+)
+
+// StartRedis starts a Redis container for the test and returns its "host:port"
+// address. The container is removed when the test finishes.
+func StartRedis(t *testing.T) (addr string) {
+	t.Helper()
+	container := start(t, testcontainers.ContainerRequest{
+		Image:        RedisImage,
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("Ready to accept connections"),
+	})
+	host, port := endpoint(t, container, "6379/tcp")
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+// StartPostgres starts a PostgreSQL container for the test and returns a
+// connection string for its database. The container is removed when the test finishes.
+func StartPostgres(t *testing.T) (connStr string) {
+	t.Helper()
+	container := start(t, testcontainers.ContainerRequest{
+		Image:        PostgresImage,
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     postgresUser,
+			"POSTGRES_PASSWORD": postgresPassword,
+			"POSTGRES_DB":       postgresDB,
+		},
+		// The server logs readiness twice: once for the init run, once for real
+		WaitingFor: wait.ForLog("database system is ready to accept connections").WithOccurrence(2),
+	})
+	host, port := endpoint(t, container, "5432/tcp")
+	return fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=disable", postgresUser, postgresPassword, host, port, postgresDB)
+}
+
+// StartQdrant starts a Qdrant container for the test and returns the host and
+// port of its HTTP API. The container is removed when the test finishes.
+func StartQdrant(t *testing.T) (host string, port int) {
+	t.Helper()
+	container := start(t, testcontainers.ContainerRequest{
+		Image:        QdrantImage,
+		ExposedPorts: []string{"6333/tcp"},
+		WaitingFor:   wait.ForHTTP("/readyz").WithPort("6333/tcp"),
+	})
+	return endpoint(t, container, "6333/tcp")
+}
+
+// start runs a container, skipping the test when no Docker daemon is
+// available, and registers its removal with t.Cleanup
+func start(t *testing.T, req testcontainers.ContainerRequest) testcontainers.Container {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	req.WaitingFor = wait.ForAll(req.WaitingFor).WithDeadline(startupTimeout)
+	container, err := testcontainers.GenericContainer(context.Background(), testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	testcontainers.CleanupContainer(t, container)
+	if err != nil {
+		t.Fatalf("failed to start %s: %v", req.Image, err)
+	}
+	return container
+}
+
+// endpoint returns the host and mapped port of a container port
+func endpoint(t *testing.T, container testcontainers.Container, containerPort string) (string, int) {
+	t.Helper()
+	ctx := context.Background()
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get container host: %v", err)
+	}
+	mapped, err := container.MappedPort(ctx, nat.Port(containerPort))
+	if err != nil {
+		t.Fatalf("failed to get mapped port %s: %v", containerPort, err)
+	}
+	return host, mapped.Int()
+}
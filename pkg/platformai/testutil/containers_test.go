@@ -0,0 +1,57 @@
+//go:build integration
+
+package testutil
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartRedis(t *testing.T) {
+	conn, err := net.DialTimeout("tcp", StartRedis(t), 5*time.Second)
+	if err != nil {
+		t.Fatalf("failed to connect to Redis: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := fmt.Fprint(conn, "PING\r\n"); err != nil {
+		t.Fatalf("failed to send PING: %v", err)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read PING reply: %v", err)
+	}
+	if strings.TrimSpace(reply) != "+PONG" {
+		t.Errorf("PING reply = %q, want +PONG", reply)
+	}
+}
+
+func TestStartPostgres(t *testing.T) {
+	connStr, err := url.Parse(StartPostgres(t))
+	if err != nil {
+		t.Fatalf("StartPostgres() returned an invalid connection string: %v", err)
+	}
+	conn, err := net.DialTimeout("tcp", connStr.Host, 5*time.Second)
+	if err != nil {
+		t.Fatalf("failed to connect to Postgres: %v", err)
+	}
+	conn.Close()
+}
+
+func TestStartQdrant(t *testing.T) {
+	host, port := StartQdrant(t)
+	resp, err := http.Get(fmt.Sprintf("http://%s:%d/readyz", host, port))
+	if err != nil {
+		t.Fatalf("failed to reach Qdrant: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /readyz status = %d, want 200", resp.StatusCode)
+	}
+}
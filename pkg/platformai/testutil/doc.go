@@ -0,0 +1,6 @@
+// Package testutil starts the external services integration tests depend on.
+// It is a separate module, so the SDK itself does not depend on testcontainers.
+// Its helpers are built only with the integration tag:
+//
+//	cd pkg/platformai/testutil && go test -tags integration ./...
+package testutil
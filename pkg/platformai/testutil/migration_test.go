@@ -0,0 +1,60 @@
+//go:build integration
+
+package testutil
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+func TestMigrateInMemoryToRedis(t *testing.T) {
+	ctx := context.Background()
+	memory := rag.NewInMemoryVectorStore()
+	docs := []rag.Document{
+		{ID: "deploy", Content: "deploying the api", Embedding: []float32{1, 0, 0}, Metadata: map[string]string{"team": "platform"}},
+		{ID: "rollback", Content: "rolling back a release", Embedding: []float32{0.8, 0.2, 0}, Metadata: map[string]string{"team": "platform"}},
+		{ID: "oncall", Content: "on-call rotation", Embedding: []float32{0, 0, 1}, Metadata: map[string]string{"team": "sre"}},
+	}
+	if err := memory.AddBatch(ctx, docs); err != nil {
+		t.Fatalf("AddBatch() error = %v", err)
+	}
+
+	redis := dialRedis(t, StartRedis(t))
+
+	// Stream the export straight into the import
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(memory.Export(ctx, writer))
+	}()
+	if err := redis.Import(ctx, reader); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	want, _ := memory.List(ctx, 0, 0)
+	got, err := redis.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("migrated documents = %+v, want %+v", got, want)
+	}
+
+	// The migrated embeddings and metadata answer searches like the originals
+	restored := rag.NewInMemoryVectorStore()
+	if err := restored.AddBatch(ctx, got); err != nil {
+		t.Fatalf("AddBatch() error = %v", err)
+	}
+	query := []float32{1, 0.1, 0}
+	wantResults, _ := memory.SearchWithFilter(ctx, query, 2, 0, map[string]string{"team": "platform"})
+	gotResults, err := restored.SearchWithFilter(ctx, query, 2, 0, map[string]string{"team": "platform"})
+	if err != nil {
+		t.Fatalf("SearchWithFilter() error = %v", err)
+	}
+	if !reflect.DeepEqual(gotResults, wantResults) {
+		t.Errorf("SearchWithFilter() after migration = %+v, want %+v", gotResults, wantResults)
+	}
+}
@@ -0,0 +1,141 @@
+//go:build integration
+
+package testutil
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// redisDocuments keeps exported documents in Redis, one JSON value per
+// document plus a set of IDs. It is just enough of a store to migrate into.
+type redisDocuments struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialRedis connects to the Redis server at addr for the duration of the test
+func dialRedis(t *testing.T, addr string) *redisDocuments {
+	t.Helper()
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		t.Fatalf("failed to connect to redis: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return &redisDocuments{conn: conn, reader: bufio.NewReader(conn)}
+}
+
+// Import stores the newline-delimited JSON documents written by an Export
+func (r *redisDocuments) Import(ctx context.Context, in io.Reader) error {
+	decoder := json.NewDecoder(in)
+	for decoder.More() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var doc rag.Document
+		if err := decoder.Decode(&doc); err != nil {
+			return fmt.Errorf("failed to decode document: %w", err)
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if _, err := r.do("SET", "doc:"+doc.ID, string(data)); err != nil {
+			return err
+		}
+		if _, err := r.do("SADD", "ids", doc.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns every stored document ordered by ID
+func (r *redisDocuments) List() ([]rag.Document, error) {
+	reply, err := r.do("SMEMBERS", "ids")
+	if err != nil {
+		return nil, err
+	}
+	ids, _ := reply.([]any)
+	docs := make([]rag.Document, 0, len(ids))
+	for _, id := range ids {
+		reply, err := r.do("GET", "doc:"+fmt.Sprint(id))
+		if err != nil {
+			return nil, err
+		}
+		var doc rag.Document
+		if err := json.Unmarshal([]byte(fmt.Sprint(reply)), &doc); err != nil {
+			return nil, fmt.Errorf("failed to decode document %v: %w", id, err)
+		}
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return docs, nil
+}
+
+// do sends one RESP command and reads its reply
+func (r *redisDocuments) do(args ...string) (any, error) {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	if _, err := r.conn.Write(buf); err != nil {
+		return nil, err
+	}
+	return readReply(r.reader)
+}
+
+// readReply reads one RESP2 value: a string, int64, []any, or nil
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("invalid redis reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("invalid redis reply %q", line)
+	}
+}
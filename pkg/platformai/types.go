@@ -1,4 +0,0 @@
-package platformai
-
-// Version is the SDK version
-const Version = "0.1.0"
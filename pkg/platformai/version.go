@@ -0,0 +1,6 @@
+package platformai
+
+import "github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/internal/httpclient"
+
+// Version is the SDK version. Every provider API request reports it in its User-Agent.
+const Version = httpclient.SDKVersion
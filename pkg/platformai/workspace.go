@@ -0,0 +1,211 @@
+package platformai
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/codemapping"
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// Workspace isolates one project within an SDK. It shares the SDK's providers
+// but keeps its documents in a RAG namespace of its own and accounts for its
+// LLM usage separately.
+type Workspace struct {
+	Name string
+
+	sdk   *SDK
+	llm   llm.Client
+	costs *CostAccumulator
+
+	mu                 sync.Mutex  // Guards the fields below
+	rag                *rag.Module // Namespace of ragBase
+	ragBase            *rag.Module // SDK module rag was created from
+	codeMapping        *codemapping.Module
+	codeMappingTimeout time.Duration // Analyze timeout codeMapping was created with
+}
+
+// NewWorkspace returns the workspace called name, creating it on first use.
+// Its RAG module stores documents in namespace name of the SDK's vector store.
+// It fails if RAG is configured with a vector store that does not support
+// namespaces, rather than sharing documents between workspaces.
+func (s *SDK) NewWorkspace(name string) (*Workspace, error) {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+
+	if ws, ok := s.workspaces[name]; ok {
+		return ws, nil
+	}
+	ws := &Workspace{Name: name, sdk: s, costs: &CostAccumulator{}}
+	ws.llm = &workspaceClient{client: s.llmClient, ws: ws}
+	if ragModule := s.RAG(); ragModule != nil {
+		var err error
+		if ws.rag, err = ragModule.Namespace(name, rag.WithLLM(ws.llm)); err != nil {
+			return nil, fmt.Errorf("failed to create workspace %s: %w", name, err)
+		}
+		ws.ragBase = ragModule
+	}
+	if s.workspaces == nil {
+		s.workspaces = make(map[string]*Workspace)
+	}
+	s.workspaces[name] = ws
+	return ws, nil
+}
+
+// Workspaces returns the names of the workspaces created so far, sorted
+func (s *SDK) Workspaces() []string {
+	s.wsMu.Lock()
+	defer s.wsMu.Unlock()
+
+	names := make([]string, 0, len(s.workspaces))
+	for name := range s.workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LLM returns the SDK's LLM client, charging usage to the workspace
+func (w *Workspace) LLM() llm.Client {
+	return w.llm
+}
+
+// RAG returns the workspace's RAG module, or nil if RAG is not configured. After
+// ApplyConfig replaces the SDK's RAG module it returns the workspace's namespace
+// of the new one, or nil if its vector store does not support namespaces.
+func (w *Workspace) RAG() *rag.Module {
+	base := w.sdk.RAG()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if base != w.ragBase {
+		w.rag, w.ragBase = nil, base
+		if base != nil {
+			w.rag, _ = base.Namespace(w.Name, rag.WithLLM(w.llm))
+		}
+	}
+	return w.rag
+}
+
+// CodeMapping returns a code mapping module whose LLM usage is charged to the
+// workspace. Like SDK.CodeMapping it returns the same module until ApplyConfig
+// changes Timeouts.AnalyzeTimeout.
+func (w *Workspace) CodeMapping() *codemapping.Module {
+	w.sdk.cfgMu.RLock()
+	timeout := w.sdk.config.Timeouts.AnalyzeTimeout
+	w.sdk.cfgMu.RUnlock()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.codeMapping == nil || w.codeMappingTimeout != timeout {
+		w.codeMapping = codemapping.NewModule(w.llm, codemapping.WithAnalyzeTimeout(timeout))
+		w.codeMappingTimeout = timeout
+	}
+	return w.codeMapping
+}
+
+// Costs returns the workspace's accumulated LLM usage
+func (w *Workspace) Costs() *CostAccumulator {
+	return w.costs
+}
+
+// CostAccumulator totals token usage and its list-price cost. The zero value is ready to use.
+type CostAccumulator struct {
+	mu      sync.Mutex
+	usage   llm.Usage
+	cache   llm.CacheStats
+	costUSD float64
+}
+
+// Add records usage of model. Usage of models without known pricing counts
+// towards the tokens but not the cost.
+func (c *CostAccumulator) Add(model string, usage llm.Usage) {
+	c.AddCached(model, usage, llm.CacheStats{})
+}
+
+// AddCached records usage of model plus prompt cache usage, which is priced at
+// the model's cache read and write rates instead of its input rate
+func (c *CostAccumulator) AddCached(model string, usage llm.Usage, cache llm.CacheStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.usage.PromptTokens += usage.PromptTokens
+	c.usage.CompletionTokens += usage.CompletionTokens
+	c.usage.TotalTokens += usage.TotalTokens
+	c.cache.CacheReadTokens += cache.CacheReadTokens
+	c.cache.CacheWriteTokens += cache.CacheWriteTokens
+	if pricing, err := llm.PricingFor(model); err == nil {
+		c.costUSD += (float64(usage.PromptTokens)*pricing.InputPerMTok +
+			float64(usage.CompletionTokens)*pricing.OutputPerMTok +
+			float64(cache.CacheReadTokens)*pricing.CacheReadPerMTok +
+			float64(cache.CacheWriteTokens)*pricing.CacheWritePerMTok) / 1e6
+	}
+}
+
+// Usage returns the total token usage recorded
+func (c *CostAccumulator) Usage() llm.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+// CacheStats returns the total prompt cache usage recorded
+func (c *CostAccumulator) CacheStats() llm.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache
+}
+
+// TotalUSD returns the list-price cost of the recorded usage
+func (c *CostAccumulator) TotalUSD() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.costUSD
+}
+
+// workspaceClient forwards to the SDK's client and charges usage to a workspace
+type workspaceClient struct {
+	client llm.Client
+	ws     *Workspace
+}
+
+// record charges a successful response's usage to the workspace at the price
+// of the model that generated it, such as the one a RoutingClient picked
+func (c *workspaceClient) record(resp *llm.GenerateResponse, err error) (*llm.GenerateResponse, error) {
+	if err == nil {
+		c.ws.costs.AddCached(cmp.Or(resp.Model, c.ws.sdk.model()), resp.Usage, resp.CacheStats)
+	}
+	return resp, err
+}
+
+// Generate forwards to the SDK's client
+func (c *workspaceClient) Generate(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+	return c.record(c.client.Generate(ctx, req))
+}
+
+// GenerateWithContext forwards to the SDK's client
+func (c *workspaceClient) GenerateWithContext(ctx context.Context, req llm.GenerateRequest, additionalContext string) (*llm.GenerateResponse, error) {
+	return c.record(c.client.GenerateWithContext(ctx, req, additionalContext))
+}
+
+// GenerateWithTools forwards to the SDK's client
+func (c *workspaceClient) GenerateWithTools(ctx context.Context, req llm.GenerateWithToolsRequest) (*llm.GenerateResponse, error) {
+	return c.record(c.client.GenerateWithTools(ctx, req))
+}
+
+// EstimateCost forwards to the SDK's client
+func (c *workspaceClient) EstimateCost(req llm.GenerateRequest) (llm.EstimatedCost, error) {
+	return c.client.EstimateCost(req)
+}
+
+// Capabilities forwards to the SDK's client
+func (c *workspaceClient) Capabilities() llm.ModelCapabilities {
+	return c.client.Capabilities()
+}
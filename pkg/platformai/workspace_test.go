@@ -0,0 +1,197 @@
+package platformai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/llm"
+	"github.com/philipsahli/innominatus-ai-sdk/pkg/platformai/rag"
+)
+
+// newWorkspaceSDK returns an SDK whose OpenAI embeddings are derived from the
+// input length and whose Anthropic calls answer with 1000 input and 100 output tokens
+func newWorkspaceSDK(t *testing.T) *SDK {
+	t.Helper()
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		var body []byte
+		switch r.URL.Host {
+		case "api.openai.com":
+			var req struct {
+				Input []string `json:"input"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				return nil, err
+			}
+			data := make([]map[string][]float32, len(req.Input))
+			for i, text := range req.Input {
+				data[i] = map[string][]float32{"embedding": {1, float32(len(text))}}
+			}
+			body, _ = json.Marshal(map[string]any{"data": data})
+		default:
+			body = []byte(`{"content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":1000,"output_tokens":100}}`)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request:    r,
+		}, nil
+	})}
+
+	sdk, err := New(context.Background(), &Config{
+		LLM: LLMConfig{Provider: "anthropic", APIKey: "test", Model: "claude-sonnet-4-5-20250929"},
+		RAG: &rag.Config{EmbeddingProvider: "openai", APIKey: "test"},
+	}, WithHTTPClient(client))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return sdk
+}
+
+// newTestWorkspaces creates the named workspaces of sdk
+func newTestWorkspaces(t *testing.T, sdk *SDK, names ...string) []*Workspace {
+	t.Helper()
+	workspaces := make([]*Workspace, len(names))
+	for i, name := range names {
+		ws, err := sdk.NewWorkspace(name)
+		if err != nil {
+			t.Fatalf("NewWorkspace(%s) error = %v", name, err)
+		}
+		workspaces[i] = ws
+	}
+	return workspaces
+}
+
+func TestWorkspace_RAGIsolation(t *testing.T) {
+	sdk := newWorkspaceSDK(t)
+	ctx := context.Background()
+
+	workspaces := newTestWorkspaces(t, sdk, "alpha", "beta")
+	alpha, beta := workspaces[0], workspaces[1]
+	if err := alpha.RAG().AddDocument(ctx, "alpha-doc", "alpha deployment guide", nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+	if err := beta.RAG().AddDocument(ctx, "beta-doc", "beta deployment guide", nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		ws   *Workspace
+		want string
+	}{{alpha, "alpha-doc"}, {beta, "beta-doc"}} {
+		resp, err := tt.ws.RAG().Retrieve(ctx, rag.RetrieveRequest{Query: "deployment guide", TopK: 10})
+		if err != nil {
+			t.Fatalf("Retrieve() error = %v", err)
+		}
+		var ids []string
+		for _, result := range resp.Results {
+			ids = append(ids, result.Document.ID)
+		}
+		if !reflect.DeepEqual(ids, []string{tt.want}) {
+			t.Errorf("workspace %s retrieved %v, want [%s]", tt.ws.Name, ids, tt.want)
+		}
+	}
+
+	if n, _ := sdk.RAG().Count(ctx); n != 0 {
+		t.Errorf("SDK RAG count = %d, want workspace documents kept out of the default namespace", n)
+	}
+	if again, _ := sdk.NewWorkspace("alpha"); again != alpha {
+		t.Error("NewWorkspace() created a second workspace with the same name")
+	}
+	if got := sdk.Workspaces(); !reflect.DeepEqual(got, []string{"alpha", "beta"}) {
+		t.Errorf("Workspaces() = %v, want [alpha beta]", got)
+	}
+}
+
+func TestWorkspace_RAGAfterApplyConfig(t *testing.T) {
+	sdk := newWorkspaceSDK(t)
+	ctx := context.Background()
+
+	alpha := newTestWorkspaces(t, sdk, "alpha")[0]
+	if err := alpha.RAG().AddDocument(ctx, "alpha-doc", "alpha deployment guide", nil); err != nil {
+		t.Fatalf("AddDocument() error = %v", err)
+	}
+	before := alpha.RAG()
+
+	next := *sdk.config
+	ragConfig := *next.RAG
+	ragConfig.Timeout = time.Minute
+	next.RAG = &ragConfig
+	if err := sdk.ApplyConfig(&next); err != nil {
+		t.Fatalf("ApplyConfig() error = %v", err)
+	}
+
+	after := alpha.RAG()
+	if after == before || alpha.ragBase != sdk.RAG() {
+		t.Fatal("Workspace.RAG() still uses the RAG module replaced by ApplyConfig")
+	}
+	if after != alpha.RAG() {
+		t.Error("Workspace.RAG() created a new namespace module on every call")
+	}
+	if _, err := after.GetDocument(ctx, "alpha-doc"); err != nil {
+		t.Errorf("GetDocument() after ApplyConfig error = %v", err)
+	}
+}
+
+func TestWorkspace_Costs(t *testing.T) {
+	sdk := newWorkspaceSDK(t)
+	workspaces := newTestWorkspaces(t, sdk, "alpha", "beta")
+	alpha, beta := workspaces[0], workspaces[1]
+
+	for i := 0; i < 2; i++ {
+		if _, err := alpha.LLM().Generate(context.Background(), llm.GenerateRequest{UserPrompt: "hi"}); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	if got := alpha.Costs().Usage(); got.PromptTokens != 2000 || got.CompletionTokens != 200 {
+		t.Errorf("alpha usage = %+v, want 2000 prompt and 200 completion tokens", got)
+	}
+	// 2000 input tokens at $3/MTok plus 200 output tokens at $15/MTok
+	if got := alpha.Costs().TotalUSD(); math.Abs(got-0.009) > 1e-9 {
+		t.Errorf("alpha cost = %v, want 0.009", got)
+	}
+	if got := beta.Costs().Usage(); got != (llm.Usage{}) {
+		t.Errorf("beta usage = %+v, want none", got)
+	}
+}
+
+func TestWorkspace_CostsResponseModel(t *testing.T) {
+	sdk := newWorkspaceSDK(t)
+	ws := newTestWorkspaces(t, sdk, "alpha")[0]
+	// A routed request answered by a cheaper model than the SDK's
+	ws.llm.(*workspaceClient).client = &llm.MockClient{
+		GenerateFunc: func(ctx context.Context, req llm.GenerateRequest) (*llm.GenerateResponse, error) {
+			return &llm.GenerateResponse{Model: "claude-haiku-4-5", Usage: llm.Usage{PromptTokens: 1000, CompletionTokens: 100}}, nil
+		},
+	}
+
+	if _, err := ws.LLM().Generate(context.Background(), llm.GenerateRequest{UserPrompt: "hi"}); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	// 1000 input tokens at $1/MTok plus 100 output tokens at $5/MTok
+	if got := ws.Costs().TotalUSD(); math.Abs(got-0.0015) > 1e-9 {
+		t.Errorf("cost = %v, want 0.0015 at the responding model's price", got)
+	}
+}
+
+func TestCostAccumulator_AddCached(t *testing.T) {
+	var costs CostAccumulator
+	costs.AddCached("claude-sonnet-4-5", llm.Usage{PromptTokens: 1000, CompletionTokens: 100, TotalTokens: 1100},
+		llm.CacheStats{CacheReadTokens: 100000, CacheWriteTokens: 10000})
+
+	if got := costs.CacheStats(); got != (llm.CacheStats{CacheReadTokens: 100000, CacheWriteTokens: 10000}) {
+		t.Errorf("CacheStats() = %+v, want the recorded cache usage", got)
+	}
+	// $0.003 input + $0.0015 output + 100k cache reads at $0.30/MTok + 10k cache writes at $3.75/MTok
+	if got := costs.TotalUSD(); math.Abs(got-0.0720) > 1e-9 {
+		t.Errorf("TotalUSD() = %v, want 0.0720", got)
+	}
+}